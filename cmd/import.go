@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/importer"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// importTimeout limits single queries of the subcommands in seconds.
+const importTimeout = 60
+
+// runImport runs import subcommand which imports books from the file and
+// writes the report to stdout or to the report file. Format is detected
+// from the file extension unless given explicitly. Returns process exit code.
+func runImport(dbPool *pgxpool.Pool, logger logger.Logger, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "file format: csv or ndjson, detected from file extension by default")
+	dryRun := flags.Bool("dry-run", false, "validate and report changes without saving them")
+	reportPath := flags.String("report", "", "path of the JSON report file, the report is printed to stdout by default")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: readyread import [-format csv|ndjson] [-dry-run] [-report file] <file>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = importer.FormatCSV
+		case ".ndjson", ".jsonl":
+			*format = importer.FormatNDJSON
+		default:
+			logger.Errorf("cannot detect format of %s, use -format flag", path)
+			return 2
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Errorf("cannot open import file: %v", err)
+		return 1
+	}
+	defer file.Close()
+
+	currencyService := currency.NewService(currency.NewStorage(dbPool, importTimeout), logger)
+	importService := importer.NewService(importer.NewStorage(dbPool), currencyService, logger)
+
+	logger.Infof("importing books from %s", path)
+	report, err := importService.Import(context.Background(), file, *format, *dryRun)
+	if err != nil {
+		logger.Errorf("cannot import books: %v", err)
+		return 1
+	}
+
+	out := os.Stdout
+	if *reportPath != "" {
+		out, err = os.Create(*reportPath)
+		if err != nil {
+			logger.Errorf("cannot create report file: %v", err)
+			return 1
+		}
+		defer out.Close()
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Errorf("cannot write import report: %v", err)
+		return 1
+	}
+
+	logger.Infof("imported books: %d created, %d updated, %d failed", report.Created, report.Updated, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+
+	return 0
+}
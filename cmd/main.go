@@ -10,7 +10,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgtype"
+	shopspring "github.com/jackc/pgtype/ext/shopspring-numeric"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/config"
 	"github.com/juicyluv/ReadyRead/internal/server"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
@@ -21,9 +24,16 @@ var (
 	configPath = flag.String("config-path", "config/config.yml", "path for application configuration file")
 )
 
+// commands are subcommands run instead of the server. They return process exit code.
+var commands = map[string]func(dbPool *pgxpool.Pool, logger logger.Logger, args []string) int{
+	"import":       runImport,
+	"import-rates": runImportRates,
+}
+
 // @title ReadyRead API
 // @version 1.0.0
 // @description API documentation for ReadyRead book shop.
+// @description Money amounts are written as decimal strings, e.g. "12.99", so they are never rounded by float parsing. Numbers are accepted in requests too.
 
 // @host localhost:8080
 // @BasePath /api
@@ -44,24 +54,40 @@ func main() {
 
 	logger.Info("connecting to database")
 
-	pgxConfig, err := pgx.ParseConfig(cfg.DB.DSN)
+	poolConfig, err := pgxpool.ParseConfig(cfg.DB.DSN)
 	if err != nil {
 		logger.Fatalf("cannot parse database config from dsn: %v", err)
 	}
+	poolConfig.MaxConns = cfg.DB.MaxConnections
+	poolConfig.ConnConfig.ConnectTimeout = time.Duration(cfg.DB.ConnectionTimeout) * time.Second
+	poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		conn.ConnInfo().RegisterDataType(pgtype.DataType{
+			Value: &shopspring.Numeric{},
+			Name:  "numeric",
+			OID:   pgtype.NumericOID,
+		})
+		return nil
+	}
 
 	dbTimeout, dbCancel := context.WithTimeout(context.Background(), time.Duration(cfg.DB.ConnectionTimeout)*time.Second)
 	defer dbCancel()
-	dbConn, err := pgx.ConnectConfig(dbTimeout, pgxConfig)
+	dbPool, err := pgxpool.ConnectConfig(dbTimeout, poolConfig)
 	if err != nil {
 		logger.Fatalf("cannot connect to database: %v", err)
 	}
 
-	if err := dbConn.Ping(dbTimeout); err != nil {
+	if err := dbPool.Ping(dbTimeout); err != nil {
 		logger.Fatalf("cannot ping database: %v", err)
 	}
 
 	logger.Info("connected to database")
 
+	if command, ok := commands[flag.Arg(0)]; ok {
+		code := command(dbPool, logger, flag.Args()[1:])
+		dbPool.Close()
+		os.Exit(code)
+	}
+
 	logger.Info("starting the server")
 	srv := server.NewServer(cfg, router, &logger)
 
@@ -70,7 +96,7 @@ func main() {
 	signal.Notify(quit, signals...)
 
 	go func() {
-		if err := srv.Run(dbConn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := srv.Run(dbPool); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Fatalf("cannot run the server: %v", err)
 		}
 	}()
@@ -81,16 +107,19 @@ func main() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer func() {
-		dbCloseCtx, dbCloseCancel := context.WithTimeout(
-			context.Background(),
-			time.Duration(cfg.DB.ShutdownTimeout)*time.Second,
-		)
-		defer dbCloseCancel()
-		err := dbConn.Close(dbCloseCtx)
-		if err != nil {
-			logger.Error("failed to close database connection: %v", err)
+		// Close waits for acquired connections to be released.
+		closed := make(chan struct{})
+		go func() {
+			dbPool.Close()
+			close(closed)
+		}()
+
+		select {
+		case <-closed:
+			logger.Info("closed database connection")
+		case <-time.After(time.Duration(cfg.DB.ShutdownTimeout) * time.Second):
+			logger.Error("failed to close database connection: timed out")
 		}
-		logger.Info("closed database connection")
 		cancel()
 	}()
 
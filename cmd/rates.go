@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// runImportRates runs import-rates subcommand which sets exchange rates
+// from the CSV file and prints the saved rates to stdout. Returns process
+// exit code.
+func runImportRates(dbPool *pgxpool.Pool, logger logger.Logger, args []string) int {
+	flags := flag.NewFlagSet("import-rates", flag.ContinueOnError)
+	source := flags.String("source", "", "source of the rates without one, e.g. ECB")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: readyread import-rates [-source name] <file>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Errorf("cannot open exchange rates file: %v", err)
+		return 1
+	}
+	defer file.Close()
+
+	currencyService := currency.NewService(currency.NewStorage(dbPool, importTimeout), logger)
+
+	logger.Infof("importing exchange rates from %s", path)
+	rates, err := currencyService.ImportRates(context.Background(), file, *source)
+	if err != nil {
+		logger.Errorf("cannot import exchange rates: %v", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rates); err != nil {
+		logger.Errorf("cannot write exchange rates: %v", err)
+		return 1
+	}
+
+	logger.Infof("imported %d exchange rates", len(rates))
+	return 0
+}
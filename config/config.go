@@ -23,7 +23,130 @@ type Config struct {
 		RequestTimeout    int    `yaml:"requestTimeout" env-default:"5"`
 		ConnectionTimeout int    `yaml:"connectionTimeout" env-default:"5"`
 		ShutdownTimeout   int    `yaml:"shutdownTimeout" env-default:"5"`
+		// MaxConnections limits connections of the pool shared by
+		// requests and background jobs.
+		MaxConnections int32 `yaml:"maxConnections" env-default:"10"`
 	} `yaml:"database" env-required:"true"`
+	// Storage represents configuration for uploaded files storage.
+	Storage struct {
+		// Driver is either local or s3.
+		Driver        string `yaml:"driver" env-default:"local"`
+		LocalDir      string `yaml:"localDir" env-default:"uploads"`
+		BaseURL       string `yaml:"baseUrl" env-default:"http://localhost:8080/media"`
+		MaxUploadSize int64  `yaml:"maxUploadSize" env-default:"5"`
+		S3            struct {
+			Endpoint  string `yaml:"endpoint"`
+			Region    string `yaml:"region" env-default:"us-east-1"`
+			Bucket    string `yaml:"bucket"`
+			PublicURL string `yaml:"publicUrl"`
+			AccessKey string `env:"S3_ACCESS_KEY"`
+			SecretKey string `env:"S3_SECRET_KEY"`
+		} `yaml:"s3"`
+	} `yaml:"storage"`
+	// Inventory represents configuration for stock reservations.
+	Inventory struct {
+		// ReservationTTL is a lifetime of basket reservations in minutes.
+		ReservationTTL int `yaml:"reservationTtl" env-default:"15"`
+		// CheckoutTTL is a lifetime of reservations after the checkout is started in minutes.
+		CheckoutTTL int `yaml:"checkoutTtl" env-default:"30"`
+		// SweepInterval is a period of expired reservations release in seconds.
+		// Zero disables the sweeper.
+		SweepInterval int `yaml:"sweepInterval" env-default:"60"`
+	} `yaml:"inventory"`
+	// Pricing represents configuration for scheduled price changes.
+	Pricing struct {
+		// ApplyInterval is a period of applying scheduled list prices in seconds.
+		// Zero disables the scheduler.
+		ApplyInterval int `yaml:"applyInterval" env-default:"60"`
+	} `yaml:"pricing"`
+	// Tax represents configuration for tax calculation.
+	Tax struct {
+		// PricesIncludeTax tells whether book prices include tax.
+		// Otherwise the tax is added on top of them.
+		PricesIncludeTax bool `yaml:"pricesIncludeTax" env-default:"false"`
+	} `yaml:"tax"`
+	// Shipping represents configuration for shipping cost calculation.
+	Shipping struct {
+		// DefaultWeight is the weight in grams of books
+		// without weight in their metadata.
+		DefaultWeight int `yaml:"defaultWeight" env-default:"400"`
+	} `yaml:"shipping"`
+	// Payments represents configuration for the payment provider.
+	Payments struct {
+		// Provider is the payment provider, only "fake" is available.
+		Provider string `yaml:"provider" env-default:"fake"`
+		// WebhookSecret verifies webhook requests of the provider.
+		WebhookSecret string `env:"PAYMENTS_WEBHOOK_SECRET"`
+		// Fake represents configuration for the fake provider for development.
+		Fake struct {
+			// WebhookURL receives events of the fake provider.
+			// Events are dropped if it's empty.
+			WebhookURL string `yaml:"webhookUrl" env-default:"http://localhost:8080/api/webhooks/payments"`
+			// Delay is a period in seconds before delayed payments succeed.
+			Delay int `yaml:"delay" env-default:"5"`
+		} `yaml:"fake"`
+	} `yaml:"payments"`
+	// Invoices represents configuration for invoices and credit notes.
+	Invoices struct {
+		// IssueInterval is a period of issuing invoices of paid orders and
+		// credit notes of refunds in seconds. Zero disables the issuer.
+		IssueInterval int `yaml:"issueInterval" env-default:"60"`
+		// Seller is printed on documents.
+		Seller struct {
+			Name    string   `yaml:"name" env-default:"ReadyRead"`
+			Address []string `yaml:"address"`
+			TaxId   string   `yaml:"taxId"`
+			Email   string   `yaml:"email"`
+		} `yaml:"seller"`
+	} `yaml:"invoices"`
+	// Recommendations represents configuration for book recommendations.
+	Recommendations struct {
+		// RefreshInterval is a period of recomputing recommendations in seconds.
+		// Zero disables the refresher.
+		RefreshInterval int `yaml:"refreshInterval" env-default:"3600"`
+		// Size is the number of recommendations kept for every book and user.
+		Size int `yaml:"size" env-default:"20"`
+	} `yaml:"recommendations"`
+	// Collections represents configuration for collections of books.
+	Collections struct {
+		// CacheTTL is a period books of collections are cached for in seconds.
+		// Zero disables caching.
+		CacheTTL int `yaml:"cacheTtl" env-default:"300"`
+	} `yaml:"collections"`
+	// Ebooks represents configuration for ebook files and their downloads.
+	Ebooks struct {
+		// MaxFileSize is the maximum size of uploaded ebook files in megabytes.
+		MaxFileSize int64 `yaml:"maxFileSize" env-default:"100"`
+		// DownloadURL is a base URL of download links.
+		DownloadURL string `yaml:"downloadUrl" env-default:"http://localhost:8080/api/downloads"`
+		// LinkTTL is a lifetime of download links in minutes.
+		LinkTTL int `yaml:"linkTtl" env-default:"15"`
+		// DownloadLimit is the number of downloads of every bought ebook.
+		DownloadLimit int `yaml:"downloadLimit" env-default:"5"`
+		// SigningKey signs download links. A random key is used if it's not
+		// set, then links stop working when the server restarts.
+		SigningKey string `env:"EBOOKS_SIGNING_KEY"`
+	} `yaml:"ebooks"`
+	// Mailer represents configuration for outgoing emails.
+	// Emails are written to the log if host is not set.
+	Mailer struct {
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port" env-default:"587"`
+		Username string `yaml:"username"`
+		Password string `env:"SMTP_PASSWORD"`
+		From     string `yaml:"from" env-default:"ReadyRead <noreply@readyread.com>"`
+	} `yaml:"mailer"`
+	// Notifications represents configuration for stock notifications.
+	Notifications struct {
+		// Interval is a period of sending notifications in seconds.
+		// Zero disables notifications.
+		Interval int `yaml:"interval" env-default:"60"`
+		// StaffEmails receive low-stock alerts.
+		StaffEmails []string `yaml:"staffEmails"`
+		// WebhookURL receives low-stock alerts if set.
+		WebhookURL    string `yaml:"webhookUrl"`
+		WebhookSecret string `env:"WEBHOOK_SECRET"`
+	} `yaml:"notifications"`
 }
 
 var instance *Config
@@ -116,7 +116,7 @@ const docTemplate_swagger = `{
                 "tags": [
                     "authors"
                 ],
-                "summary": "Update user",
+                "summary": "Update author",
                 "parameters": [
                     {
                         "type": "integer",
@@ -190,6 +190,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -253,9 +259,9 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/genres": {
-            "post": {
-                "description": "Insert genre in database.",
+        "/authors/{id}/books": {
+            "get": {
+                "description": "Get books of the author with specified id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,25 +269,64 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "authors"
                 ],
-                "summary": "Create genre",
+                "summary": "List author books",
                 "parameters": [
                     {
-                        "description": "JSON input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CreateGenreInput"
-                        }
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the book title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genreId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "languageId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
                         }
                     },
                     "400": {
@@ -290,6 +335,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -299,33 +350,46 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/genres/{id}": {
-            "get": {
-                "description": "Get genre by id.",
+        "/authors/{id}/photo": {
+            "put": {
+                "description": "Upload JPEG, PNG or GIF image as the author photo. Medium and thumbnail\nvariants are generated from it. The previous photo is removed.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "authors"
                 ],
-                "summary": "Show genre information",
+                "summary": "Upload author photo",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Genre id",
+                        "description": "Author id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Author photo",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Genre"
+                            "$ref": "#/definitions/Image"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -334,6 +398,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -342,8 +412,8 @@ const docTemplate_swagger = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update genre with specified id.",
+            "delete": {
+                "description": "Delete uploaded photo of the author with specified id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -351,37 +421,22 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "authors"
                 ],
-                "summary": "Update genre",
+                "summary": "Delete author photo",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Genre id",
+                        "description": "Author id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "JSON input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UpdateGenreInput"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": ""
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -395,9 +450,11 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete genre with specified id.",
+            }
+        },
+        "/books": {
+            "get": {
+                "description": "Get catalog books filtered by title, author, genre and language.",
                 "consumes": [
                     "application/json"
                 ],
@@ -405,24 +462,73 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "books"
                 ],
-                "summary": "Delete genre",
+                "summary": "List books",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the book title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "authorId",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Genre id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "genreId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "languageId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Work id",
+                        "name": "workId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": ""
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -434,11 +540,9 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
-            }
-        },
-        "/languages": {
+            },
             "post": {
-                "description": "Insert language in database.",
+                "description": "Insert book with its contributors and genres in database.",
                 "consumes": [
                     "application/json"
                 ],
@@ -446,9 +550,9 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "languages"
+                    "books"
                 ],
-                "summary": "Create language",
+                "summary": "Create book",
                 "parameters": [
                     {
                         "description": "JSON input",
@@ -456,7 +560,7 @@ const docTemplate_swagger = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreateLanguageInput"
+                            "$ref": "#/definitions/CreateBookInput"
                         }
                     }
                 ],
@@ -464,7 +568,7 @@ const docTemplate_swagger = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/Language"
+                            "$ref": "#/definitions/Book"
                         }
                     },
                     "400": {
@@ -473,6 +577,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -482,9 +592,9 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/languages/{id}": {
+        "/books/{id}": {
             "get": {
-                "description": "Get language by id.",
+                "description": "Get book by id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -492,23 +602,29 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "languages"
+                    "books"
                 ],
-                "summary": "Show language information",
+                "summary": "Show book information",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Language id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Language"
+                            "$ref": "#/definitions/Book"
                         }
                     },
                     "404": {
@@ -526,7 +642,7 @@ const docTemplate_swagger = `{
                 }
             },
             "put": {
-                "description": "Update language with specified id.",
+                "description": "Update book with specified id. Contributors and genres are replaced with given ones.",
                 "consumes": [
                     "application/json"
                 ],
@@ -534,13 +650,13 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "languages"
+                    "books"
                 ],
-                "summary": "Update language",
+                "summary": "Update book",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Language id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -551,7 +667,7 @@ const docTemplate_swagger = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UpdateLanguageInput"
+                            "$ref": "#/definitions/UpdateBookInput"
                         }
                     }
                 ],
@@ -571,6 +687,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -580,7 +702,7 @@ const docTemplate_swagger = `{
                 }
             },
             "delete": {
-                "description": "Delete language with specified id.",
+                "description": "Delete book with specified id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -588,13 +710,13 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "languages"
+                    "books"
                 ],
-                "summary": "Delete genre",
+                "summary": "Delete book",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Language id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -619,32 +741,32 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/users": {
-            "get": {
-                "description": "Get user by email and password.",
+        "/books/{id}/cover": {
+            "put": {
+                "description": "Upload JPEG, PNG or GIF image as the book cover. Medium and thumbnail\nvariants are generated from it. The previous cover is removed.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "books"
                 ],
-                "summary": "Get user by email and password from query parameters",
+                "summary": "Upload book cover",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "user email",
-                        "name": "email",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "user raw password",
-                        "name": "password",
-                        "in": "query",
+                        "type": "file",
+                        "description": "Cover image",
+                        "name": "image",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -652,7 +774,7 @@ const docTemplate_swagger = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "$ref": "#/definitions/Image"
                         }
                     },
                     "400": {
@@ -667,6 +789,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -675,8 +803,8 @@ const docTemplate_swagger = `{
                     }
                 }
             },
-            "post": {
-                "description": "Register a new user.",
+            "delete": {
+                "description": "Delete cover of the book with specified id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -684,29 +812,24 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "books"
                 ],
-                "summary": "Create user",
+                "summary": "Delete book cover",
                 "parameters": [
                     {
-                        "description": "JSON input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CreateUserInput"
-                        }
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/User"
-                        }
+                    "200": {
+                        "description": ""
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -720,9 +843,9 @@ const docTemplate_swagger = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/books/{id}/editions": {
             "get": {
-                "description": "Get user by id.",
+                "description": "Get all editions of the same work as the book with specified id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -730,23 +853,52 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "books"
                 ],
-                "summary": "Show user information",
+                "summary": "List book editions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Book"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -762,40 +914,107 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update the user with provided current password.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/books/{id}/files": {
+            "get": {
+                "description": "Get formats and sizes of files the ebook is delivered in.",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "ebooks"
                 ],
-                "summary": "Update user",
+                "summary": "List ebook files",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/EbookFile"
+                            }
+                        }
                     },
-                    {
-                        "description": "JSON input",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/UpdateUserInput"
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/books/{id}/files/{format}": {
+            "put": {
+                "description": "Upload EPUB or PDF file of the ebook, the previous file of the format is removed. Only books of the ebook format can have files. Files are not public, customers download them with signed links. Admin only.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ebooks"
+                ],
+                "summary": "Upload ebook file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "epub",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Ebook file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": ""
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EbookFile"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -809,6 +1028,18 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -818,7 +1049,7 @@ const docTemplate_swagger = `{
                 }
             },
             "delete": {
-                "description": "Delete the user by id.",
+                "description": "Delete the file of the ebook in the format. Customers can't download the ebook in the format anymore. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -826,22 +1057,39 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "ebooks"
                 ],
-                "summary": "Delete user",
+                "summary": "Delete ebook file",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "epub",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": ""
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -855,9 +1103,70 @@ const docTemplate_swagger = `{
                         }
                     }
                 }
+            }
+        },
+        "/books/{id}/prices": {
+            "get": {
+                "description": "Get price history of the book including scheduled list prices and sales, the latest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "List book prices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BookPrice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
             },
-            "patch": {
-                "description": "Partially update the user with provided current password.",
+            "post": {
+                "description": "Change the list price of the book now or schedule it. Later scheduled list prices are cancelled. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -865,13 +1174,13 @@ const docTemplate_swagger = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "prices"
                 ],
-                "summary": "Update user",
+                "summary": "Change list price",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User id",
+                        "description": "Book id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -882,9 +1191,66 @@ const docTemplate_swagger = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/UpdateUserPartiallyInput"
+                            "$ref": "#/definitions/ListPriceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/BookPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/books/{id}/prices/{priceId}": {
+            "delete": {
+                "description": "Cancel the scheduled list price or sale of the book. The sale which is going on is ended now. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Cancel price",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Price id",
+                        "name": "priceId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -902,6 +1268,12 @@ const docTemplate_swagger = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -910,149 +1282,9845 @@ const docTemplate_swagger = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "Author": {
+        },
+        "/books/{id}/recommendations": {
+            "get": {
+                "description": "Get books customers also bought with the book, ranked by the number of paid orders they share. Books sharing authors, genres and language with the book fill up the list, so books with few orders get recommendations too. Recommendations are refreshed periodically.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "List book recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of recommendations",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Recommendation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/reviews": {
+            "get": {
+                "description": "Get published reviews of the book, latest first. The average rating and the distribution of ratings are returned with the book.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List book reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Review"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/sales": {
+            "post": {
+                "description": "Set the sale price of the book for the given period. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Put book on sale",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SaleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/BookPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/stock": {
+            "get": {
+                "description": "Get on-hand, reserved and available quantities of the book derived from the stock ledger.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Show book stock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/stock/adjustments": {
+            "post": {
+                "description": "Correct on-hand quantity of the book, e.g. after a stocktake. Positive quantity adds copies, negative one takes them. The actor is stated by the client and is not verified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Record stock adjustment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/StockAdjustmentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/stock/movements": {
+            "get": {
+                "description": "Get stock ledger of the book, newest movements first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List stock movements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/stock/receipts": {
+            "post": {
+                "description": "Record copies of the book received from a supplier. The actor is stated by the client and is not verified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Record stock receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/StockReceiptInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{id}/stock/threshold": {
+            "put": {
+                "description": "Set the reorder threshold of the book. Staff are alerted when on-hand quantity drops to it. Null threshold disables alerts. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Set reorder threshold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReorderThresholdInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections": {
+            "get": {
+                "description": "Get computed collections (bestsellers, new arrivals and trending books) followed by collections curated by staff ordered by name, without their books.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "List collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Collection"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create the curated collection without books. Slugs of computed collections are reserved. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Create collection",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateCollectionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{slug}": {
+            "get": {
+                "description": "Get the collection with a page of its books. Bestsellers are books with the most copies sold in paid orders of the period, the last month by default. New arrivals are books added to the catalog last, of all time by default. Trending books are books selling faster in the last 7 days than in an average week of the 4 weeks before, the period doesn't apply to them. Computed collections may be limited to the genre. Books of curated collections are listed in the order set by staff. Collections are cached for a few minutes, so the latest orders may not be counted yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Show collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug, e.g. bestsellers, new-arrivals or trending",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "week",
+                            "month",
+                            "year",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Period of computed collections",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id of computed collections",
+                        "name": "genreId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the name and the description of the curated collection. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Update collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateCollectionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the curated collection. Books of the collection are kept in the catalog. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Delete collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{slug}/books": {
+            "put": {
+                "description": "Replace books of the curated collection with the books in the order they are listed. An empty list clears the collection. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Set collection books",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetCollectionBooksInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies": {
+            "get": {
+                "description": "Get supported currencies with their rounding rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "List currencies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Currency"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies/{code}": {
+            "put": {
+                "description": "Create the currency or update its name and rounding rules. Amounts are rounded to the number of decimals with the rounding mode, or to a multiple of the increment if it's set. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Save currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CurrencyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Currency"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/downloads/{token}": {
+            "get": {
+                "description": "Download the ebook file by the signed link. The file is streamed from the storage, the download is counted towards the download limit of the ebook once the file is opened.",
+                "produces": [
+                    "application/epub+zip",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "ebooks"
+                ],
+                "summary": "Download ebook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed token of the link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ebook file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "Get exchange rates relative to the base currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "List exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExchangeRate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Create or update exchange rates. Rate is the number of units of the currency for one unit of the base currency. Either all of the rates are saved or none of them. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Set exchange rates",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExchangeRateInput"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExchangeRate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/import": {
+            "post": {
+                "description": "Set exchange rates from CSV file sent as request body. Lines have currency, rate and optional source columns, e.g. \"EUR,0.9132,ECB\". The header line is optional. Either all of the rates are saved or none of them. Admin only.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Import exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source of the rates without one",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExchangeRate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/books": {
+            "get": {
+                "description": "Export catalog books with their contributors, genres, language, price and stock.\nBooks are filtered like in the books list, but all matching books are exported at once.\nCSV can be imported back, NDJSON objects are the same as in the books API,\nONIX is a basic ONIX for Books 3.0 feed.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export books",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "onix"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the book title",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Author id",
+                        "name": "authorId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "genreId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "languageId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Work id",
+                        "name": "workId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Get genres with the number of books of each one. Optionally filtered by name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "List genres",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the genre name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Genre"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Insert genre in database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Create genre",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateGenreInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "description": "Get genre by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Show genre information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update genre with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Update genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateGenreInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete genre with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Delete genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/import/books": {
+            "post": {
+                "description": "Import books from CSV or NDJSON file sent as request body. Books are upserted by ISBN,\nauthors, genres, languages and publishers are found by name or created.\nCSV must have a header line, authors and genres are separated with semicolons.\nInvalid rows are skipped and listed in the report. Dry run reports changes without saving them.\nPrices are in the currency column or the base currency, prices of existing books must be in their currency.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Import books",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "File format, detected from Content-Type if omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and report changes without saving them",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV or NDJSON file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/alerts": {
+            "get": {
+                "description": "Get low-stock alerts, newest first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "List low-stock alerts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "Alert status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/StockAlert"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/reconciliation": {
+            "get": {
+                "description": "Compare cached count of every book with on-hand quantity derived from the stock ledger. Lists books which counts differ.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory"
+                ],
+                "summary": "Reconcile stock",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StockReconciliation"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices": {
+            "get": {
+                "description": "Get invoices and credit notes of all orders, latest first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "List invoices",
+                "parameters": [
+                    {
+                        "enum": [
+                            "invoice",
+                            "credit_note"
+                        ],
+                        "type": "string",
+                        "description": "Document kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Invoice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
+                "description": "Get the invoice or the credit note by id with its document.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Show invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/invoices/{id}/document": {
+            "get": {
+                "description": "Download the invoice or the credit note as an HTML page or a PDF file.\nPDF is printed in a font covering Latin, Greek and Cyrillic scripts, other characters are replaced with question marks.",
+                "produces": [
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Download invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "html",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/isbn/{isbn}": {
+            "get": {
+                "description": "Get book by ISBN-10 or ISBN-13. Hyphens are allowed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Show book information by ISBN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Book ISBN",
+                        "name": "isbn",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Book"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages": {
+            "get": {
+                "description": "Get languages with the number of books of each one. Optionally filtered by name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "languages"
+                ],
+                "summary": "List languages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the language name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Language"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Insert language in database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "languages"
+                ],
+                "summary": "Create language",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateLanguageInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/languages/{id}": {
+            "get": {
+                "description": "Get language by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "languages"
+                ],
+                "summary": "Show language information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Language"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update language with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "languages"
+                ],
+                "summary": "Update language",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateLanguageInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete language with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "languages"
+                ],
+                "summary": "Delete genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Language id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "description": "Get order by id with its lines.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Show order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "description": "Cancel the pending or paid order. Books reserved for the pending order are released. Books sold for the paid order are returned to stock and its captured payments are refunded in full. Orders with returns which are not rejected cannot be cancelled. Every change is recorded in the order history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/OrderCancelInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/invoices": {
+            "get": {
+                "description": "Get the invoice and credit notes of the order in order of issue.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "List order invoices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Invoice"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Issue the invoice of the paid order with the next invoice number now instead of waiting for the issuer. The invoice which has been issued already is returned instead of a new one. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "Issue invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/payments": {
+            "get": {
+                "description": "Get payments of the order with their refunds, latest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List order payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create the payment of the pending order total at the payment provider. The payment which is not confirmed or is still processing is returned instead of a new one.\nThe client confirms the payment with the payment method of the customer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Create payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/returns": {
+            "get": {
+                "description": "Get returns of the order with their items, newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "List order returns",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Return"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Request the return of copies of paid order lines with the reason. Digital copies cannot be returned. Staff approve or reject the request. The request is recorded in the order history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Request return",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateReturnInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "patch": {
+                "description": "Move the paid order along its shipping flow: paid to shipped, shipped to delivered. Orders without shipping are delivered once paid.\nOrders are paid by their payments and cancelled with POST /orders/{id}/cancel, which refunds them.\nOrders shipped by courier or post need a tracking number. Every change is recorded in the order history. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Change order status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/OrderStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}": {
+            "get": {
+                "description": "Get payment by id with its refunds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Show payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/confirm": {
+            "post": {
+                "description": "Charge the payment method of the customer. The payment either succeeds or fails at once, or stays processing until the provider delivers the result by the webhook. The order is paid once the payment succeeds, the order which books are out of stock by then is cancelled and the payment is refunded.\nThe fake provider captures \"fake_success\" at once, \"fake_delayed\" after the configured delay and declines \"fake_decline\" and any other method.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Confirm payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/PaymentConfirmInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/{id}/refunds": {
+            "post": {
+                "description": "Return the amount of the captured payment to the customer through the payment provider. Payments can be refunded partially several times up to the paid amount. The refund stays pending until the provider makes it, sending the same amount again retries the pending refund instead of refunding twice. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Refund payment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payment id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/PaymentRefundInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes": {
+            "get": {
+                "description": "Get promo codes with the number of their redemptions, the latest first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo codes"
+                ],
+                "summary": "List promo codes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/PromoCode"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create promo code. Percentage and fixed codes require value, buy_x_get_y codes require buyQuantity and getQuantity. Codes are case-insensitive. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo codes"
+                ],
+                "summary": "Create promo code",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreatePromoCodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo-codes/{id}": {
+            "get": {
+                "description": "Get promo code by id with the number of its redemptions. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo codes"
+                ],
+                "summary": "Show promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo code id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PromoCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete promo code with specified id. The code is removed from baskets, orders keep their discounts. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo codes"
+                ],
+                "summary": "Delete promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo code id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publishers": {
+            "get": {
+                "description": "Get publishers with the number of books of each one. Optionally filtered by name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "List publishers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the publisher name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Publisher"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Insert publisher in database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Create publisher",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreatePublisherInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Publisher"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publishers/{id}": {
+            "get": {
+                "description": "Get publisher by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Show publisher information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Publisher id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Publisher"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update publisher with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Update publisher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Publisher id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdatePublisherInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete publisher with specified id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Delete publisher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Publisher id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recommendations/refresh": {
+            "post": {
+                "description": "Recompute recommendations of all books and personal feeds now instead of waiting for the refresher. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Refresh recommendations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RecommendationSummary"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns": {
+            "get": {
+                "description": "Get returns of all orders, newest first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "List returns",
+                "parameters": [
+                    {
+                        "enum": [
+                            "requested",
+                            "approved",
+                            "rejected",
+                            "received"
+                        ],
+                        "type": "string",
+                        "description": "Return status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Return"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}": {
+            "get": {
+                "description": "Get return by id with its items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Show return",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Return id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/returns/{id}/status": {
+            "patch": {
+                "description": "Approve or reject the requested return, or mark books of the approved return received. Received books are returned to stock and the refund goes through the payment provider. The refund amount defaults to the paid amount of returned copies without shipping, it is split across payments of the order when no payment covers it. Amounts refunded from payments are fixed by the first attempt. The return is marked received once books are restocked and refunded, so the request can be retried if either fails. Every change is recorded in the order history. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "returns"
+                ],
+                "summary": "Change return status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Return id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReturnStatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Return"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "description": "Get reviews of all books, latest first. Reported reviews are the moderation queue, they are listed by their oldest open report. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List reviews",
+                "parameters": [
+                    {
+                        "enum": [
+                            "published",
+                            "hidden"
+                        ],
+                        "type": "string",
+                        "description": "Review status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether reviews are flagged",
+                        "name": "flagged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reviews with open reports",
+                        "name": "reported",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Review"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "get": {
+                "description": "Get the review by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Show review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/moderation": {
+            "patch": {
+                "description": "Hide or publish the review, flag or unflag it and leave a note. Missing fields are left as is. Open reports of the review are resolved. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Moderate review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ModerateReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/reports": {
+            "get": {
+                "description": "Get reports of the review, open ones first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List review reports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ReviewReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Report the review to staff. The user can report the review again once staff have moderated it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Report review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReportReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ReviewReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-methods": {
+            "get": {
+                "description": "Get shipping methods with their rates, including inactive ones. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "List shipping methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ShippingMethod"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create shipping method. Add rates to make it available for destinations. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Create shipping method",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ShippingMethodInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ShippingMethod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-methods/{id}": {
+            "get": {
+                "description": "Get shipping method by id with its rates. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Show shipping method",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ShippingMethod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update shipping method with specified id. Inactive methods can't be chosen for baskets, placed orders keep their shipping cost. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Update shipping method",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ShippingMethodInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ShippingMethod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete shipping method with its rates. Placed orders keep the name of the method. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Delete shipping method",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-methods/{id}/rates": {
+            "post": {
+                "description": "Add the rate to the shipping method. Amounts are in the base currency. The most specific rate matching the parcel is used: rates of the region win over rates of the country, then lighter weight limits and higher minimum totals win. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Create shipping rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ShippingRateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ShippingRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipping-methods/{id}/rates/{rateId}": {
+            "put": {
+                "description": "Update the rate of the shipping method. Amounts are in the base currency. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Update shipping rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shipping rate id",
+                        "name": "rateId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ShippingRateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ShippingRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the rate of the shipping method. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipping"
+                ],
+                "summary": "Delete shipping rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipping method id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shipping rate id",
+                        "name": "rateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rates": {
+            "get": {
+                "description": "Get tax rates of the country or of all countries. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "List tax rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Country code, e.g. DE",
+                        "name": "country",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TaxRate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create tax rate of the country. Rates without a region apply to the whole country, rates without a category apply to print books and ebooks. The most specific rate is applied, region is more specific than category. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Create tax rate",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TaxRateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TaxRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-rates/{id}": {
+            "put": {
+                "description": "Update tax rate with specified id. Orders keep the tax they were placed with. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Update tax rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rate id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TaxRateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TaxRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete tax rate with specified id. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax"
+                ],
+                "summary": "Delete tax rate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rate id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get user by email and password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by email and password from query parameters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user email",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user raw password",
+                        "name": "password",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a new user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get user by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Show user information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the user with provided current password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the user by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update the user with provided current password. Currency is the preferred display currency of prices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateUserPartiallyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/addresses": {
+            "get": {
+                "description": "Get addresses of the user, default ones first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "List user addresses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Address"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add the address to the user. The address which is made default replaces the previous default address of the kind. The first address of the user becomes the default shipping and billing one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Create user address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AddressInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/addresses/{addressId}": {
+            "get": {
+                "description": "Get the address of the user by id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Show user address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address id",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the address of the user. The address which is made default replaces the previous default address of the kind. Placed orders keep their copies of the address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Update user address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address id",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AddressInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the address of the user. Placed orders keep their copies of the address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Delete user address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address id",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket": {
+            "get": {
+                "description": "Get the current basket of the user. A new basket is created if the user doesn't have one.\nPrices are in the first accepted currency with an exchange rate, the currency preferred by the user or the base one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Show basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/address": {
+            "put": {
+                "description": "Choose the address of the user the basket is shipped to. Tax of the basket is calculated by rates of the address country and region.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Choose shipping address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetBasketAddressInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/books/{bookId}": {
+            "put": {
+                "description": "Set the number of copies of the book in the basket. The copies are reserved for a limited time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Put book in basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "bookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetBasketItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the book from the basket and release its reservation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Remove book from basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "bookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/checkout": {
+            "post": {
+                "description": "Place the pending order from the basket. All books of the basket are reserved again for the checkout time. Order lines record the prices effective at checkout, promo codes giving a discount are redeemed with the order.\nThe order is shipped to the address chosen for the basket or the default shipping address, and billed to the default billing address or the shipping one. The order keeps copies of the addresses.\nThe order is placed in the currency of the basket with its current exchange rate, the tax of the shipping address and the cost of the chosen shipping method. A shipping method must be chosen if the basket has printed books.\nEbooks need no stock and are not shipped. The order of ebooks only is billed to the default billing address or the default shipping one, and taxed by it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Checkout basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/destination": {
+            "put": {
+                "description": "Set the country and region the basket is shipped to without choosing an address, e.g. to estimate tax. Tax of the basket is calculated by rates of the destination.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Set shipping destination",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Shipping destination",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ShippingDestination"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/promo-codes": {
+            "post": {
+                "description": "Apply the promo code to the basket. Codes which are not stackable can't be combined with other codes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Apply promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ApplyPromoCodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/promo-codes/{code}": {
+            "delete": {
+                "description": "Remove the promo code from the basket.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Remove promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Promo code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/shipping-method": {
+            "put": {
+                "description": "Choose the method printed books of the basket are shipped by. The method must ship the basket to the shipping destination of the basket or the default shipping address. The shipping cost is added to the basket total.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Choose shipping method",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetBasketShippingMethodInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/basket/shipping-methods": {
+            "get": {
+                "description": "Get costs of shipping the basket by active methods which ship it to the shipping destination of the basket or the default shipping address, cheapest first.\nCosts depend on the region, the weight of printed books and the basket total, shipping is free over the threshold of the rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Get shipping methods for basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ShippingQuote"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/library": {
+            "get": {
+                "description": "Get ebooks the user has paid for, latest first. Every ebook lists formats it can be downloaded in and downloads left. Ebooks of cancelled orders are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ebooks"
+                ],
+                "summary": "List user library",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Entitlement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/library/{entitlementId}/links": {
+            "post": {
+                "description": "Create the signed link to download the ebook of the user library in the format. The link expires in a few minutes, every use of it counts towards the download limit of the ebook.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ebooks"
+                ],
+                "summary": "Create download link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entitlement id",
+                        "name": "entitlementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DownloadLinkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/DownloadLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/orders": {
+            "get": {
+                "description": "Get orders of the user, newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List user orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/recommendations": {
+            "get": {
+                "description": "Get the personal feed of the user built from books recommended with books the user bought. Books of works the user bought are left out. The feed is empty until the user has paid orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "List user recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of recommendations",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Recommendation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restock-subscriptions": {
+            "get": {
+                "description": "Get books the user waits to be back in stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restock"
+                ],
+                "summary": "List restock subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/RestockSubscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Notify the user by email when the out-of-stock book is available again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restock"
+                ],
+                "summary": "Subscribe to restock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RestockSubscriptionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/RestockSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restock-subscriptions/{bookId}": {
+            "delete": {
+                "description": "Delete the pending restock subscription of the user to the book.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restock"
+                ],
+                "summary": "Unsubscribe from restock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "bookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reviews": {
+            "get": {
+                "description": "Get reviews of the user including hidden ones, latest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List user reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Review"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Rate the book from 1 to 5 stars with an optional title and text. Only books of paid orders of the user can be reviewed, once per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Review book",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reviews/{reviewId}": {
+            "put": {
+                "description": "Edit the rating and the text of the review of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Update review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the review of the user with its reports.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Delete review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Review id",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists": {
+            "get": {
+                "description": "Get wishlists of the user ordered by name, without their items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "List user wishlists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Wishlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create the named wishlist of the user. Names of wishlists of the user are unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Create user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/WishlistInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists/{wishlistId}": {
+            "get": {
+                "description": "Get the wishlist of the user with its items, latest added first. Items show whether the book got cheaper or came back in stock since it was added.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Show user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Rename the wishlist of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Rename user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/WishlistInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the wishlist of the user with its items. Its share link stops working.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Delete user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists/{wishlistId}/items": {
+            "post": {
+                "description": "Add the book to the wishlist of the user. Its price and whether it's in stock are remembered to show price drops and restocks. The book which is in the wishlist already is left as is.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Add book to wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AddWishlistItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists/{wishlistId}/items/{bookId}": {
+            "delete": {
+                "description": "Remove the book from the wishlist of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Remove book from wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "bookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists/{wishlistId}/items/{bookId}/basket": {
+            "post": {
+                "description": "Reserve copies of the book and put them in the basket of the user, in addition to copies which are there already, then remove the book from the wishlist. One copy is added if count is not set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Move book from wishlist to basket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Book id",
+                        "name": "bookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON input",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/MoveWishlistItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Basket"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wishlists/{wishlistId}/share": {
+            "put": {
+                "description": "Make the wishlist of the user available by a link with an unguessable share token. The wishlist which is shared already keeps its token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Share user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revoke the share token of the wishlist of the user, its link stops working.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Stop sharing user wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wishlist id",
+                        "name": "wishlistId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/payments": {
+            "post": {
+                "description": "Receive the event of the payment provider. The request must be signed by the provider. Events are applied once, redelivered ones are acknowledged without changes. The order is paid once its payment succeeds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Payment provider webhook",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wishlists/{token}": {
+            "get": {
+                "description": "Get the wishlist shared with the token with its items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlists"
+                ],
+                "summary": "Show shared wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currencies to show prices in, e.g. EUR, GBP;q=0.5",
+                        "name": "Accept-Currency",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Wishlist"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "AddWishlistItemInput": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Moscow"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-02-20T12:00:00Z"
+                },
+                "defaultBilling": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "defaultShipping": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Malaya Semenovskaya 12",
+                        "apt. 5"
+                    ]
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+7 (800) 555-35-35"
+                },
+                "postalCode": {
+                    "type": "string",
+                    "example": "107023"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "Лев Толстой"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Moscow"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-02-20T12:00:00Z"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "AddressInput": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Moscow"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "defaultBilling": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "defaultShipping": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Malaya Semenovskaya 12",
+                        "apt. 5"
+                    ]
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+7 (800) 555-35-35"
+                },
+                "postalCode": {
+                    "type": "string",
+                    "example": "107023"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "Лев Толстой"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Moscow"
+                }
+            }
+        },
+        "ApplyPromoCodeInput": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                }
+            }
+        },
+        "Author": {
+            "type": "object",
+            "properties": {
+                "biography": {
+                    "type": "string",
+                    "example": "Russian writer, author of War and Peace."
+                },
+                "birthYear": {
+                    "type": "integer",
+                    "example": 1828
+                },
+                "country": {
+                    "type": "string",
+                    "example": "Russia"
+                },
+                "deathYear": {
+                    "type": "integer",
+                    "example": 1910
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lev"
+                },
+                "penNames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "L. N. Tolstoy"
+                    ]
+                },
+                "photo": {
+                    "$ref": "#/definitions/Image"
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Tolstoy"
+                }
+            }
+        },
+        "Basket": {
+            "type": "object",
+            "properties": {
+                "addressId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "destination": {
+                    "$ref": "#/definitions/ShippingDestination"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "5.2"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PromoDiscount"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BasketItem"
+                    }
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "shipping": {
+                    "$ref": "#/definitions/ShippingQuote"
+                },
+                "shippingMethodId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "25.98"
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.45"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "20.78"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "BasketItem": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "2.3"
+                },
+                "listPrice": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "reservedUntil": {
+                    "type": "string",
+                    "example": "2022-03-01T12:15:00Z"
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.45"
+                },
+                "taxRate": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                }
+            }
+        },
+        "Book": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BookAuthor"
+                    }
+                },
+                "available": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "cover": {
+                    "$ref": "#/definitions/Image"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Novel about the French invasion of Russia."
+                },
+                "edition": {
+                    "type": "string",
+                    "example": "Penguin Classics"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/BookGenre"
+                    }
+                },
+                "heightMm": {
+                    "type": "integer",
+                    "example": 198
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "isbn10": {
+                    "type": "string",
+                    "example": "0140447938"
+                },
+                "isbn13": {
+                    "type": "string",
+                    "example": "9780140447934"
+                },
+                "language": {
+                    "$ref": "#/definitions/BookLanguage"
+                },
+                "pageCount": {
+                    "type": "integer",
+                    "example": 1225
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "priceNotConverted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "publicationDate": {
+                    "type": "string",
+                    "example": "2007-10-30"
+                },
+                "publisher": {
+                    "$ref": "#/definitions/BookPublisher"
+                },
+                "rating": {
+                    "$ref": "#/definitions/BookRating"
+                },
+                "saleEndsAt": {
+                    "type": "string",
+                    "example": "2022-03-31T00:00:00Z"
+                },
+                "salePrice": {
+                    "type": "string",
+                    "example": "9.99"
+                },
+                "thicknessMm": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "weightGrams": {
+                    "type": "integer",
+                    "example": 1040
+                },
+                "widthMm": {
+                    "type": "integer",
+                    "example": 129
+                },
+                "workId": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1869
+                }
+            }
+        },
+        "BookAuthor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lev"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "author"
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Tolstoy"
+                }
+            }
+        },
+        "BookGenre": {
+            "type": "object",
+            "properties": {
+                "genre": {
+                    "type": "string",
+                    "example": "novel"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "BookLanguage": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                }
+            }
+        },
+        "BookPrice": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-02-20T12:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "effectiveFrom": {
+                    "type": "string",
+                    "example": "2022-03-01T00:00:00Z"
+                },
+                "effectiveTo": {
+                    "type": "string",
+                    "example": "2022-04-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "list"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "publisher price change"
+                }
+            }
+        },
+        "BookPublisher": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Penguin Books"
+                }
+            }
+        },
+        "BookRating": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "string",
+                    "example": "4.25"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "distribution": {
+                    "$ref": "#/definitions/RatingDistribution"
+                }
+            }
+        },
+        "Collection": {
+            "type": "object",
+            "properties": {
+                "bookCount": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "books": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Book"
+                    }
+                },
+                "computed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Light books for the beach."
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Summer reads"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "summer-reads"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                }
+            }
+        },
+        "ContributorInput": {
+            "type": "object",
+            "properties": {
+                "authorId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "role": {
+                    "type": "string",
+                    "example": "author"
+                }
+            }
+        },
+        "CreateAuthorInput": {
+            "type": "object",
+            "properties": {
+                "biography": {
+                    "type": "string",
+                    "example": "Russian writer, author of War and Peace."
+                },
+                "birthYear": {
+                    "type": "integer",
+                    "example": 1828
+                },
+                "country": {
+                    "type": "string",
+                    "example": "Russia"
+                },
+                "deathYear": {
+                    "type": "integer",
+                    "example": 1910
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lev"
+                },
+                "penNames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "L. N. Tolstoy"
+                    ]
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Tolstoy"
+                }
+            }
+        },
+        "CreateBookInput": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ContributorInput"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Novel about the French invasion of Russia."
+                },
+                "edition": {
+                    "type": "string",
+                    "example": "Penguin Classics"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "genreIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "heightMm": {
+                    "type": "integer",
+                    "example": 198
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "978-0-14-044793-4"
+                },
+                "languageId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageCount": {
+                    "type": "integer",
+                    "example": 1225
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "publicationDate": {
+                    "type": "string",
+                    "example": "2007-10-30"
+                },
+                "publisherId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "thicknessMm": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "weightGrams": {
+                    "type": "integer",
+                    "example": 1040
+                },
+                "widthMm": {
+                    "type": "integer",
+                    "example": 129
+                },
+                "workId": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1869
+                }
+            }
+        },
+        "CreateCollectionInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Light books for the beach."
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Summer reads"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "summer-reads"
+                }
+            }
+        },
+        "CreateGenreInput": {
+            "type": "object",
+            "properties": {
+                "genre": {
+                    "type": "string",
+                    "example": "fantasy"
+                }
+            }
+        },
+        "CreateLanguageInput": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                }
+            }
+        },
+        "CreatePromoCodeInput": {
+            "type": "object",
+            "properties": {
+                "authorId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "buyQuantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "20% off fantasy books"
+                },
+                "endsAt": {
+                    "type": "string",
+                    "example": "2022-04-01T00:00:00Z"
+                },
+                "genreId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "getQuantity": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "percentage"
+                },
+                "minTotal": {
+                    "type": "string",
+                    "example": "30"
+                },
+                "perUserLimit": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "stackable": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2022-03-01T00:00:00Z"
+                },
+                "usageLimit": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "value": {
+                    "type": "string",
+                    "example": "20"
+                }
+            }
+        },
+        "CreatePublisherInput": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "United Kingdom"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Penguin Books"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://www.penguin.co.uk"
+                }
+            }
+        },
+        "CreateReturnInput": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "The cover is torn"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReturnItemInput"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged"
+                }
+            }
+        },
+        "CreateReviewInput": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Long, but worth every page."
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "title": {
+                    "type": "string",
+                    "example": "A masterpiece"
+                }
+            }
+        },
+        "CreateUserInput": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Russia, Moscow, Malaya Semenovskaya, 12"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "admin@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "qwERty123"
+                },
+                "phoneNumber": {
+                    "type": "string",
+                    "example": "88005553535"
+                },
+                "repeatPassword": {
+                    "type": "string",
+                    "example": "qwERty123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "Currency": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "code": {
+                    "type": "string",
+                    "example": "CHF"
+                },
+                "decimals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "increment": {
+                    "type": "string",
+                    "example": "0.05"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Swiss Franc"
+                },
+                "rounding": {
+                    "type": "string",
+                    "example": "half_up"
+                }
+            }
+        },
+        "CurrencyInput": {
+            "type": "object",
+            "properties": {
+                "decimals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "increment": {
+                    "type": "string",
+                    "example": "0.05"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Swiss Franc"
+                },
+                "rounding": {
+                    "type": "string",
+                    "example": "half_up"
+                }
+            }
+        },
+        "DownloadLink": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:15:00Z"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "epub"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8080/api/downloads/MS5lcHViLjE2NDYxMzYwMDA.3q2-7w"
+                }
+            }
+        },
+        "DownloadLinkInput": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "example": "epub"
+                }
+            }
+        },
+        "EbookFile": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "epub"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 524288
+                }
+            }
+        },
+        "Entitlement": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "downloadLimit": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "downloads": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "formats": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "epub",
+                        "pdf"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "orderId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "developerMessage": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.9132"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "ECB"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                }
+            }
+        },
+        "ExchangeRateInput": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.9132"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "ECB"
+                }
+            }
+        },
+        "Genre": {
+            "type": "object",
+            "properties": {
+                "booksCount": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "genre": {
+                    "type": "string",
+                    "example": "fantasy"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "Image": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "key": {
+                    "type": "string",
+                    "example": "books/1/cover/9f86d081884c7d65"
+                },
+                "mediumUrl": {
+                    "type": "string",
+                    "example": "http://localhost:8080/media/books/1/cover/9f86d081884c7d65/medium.jpg"
+                },
+                "originalUrl": {
+                    "type": "string",
+                    "example": "http://localhost:8080/media/books/1/cover/9f86d081884c7d65/original.jpg"
+                },
+                "thumbnailUrl": {
+                    "type": "string",
+                    "example": "http://localhost:8080/media/books/1/cover/9f86d081884c7d65/thumbnail.jpg"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1200
+                }
+            }
+        },
+        "ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 950
+                },
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ImportRowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 48
+                }
+            }
+        },
+        "ImportRowError": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "978-0-14-044793-4"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 17
+                }
+            }
+        },
+        "Invoice": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "document": {
+                    "$ref": "#/definitions/InvoiceDocument"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "invoiceId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "issuedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:05:00Z"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "invoice"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "INV-000001"
+                },
+                "orderId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "refundId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "string",
+                    "example": "18.38"
+                }
+            }
+        },
+        "InvoiceDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "4.6"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                }
+            }
+        },
+        "InvoiceDocument": {
+            "type": "object",
+            "properties": {
+                "buyer": {
+                    "$ref": "#/definitions/InvoiceParty"
+                },
+                "decimals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "4.6"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/InvoiceDiscount"
+                    }
+                },
+                "invoiceNumber": {
+                    "type": "string",
+                    "example": "INV-000001"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/InvoiceLine"
+                    }
+                },
+                "orderDate": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "orderId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "return 1: damaged"
+                },
+                "seller": {
+                    "$ref": "#/definitions/InvoiceParty"
+                },
+                "shipping": {
+                    "$ref": "#/definitions/InvoiceShipping"
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "22.98"
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/InvoiceTax"
+                    }
+                },
+                "total": {
+                    "type": "string",
+                    "example": "18.38"
+                }
+            }
+        },
+        "InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "22.98"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "2.3"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "taxRate": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "unitPrice": {
+                    "type": "string",
+                    "example": "11.49"
+                }
+            }
+        },
+        "InvoiceParty": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Friedrichstraße 68",
+                        "10117 Berlin",
+                        "DE"
+                    ]
+                },
+                "email": {
+                    "type": "string",
+                    "example": "billing@readyread.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ReadyRead GmbH"
+                },
+                "taxId": {
+                    "type": "string",
+                    "example": "DE123456789"
+                }
+            }
+        },
+        "InvoiceShipping": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string",
+                    "example": "4.99"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "Express courier"
+                }
+            }
+        },
+        "InvoiceTax": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "17.18"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.2"
+                }
+            }
+        },
+        "Language": {
+            "type": "object",
+            "properties": {
+                "booksCount": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "language": {
+                    "type": "string",
+                    "example": "ru"
+                }
+            }
+        },
+        "ListPriceInput": {
+            "type": "object",
+            "properties": {
+                "effectiveFrom": {
+                    "type": "string",
+                    "example": "2022-04-01T00:00:00Z"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "14.99"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "publisher price change"
+                }
+            }
+        },
+        "ModerateReviewInput": {
+            "type": "object",
+            "properties": {
+                "flagged": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Contains spoilers"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "hidden"
+                }
+            }
+        },
+        "MoveWishlistItemInput": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "Order": {
+            "type": "object",
+            "properties": {
+                "basketId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "billingAddress": {
+                    "$ref": "#/definitions/OrderAddress"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "destination": {
+                    "$ref": "#/definitions/ShippingDestination"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "4.6"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OrderDiscount"
+                    }
+                },
+                "exchangeRate": {
+                    "type": "string",
+                    "example": "0.9132"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OrderStatusChange"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OrderItem"
+                    }
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "shipping": {
+                    "$ref": "#/definitions/OrderShipping"
+                },
+                "shippingAddress": {
+                    "$ref": "#/definitions/OrderAddress"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "totalPrice": {
+                    "type": "string",
+                    "example": "18.38"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "OrderAddress": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Moscow"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "RU"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Malaya Semenovskaya 12",
+                        "apt. 5"
+                    ]
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+7 (800) 555-35-35"
+                },
+                "postalCode": {
+                    "type": "string",
+                    "example": "107023"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "Лев Толстой"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Moscow"
+                }
+            }
+        },
+        "OrderCancelInput": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "ordered by mistake"
+                }
+            }
+        },
+        "OrderDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "4.6"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                },
+                "promoCodeId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "OrderItem": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "digital": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "2.3"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5001
+                },
+                "listPrice": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "tax": {
+                    "type": "string",
+                    "example": "1.2"
+                },
+                "taxRate": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "unitPrice": {
+                    "type": "string",
+                    "example": "11.49"
+                }
+            }
+        },
+        "OrderShipping": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string",
+                    "example": "4.99"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "courier"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "Express courier"
+                },
+                "methodId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trackingNumber": {
+                    "type": "string",
+                    "example": "JD014600003828"
+                }
+            }
+        },
+        "OrderStatusChange": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-02T09:30:00Z"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "status_changed"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Shipped from the Berlin warehouse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "shipped"
+                },
+                "trackingNumber": {
+                    "type": "string",
+                    "example": "JD014600003828"
+                }
+            }
+        },
+        "OrderStatusInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Shipped from the Berlin warehouse"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "shipped"
+                },
+                "trackingNumber": {
+                    "type": "string",
+                    "example": "JD014600003828"
+                }
+            }
+        },
+        "Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "18.38"
+                },
+                "clientSecret": {
+                    "type": "string",
+                    "example": "fake_pi_000001_secret_5d41402abc4b2a76"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "failureReason": {
+                    "type": "string",
+                    "example": "card declined"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "intentId": {
+                    "type": "string",
+                    "example": "fake_pi_000001"
+                },
+                "orderId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "fake"
+                },
+                "refunded": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PaymentRefund"
+                    }
+                },
+                "settledAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:01:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:01:00Z"
+                }
+            }
+        },
+        "PaymentConfirmInput": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string",
+                    "example": "fake_success"
+                }
+            }
+        },
+        "PaymentRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-05T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "paymentId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged book"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "return 5"
+                },
+                "refundId": {
+                    "type": "string",
+                    "example": "fake_re_000004"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                }
+            }
+        },
+        "PaymentRefundInput": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged book"
+                }
+            }
+        },
+        "PromoCode": {
+            "type": "object",
+            "properties": {
+                "authorId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "buyQuantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-02-20T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "20% off fantasy books"
+                },
+                "endsAt": {
+                    "type": "string",
+                    "example": "2022-04-01T00:00:00Z"
+                },
+                "genreId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "getQuantity": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "percentage"
+                },
+                "minTotal": {
+                    "type": "string",
+                    "example": "30"
+                },
+                "perUserLimit": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "redemptions": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "stackable": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2022-03-01T00:00:00Z"
+                },
+                "usageLimit": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "value": {
+                    "type": "string",
+                    "example": "20"
+                }
+            }
+        },
+        "PromoDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "5.2"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "SPRING-2022"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "20% off fantasy books"
+                },
+                "promoCodeId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "basket total is less than 30.00"
+                }
+            }
+        },
+        "Publisher": {
+            "type": "object",
+            "properties": {
+                "booksCount": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "country": {
+                    "type": "string",
+                    "example": "United Kingdom"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Penguin Books"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://www.penguin.co.uk"
+                }
+            }
+        },
+        "RatingDistribution": {
+            "type": "object",
+            "properties": {
+                "1": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "2": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "3": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "4": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "5": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "Recommendation": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/Book"
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 124
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "bought_together"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 12
+                }
+            }
+        },
+        "RecommendationSummary": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer",
+                    "example": 820
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 140
+                }
+            }
+        },
+        "ReorderThresholdInput": {
+            "type": "object",
+            "properties": {
+                "threshold": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "ReportReviewInput": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Spoilers in the first line"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 8
+                }
+            }
+        },
+        "RestockSubscription": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "RestockSubscriptionInput": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "Return": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "The cover is torn"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-05T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReturnItem"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Replacement is out of stock"
+                },
+                "orderId": {
+                    "type": "integer",
+                    "example": 1001
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged"
+                },
+                "refundAmount": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReturnRefund"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "requested"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-06T09:00:00Z"
+                }
+            }
+        },
+        "ReturnItem": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "orderItemId": {
+                    "type": "integer",
+                    "example": 5001
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                }
+            }
+        },
+        "ReturnItemInput": {
+            "type": "object",
+            "properties": {
+                "orderItemId": {
+                    "type": "integer",
+                    "example": 5001
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ReturnRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "paymentId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "ReturnStatusInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Replacement is out of stock"
+                },
+                "refundAmount": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "approved"
+                }
+            }
+        },
+        "Review": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Long, but worth every page."
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-05T10:00:00Z"
+                },
+                "flagged": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "moderationNote": {
+                    "type": "string",
+                    "example": "Contains spoilers"
+                },
+                "openReports": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "A masterpiece"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-06T09:00:00Z"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "username": {
+                    "type": "string",
+                    "example": "reader"
+                }
+            }
+        },
+        "ReviewReport": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-07T10:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Spoilers in the first line"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2022-03-07T12:00:00Z"
+                },
+                "reviewId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 8
+                }
+            }
+        },
+        "SaleInput": {
+            "type": "object",
+            "properties": {
+                "endsAt": {
+                    "type": "string",
+                    "example": "2022-03-31T00:00:00Z"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "9.99"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "spring sale"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2022-03-01T00:00:00Z"
+                }
+            }
+        },
+        "SetBasketAddressInput": {
+            "type": "object",
+            "properties": {
+                "addressId": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "SetBasketItemInput": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "SetBasketShippingMethodInput": {
+            "type": "object",
+            "properties": {
+                "methodId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "SetCollectionBooksInput": {
+            "type": "object",
+            "properties": {
+                "bookIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        123,
+                        124,
+                        125
+                    ]
+                }
+            }
+        },
+        "ShippingDestination": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Bavaria"
+                }
+            }
+        },
+        "ShippingMethod": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "code": {
+                    "type": "string",
+                    "example": "courier-express"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-02-20T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Delivery to the door in 1-2 days"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "courier"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Express courier"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ShippingRate"
+                    }
+                }
+            }
+        },
+        "ShippingMethodInput": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "code": {
+                    "type": "string",
+                    "example": "courier-express"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Delivery to the door in 1-2 days"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "courier"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Express courier"
+                }
+            }
+        },
+        "ShippingQuote": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "courier-express"
+                },
+                "cost": {
+                    "type": "string",
+                    "example": "4.99"
+                },
+                "free": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "courier"
+                },
+                "methodId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Express courier"
+                }
+            }
+        },
+        "ShippingRate": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "freeOver": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "maxWeight": {
+                    "type": "integer",
+                    "example": 2000
+                },
+                "methodId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "minTotal": {
+                    "type": "string",
+                    "example": "20"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "4.99"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Bavaria"
+                }
+            }
+        },
+        "ShippingRateInput": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "freeOver": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "maxWeight": {
+                    "type": "integer",
+                    "example": 2000
+                },
+                "minTotal": {
+                    "type": "string",
+                    "example": "20"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "4.99"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Bavaria"
+                }
+            }
+        },
+        "Stock": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "onHand": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reserved": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "StockAdjustmentInput": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is not verified, the API has no authentication.",
+                    "type": "string",
+                    "example": "warehouse@readyread.com"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "damaged copies"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "STOCKTAKE-2022-03"
+                }
+            }
+        },
+        "StockAlert": {
+            "type": "object",
+            "properties": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "emailedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:01:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notifiedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:01:00Z"
+                },
+                "onHand": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "postedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:01:00Z"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2022-03-05T09:00:00Z"
+                },
+                "threshold": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                }
+            }
+        },
+        "StockDiscrepancy": {
             "type": "object",
             "properties": {
-                "id": {
+                "bookId": {
                     "type": "integer",
                     "example": 123
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Ilya"
+                "cachedCount": {
+                    "type": "integer",
+                    "example": 12
                 },
-                "surname": {
+                "difference": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "ledgerCount": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "title": {
                     "type": "string",
-                    "example": "Sokolov"
+                    "example": "War and Peace"
                 }
             }
         },
-        "CreateAuthorInput": {
+        "StockMovement": {
             "type": "object",
             "properties": {
-                "name": {
+                "actor": {
+                    "description": "Actor is who made the movement. Actors of receipts and adjustments\nare stated by the client and are not verified.",
                     "type": "string",
-                    "example": "Ilya"
+                    "example": "warehouse@readyread.com"
                 },
-                "surname": {
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "delivery from the supplier"
+                },
+                "reference": {
                     "type": "string",
-                    "example": "Sokolov"
+                    "example": "INV-2022-0042"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "receipt"
                 }
             }
         },
-        "CreateGenreInput": {
+        "StockReceiptInput": {
             "type": "object",
             "properties": {
-                "genre": {
+                "actor": {
+                    "description": "Actor is not verified, the API has no authentication.",
                     "type": "string",
-                    "example": "fantasy"
+                    "example": "warehouse@readyread.com"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "delivery from the supplier"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "INV-2022-0042"
                 }
             }
         },
-        "CreateLanguageInput": {
+        "StockReconciliation": {
             "type": "object",
             "properties": {
-                "language": {
-                    "type": "string",
-                    "example": "ru"
+                "booksChecked": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StockDiscrepancy"
+                    }
                 }
             }
         },
-        "CreateUserInput": {
+        "TaxRate": {
             "type": "object",
             "properties": {
-                "address": {
+                "category": {
                     "type": "string",
-                    "example": "Russia, Moscow, Malaya Semenovskaya, 12"
+                    "example": "ebook"
                 },
-                "email": {
+                "country": {
                     "type": "string",
-                    "example": "admin@example.com"
+                    "example": "DE"
                 },
-                "password": {
+                "createdAt": {
                     "type": "string",
-                    "example": "qwERty123"
+                    "example": "2022-02-20T12:00:00Z"
                 },
-                "phoneNumber": {
-                    "type": "string",
-                    "example": "88005553535"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "repeatPassword": {
+                "rate": {
                     "type": "string",
-                    "example": "qwERty123"
+                    "example": "19"
                 },
-                "username": {
+                "region": {
                     "type": "string",
-                    "example": "admin"
+                    "example": "Bavaria"
                 }
             }
         },
-        "ErrorResponse": {
+        "TaxRateInput": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "category": {
+                    "type": "string",
+                    "example": "ebook"
                 },
-                "developerMessage": {
-                    "type": "string"
+                "country": {
+                    "type": "string",
+                    "example": "DE"
                 },
-                "message": {
-                    "type": "string"
+                "rate": {
+                    "type": "string",
+                    "example": "19"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "Bavaria"
                 }
             }
         },
-        "Genre": {
+        "UpdateAuthorInput": {
             "type": "object",
             "properties": {
-                "genre": {
+                "biography": {
                     "type": "string",
-                    "example": "fantasy"
+                    "example": "Russian writer, author of War and Peace."
                 },
-                "id": {
+                "birthYear": {
                     "type": "integer",
-                    "example": 123
+                    "example": 1828
+                },
+                "country": {
+                    "type": "string",
+                    "example": "Russia"
+                },
+                "deathYear": {
+                    "type": "integer",
+                    "example": 1910
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lev"
+                },
+                "penNames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "L. N. Tolstoy"
+                    ]
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Tolstoy"
                 }
             }
         },
-        "Language": {
+        "UpdateAuthorPartiallyInput": {
             "type": "object",
             "properties": {
-                "id": {
+                "biography": {
+                    "type": "string",
+                    "example": "Russian writer, author of War and Peace."
+                },
+                "birthYear": {
                     "type": "integer",
-                    "example": 123
+                    "example": 1828
                 },
-                "language": {
+                "country": {
                     "type": "string",
-                    "example": "ru"
+                    "example": "Russia"
+                },
+                "deathYear": {
+                    "type": "integer",
+                    "example": 1910
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lev"
+                },
+                "penNames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "L. N. Tolstoy"
+                    ]
+                },
+                "surname": {
+                    "type": "string",
+                    "example": "Tolstoy"
                 }
             }
         },
-        "UpdateAuthorInput": {
+        "UpdateBookInput": {
             "type": "object",
             "properties": {
-                "name": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ContributorInput"
+                    }
+                },
+                "description": {
                     "type": "string",
-                    "example": "Ilya"
+                    "example": "Novel about the French invasion of Russia."
                 },
-                "surname": {
+                "edition": {
+                    "type": "string",
+                    "example": "Penguin Classics"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "paperback"
+                },
+                "genreIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "heightMm": {
+                    "type": "integer",
+                    "example": 198
+                },
+                "isbn": {
+                    "type": "string",
+                    "example": "978-0-14-044793-4"
+                },
+                "languageId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageCount": {
+                    "type": "integer",
+                    "example": 1225
+                },
+                "publicationDate": {
+                    "type": "string",
+                    "example": "2007-10-30"
+                },
+                "publisherId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "thicknessMm": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "title": {
                     "type": "string",
-                    "example": "Sokolov"
+                    "example": "War and Peace"
+                },
+                "weightGrams": {
+                    "type": "integer",
+                    "example": 1040
+                },
+                "widthMm": {
+                    "type": "integer",
+                    "example": 129
+                },
+                "workId": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 1869
                 }
             }
         },
-        "UpdateAuthorPartiallyInput": {
+        "UpdateCollectionInput": {
             "type": "object",
             "properties": {
-                "name": {
+                "description": {
                     "type": "string",
-                    "example": "Ilya"
+                    "example": "Light books for the beach."
                 },
-                "surname": {
+                "name": {
                     "type": "string",
-                    "example": "Sokolov"
+                    "example": "Summer reads"
                 }
             }
         },
@@ -1074,6 +11142,40 @@ const docTemplate_swagger = `{
                 }
             }
         },
+        "UpdatePublisherInput": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "United Kingdom"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Penguin Books"
+                },
+                "website": {
+                    "type": "string",
+                    "example": "https://www.penguin.co.uk"
+                }
+            }
+        },
+        "UpdateReviewInput": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Long, but worth every page."
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "title": {
+                    "type": "string",
+                    "example": "A masterpiece"
+                }
+            }
+        },
         "UpdateUserInput": {
             "type": "object",
             "properties": {
@@ -1106,6 +11208,10 @@ const docTemplate_swagger = `{
                     "type": "string",
                     "example": "Russia, Moscow, Malaya Semenovskaya, 12"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
                 "email": {
                     "type": "string",
                     "example": "admin@example.com"
@@ -1134,6 +11240,10 @@ const docTemplate_swagger = `{
                 "address": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
                 "email": {
                     "type": "string",
                     "example": "admin@example.com"
@@ -1158,6 +11268,107 @@ const docTemplate_swagger = `{
                     "example": true
                 }
             }
+        },
+        "Wishlist": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "itemCount": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WishlistItem"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Birthday"
+                },
+                "shareToken": {
+                    "type": "string",
+                    "example": "q8J2xk1bT9n4LrYvZc0sWmEa5uHdPf3G"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "WishlistInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Birthday"
+                }
+            }
+        },
+        "WishlistItem": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string",
+                    "example": "2022-03-01T12:00:00Z"
+                },
+                "addedPrice": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "available": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "backInStock": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "bookId": {
+                    "type": "integer",
+                    "example": 123
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "inStock": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "listPrice": {
+                    "type": "string",
+                    "example": "12.99"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "11.49"
+                },
+                "priceDrop": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "priceNotConverted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "title": {
+                    "type": "string",
+                    "example": "War and Peace"
+                }
+            }
         }
     }
 }`
@@ -1169,7 +11380,7 @@ var SwaggerInfo_swagger = &swag.Spec{
 	BasePath:         "/api",
 	Schemes:          []string{},
 	Title:            "ReadyRead API",
-	Description:      "API documentation for ReadyRead book shop.",
+	Description:      "API documentation for ReadyRead book shop.\nMoney amounts are written as decimal strings, e.g. \"12.99\", so they are never rounded by float parsing. Numbers are accepted in requests too.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate_swagger,
 }
@@ -0,0 +1,257 @@
+package address
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	addressesURL = "/api/users/:id/addresses"
+	addressURL   = "/api/users/:id/addresses/:addressId"
+)
+
+// Handler handles requests specified to address service.
+type Handler struct {
+	logger         logger.Logger
+	addressService Service
+}
+
+// NewHandler returns a new address Handler instance.
+func NewHandler(logger logger.Logger, addressService Service) handler.Handling {
+	return &Handler{
+		logger:         logger,
+		addressService: addressService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, addressesURL, h.ListAddresses)
+	router.HandlerFunc(http.MethodPost, addressesURL, h.CreateAddress)
+	router.HandlerFunc(http.MethodGet, addressURL, h.GetAddress)
+	router.HandlerFunc(http.MethodPut, addressURL, h.UpdateAddress)
+	router.HandlerFunc(http.MethodDelete, addressURL, h.DeleteAddress)
+}
+
+// ListAddresses godoc
+// @Summary List user addresses
+// @Description Get addresses of the user, default ones first.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Success 200 {array} Address
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/addresses [get]
+func (h *Handler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST ADDRESSES")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	addresses, err := h.addressService.GetByUser(r.Context(), userId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, addresses)
+}
+
+// GetAddress godoc
+// @Summary Show user address
+// @Description Get the address of the user by id.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param addressId path int64 true "Address id"
+// @Success 200 {object} Address
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/addresses/{addressId} [get]
+func (h *Handler) GetAddress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET ADDRESS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	addressId, err := handler.ReadParam64(r, "addressId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	address, err := h.addressService.GetById(r.Context(), userId, addressId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, address)
+}
+
+// CreateAddress godoc
+// @Summary Create user address
+// @Description Add the address to the user. The address which is made default replaces the previous default address of the kind. The first address of the user becomes the default shipping and billing one.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param input body AddressDTO true "JSON input"
+// @Success 201 {object} Address
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/addresses [post]
+func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE ADDRESS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input AddressDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Country = strings.ToUpper(input.Country)
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+
+	address, err := h.addressService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, address)
+}
+
+// UpdateAddress godoc
+// @Summary Update user address
+// @Description Update the address of the user. The address which is made default replaces the previous default address of the kind. Placed orders keep their copies of the address.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param addressId path int64 true "Address id"
+// @Param input body AddressDTO true "JSON input"
+// @Success 200 {object} Address
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/addresses/{addressId} [put]
+func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE ADDRESS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	addressId, err := handler.ReadParam64(r, "addressId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input AddressDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Country = strings.ToUpper(input.Country)
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = addressId
+	input.UserId = userId
+
+	address, err := h.addressService.Update(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, address)
+}
+
+// DeleteAddress godoc
+// @Summary Delete user address
+// @Description Delete the address of the user. Placed orders keep their copies of the address.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param addressId path int64 true "Address id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/addresses/{addressId} [delete]
+func (h *Handler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE ADDRESS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	addressId, err := handler.ReadParam64(r, "addressId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.addressService.Delete(r.Context(), userId, addressId); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
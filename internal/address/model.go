@@ -0,0 +1,94 @@
+package address
+
+import (
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+)
+
+const (
+	// KindShipping is a kind of the default address orders are shipped to.
+	KindShipping = "shipping"
+	// KindBilling is a kind of the default address orders are billed to.
+	KindBilling = "billing"
+)
+
+var (
+	// postalCodePattern matches postal codes of letters and digits
+	// which may be separated by spaces or hyphens, e.g. "SW1A 1AA".
+	postalCodePattern = regexp.MustCompile(`^[\p{L}\p{N}]+([ -][\p{L}\p{N}]+)*$`)
+
+	// phonePattern matches phone numbers of digits which may be grouped
+	// by spaces, hyphens or parentheses, e.g. "+49 (89) 123-456".
+	phonePattern = regexp.MustCompile(`^\+?[0-9]+([ -]?(\([0-9]+\)|[0-9]+))*$`)
+)
+
+// Address represents the postal address of the user. Lines hold the street,
+// house and apartment. The first address of the user becomes the default
+// shipping and billing one.
+type Address struct {
+	Id              int64     `json:"id" example:"3"`
+	UserId          int64     `json:"userId" example:"7"`
+	Recipient       string    `json:"recipient" example:"Лев Толстой"`
+	Lines           []string  `json:"lines" example:"Malaya Semenovskaya 12,apt. 5"`
+	City            string    `json:"city" example:"Moscow"`
+	Region          *string   `json:"region,omitempty" example:"Moscow"`
+	PostalCode      *string   `json:"postalCode,omitempty" example:"107023"`
+	Country         string    `json:"country" example:"RU"`
+	Phone           *string   `json:"phone,omitempty" example:"+7 (800) 555-35-35"`
+	DefaultShipping bool      `json:"defaultShipping" example:"true"`
+	DefaultBilling  bool      `json:"defaultBilling" example:"true"`
+	CreatedAt       time.Time `json:"createdAt" example:"2022-02-20T12:00:00Z"`
+	UpdatedAt       time.Time `json:"updatedAt" example:"2022-02-20T12:00:00Z"`
+} // @name Address
+
+// Destination returns the shipping destination of the address.
+func (a *Address) Destination() *tax.Destination {
+	return &tax.Destination{Country: a.Country, Region: a.Region}
+}
+
+// AddressDTO is used to create or update the address of the user.
+type AddressDTO struct {
+	Id              int64    `json:"-"`
+	UserId          int64    `json:"-"`
+	Recipient       string   `json:"recipient" example:"Лев Толстой"`
+	Lines           []string `json:"lines" example:"Malaya Semenovskaya 12,apt. 5"`
+	City            string   `json:"city" example:"Moscow"`
+	Region          *string  `json:"region,omitempty" example:"Moscow"`
+	PostalCode      *string  `json:"postalCode,omitempty" example:"107023"`
+	Country         string   `json:"country" example:"RU"`
+	Phone           *string  `json:"phone,omitempty" example:"+7 (800) 555-35-35"`
+	DefaultShipping bool     `json:"defaultShipping" example:"true"`
+	DefaultBilling  bool     `json:"defaultBilling" example:"true"`
+} // @name AddressInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (a *AddressDTO) Validate() error {
+	return validation.ValidateStruct(
+		a,
+		validation.Field(&a.Recipient, validation.Required, validation.RuneLength(1, 100), validator.Text),
+		validation.Field(
+			&a.Lines,
+			validation.Required,
+			validation.Length(1, 3),
+			validation.Each(validation.Required, validation.RuneLength(1, 100), validator.Text),
+		),
+		validation.Field(&a.City, validation.Required, validation.RuneLength(1, 100), validator.Text),
+		validation.Field(&a.Region, validation.RuneLength(1, 100), validator.Text),
+		validation.Field(
+			&a.PostalCode,
+			validation.RuneLength(2, 12),
+			validation.Match(postalCodePattern).Error("must contain only letters, digits, spaces and hyphens"),
+		),
+		validation.Field(&a.Country, validation.Required, validator.CountryCode),
+		validation.Field(
+			&a.Phone,
+			validation.Length(5, 20),
+			validation.Match(phonePattern).Error("must be a phone number of digits, spaces, hyphens and parentheses"),
+		),
+	)
+}
@@ -0,0 +1,319 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const tableName = "addresses"
+
+// columns are columns of the address in the order they are scanned by scanAddress.
+const columns = `id, user_id, recipient, lines, city, region, postal_code, country, phone,
+	default_shipping, default_billing, created_at, updated_at`
+
+// defaultColumns are flag columns of default addresses by their kind.
+var defaultColumns = map[string]string{
+	KindShipping: "default_shipping",
+	KindBilling:  "default_billing",
+}
+
+// Check whether db implements address storage interface.
+var _ Storage = &db{}
+
+// db implements address storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new address storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// FindByUser finds addresses of the user, default ones first.
+// Returns an error on failure.
+func (d *db) FindByUser(userId int64) ([]*Address, error) {
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM %s
+	WHERE user_id = $1
+	ORDER BY default_shipping DESC, default_billing DESC, id`, columns, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find addresses query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := make([]*Address, 0)
+	for rows.Next() {
+		a, err := scanAddress(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan address: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read addresses: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// FindById finds the address of the user with specified id.
+// Returns ErrNoRows if the user has no such address or an error on failure.
+func (d *db) FindById(userId, id int64) (*Address, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND user_id = $2", columns, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	a, err := scanAddress(d.conn.QueryRow(ctx, query, id, userId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find address query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// FindDefault finds the default address of the given kind of the user.
+// Returns ErrNoRows if the user has no such address or an error on failure.
+func (d *db) FindDefault(userId int64, kind string) (*Address, error) {
+	column, ok := defaultColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown address kind %q", kind)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND %s", columns, tableName, column)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	a, err := scanAddress(d.conn.QueryRow(ctx, query, userId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find default address query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Create inserts the address of the user. If the address is a default one,
+// the previous default address of the kind stops being default. The first
+// address of the user becomes default for both kinds. Returns ErrNoRows if
+// the user doesn't exist, an error on failure or the inserted address
+// on success.
+func (d *db) Create(address *Address) (*Address, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %[1]s (
+		user_id, recipient, lines, city, region, postal_code, country, phone,
+		default_shipping, default_billing
+	)
+	VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8,
+		$9 OR NOT EXISTS (SELECT 1 FROM %[1]s WHERE user_id = $1),
+		$10 OR NOT EXISTS (SELECT 1 FROM %[1]s WHERE user_id = $1)
+	)
+	RETURNING id, default_shipping, default_billing, created_at, updated_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := clearDefaults(ctx, tx, address); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		address.UserId,
+		address.Recipient,
+		address.Lines,
+		address.City,
+		address.Region,
+		address.PostalCode,
+		address.Country,
+		address.Phone,
+		address.DefaultShipping,
+		address.DefaultBilling,
+	).Scan(&address.Id, &address.DefaultShipping, &address.DefaultBilling, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create address query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return address, nil
+}
+
+// Update updates the address of the user. If the address becomes a default
+// one, the previous default address of the kind stops being default.
+// Returns ErrNoRows if the user has no such address, an error on failure
+// or the updated address on success.
+func (d *db) Update(address *Address) (*Address, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET recipient = $3, lines = $4, city = $5, region = $6, postal_code = $7, country = $8, phone = $9,
+		default_shipping = $10, default_billing = $11, updated_at = now()
+	WHERE id = $1 AND user_id = $2
+	RETURNING created_at, updated_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := clearDefaults(ctx, tx, address); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		address.Id,
+		address.UserId,
+		address.Recipient,
+		address.Lines,
+		address.City,
+		address.Region,
+		address.PostalCode,
+		address.Country,
+		address.Phone,
+		address.DefaultShipping,
+		address.DefaultBilling,
+	).Scan(&address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute update address query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return address, nil
+}
+
+// Delete deletes the address of the user with specified id.
+// Returns ErrNoRows if the user has no such address or an error on failure.
+func (d *db) Delete(userId, id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, id, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete address query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// clearDefaults makes other addresses of the user not default
+// for the kinds the address is default for.
+func clearDefaults(ctx context.Context, tx pgx.Tx, address *Address) error {
+	for kind, isDefault := range map[string]bool{
+		KindShipping: address.DefaultShipping,
+		KindBilling:  address.DefaultBilling,
+	} {
+		if !isDefault {
+			continue
+		}
+
+		column := defaultColumns[kind]
+		query := fmt.Sprintf(
+			"UPDATE %s SET %s = false WHERE user_id = $1 AND id <> $2 AND %s",
+			tableName, column, column,
+		)
+		if _, err := tx.Exec(ctx, query, address.UserId, address.Id); err != nil {
+			return fmt.Errorf("failed to clear default %s address: %v", kind, err)
+		}
+	}
+
+	return nil
+}
+
+// scanAddress scans a row of columns into an address.
+func scanAddress(row pgx.Row) (*Address, error) {
+	var a Address
+	err := row.Scan(
+		&a.Id,
+		&a.UserId,
+		&a.Recipient,
+		&a.Lines,
+		&a.City,
+		&a.Region,
+		&a.PostalCode,
+		&a.Country,
+		&a.Phone,
+		&a.DefaultShipping,
+		&a.DefaultBilling,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
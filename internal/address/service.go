@@ -0,0 +1,121 @@
+package address
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes address service functionality.
+type Service interface {
+	GetByUser(ctx context.Context, userId int64) ([]*Address, error)
+	GetById(ctx context.Context, userId, id int64) (*Address, error)
+	GetDefault(ctx context.Context, userId int64, kind string) (*Address, error)
+	Create(ctx context.Context, input *AddressDTO) (*Address, error)
+	Update(ctx context.Context, input *AddressDTO) (*Address, error)
+	Delete(ctx context.Context, userId, id int64) error
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) GetByUser(ctx context.Context, userId int64) ([]*Address, error) {
+	addresses, err := s.storage.FindByUser(userId)
+	if err != nil {
+		s.logger.Warnf("cannot find addresses: %v", err)
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (s *service) GetById(ctx context.Context, userId, id int64) (*Address, error) {
+	address, err := s.storage.FindById(userId, id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find address by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// GetDefault returns the default address of the given kind of the user.
+// Returns ErrNoRows if the user has no such address.
+func (s *service) GetDefault(ctx context.Context, userId int64, kind string) (*Address, error) {
+	address, err := s.storage.FindDefault(userId, kind)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find default address: %v", err)
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (s *service) Create(ctx context.Context, input *AddressDTO) (*Address, error) {
+	address, err := s.storage.Create(fromDTO(input))
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to create address: %v", err)
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (s *service) Update(ctx context.Context, input *AddressDTO) (*Address, error) {
+	address, err := s.storage.Update(fromDTO(input))
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to update address: %v", err)
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (s *service) Delete(ctx context.Context, userId, id int64) error {
+	err := s.storage.Delete(userId, id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete address: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// fromDTO returns the address of the input.
+func fromDTO(input *AddressDTO) *Address {
+	return &Address{
+		Id:              input.Id,
+		UserId:          input.UserId,
+		Recipient:       input.Recipient,
+		Lines:           input.Lines,
+		City:            input.City,
+		Region:          input.Region,
+		PostalCode:      input.PostalCode,
+		Country:         input.Country,
+		Phone:           input.Phone,
+		DefaultShipping: input.DefaultShipping,
+		DefaultBilling:  input.DefaultBilling,
+	}
+}
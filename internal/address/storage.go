@@ -0,0 +1,11 @@
+package address
+
+// Storage describes an address storage functionality.
+type Storage interface {
+	FindByUser(userId int64) ([]*Address, error)
+	FindById(userId, id int64) (*Address, error)
+	FindDefault(userId int64, kind string) (*Address, error)
+	Create(address *Address) (*Address, error)
+	Update(address *Address) (*Address, error)
+	Delete(userId, id int64) error
+}
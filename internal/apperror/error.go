@@ -23,11 +23,146 @@ var (
 	// ErrEmailTaken is used when the user is being created and given email is already taken.
 	ErrEmailTaken = errors.New("email already taken")
 
+	// ErrPublisherExists is used when the publisher is being created or updated and given name is already taken.
+	ErrPublisherExists = errors.New("publisher with this name already exists")
+
+	// ErrISBNTaken is used when the book is being created or updated and given ISBN is already taken.
+	ErrISBNTaken = errors.New("book with this isbn already exists")
+
 	// ErrWrongPassword is used when client provided wrong password.
 	ErrWrongPassword = errors.New("wrong email or password")
 
 	// ErrInvalidRequestBody is used when client sends invalid request body.
 	ErrInvalidRequestBody = errors.New("invalid request body")
+
+	// ErrReferenced is used when the record cannot be deleted because other records still reference it.
+	ErrReferenced = errors.New("resource is still referenced by other records")
+
+	// ErrInvalidReference is used when the record references a resource that doesn't exist.
+	ErrInvalidReference = errors.New("referenced resource does not exist")
+
+	// ErrInsufficientStock is used when the stock movement would take more copies than there are in stock.
+	ErrInsufficientStock = errors.New("not enough copies in stock")
+
+	// ErrEmptyBasket is used when the checkout is started with an empty basket.
+	ErrEmptyBasket = errors.New("basket is empty")
+
+	// ErrInStock is used when the user subscribes to restock of the book which is available.
+	ErrInStock = errors.New("book is in stock")
+
+	// ErrSaleOverlaps is used when the sale overlaps another sale of the book.
+	ErrSaleOverlaps = errors.New("sale overlaps another sale of the book")
+
+	// ErrInvalidSalePrice is used when the sale price is not lower than the list price.
+	ErrInvalidSalePrice = errors.New("sale price must be lower than the list price")
+
+	// ErrPriceInEffect is used when the price which is already in effect is being cancelled.
+	ErrPriceInEffect = errors.New("price is already in effect")
+
+	// ErrPromoCodeExists is used when the promo code is being created and given code is already taken.
+	ErrPromoCodeExists = errors.New("promo code already exists")
+
+	// ErrPromoInactive is used when the promo code is applied before or after its validity window.
+	ErrPromoInactive = errors.New("promo code is not active")
+
+	// ErrPromoLimitReached is used when the promo code has been used the maximum number of times.
+	ErrPromoLimitReached = errors.New("promo code usage limit reached")
+
+	// ErrPromoNotStackable is used when the promo code cannot be combined with codes of the basket.
+	ErrPromoNotStackable = errors.New("promo code cannot be combined with other promo codes")
+
+	// ErrPromoNotApplicable is used when the promo code gives no discount for the basket.
+	ErrPromoNotApplicable = errors.New("promo code does not apply to the basket")
+
+	// ErrUnknownCurrency is used when the currency is not supported or has no exchange rate.
+	ErrUnknownCurrency = errors.New("currency is not supported")
+
+	// ErrBaseCurrency is used when the exchange rate of the base currency is being changed.
+	ErrBaseCurrency = errors.New("exchange rate of the base currency is always 1")
+
+	// ErrTaxRateExists is used when the tax rate is being created or updated and the same scope already has a rate.
+	ErrTaxRateExists = errors.New("tax rate for this country, region and category already exists")
+
+	// ErrShippingMethodExists is used when the shipping method is being created or updated and given code is already taken.
+	ErrShippingMethodExists = errors.New("shipping method with this code already exists")
+
+	// ErrShippingUnavailable is used when the shipping method has no rate for the destination or the parcel.
+	ErrShippingUnavailable = errors.New("shipping method is not available for the destination")
+
+	// ErrNoShippingMethod is used when the checkout is started before the shipping method is chosen.
+	ErrNoShippingMethod = errors.New("shipping method is not chosen")
+
+	// ErrInvalidStatusTransition is used when the order cannot move from its status to the requested one.
+	ErrInvalidStatusTransition = errors.New("order cannot change to this status")
+
+	// ErrTrackingNumberRequired is used when the order is shipped by courier or post without a tracking number.
+	ErrTrackingNumberRequired = errors.New("tracking number is required to ship the order")
+
+	// ErrOrderNotPayable is used when the payment is created for the order which is not pending.
+	ErrOrderNotPayable = errors.New("order is not waiting for payment")
+
+	// ErrPaymentNotConfirmable is used when the payment is confirmed again.
+	ErrPaymentNotConfirmable = errors.New("payment is already confirmed")
+
+	// ErrRefundExceedsPayment is used when the refund is larger than the captured and not refunded amount.
+	ErrRefundExceedsPayment = errors.New("refund exceeds the paid amount")
+
+	// ErrInvalidSignature is used when the signature of the webhook request doesn't match its body.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrOrderNotReturnable is used when the return is requested for the order which is not paid.
+	ErrOrderNotReturnable = errors.New("order cannot be returned")
+
+	// ErrReturnQuantityExceeded is used when the return has more copies of the order line than left to return.
+	ErrReturnQuantityExceeded = errors.New("return quantity exceeds the quantity left to return")
+
+	// ErrOrderHasReturns is used when the order with returns which are not rejected is cancelled.
+	ErrOrderHasReturns = errors.New("order has returns and cannot be cancelled")
+
+	// ErrDigitalNotReturnable is used when the return has digital copies of the order, they can't be sent back.
+	ErrDigitalNotReturnable = errors.New("digital copies cannot be returned")
+
+	// ErrInvalidReturnTransition is used when the return cannot move from its status to the requested one.
+	ErrInvalidReturnTransition = errors.New("return cannot change to this status")
+
+	// ErrOrderNotInvoiceable is used when the invoice is issued for the order which is not paid.
+	ErrOrderNotInvoiceable = errors.New("order is not paid")
+
+	// ErrInvoiceExists is used when the invoice or the credit note has been issued already.
+	ErrInvoiceExists = errors.New("invoice is already issued")
+
+	// ErrReviewExists is used when the user reviews the book again.
+	ErrReviewExists = errors.New("book is already reviewed by the user")
+
+	// ErrReviewNotAllowed is used when the user reviews the book which the user hasn't bought.
+	ErrReviewNotAllowed = errors.New("only bought books can be reviewed")
+
+	// ErrReviewReported is used when the user reports the review again.
+	ErrReviewReported = errors.New("review is already reported by the user")
+
+	// ErrWishlistExists is used when the user already has a wishlist with the name.
+	ErrWishlistExists = errors.New("wishlist with the name already exists")
+
+	// ErrCollectionExists is used when the collection with the slug already exists.
+	ErrCollectionExists = errors.New("collection with the slug already exists")
+
+	// ErrNotEbook is used when the file is uploaded for the book which is not an ebook.
+	ErrNotEbook = errors.New("only ebooks can have files")
+
+	// ErrInvalidLink is used when the download link is malformed or its signature doesn't match.
+	ErrInvalidLink = errors.New("invalid download link")
+
+	// ErrLinkExpired is used when the download link is used after it has expired.
+	ErrLinkExpired = errors.New("download link has expired")
+
+	// ErrDownloadLimitReached is used when the ebook has been downloaded the maximum number of times.
+	ErrDownloadLimitReached = errors.New("download limit reached")
+
+	// ErrNoBillingAddress is used when the checkout of ebooks is started and the user has no address to bill to.
+	ErrNoBillingAddress = errors.New("billing address is not set")
+
+	// ErrNoShippingAddress is used when the checkout is started and the user has no address to ship to.
+	ErrNoShippingAddress = errors.New("shipping address is not set")
 )
 
 // AppError describes a structure of an error response in JSON format.
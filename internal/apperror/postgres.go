@@ -0,0 +1,29 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+const (
+	// foreignKeyViolationCode is a postgres error code raised when
+	// the record references a missing row or is still referenced by another table.
+	foreignKeyViolationCode = "23503"
+
+	// uniqueViolationCode is a postgres error code raised when
+	// the record violates unique constraint.
+	uniqueViolationCode = "23505"
+)
+
+// IsForeignKeyViolation reports whether err is a postgres foreign key violation error.
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
+
+// IsUniqueViolation reports whether err is a postgres unique constraint violation error.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
@@ -5,39 +5,53 @@ import (
 	"fmt"
 	"net/http"
 
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/currency"
 	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/media"
 	"github.com/juicyluv/ReadyRead/internal/response"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 	"github.com/julienschmidt/httprouter"
 )
 
 const (
-	authorsURL = "/api/authors"
-	authorURL  = "/api/authors/:id"
+	authorsURL     = "/api/authors"
+	authorURL      = "/api/authors/:id"
+	authorBooksURL = "/api/authors/:id/books"
+	authorPhotoURL = "/api/authors/:id/photo"
 )
 
 // Handler handles requests specified to author service.
 type Handler struct {
 	logger        logger.Logger
 	authorService Service
+	bookService   book.Service
+	maxUploadSize int64
 }
 
 // NewHandler returns a new author Handler instance.
-func NewHandler(logger logger.Logger, authorService Service) handler.Handling {
+// Uploaded images are limited to maxUploadSize bytes.
+func NewHandler(logger logger.Logger, authorService Service, bookService book.Service, maxUploadSize int64) handler.Handling {
 	return &Handler{
 		logger:        logger,
 		authorService: authorService,
+		bookService:   bookService,
+		maxUploadSize: maxUploadSize,
 	}
 }
 
 // Register registers new routes for router.
 func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodGet, authorURL, h.GetAuthor)
+	router.HandlerFunc(http.MethodGet, authorBooksURL, h.ListAuthorBooks)
 	router.HandlerFunc(http.MethodPost, authorsURL, h.CreateAuthor)
 	router.HandlerFunc(http.MethodPut, authorURL, h.UpdateAuthor)
 	router.HandlerFunc(http.MethodPatch, authorURL, h.UpdateAuthorPartially)
 	router.HandlerFunc(http.MethodDelete, authorURL, h.DeleteAuthor)
+	router.HandlerFunc(http.MethodPut, authorPhotoURL, h.UploadAuthorPhoto)
+	router.HandlerFunc(http.MethodDelete, authorPhotoURL, h.DeleteAuthorPhoto)
 }
 
 // GetAuthor godoc
@@ -74,6 +88,60 @@ func (h *Handler) GetAuthor(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, author)
 }
 
+// ListAuthorBooks godoc
+// @Summary List author books
+// @Description Get books of the author with specified id.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Author id"
+// @Param search query string false "Part of the book title"
+// @Param genreId query int false "Genre id"
+// @Param languageId query int false "Language id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} book.Book
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /authors/{id}/books [get]
+func (h *Handler) ListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST AUTHOR BOOKS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	filter, err := book.ReadFilter(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	filter.AuthorId = &id
+
+	_, err = h.authorService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	books, err := h.bookService.GetAll(ctx, filter)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, books)
+}
+
 // CreateAuthor godoc
 // @Summary Create author
 // @Description Register a new author.
@@ -200,6 +268,12 @@ func (h *Handler) UpdateAuthorPartially(w http.ResponseWriter, r *http.Request)
 
 	err = h.authorService.UpdatePartially(r.Context(), &input)
 	if err != nil {
+		var validationErr validation.Errors
+		if errors.As(err, &validationErr) {
+			response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+			return
+		}
+
 		switch err {
 		case apperror.ErrNoRows:
 			response.NotFound(w)
@@ -223,6 +297,7 @@ func (h *Handler) UpdateAuthorPartially(w http.ResponseWriter, r *http.Request)
 // @Param id path int64 true "Author id"
 // @Success 200
 // @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
 // @Failure 500 {object} apperror.AppError
 // @Router /authors/{id} [delete]
 func (h *Handler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
@@ -240,9 +315,98 @@ func (h *Handler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrReferenced) {
+			response.Conflict(w, err.Error(), "delete books of this author or remove the author from them first")
+			return
+		}
 		response.InternalError(w, err.Error(), "something went wrong on the server side")
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// UploadAuthorPhoto godoc
+// @Summary Upload author photo
+// @Description Upload JPEG, PNG or GIF image as the author photo. Medium and thumbnail
+// @Description variants are generated from it. The previous photo is removed.
+// @Tags authors
+// @Accept mpfd
+// @Produce json
+// @Param id path int64 true "Author id"
+// @Param image formData file true "Author photo"
+// @Success 200 {object} media.Image
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /authors/{id}/photo [put]
+func (h *Handler) UploadAuthorPhoto(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPLOAD AUTHOR PHOTO")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	data, err := handler.ReadImage(w, r, h.maxUploadSize)
+	if err != nil {
+		if errors.Is(err, handler.ErrUploadTooLarge) {
+			response.TooLarge(w, err.Error(), fmt.Sprintf("maximum size is %d bytes", h.maxUploadSize))
+			return
+		}
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	photo, err := h.authorService.SetPhoto(r.Context(), id, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, media.ErrUnsupportedType):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, media.ErrTooLarge):
+			response.TooLarge(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, photo)
+}
+
+// DeleteAuthorPhoto godoc
+// @Summary Delete author photo
+// @Description Delete uploaded photo of the author with specified id.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Author id"
+// @Success 200
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /authors/{id}/photo [delete]
+func (h *Handler) DeleteAuthorPhoto(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE AUTHOR PHOTO")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	err = h.authorService.DeletePhoto(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -1,73 +1,139 @@
 package author
 
 import (
+	"errors"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
-	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/internal/validator"
 )
 
 // Author represents the author model.
 type Author struct {
-	Id      int64  `json:"id" example:"123"`
-	Name    string `json:"name" example:"Ilya"`
-	Surname string `json:"surname" example:"Sokolov"`
+	Id        int64        `json:"id" example:"123"`
+	Name      string       `json:"name" example:"Lev"`
+	Surname   string       `json:"surname" example:"Tolstoy"`
+	Biography *string      `json:"biography,omitempty" example:"Russian writer, author of War and Peace."`
+	BirthYear *int16       `json:"birthYear,omitempty" example:"1828"`
+	DeathYear *int16       `json:"deathYear,omitempty" example:"1910"`
+	Country   *string      `json:"country,omitempty" example:"Russia"`
+	PenNames  []string     `json:"penNames" example:"L. N. Tolstoy"`
+	Photo     *media.Image `json:"photo,omitempty"`
 } // @name Author
 
 // CreateAuthorDTO is used to create author.
 type CreateAuthorDTO struct {
-	Name    string `json:"name" example:"Ilya"`
-	Surname string `json:"surname" example:"Sokolov"`
+	Name      string   `json:"name" example:"Lev"`
+	Surname   string   `json:"surname" example:"Tolstoy"`
+	Biography *string  `json:"biography,omitempty" example:"Russian writer, author of War and Peace."`
+	BirthYear *int16   `json:"birthYear,omitempty" example:"1828"`
+	DeathYear *int16   `json:"deathYear,omitempty" example:"1910"`
+	Country   *string  `json:"country,omitempty" example:"Russia"`
+	PenNames  []string `json:"penNames,omitempty" example:"L. N. Tolstoy"`
 } // @name CreateAuthorInput
 
 // Validate will validates current struct fields.
 // Returns an error if something doesn't fit rules.
 func (a *CreateAuthorDTO) Validate() error {
-	return validation.ValidateStruct(
+	err := validation.ValidateStruct(
 		a,
 		validation.Field(
 			&a.Name,
-			is.Alpha,
-			validation.Length(1, 30),
+			validator.Name,
+			validation.RuneLength(1, 50),
 			validation.Required,
 		),
 		validation.Field(
 			&a.Surname,
-			is.Alpha,
-			validation.Length(1, 30),
+			validator.Name,
+			validation.RuneLength(1, 50),
 			validation.Required,
 		),
+		validation.Field(&a.Biography, validation.RuneLength(1, 5000)),
+		validation.Field(&a.BirthYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.DeathYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.Country, validator.Name, validation.RuneLength(2, 60)),
+		validation.Field(&a.PenNames, validation.Each(validator.Name, validation.RuneLength(1, 100))),
 	)
+	if err != nil {
+		return err
+	}
+
+	return validateLifeYears(a.BirthYear, a.DeathYear)
 }
 
 // UpdateAuthorDTO is used to update author record.
 type UpdateAuthorDTO struct {
-	Id      int64  `json:"-"`
-	Name    string `json:"name" example:"Ilya"`
-	Surname string `json:"surname" example:"Sokolov"`
+	Id        int64    `json:"-"`
+	Name      string   `json:"name" example:"Lev"`
+	Surname   string   `json:"surname" example:"Tolstoy"`
+	Biography *string  `json:"biography" example:"Russian writer, author of War and Peace."`
+	BirthYear *int16   `json:"birthYear" example:"1828"`
+	DeathYear *int16   `json:"deathYear" example:"1910"`
+	Country   *string  `json:"country" example:"Russia"`
+	PenNames  []string `json:"penNames" example:"L. N. Tolstoy"`
 } // @name UpdateAuthorInput
 
 // Validate will validates current struct fields.
 // Returns an error if something doesn't fit rules.
 func (a *UpdateAuthorDTO) Validate() error {
-	return validation.ValidateStruct(
+	err := validation.ValidateStruct(
 		a,
-		validation.Field(&a.Name, is.Alpha, validation.Length(1, 30), validation.Required),
-		validation.Field(&a.Surname, is.Alpha, validation.Length(1, 30), validation.Required),
+		validation.Field(&a.Name, validator.Name, validation.RuneLength(1, 50), validation.Required),
+		validation.Field(&a.Surname, validator.Name, validation.RuneLength(1, 50), validation.Required),
+		validation.Field(&a.Biography, validation.RuneLength(1, 5000)),
+		validation.Field(&a.BirthYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.DeathYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.Country, validator.Name, validation.RuneLength(2, 60)),
+		validation.Field(&a.PenNames, validation.Each(validator.Name, validation.RuneLength(1, 100))),
 	)
+	if err != nil {
+		return err
+	}
+
+	return validateLifeYears(a.BirthYear, a.DeathYear)
 }
 
 // UpdateAuthorPartiallyDTO is used to update author record.
 type UpdateAuthorPartiallyDTO struct {
-	Id      int64   `json:"-"`
-	Name    *string `json:"name" example:"Ilya"`
-	Surname *string `json:"surname" example:"Sokolov"`
+	Id        int64    `json:"-"`
+	Name      *string  `json:"name" example:"Lev"`
+	Surname   *string  `json:"surname" example:"Tolstoy"`
+	Biography *string  `json:"biography" example:"Russian writer, author of War and Peace."`
+	BirthYear *int16   `json:"birthYear" example:"1828"`
+	DeathYear *int16   `json:"deathYear" example:"1910"`
+	Country   *string  `json:"country" example:"Russia"`
+	PenNames  []string `json:"penNames" example:"L. N. Tolstoy"`
 } // @name UpdateAuthorPartiallyInput
 
 // Validate will validates current struct fields.
 // Returns an error if something doesn't fit rules.
 func (a *UpdateAuthorPartiallyDTO) Validate() error {
-	return validation.ValidateStruct(
+	err := validation.ValidateStruct(
 		a,
-		validation.Field(&a.Name, is.Alpha, validation.Length(1, 30)),
-		validation.Field(&a.Surname, is.Alpha, validation.Length(1, 30)),
+		validation.Field(&a.Name, validator.Name, validation.RuneLength(1, 50)),
+		validation.Field(&a.Surname, validator.Name, validation.RuneLength(1, 50)),
+		validation.Field(&a.Biography, validation.RuneLength(1, 5000)),
+		validation.Field(&a.BirthYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.DeathYear, validation.Max(time.Now().Year())),
+		validation.Field(&a.Country, validator.Name, validation.RuneLength(2, 60)),
+		validation.Field(&a.PenNames, validation.Each(validator.Name, validation.RuneLength(1, 100))),
 	)
+	if err != nil {
+		return err
+	}
+
+	return validateLifeYears(a.BirthYear, a.DeathYear)
+}
+
+// validateLifeYears checks that author didn't die before being born.
+func validateLifeYears(birthYear, deathYear *int16) error {
+	if birthYear != nil && deathYear != nil && *deathYear < *birthYear {
+		return validation.Errors{
+			"deathYear": errors.New("must not be less than birthYear"),
+		}
+	}
+
+	return nil
 }
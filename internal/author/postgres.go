@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/media"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 )
 
@@ -22,12 +24,12 @@ var _ Storage = &db{}
 // db implements author storage interface.
 type db struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 // NewStorage returns a new author storage instance.
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &db{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -39,8 +41,8 @@ func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
 // Returns an error on failure or inserted author with it's id on success.
 func (d *db) Create(author *Author) (*Author, error) {
 	query := fmt.Sprintf(`
-	INSERT INTO %s (name, surname)
-	VALUES ($1, $2)
+	INSERT INTO %s (name, surname, biography, birth_year, death_year, country, pen_names)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING id`, tableName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
@@ -51,6 +53,11 @@ func (d *db) Create(author *Author) (*Author, error) {
 		query,
 		author.Name,
 		author.Surname,
+		author.Biography,
+		author.BirthYear,
+		author.DeathYear,
+		author.Country,
+		author.PenNames,
 	).Scan(&author.Id)
 
 	if err != nil {
@@ -64,7 +71,7 @@ func (d *db) Create(author *Author) (*Author, error) {
 
 func (d *db) FindById(id int64) (*Author, error) {
 	query := fmt.Sprintf(`
-	SELECT id, name, surname
+	SELECT id, name, surname, biography, birth_year, death_year, country, pen_names, photo
 	FROM %s 
 	WHERE id = $1`, tableName)
 
@@ -77,6 +84,12 @@ func (d *db) FindById(id int64) (*Author, error) {
 		&found.Id,
 		&found.Name,
 		&found.Surname,
+		&found.Biography,
+		&found.BirthYear,
+		&found.DeathYear,
+		&found.Country,
+		&found.PenNames,
+		&found.Photo,
 	)
 
 	if err != nil {
@@ -94,12 +107,17 @@ func (d *db) FindById(id int64) (*Author, error) {
 func (d *db) Update(author *UpdateAuthorDTO) error {
 	query := fmt.Sprintf(`
 	UPDATE %s
-	SET name=$1, surname=$2
-	WHERE id = $3`, tableName)
+	SET name=$1, surname=$2, biography=$3, birth_year=$4, death_year=$5, country=$6, pen_names=$7
+	WHERE id = $8`, tableName)
 
 	args := []interface{}{
 		author.Name,
 		author.Surname,
+		author.Biography,
+		author.BirthYear,
+		author.DeathYear,
+		author.Country,
+		author.PenNames,
 		author.Id,
 	}
 
@@ -140,6 +158,36 @@ func (d *db) UpdatePartially(author *UpdateAuthorPartiallyDTO) error {
 		argId++
 	}
 
+	if author.Biography != nil {
+		values = append(values, fmt.Sprintf("biography=$%d", argId))
+		args = append(args, *author.Biography)
+		argId++
+	}
+
+	if author.BirthYear != nil {
+		values = append(values, fmt.Sprintf("birth_year=$%d", argId))
+		args = append(args, *author.BirthYear)
+		argId++
+	}
+
+	if author.DeathYear != nil {
+		values = append(values, fmt.Sprintf("death_year=$%d", argId))
+		args = append(args, *author.DeathYear)
+		argId++
+	}
+
+	if author.Country != nil {
+		values = append(values, fmt.Sprintf("country=$%d", argId))
+		args = append(args, *author.Country)
+		argId++
+	}
+
+	if author.PenNames != nil {
+		values = append(values, fmt.Sprintf("pen_names=$%d", argId))
+		args = append(args, author.PenNames)
+		argId++
+	}
+
 	valuesQuery := strings.Join(values, ", ")
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", tableName, valuesQuery, argId)
 	args = append(args, author.Id)
@@ -155,16 +203,49 @@ func (d *db) UpdatePartially(author *UpdateAuthorPartiallyDTO) error {
 	return nil
 }
 
+// UpdatePhoto sets the photo of the author with specified id.
+// Nil photo removes the current one.
+// Returns ErrNoRows if author doesn't exist or an error on failure.
+func (d *db) UpdatePhoto(id int64, photo *media.Image) error {
+	query := fmt.Sprintf("UPDATE %s SET photo = $1 WHERE id = $2", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, photo, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update author photo query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete deletes the author with specified id.
+// Returns ErrNoRows if author doesn't exist, ErrReferenced if
+// author still has books or an error on failure.
 func (d *db) Delete(id int64) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
-	_, err := d.conn.Exec(ctx, query, id)
+	result, err := d.conn.Exec(ctx, query, id)
 	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrReferenced
+		}
 		return fmt.Errorf("failed to delete author: %v", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
 	return nil
 }
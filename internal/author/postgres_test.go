@@ -0,0 +1,331 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+// newStorage returns the storage backed by a new test database.
+func newStorage(t *testing.T) (Storage, *pgxpool.Pool) {
+	t.Helper()
+
+	conn := testdb.New(t)
+	return NewStorage(conn, 5), conn
+}
+
+// mustCreate creates the author with specified name and surname.
+func mustCreate(t *testing.T, s Storage, name, surname string) *Author {
+	t.Helper()
+
+	author, err := s.Create(&Author{Name: name, Surname: surname, PenNames: []string{}})
+	if err != nil {
+		t.Fatalf("cannot create author %s %s: %v", name, surname, err)
+	}
+	return author
+}
+
+// mustCreateBook creates a book written by the author.
+func mustCreateBook(t *testing.T, conn *pgxpool.Pool, authorId int64) {
+	t.Helper()
+
+	_, err := conn.Exec(context.Background(), `
+	WITH w AS (INSERT INTO works (title) VALUES ('War and Peace') RETURNING id),
+		l AS (INSERT INTO languages (language) VALUES ('ru') RETURNING id),
+		b AS (
+			INSERT INTO books (title, description, price, count, language_id, work_id)
+			SELECT 'War and Peace', 'Novel', 9.99, 1, l.id, w.id FROM w, l
+			RETURNING id
+		)
+	INSERT INTO book_authors (book_id, author_id) SELECT b.id, $1 FROM b`, authorId)
+	if err != nil {
+		t.Fatalf("cannot create book: %v", err)
+	}
+}
+
+func int16Ptr(v int16) *int16 { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+func TestCreate(t *testing.T) {
+	s, _ := newStorage(t)
+
+	tests := []struct {
+		name    string
+		input   *Author
+		wantErr bool
+	}{
+		{
+			name: "full profile",
+			input: &Author{
+				Name:      "Lev",
+				Surname:   "Tolstoy",
+				Biography: stringPtr("Russian writer."),
+				BirthYear: int16Ptr(1828),
+				DeathYear: int16Ptr(1910),
+				Country:   stringPtr("Russia"),
+				PenNames:  []string{"L. N. Tolstoy"},
+			},
+		},
+		{
+			name:  "name only",
+			input: &Author{Name: "Homer", Surname: "", PenNames: []string{}},
+		},
+		{
+			name: "died before birth",
+			input: &Author{
+				Name:      "Anton",
+				Surname:   "Chekhov",
+				BirthYear: int16Ptr(1904),
+				DeathYear: int16Ptr(1860),
+				PenNames:  []string{},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := s.Create(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(created.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, created) {
+				t.Errorf("FindById() = %+v, want %+v", got, created)
+			}
+		})
+	}
+}
+
+func TestFindById(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "Lev", "Tolstoy")
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{name: "existing", id: created.Id},
+		{name: "not found", id: created.Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindById(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FindById() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Id != tt.id {
+				t.Errorf("FindById() id = %d, want %d", got.Id, tt.id)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "Lev", "Tolstoy")
+
+	tests := []struct {
+		name       string
+		input      *UpdateAuthorDTO
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name: "existing",
+			input: &UpdateAuthorDTO{
+				Id:        created.Id,
+				Name:      "Leo",
+				Surname:   "Tolstoy",
+				BirthYear: int16Ptr(1828),
+				DeathYear: int16Ptr(1910),
+				PenNames:  []string{"L. N. Tolstoy"},
+			},
+		},
+		{
+			name:    "not found",
+			input:   &UpdateAuthorDTO{Id: created.Id + 1, Name: "Anton", Surname: "Chekhov", PenNames: []string{}},
+			wantErr: apperror.ErrNoRows,
+		},
+		{
+			name: "died before birth",
+			input: &UpdateAuthorDTO{
+				Id:        created.Id,
+				Name:      "Leo",
+				Surname:   "Tolstoy",
+				BirthYear: int16Ptr(1910),
+				DeathYear: int16Ptr(1828),
+				PenNames:  []string{},
+			},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Update(tt.input)
+			if tt.wantAnyErr {
+				if err == nil {
+					t.Fatal("Update() error = nil, want an error")
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if got.Name != tt.input.Name || *got.BirthYear != *tt.input.BirthYear ||
+				!reflect.DeepEqual(got.PenNames, tt.input.PenNames) {
+				t.Errorf("FindById() = %+v, want %+v", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestUpdatePartially(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "Lev", "Tolstoy")
+
+	tests := []struct {
+		name    string
+		input   *UpdateAuthorPartiallyDTO
+		check   func(a *Author) bool
+		wantErr bool
+	}{
+		{
+			name:  "name",
+			input: &UpdateAuthorPartiallyDTO{Id: created.Id, Name: stringPtr("Leo")},
+			check: func(a *Author) bool { return a.Name == "Leo" && a.Surname == "Tolstoy" },
+		},
+		{
+			name:  "life years",
+			input: &UpdateAuthorPartiallyDTO{Id: created.Id, BirthYear: int16Ptr(1828), DeathYear: int16Ptr(1910)},
+			check: func(a *Author) bool { return *a.BirthYear == 1828 && *a.DeathYear == 1910 },
+		},
+		{
+			name:    "died before birth",
+			input:   &UpdateAuthorPartiallyDTO{Id: created.Id, BirthYear: int16Ptr(1900), DeathYear: int16Ptr(1800)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.UpdatePartially(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdatePartially() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if !tt.check(got) {
+				t.Errorf("FindById() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdatePhoto(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "Lev", "Tolstoy")
+
+	photo := &media.Image{
+		Key:          "authors/1/photo/abc",
+		OriginalURL:  "http://localhost:8080/media/authors/1/photo/abc/original.jpg",
+		MediumURL:    "http://localhost:8080/media/authors/1/photo/abc/medium.jpg",
+		ThumbnailURL: "http://localhost:8080/media/authors/1/photo/abc/thumbnail.jpg",
+		Width:        600,
+		Height:       800,
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		photo   *media.Image
+		wantErr error
+	}{
+		{name: "set", id: created.Id, photo: photo},
+		{name: "remove", id: created.Id},
+		{name: "not found", id: created.Id + 1, photo: photo, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.UpdatePhoto(tt.id, tt.photo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdatePhoto() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if (got.Photo == nil) != (tt.photo == nil) ||
+				(got.Photo != nil && got.Photo.Key != tt.photo.Key) {
+				t.Errorf("photo = %+v, want %+v", got.Photo, tt.photo)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s, conn := newStorage(t)
+	unused := mustCreate(t, s, "Anton", "Chekhov")
+	writer := mustCreate(t, s, "Lev", "Tolstoy")
+	mustCreateBook(t, conn, writer.Id)
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{name: "unused", id: unused.Id},
+		{name: "already deleted", id: unused.Id, wantErr: apperror.ErrNoRows},
+		{name: "referenced by books", id: writer.Id, wantErr: apperror.ErrReferenced},
+		{name: "not found", id: writer.Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Delete(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := s.FindById(tt.id)
+			if tt.wantErr == nil && !errors.Is(err, apperror.ErrNoRows) {
+				t.Errorf("FindById() error = %v, want %v", err, apperror.ErrNoRows)
+			}
+		})
+	}
+}
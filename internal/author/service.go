@@ -3,8 +3,10 @@ package author
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/media"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 )
 
@@ -14,26 +16,39 @@ type Service interface {
 	GetById(ctx context.Context, id int64) (*Author, error)
 	Update(ctx context.Context, author *UpdateAuthorDTO) error
 	UpdatePartially(ctx context.Context, author *UpdateAuthorPartiallyDTO) error
+	SetPhoto(ctx context.Context, id int64, data []byte) (*media.Image, error)
+	DeletePhoto(ctx context.Context, id int64) error
 	Delete(ctx context.Context, id int64) error
 }
 
 type service struct {
 	logger  logger.Logger
 	storage Storage
+	images  *media.Processor
 }
 
 // NewService returns a new instance that implements Service interface.
-func NewService(storage Storage, logger logger.Logger) Service {
+func NewService(storage Storage, images *media.Processor, logger logger.Logger) Service {
 	return &service{
 		logger:  logger,
 		storage: storage,
+		images:  images,
 	}
 }
 
 func (s *service) Create(ctx context.Context, input *CreateAuthorDTO) (*Author, error) {
 	a := Author{
-		Name:    input.Name,
-		Surname: input.Surname,
+		Name:      input.Name,
+		Surname:   input.Surname,
+		Biography: input.Biography,
+		BirthYear: input.BirthYear,
+		DeathYear: input.DeathYear,
+		Country:   input.Country,
+		PenNames:  input.PenNames,
+	}
+
+	if a.PenNames == nil {
+		a.PenNames = make([]string, 0)
 	}
 
 	author, err := s.storage.Create(&a)
@@ -70,6 +85,10 @@ func (s *service) Update(ctx context.Context, author *UpdateAuthorDTO) error {
 		return apperror.ErrNoRows
 	}
 
+	if author.PenNames == nil {
+		author.PenNames = make([]string, 0)
+	}
+
 	err = s.storage.Update(author)
 	if err != nil {
 		s.logger.Errorf("failed to update author: %v", err)
@@ -92,6 +111,18 @@ func (s *service) UpdatePartially(ctx context.Context, author *UpdateAuthorParti
 		return apperror.ErrNoRows
 	}
 
+	birthYear, deathYear := a.BirthYear, a.DeathYear
+	if author.BirthYear != nil {
+		birthYear = author.BirthYear
+	}
+	if author.DeathYear != nil {
+		deathYear = author.DeathYear
+	}
+
+	if err := validateLifeYears(birthYear, deathYear); err != nil {
+		return err
+	}
+
 	err = s.storage.UpdatePartially(author)
 	if err != nil {
 		s.logger.Errorf("failed to partially update author: %v", err)
@@ -101,14 +132,84 @@ func (s *service) UpdatePartially(ctx context.Context, author *UpdateAuthorParti
 	return nil
 }
 
+// SetPhoto saves uploaded image as the author photo and removes the previous one.
+// Returns ErrNoRows if author doesn't exist, media errors if the image
+// is not acceptable or an error on failure.
+func (s *service) SetPhoto(ctx context.Context, id int64, data []byte) (*media.Image, error) {
+	author, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := s.images.Save(ctx, fmt.Sprintf("authors/%d/photo", id), data)
+	if err != nil {
+		if !errors.Is(err, media.ErrUnsupportedType) && !errors.Is(err, media.ErrTooLarge) {
+			s.logger.Errorf("failed to save author photo: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.storage.UpdatePhoto(id, photo); err != nil {
+		s.removeImage(ctx, photo)
+		return nil, err
+	}
+
+	if author.Photo != nil {
+		s.removeImage(ctx, author.Photo)
+	}
+
+	return photo, nil
+}
+
+// DeletePhoto removes the author photo.
+// Returns ErrNoRows if author or its photo doesn't exist or an error on failure.
+func (s *service) DeletePhoto(ctx context.Context, id int64) error {
+	author, err := s.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if author.Photo == nil {
+		return apperror.ErrNoRows
+	}
+
+	if err := s.storage.UpdatePhoto(id, nil); err != nil {
+		return err
+	}
+
+	s.removeImage(ctx, author.Photo)
+
+	return nil
+}
+
 func (s *service) Delete(ctx context.Context, id int64) error {
-	err := s.storage.Delete(id)
+	author, err := s.storage.FindById(id)
 	if err != nil {
 		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("failed to find author: %v", err)
+		}
+		return err
+	}
+
+	err = s.storage.Delete(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrReferenced) {
 			s.logger.Warnf("failed to delete author: %v", err)
 		}
 		return err
 	}
 
+	if author.Photo != nil {
+		s.removeImage(ctx, author.Photo)
+	}
+
 	return nil
 }
+
+// removeImage deletes image files. Failures are only logged since
+// the image is not referenced anymore.
+func (s *service) removeImage(ctx context.Context, img *media.Image) {
+	if err := s.images.Delete(ctx, img); err != nil {
+		s.logger.Warnf("failed to remove image %s: %v", img.Key, err)
+	}
+}
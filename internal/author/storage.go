@@ -1,10 +1,13 @@
 package author
 
+import "github.com/juicyluv/ReadyRead/internal/media"
+
 // Storage descibes author storage functionality.
 type Storage interface {
 	Create(user *Author) (*Author, error)
 	FindById(id int64) (*Author, error)
 	Update(user *UpdateAuthorDTO) error
 	UpdatePartially(user *UpdateAuthorPartiallyDTO) error
+	UpdatePhoto(id int64, photo *media.Image) error
 	Delete(id int64) error
 }
@@ -0,0 +1,541 @@
+package basket
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/promo"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	basketURL      = "/api/users/:id/basket"
+	basketBookURL  = "/api/users/:id/basket/books/:bookId"
+	checkoutURL    = "/api/users/:id/basket/checkout"
+	destinationURL = "/api/users/:id/basket/destination"
+	addressURL     = "/api/users/:id/basket/address"
+	shippingURL    = "/api/users/:id/basket/shipping-method"
+	quotesURL      = "/api/users/:id/basket/shipping-methods"
+	promoCodesURL  = "/api/users/:id/basket/promo-codes"
+	promoCodeURL   = "/api/users/:id/basket/promo-codes/:code"
+)
+
+// Handler handles requests specified to basket service.
+type Handler struct {
+	logger        logger.Logger
+	basketService Service
+}
+
+// NewHandler returns a new basket Handler instance.
+func NewHandler(logger logger.Logger, basketService Service) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		basketService: basketService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, basketURL, h.GetBasket)
+	router.HandlerFunc(http.MethodPut, basketBookURL, h.SetBasketItem)
+	router.HandlerFunc(http.MethodDelete, basketBookURL, h.RemoveBasketItem)
+	router.HandlerFunc(http.MethodPost, promoCodesURL, h.ApplyPromoCode)
+	router.HandlerFunc(http.MethodDelete, promoCodeURL, h.RemovePromoCode)
+	router.HandlerFunc(http.MethodPut, destinationURL, h.SetDestination)
+	router.HandlerFunc(http.MethodPut, addressURL, h.SetAddress)
+	router.HandlerFunc(http.MethodGet, quotesURL, h.ListShippingQuotes)
+	router.HandlerFunc(http.MethodPut, shippingURL, h.SetShippingMethod)
+	router.HandlerFunc(http.MethodPost, checkoutURL, h.Checkout)
+}
+
+// GetBasket godoc
+// @Summary Show basket
+// @Description Get the current basket of the user. A new basket is created if the user doesn't have one.
+// @Description Prices are in the first accepted currency with an exchange rate, the currency preferred by the user or the base one.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket [get]
+func (h *Handler) GetBasket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET BASKET")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.Get(ctx, userId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// SetBasketItem godoc
+// @Summary Put book in basket
+// @Description Set the number of copies of the book in the basket. The copies are reserved for a limited time.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param bookId path int64 true "Book id"
+// @Param input body SetItemDTO true "JSON input"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/books/{bookId} [put]
+func (h *Handler) SetBasketItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET BASKET ITEM")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	bookId, err := handler.ReadParam64(r, "bookId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input SetItemDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+	input.BookId = bookId
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.SetItem(ctx, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInsufficientStock):
+			response.Conflict(w, err.Error(), "there are not enough available copies of the book")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// RemoveBasketItem godoc
+// @Summary Remove book from basket
+// @Description Remove the book from the basket and release its reservation.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param bookId path int64 true "Book id"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/books/{bookId} [delete]
+func (h *Handler) RemoveBasketItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REMOVE BASKET ITEM")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	bookId, err := handler.ReadParam64(r, "bookId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.RemoveItem(ctx, userId, bookId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// ApplyPromoCode godoc
+// @Summary Apply promo code
+// @Description Apply the promo code to the basket. Codes which are not stackable can't be combined with other codes.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body promo.ApplyPromoCodeDTO true "JSON input"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/promo-codes [post]
+func (h *Handler) ApplyPromoCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("APPLY PROMO CODE")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input promo.ApplyPromoCodeDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.ApplyPromoCode(ctx, userId, input.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrPromoInactive),
+			errors.Is(err, apperror.ErrPromoNotApplicable):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrPromoLimitReached):
+			response.Conflict(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrPromoNotStackable):
+			response.Conflict(w, err.Error(), "remove other promo codes from the basket first")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// RemovePromoCode godoc
+// @Summary Remove promo code
+// @Description Remove the promo code from the basket.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param code path string true "Promo code"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/promo-codes/{code} [delete]
+func (h *Handler) RemovePromoCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REMOVE PROMO CODE")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	code := httprouter.ParamsFromContext(r.Context()).ByName("code")
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.RemovePromoCode(ctx, userId, code)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// SetDestination godoc
+// @Summary Set shipping destination
+// @Description Set the country and region the basket is shipped to without choosing an address, e.g. to estimate tax. Tax of the basket is calculated by rates of the destination.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body tax.Destination true "Shipping destination"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/destination [put]
+func (h *Handler) SetDestination(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET BASKET DESTINATION")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input tax.Destination
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Country = strings.ToUpper(input.Country)
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.SetDestination(ctx, userId, &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// SetAddress godoc
+// @Summary Choose shipping address
+// @Description Choose the address of the user the basket is shipped to. Tax of the basket is calculated by rates of the address country and region.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body SetAddressDTO true "JSON input"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/address [put]
+func (h *Handler) SetAddress(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET BASKET ADDRESS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input SetAddressDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.SetAddress(ctx, userId, input.AddressId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// ListShippingQuotes godoc
+// @Summary Get shipping methods for basket
+// @Description Get costs of shipping the basket by active methods which ship it to the shipping destination of the basket or the default shipping address, cheapest first.
+// @Description Costs depend on the region, the weight of printed books and the basket total, shipping is free over the threshold of the rate.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} shipping.Quote
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/shipping-methods [get]
+func (h *Handler) ListShippingQuotes(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BASKET SHIPPING QUOTES")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	quotes, err := h.basketService.ShippingQuotes(ctx, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrNoShippingAddress):
+			response.BadRequest(w, err.Error(), "add an address or set the basket destination first")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, quotes)
+}
+
+// SetShippingMethod godoc
+// @Summary Choose shipping method
+// @Description Choose the method printed books of the basket are shipped by. The method must ship the basket to the shipping destination of the basket or the default shipping address. The shipping cost is added to the basket total.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body SetShippingMethodDTO true "JSON input"
+// @Success 200 {object} Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/shipping-method [put]
+func (h *Handler) SetShippingMethod(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET BASKET SHIPPING METHOD")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input SetShippingMethodDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.basketService.SetShippingMethod(ctx, userId, input.MethodId)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrNoShippingAddress):
+			response.BadRequest(w, err.Error(), "add an address or set the basket destination first")
+		case errors.Is(err, apperror.ErrShippingUnavailable):
+			response.Conflict(w, err.Error(), "choose another shipping method")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// Checkout godoc
+// @Summary Checkout basket
+// @Description Place the pending order from the basket. All books of the basket are reserved again for the checkout time. Order lines record the prices effective at checkout, promo codes giving a discount are redeemed with the order.
+// @Description The order is shipped to the address chosen for the basket or the default shipping address, and billed to the default billing address or the shipping one. The order keeps copies of the addresses.
+// @Description The order is placed in the currency of the basket with its current exchange rate, the tax of the shipping address and the cost of the chosen shipping method. A shipping method must be chosen if the basket has printed books.
+// @Description Ebooks need no stock and are not shipped. The order of ebooks only is billed to the default billing address or the default shipping one, and taxed by it.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 201 {object} order.Order
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/basket/checkout [post]
+func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CHECKOUT")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	order, err := h.basketService.Checkout(ctx, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrEmptyBasket):
+			response.BadRequest(w, err.Error(), "add books to the basket first")
+		case errors.Is(err, apperror.ErrNoShippingAddress):
+			response.BadRequest(w, err.Error(), "add an address or choose one for the basket first")
+		case errors.Is(err, apperror.ErrNoBillingAddress):
+			response.BadRequest(w, err.Error(), "add a billing or shipping address first")
+		case errors.Is(err, apperror.ErrNoShippingMethod):
+			response.BadRequest(w, err.Error(), "choose a shipping method for the basket first")
+		case errors.Is(err, apperror.ErrShippingUnavailable):
+			response.Conflict(w, err.Error(), "choose another shipping method")
+		case errors.Is(err, apperror.ErrInsufficientStock):
+			response.Conflict(w, err.Error(), "remove the book from the basket or reduce its count")
+		case errors.Is(err, apperror.ErrPromoInactive),
+			errors.Is(err, apperror.ErrPromoLimitReached):
+			response.Conflict(w, err.Error(), "remove the promo code from the basket")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, order)
+}
@@ -0,0 +1,169 @@
+package basket
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/promo"
+	"github.com/juicyluv/ReadyRead/internal/shipping"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/shopspring/decimal"
+)
+
+// Basket represents the current basket of the user.
+// A basket becomes an order on checkout, then the user gets a new one.
+// Total is the subtotal of items less the discount of applied promo codes,
+// plus the tax unless prices include it. The tax is calculated once the
+// shipping destination is set, it's the destination of the shipping address
+// if one is chosen. Total includes the cost of the chosen shipping method
+// once the basket has something to ship. All amounts are in the display
+// currency of the basket.
+type Basket struct {
+	Id               int64            `json:"id" example:"42"`
+	UserId           int64            `json:"userId" example:"7"`
+	Currency         string           `json:"currency" example:"EUR"`
+	AddressId        *int64           `json:"addressId,omitempty" example:"3"`
+	ShippingMethodId *int64           `json:"shippingMethodId,omitempty" example:"1"`
+	Destination      *tax.Destination `json:"destination,omitempty"`
+	PricesIncludeTax bool             `json:"pricesIncludeTax" example:"true"`
+	Items            []BasketItem     `json:"items"`
+	Discounts        []promo.Discount `json:"discounts"`
+	Subtotal         decimal.Decimal  `json:"subtotal" swaggertype:"string" example:"25.98"`
+	Discount         decimal.Decimal  `json:"discount" swaggertype:"string" example:"5.2"`
+	Tax              decimal.Decimal  `json:"tax" swaggertype:"string" example:"1.45"`
+	Shipping         *shipping.Quote  `json:"shipping,omitempty"`
+	Total            decimal.Decimal  `json:"total" swaggertype:"string" example:"20.78"`
+
+	// cur and rates are used to price the basket in its currency.
+	cur   *currency.Currency
+	rates *currency.Rates
+} // @name Basket
+
+// promoLines returns items of the basket to calculate promo code discounts for.
+func (b *Basket) promoLines() []promo.Line {
+	lines := make([]promo.Line, 0, len(b.Items))
+	for _, item := range b.Items {
+		lines = append(lines, promo.Line{
+			BookId:    item.BookId,
+			GenreIds:  item.GenreIds,
+			AuthorIds: item.AuthorIds,
+			UnitPrice: item.Price,
+			Quantity:  item.Count,
+		})
+	}
+	return lines
+}
+
+// taxLines returns items of the basket to calculate tax for.
+func (b *Basket) taxLines() []tax.Line {
+	lines := make([]tax.Line, 0, len(b.Items))
+	for _, item := range b.Items {
+		lines = append(lines, tax.Line{
+			Category: tax.CategoryOf(item.Format),
+			Amount:   item.Price.Mul(decimal.New(int64(item.Count), 0)).Sub(item.Discount),
+		})
+	}
+	return lines
+}
+
+// needsShipping reports whether the basket has printed books to ship.
+func (b *Basket) needsShipping() bool {
+	for _, item := range b.Items {
+		if item.Format != book.FormatEbook {
+			return true
+		}
+	}
+	return false
+}
+
+// parcel returns printed books of the basket to ship. Books without
+// weight in their metadata weigh defaultWeight grams.
+func (b *Basket) parcel(defaultWeight int32) shipping.Parcel {
+	var weight int64
+	for _, item := range b.Items {
+		if item.Format == book.FormatEbook {
+			continue
+		}
+		w := defaultWeight
+		if item.Weight != nil {
+			w = *item.Weight
+		}
+		weight += int64(w) * int64(item.Count)
+	}
+
+	return shipping.Parcel{
+		Weight: weight,
+		Total:  b.Subtotal.Sub(b.Discount),
+	}
+}
+
+// BasketItem represents copies of the book in the basket.
+// Price is the price to pay, it's lower than ListPrice while the book is on sale.
+// Discount is the part of promo code discounts taken off the item, and Tax
+// is the tax of the item after the discount at TaxRate percent.
+// ReservedUntil is missing if the reservation has expired,
+// then the copies are reserved again on checkout.
+// BookCurrency is the currency the book is priced in, Weight is the weight
+// of the book in grams if it's known.
+type BasketItem struct {
+	BookId        int64           `json:"bookId" example:"123"`
+	Title         string          `json:"title" example:"War and Peace"`
+	Price         decimal.Decimal `json:"price" swaggertype:"string" example:"11.49"`
+	ListPrice     decimal.Decimal `json:"listPrice" swaggertype:"string" example:"12.99"`
+	Count         int32           `json:"count" example:"2"`
+	Discount      decimal.Decimal `json:"discount" swaggertype:"string" example:"2.3"`
+	TaxRate       decimal.Decimal `json:"taxRate" swaggertype:"string" example:"7"`
+	Tax           decimal.Decimal `json:"tax" swaggertype:"string" example:"1.45"`
+	ReservedUntil *time.Time      `json:"reservedUntil,omitempty" example:"2022-03-01T12:15:00Z"`
+	BookCurrency  string          `json:"-"`
+	Format        string          `json:"-"`
+	Weight        *int32          `json:"-"`
+	GenreIds      []int16         `json:"-"`
+	AuthorIds     []int64         `json:"-"`
+} // @name BasketItem
+
+// SetAddressDTO is used to choose the shipping address of the basket.
+type SetAddressDTO struct {
+	AddressId int64 `json:"addressId" example:"3"`
+} // @name SetBasketAddressInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (i *SetAddressDTO) Validate() error {
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.AddressId, validation.Required, validation.Min(1)),
+	)
+}
+
+// SetShippingMethodDTO is used to choose the shipping method of the basket.
+type SetShippingMethodDTO struct {
+	MethodId int64 `json:"methodId" example:"1"`
+} // @name SetBasketShippingMethodInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (i *SetShippingMethodDTO) Validate() error {
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.MethodId, validation.Required, validation.Min(1)),
+	)
+}
+
+// SetItemDTO is used to set the number of copies of the book in the basket.
+type SetItemDTO struct {
+	UserId int64 `json:"-"`
+	BookId int64 `json:"-"`
+	Count  int32 `json:"count" example:"2"`
+} // @name SetBasketItemInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (i *SetItemDTO) Validate() error {
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.Count, validation.Required, validation.Min(1), validation.Max(100)),
+	)
+}
@@ -0,0 +1,362 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/address"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName           = "baskets"
+	itemsTableName      = "baskets_books"
+	promoCodesTableName = "baskets_promo_codes"
+)
+
+// Check whether db implements basket storage interface.
+var _ Storage = &db{}
+
+// db implements basket storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new basket storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts an empty basket of the user in the database.
+// Returns ErrNoRows if user doesn't exist, an error on failure
+// or the basket on success.
+func (d *db) Create(userId int64) (*Basket, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id) VALUES ($1) RETURNING id", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	basket := Basket{
+		UserId: userId,
+		Items:  make([]BasketItem, 0),
+	}
+
+	if err := d.conn.QueryRow(ctx, query, userId).Scan(&basket.Id); err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create basket query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return &basket, nil
+}
+
+// FindCurrent finds the latest basket of the user which hasn't become an order.
+// Items of the basket are not loaded.
+// Returns ErrNoRows if there is no such basket or an error on failure.
+func (d *db) FindCurrent(userId int64) (*Basket, error) {
+	query := fmt.Sprintf(`
+	SELECT id, user_id, address_id, shipping_method_id, country, region
+	FROM %s b
+	WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.basket_id = b.id)
+	ORDER BY id DESC
+	LIMIT 1`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	var basket Basket
+	var country, region *string
+	if err := d.conn.QueryRow(ctx, query, userId).Scan(&basket.Id, &basket.UserId, &basket.AddressId, &basket.ShippingMethodId, &country, &region); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find current basket query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if country != nil {
+		basket.Destination = &tax.Destination{Country: *country, Region: region}
+	}
+
+	return &basket, nil
+}
+
+// SetDestination sets the shipping destination of the basket, the chosen
+// shipping address is cleared. Returns ErrNoRows if the basket doesn't
+// exist or an error on failure.
+func (d *db) SetDestination(basketId int64, dest *tax.Destination) error {
+	query := fmt.Sprintf("UPDATE %s SET address_id = NULL, country = $2, region = $3 WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, basketId, dest.Country, dest.Region)
+	if err != nil {
+		err = fmt.Errorf("failed to execute set basket destination query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetAddress chooses the shipping address of the basket, the shipping
+// destination becomes the destination of the address. Returns ErrNoRows
+// if the basket or the address doesn't exist or an error on failure.
+func (d *db) SetAddress(basketId int64, address *address.Address) error {
+	query := fmt.Sprintf("UPDATE %s SET address_id = $2, country = $3, region = $4 WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, basketId, address.Id, address.Country, address.Region)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute set basket address query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetShippingMethod chooses the shipping method of the basket. Returns
+// ErrNoRows if the basket or the method doesn't exist or an error on failure.
+func (d *db) SetShippingMethod(basketId, methodId int64) error {
+	query := fmt.Sprintf("UPDATE %s SET shipping_method_id = $2 WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, basketId, methodId)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute set basket shipping method query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindItems finds items of the basket ordered by book title.
+// Items are returned with prices effective now in the currency of the
+// book, the format and weight of the book, the expiry of their active
+// reservation and genres and authors of books promo codes are scoped by.
+// Returns an error on failure.
+func (d *db) FindItems(basketId int64) ([]BasketItem, error) {
+	query := fmt.Sprintf(`
+	SELECT bb.book_id, b.title, COALESCE(book_sale_price(b.id, now()), l.price), l.price,
+		b.currency, b.format, b.weight_g, bb.count, r.expires_at,
+		ARRAY(SELECT genre_id FROM book_genres WHERE book_id = b.id),
+		ARRAY(SELECT author_id FROM book_authors WHERE book_id = b.id AND role = 'author')
+	FROM %s bb
+	JOIN books b ON b.id = bb.book_id
+	CROSS JOIN LATERAL (SELECT COALESCE(book_list_price(b.id, now()), b.price) AS price) l
+	LEFT JOIN stock_reservations r
+		ON r.basket_id = bb.basket_id AND r.book_id = bb.book_id AND r.expires_at > now()
+	WHERE bb.basket_id = $1
+	ORDER BY b.title, b.id`, itemsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, basketId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find basket items query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]BasketItem, 0)
+	for rows.Next() {
+		var item BasketItem
+		err := rows.Scan(
+			&item.BookId,
+			&item.Title,
+			&item.Price,
+			&item.ListPrice,
+			&item.BookCurrency,
+			&item.Format,
+			&item.Weight,
+			&item.Count,
+			&item.ReservedUntil,
+			&item.GenreIds,
+			&item.AuthorIds,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan basket item: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read basket items: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetItem sets the number of copies of the book in the basket.
+// Returns ErrNoRows if the book doesn't exist or an error on failure.
+func (d *db) SetItem(basketId, bookId int64, count int32) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (basket_id, book_id, count)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (basket_id, book_id) DO UPDATE SET count = EXCLUDED.count`, itemsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, basketId, bookId, count); err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute set basket item query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteItem removes the book from the basket.
+// Returns ErrNoRows if the book is not in the basket or an error on failure.
+func (d *db) DeleteItem(basketId, bookId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE basket_id = $1 AND book_id = $2", itemsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, basketId, bookId)
+	if err != nil {
+		return fmt.Errorf("failed to delete basket item: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindPromoCodeIds finds ids of promo codes applied to the basket
+// in the order they were applied. Returns an error on failure.
+func (d *db) FindPromoCodeIds(basketId int64) ([]int64, error) {
+	query := fmt.Sprintf(`
+	SELECT promo_code_id
+	FROM %s
+	WHERE basket_id = $1
+	ORDER BY added_at, promo_code_id`, promoCodesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, basketId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find basket promo codes query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			err = fmt.Errorf("failed to scan basket promo code: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read basket promo codes: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// AddPromoCode applies the promo code to the basket. Applying the code
+// again does nothing. Returns ErrNoRows if the code doesn't exist
+// or an error on failure.
+func (d *db) AddPromoCode(basketId, promoCodeId int64) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (basket_id, promo_code_id)
+	VALUES ($1, $2)
+	ON CONFLICT (basket_id, promo_code_id) DO NOTHING`, promoCodesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, basketId, promoCodeId); err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute add basket promo code query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePromoCode removes the promo code from the basket.
+// Returns ErrNoRows if the code is not applied or an error on failure.
+func (d *db) DeletePromoCode(basketId, promoCodeId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE basket_id = $1 AND promo_code_id = $2", promoCodesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, basketId, promoCodeId)
+	if err != nil {
+		return fmt.Errorf("failed to delete basket promo code: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,745 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/address"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/inventory"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/internal/promo"
+	"github.com/juicyluv/ReadyRead/internal/shipping"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/internal/user"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+// Service describes basket service functionality.
+type Service interface {
+	Get(ctx context.Context, userId int64) (*Basket, error)
+	SetItem(ctx context.Context, input *SetItemDTO) (*Basket, error)
+	RemoveItem(ctx context.Context, userId, bookId int64) (*Basket, error)
+	ApplyPromoCode(ctx context.Context, userId int64, code string) (*Basket, error)
+	RemovePromoCode(ctx context.Context, userId int64, code string) (*Basket, error)
+	SetDestination(ctx context.Context, userId int64, dest *tax.Destination) (*Basket, error)
+	SetAddress(ctx context.Context, userId, addressId int64) (*Basket, error)
+	SetShippingMethod(ctx context.Context, userId, methodId int64) (*Basket, error)
+	ShippingQuotes(ctx context.Context, userId int64) ([]*shipping.Quote, error)
+	Checkout(ctx context.Context, userId int64) (*order.Order, error)
+}
+
+type service struct {
+	logger           logger.Logger
+	storage          Storage
+	inventoryService inventory.Service
+	orderService     order.Service
+	promoService     promo.Service
+	addressService   address.Service
+	currencyService  currency.Service
+	taxService       tax.Service
+	shippingService  shipping.Service
+	userService      user.Service
+	reservationTTL   time.Duration
+	checkoutTTL      time.Duration
+	defaultWeight    int32
+}
+
+// NewService returns a new instance that implements Service interface.
+// Books added to the basket are reserved for reservationTTL,
+// and for checkoutTTL after the checkout is started. Books without weight
+// in their metadata are shipped as weighing defaultWeight grams.
+func NewService(
+	storage Storage,
+	inventoryService inventory.Service,
+	orderService order.Service,
+	promoService promo.Service,
+	addressService address.Service,
+	currencyService currency.Service,
+	taxService tax.Service,
+	shippingService shipping.Service,
+	userService user.Service,
+	reservationTTL, checkoutTTL time.Duration,
+	defaultWeight int32,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:           logger,
+		storage:          storage,
+		inventoryService: inventoryService,
+		orderService:     orderService,
+		promoService:     promoService,
+		addressService:   addressService,
+		currencyService:  currencyService,
+		taxService:       taxService,
+		shippingService:  shippingService,
+		userService:      userService,
+		reservationTTL:   reservationTTL,
+		checkoutTTL:      checkoutTTL,
+		defaultWeight:    defaultWeight,
+	}
+}
+
+// Get returns the current basket of the user with its items.
+// A new basket is created if the user doesn't have one. The basket is
+// priced in the first currency accepted by the client which has an
+// exchange rate, the currency preferred by the user or the base one.
+func (s *service) Get(ctx context.Context, userId int64) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// SetItem reserves the copies of the book and puts them in the basket.
+// Ebooks need no stock, so they are not reserved. If the basket can't be
+// updated, the reservation is restored as it was before. Returns
+// ErrInsufficientStock if there are not enough available copies.
+func (s *service) SetItem(ctx context.Context, input *SetItemDTO) (*Basket, error) {
+	basket, err := s.current(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.inventoryService.Reserve(ctx, basket.Id, input.BookId, input.Count, s.reservationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.SetItem(basket.Id, input.BookId, input.Count); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set basket item: %v", err)
+		}
+		s.restoreReservation(ctx, basket.Id, input.BookId)
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// RemoveItem removes the book from the basket and releases its reservation.
+// Returns ErrNoRows if the book is not in the basket.
+func (s *service) RemoveItem(ctx context.Context, userId, bookId int64) (*Basket, error) {
+	basket, err := s.storage.FindCurrent(userId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find basket: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.storage.DeleteItem(basket.Id, bookId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to remove basket item: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.inventoryService.Release(ctx, basket.Id, bookId); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// ApplyPromoCode applies the promo code to the basket. Returns ErrNoRows
+// if the code doesn't exist, ErrPromoInactive, ErrPromoLimitReached or
+// ErrPromoNotStackable if the code can't be used and ErrPromoNotApplicable
+// if it gives no discount for the basket.
+func (s *service) ApplyPromoCode(ctx context.Context, userId int64, code string) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.promoService.Redeemable(ctx, code, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(basket.Discounts))
+	for _, d := range basket.Discounts {
+		if d.PromoCodeId == p.Id {
+			return basket, nil
+		}
+		ids = append(ids, d.PromoCodeId)
+	}
+
+	applied, err := s.promoService.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := promo.CheckStacking(applied, p); err != nil {
+		return nil, err
+	}
+
+	codes, err := promo.Convert(append(applied, p), basket.rates, basket.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	discounts, _ := promo.Calculate(codes, basket.promoLines(), time.Now(), basket.cur)
+	if d := discounts[len(discounts)-1]; d.Amount.IsZero() {
+		return nil, fmt.Errorf("%w: %s", apperror.ErrPromoNotApplicable, d.Reason)
+	}
+
+	if err := s.storage.AddPromoCode(basket.Id, p.Id); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to add basket promo code: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// RemovePromoCode removes the promo code from the basket.
+// Returns ErrNoRows if the code is not applied to the basket.
+func (s *service) RemovePromoCode(ctx context.Context, userId int64, code string) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	var promoCodeId int64
+	for _, d := range basket.Discounts {
+		if strings.EqualFold(d.Code, code) {
+			promoCodeId = d.PromoCodeId
+		}
+	}
+	if promoCodeId == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	if err := s.storage.DeletePromoCode(basket.Id, promoCodeId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to remove basket promo code: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// SetDestination sets the shipping destination of the basket without
+// choosing an address, tax of the basket is calculated by its rates.
+func (s *service) SetDestination(ctx context.Context, userId int64, dest *tax.Destination) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.SetDestination(basket.Id, dest); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set basket destination: %v", err)
+		}
+		return nil, err
+	}
+	basket.AddressId = nil
+	basket.Destination = dest
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// SetAddress chooses the address of the user the basket is shipped to.
+// Returns ErrNoRows if the user has no such address.
+func (s *service) SetAddress(ctx context.Context, userId, addressId int64) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.addressService.GetById(ctx, userId, addressId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.SetAddress(basket.Id, a); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set basket address: %v", err)
+		}
+		return nil, err
+	}
+	basket.AddressId = &a.Id
+	basket.Destination = a.Destination()
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// SetShippingMethod chooses the method printed books of the basket are
+// shipped by. The method must have a rate for the shipping destination of
+// the basket or the default shipping address of the user. Returns
+// ErrNoShippingAddress if there is no destination, ErrNoRows if the method
+// doesn't exist or is inactive and ErrShippingUnavailable if it doesn't
+// ship the basket to the destination.
+func (s *service) SetShippingMethod(ctx context.Context, userId, methodId int64) (*Basket, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.destination(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	_, err = s.shippingService.Quote(
+		ctx, methodId, basket.Destination, basket.parcel(s.defaultWeight), basket.rates, basket.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.SetShippingMethod(basket.Id, methodId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set basket shipping method: %v", err)
+		}
+		return nil, err
+	}
+	basket.ShippingMethodId = &methodId
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// ShippingQuotes returns costs of shipping the basket by methods which
+// ship it to the shipping destination of the basket or the default
+// shipping address of the user, cheapest first. Returns
+// ErrNoShippingAddress if there is no destination.
+func (s *service) ShippingQuotes(ctx context.Context, userId int64) ([]*shipping.Quote, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.destination(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return s.shippingService.QuoteAll(
+		ctx, basket.Destination, basket.parcel(s.defaultWeight), basket.rates, basket.Currency,
+	)
+}
+
+// Checkout starts the checkout of the basket. All printed books are
+// reserved again for checkoutTTL, so the reservations can't expire while
+// the user pays. The order is shipped to the address chosen for the basket
+// or the default shipping address of the user, and billed to the default
+// billing address or the shipping one. The order keeps copies of the
+// addresses. It's placed in the currency of the basket with its current
+// exchange rate, the tax of the shipping address and the cost of the
+// chosen shipping method. Orders of ebooks only are not shipped, they are
+// billed to the default billing or shipping address and taxed by it.
+// Returns ErrNoShippingAddress if there is no address to ship to,
+// ErrNoBillingAddress if there is no address to bill ebooks to,
+// ErrEmptyBasket if there are no items,
+// ErrNoShippingMethod if printed books are in the basket but no shipping
+// method is chosen, ErrShippingUnavailable if the method doesn't ship
+// them to the address anymore and ErrInsufficientStock if some book is
+// not available anymore.
+func (s *service) Checkout(ctx context.Context, userId int64) (*order.Order, error) {
+	basket, err := s.current(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	if len(basket.Items) == 0 {
+		return nil, apperror.ErrEmptyBasket
+	}
+
+	shipping, billing, err := s.addresses(ctx, basket)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are priced again with the tax of the address the order is
+	// shipped or billed to.
+	if shipping != nil {
+		basket.Destination = shipping.Destination()
+	} else {
+		basket.Destination = billing.Destination()
+	}
+
+	if err := s.loadItems(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	if basket.needsShipping() && basket.Shipping == nil {
+		if basket.ShippingMethodId == nil {
+			return nil, apperror.ErrNoShippingMethod
+		}
+		return nil, apperror.ErrShippingUnavailable
+	}
+
+	for _, item := range basket.Items {
+		_, err := s.inventoryService.Reserve(ctx, basket.Id, item.BookId, item.Count, s.checkoutTTL)
+		if err != nil {
+			if errors.Is(err, apperror.ErrInsufficientStock) {
+				return nil, fmt.Errorf("%w: %s", err, item.Title)
+			}
+			return nil, err
+		}
+	}
+
+	rate, _ := basket.rates.Rate(basket.Currency)
+	draft := order.Order{
+		UserId:           userId,
+		BasketId:         basket.Id,
+		Currency:         basket.Currency,
+		ExchangeRate:     rate,
+		Destination:      basket.Destination,
+		BillingAddress:   orderAddress(billing),
+		PricesIncludeTax: basket.PricesIncludeTax,
+		Discount:         basket.Discount,
+		Tax:              basket.Tax,
+		TotalPrice:       basket.Total,
+	}
+
+	if shipping != nil {
+		draft.ShippingAddress = orderAddress(shipping)
+	}
+
+	if basket.Shipping != nil {
+		draft.Shipping = &order.Shipping{
+			MethodId: &basket.Shipping.MethodId,
+			Method:   basket.Shipping.Name,
+			Kind:     basket.Shipping.Kind,
+			Cost:     basket.Shipping.Cost,
+		}
+	}
+
+	for _, item := range basket.Items {
+		bookId := item.BookId
+		draft.Items = append(draft.Items, order.OrderItem{
+			BookId:    &bookId,
+			Title:     item.Title,
+			Quantity:  item.Count,
+			ListPrice: item.ListPrice,
+			UnitPrice: item.Price,
+			Discount:  item.Discount,
+			TaxRate:   item.TaxRate,
+			Tax:       item.Tax,
+			Digital:   item.Format == book.FormatEbook,
+		})
+	}
+
+	for _, d := range basket.Discounts {
+		if d.Amount.IsZero() {
+			continue
+		}
+		promoCodeId := d.PromoCodeId
+		draft.Discounts = append(draft.Discounts, order.Discount{
+			PromoCodeId: &promoCodeId,
+			Code:        d.Code,
+			Amount:      d.Amount,
+		})
+	}
+
+	return s.orderService.Create(ctx, &draft)
+}
+
+// destination sets the shipping destination of the basket to the
+// destination of the default shipping address of the user if it's not set.
+// Returns ErrNoShippingAddress if there is no address to ship to.
+func (s *service) destination(ctx context.Context, basket *Basket) error {
+	if basket.Destination != nil {
+		return nil
+	}
+
+	a, err := s.addressService.GetDefault(ctx, basket.UserId, address.KindShipping)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return apperror.ErrNoShippingAddress
+		}
+		return err
+	}
+	basket.Destination = a.Destination()
+
+	return nil
+}
+
+// addresses returns the addresses the basket is shipped and billed to.
+// Baskets of ebooks only are not shipped, they are billed to the default
+// billing address or the default shipping one. Returns ErrNoShippingAddress
+// if there is no address to ship to and ErrNoBillingAddress if there is
+// no address to bill ebooks to.
+func (s *service) addresses(ctx context.Context, basket *Basket) (*address.Address, *address.Address, error) {
+	if !basket.needsShipping() {
+		billing, err := s.addressService.GetDefault(ctx, basket.UserId, address.KindBilling)
+		if errors.Is(err, apperror.ErrNoRows) {
+			billing, err = s.addressService.GetDefault(ctx, basket.UserId, address.KindShipping)
+		}
+		if err != nil {
+			if errors.Is(err, apperror.ErrNoRows) {
+				return nil, nil, apperror.ErrNoBillingAddress
+			}
+			return nil, nil, err
+		}
+		return nil, billing, nil
+	}
+
+	var shipping *address.Address
+	var err error
+	if basket.AddressId != nil {
+		shipping, err = s.addressService.GetById(ctx, basket.UserId, *basket.AddressId)
+	} else {
+		shipping, err = s.addressService.GetDefault(ctx, basket.UserId, address.KindShipping)
+	}
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return nil, nil, apperror.ErrNoShippingAddress
+		}
+		return nil, nil, err
+	}
+
+	billing, err := s.addressService.GetDefault(ctx, basket.UserId, address.KindBilling)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			return nil, nil, err
+		}
+		billing = shipping
+	}
+
+	return shipping, billing, nil
+}
+
+// orderAddress returns the copy of the address kept by the order.
+func orderAddress(a *address.Address) *order.Address {
+	return &order.Address{
+		Recipient:  a.Recipient,
+		Lines:      a.Lines,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+	}
+}
+
+// restoreReservation reserves the book for the basket as many copies as the
+// basket holds, or releases the reservation if the book is not in the basket.
+// Failures are logged, expired reservations are released by the sweeper anyway.
+func (s *service) restoreReservation(ctx context.Context, basketId, bookId int64) {
+	items, err := s.storage.FindItems(basketId)
+	if err != nil {
+		s.logger.Warnf("cannot find basket items: %v", err)
+		return
+	}
+
+	for _, item := range items {
+		if item.BookId == bookId {
+			if _, err := s.inventoryService.Reserve(ctx, basketId, bookId, item.Count, s.reservationTTL); err != nil {
+				s.logger.Warnf("cannot restore reservation of book %d: %v", bookId, err)
+			}
+			return
+		}
+	}
+
+	if err := s.inventoryService.Release(ctx, basketId, bookId); err != nil {
+		s.logger.Warnf("cannot release reservation of book %d: %v", bookId, err)
+	}
+}
+
+// current returns the current basket of the user, creating it if needed.
+func (s *service) current(userId int64) (*Basket, error) {
+	basket, err := s.storage.FindCurrent(userId)
+	if err == nil {
+		return basket, nil
+	}
+
+	if !errors.Is(err, apperror.ErrNoRows) {
+		s.logger.Warnf("cannot find basket: %v", err)
+		return nil, err
+	}
+
+	basket, err = s.storage.Create(userId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to create basket: %v", err)
+		}
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// loadItems loads items and promo codes of the basket, converts prices
+// to the currency of the basket and calculates its discounts, tax, shipping
+// and total. The tax and shipping are calculated if the shipping destination
+// of the basket is set. The shipping is left out if the chosen method
+// doesn't ship the basket to the destination anymore.
+func (s *service) loadItems(ctx context.Context, basket *Basket) error {
+	items, err := s.storage.FindItems(basket.Id)
+	if err != nil {
+		s.logger.Warnf("cannot find basket items: %v", err)
+		return err
+	}
+
+	ids, err := s.storage.FindPromoCodeIds(basket.Id)
+	if err != nil {
+		s.logger.Warnf("cannot find basket promo codes: %v", err)
+		return err
+	}
+
+	codes, err := s.promoService.GetByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	rates, err := s.currencyService.Rates(ctx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := s.currency(ctx, basket.UserId, rates)
+	if err != nil {
+		return err
+	}
+
+	subtotal := decimal.Zero
+	for i := range items {
+		item := &items[i]
+		if item.Price, err = rates.Convert(item.Price, item.BookCurrency, cur.Code); err != nil {
+			return err
+		}
+		if item.ListPrice, err = rates.Convert(item.ListPrice, item.BookCurrency, cur.Code); err != nil {
+			return err
+		}
+		subtotal = subtotal.Add(item.Price.Mul(decimal.New(int64(item.Count), 0)))
+	}
+
+	codes, err = promo.Convert(codes, rates, cur.Code)
+	if err != nil {
+		return err
+	}
+
+	basket.Currency = cur.Code
+	basket.Items = items
+	basket.cur = cur
+	basket.rates = rates
+	basket.PricesIncludeTax = s.taxService.PricesIncludeTax()
+
+	var lineDiscounts []decimal.Decimal
+	basket.Discounts, lineDiscounts = promo.Calculate(codes, basket.promoLines(), time.Now(), cur)
+	for i := range items {
+		items[i].Discount = lineDiscounts[i]
+	}
+
+	discount := decimal.Zero
+	for _, d := range basket.Discounts {
+		discount = discount.Add(d.Amount)
+	}
+
+	taxTotal := decimal.Zero
+	if basket.Destination != nil {
+		taxes, err := s.taxService.Calculate(ctx, basket.Destination, basket.taxLines(), cur)
+		if err != nil {
+			return err
+		}
+		for i, t := range taxes {
+			items[i].TaxRate = t.Rate
+			items[i].Tax = t.Amount
+			taxTotal = taxTotal.Add(t.Amount)
+		}
+	}
+
+	basket.Subtotal = subtotal
+	basket.Discount = discount
+	basket.Tax = taxTotal
+	basket.Total = subtotal.Sub(discount)
+	if !basket.PricesIncludeTax {
+		basket.Total = basket.Total.Add(taxTotal)
+	}
+
+	basket.Shipping = nil
+	if basket.ShippingMethodId != nil && basket.Destination != nil && basket.needsShipping() {
+		quote, err := s.shippingService.Quote(
+			ctx, *basket.ShippingMethodId, basket.Destination, basket.parcel(s.defaultWeight), rates, cur.Code,
+		)
+		if err != nil && !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrShippingUnavailable) {
+			return err
+		}
+		if quote != nil {
+			basket.Shipping = quote
+			basket.Total = basket.Total.Add(quote.Cost)
+		}
+	}
+
+	return nil
+}
+
+// currency returns the currency the basket of the user is priced in: the
+// first currency accepted by the client, the currency preferred by the
+// user or the base one, whichever has an exchange rate first.
+func (s *service) currency(ctx context.Context, userId int64, rates *currency.Rates) (*currency.Currency, error) {
+	codes := currency.FromContext(ctx)
+
+	u, err := s.userService.GetById(ctx, userId)
+	if err != nil && !errors.Is(err, apperror.ErrNoRows) {
+		return nil, err
+	}
+	if u != nil && u.Currency != nil {
+		codes = append(codes, *u.Currency)
+	}
+
+	return rates.Select(codes...), nil
+}
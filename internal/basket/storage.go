@@ -0,0 +1,21 @@
+package basket
+
+import (
+	"github.com/juicyluv/ReadyRead/internal/address"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+)
+
+// Storage describes a basket storage functionality.
+type Storage interface {
+	Create(userId int64) (*Basket, error)
+	FindCurrent(userId int64) (*Basket, error)
+	SetDestination(basketId int64, dest *tax.Destination) error
+	SetAddress(basketId int64, address *address.Address) error
+	SetShippingMethod(basketId, methodId int64) error
+	FindItems(basketId int64) ([]BasketItem, error)
+	SetItem(basketId, bookId int64, count int32) error
+	DeleteItem(basketId, bookId int64) error
+	FindPromoCodeIds(basketId int64) ([]int64, error)
+	AddPromoCode(basketId, promoCodeId int64) error
+	DeletePromoCode(basketId, promoCodeId int64) error
+}
@@ -0,0 +1,268 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3Service is a service name used in the request signature.
+	s3Service = "s3"
+
+	// signingAlgorithm is an algorithm of AWS Signature Version 4.
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+
+	amzDateLayout   = "20060102T150405Z"
+	shortDateLayout = "20060102"
+)
+
+// S3Config describes a connection to S3-compatible storage.
+type S3Config struct {
+	// Endpoint is a storage URL, e.g. https://s3.eu-central-1.amazonaws.com
+	// or http://localhost:9000 for a local MinIO instance.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is a base URL objects are served from.
+	// Defaults to the bucket URL.
+	PublicURL string
+}
+
+// Check whether s3Store implements blob store interface.
+var _ Store = &s3Store{}
+
+// s3Store implements blob store interface on top of S3-compatible storage.
+// Requests use path-style addressing and are signed with AWS Signature Version 4.
+type s3Store struct {
+	cfg       S3Config
+	bucketURL *url.URL
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Store returns a new blob store which keeps objects in the S3 bucket.
+func NewS3Store(cfg S3Config) (Store, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket must be provided")
+	}
+
+	bucketURL := *endpoint
+	bucketURL.Path = endpoint.Path + "/" + cfg.Bucket
+
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = bucketURL.String()
+	}
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+
+	return &s3Store{
+		cfg:       cfg,
+		bucketURL: &bucketURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+// Put uploads the object with PUT Object request.
+func (s *s3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data, contentType)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusOK)
+}
+
+// Get downloads the object with GET Object request.
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read s3 object: %v", err)
+	}
+
+	return data, nil
+}
+
+// Open starts downloading the object with GET Object request
+// and returns the response body.
+func (s *s3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 responded with status %d: %s", resp.StatusCode, message)
+	}
+}
+
+// Delete removes the object with DELETE Object request.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// URL returns the object URL under the public URL.
+func (s *s3Store) URL(key string) string {
+	return s.cfg.PublicURL + "/" + encodePath(key)
+}
+
+// newRequest creates a new signed request to the object with the given key.
+func (s *s3Store) newRequest(ctx context.Context, method, key string, body []byte, contentType string) (*http.Request, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return nil, fmt.Errorf("invalid object key %q", key)
+	}
+
+	objectURL := *s.bucketURL
+	objectURL.Path = s.bucketURL.Path + "/" + key
+	objectURL.RawPath = encodePath(objectURL.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create s3 request: %v", err)
+	}
+	req.ContentLength = int64(len(body))
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, body)
+
+	return req, nil
+}
+
+// do sends the request. Returns an error if response status
+// is not one of the expected statuses.
+func (s *s3Store) do(req *http.Request, expected ...int) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			io.Copy(ioutil.Discard, resp.Body)
+			return nil
+		}
+	}
+
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 responded with status %d: %s", resp.StatusCode, message)
+}
+
+// sign adds AWS Signature Version 4 headers to the request.
+func (s *s3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateLayout)
+	shortDate := now.Format(shortDateLayout)
+	payloadHash := hashHex(body)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headerNames := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		headerNames = append(headerNames, strings.ToLower(name))
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name)
+		canonicalHeaders.WriteByte(':')
+		canonicalHeaders.WriteString(strings.TrimSpace(req.Header.Get(name)))
+		canonicalHeaders.WriteByte('\n')
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{shortDate, s.cfg.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), shortDate)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, s.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+// encodePath escapes every path segment as required by S3 signing rules.
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,28 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when the object doesn't exist in the store.
+var ErrNotFound = errors.New("object not found")
+
+// Store describes a blob storage functionality.
+// Objects are identified by slash-separated keys, e.g. "books/1/cover/original.jpg".
+type Store interface {
+	// Put saves the object under the given key, replacing existing one.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get reads the object with the given key.
+	// Returns ErrNotFound if the object doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Open opens the object with the given key for reading, the caller
+	// must close it. Returns ErrNotFound if the object doesn't exist.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object with the given key.
+	// Deleting an object that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns a public URL of the object with the given key.
+	URL(key string) string
+}
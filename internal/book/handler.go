@@ -0,0 +1,475 @@
+package book
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/isbn"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	booksURL        = "/api/books"
+	bookURL         = "/api/books/:id"
+	bookEditionsURL = "/api/books/:id/editions"
+	bookCoverURL    = "/api/books/:id/cover"
+	isbnURL         = "/api/isbn/:isbn"
+)
+
+// Handler handles requests specified to book service.
+type Handler struct {
+	logger        logger.Logger
+	bookService   Service
+	maxUploadSize int64
+}
+
+// NewHandler returns a new book Handler instance.
+// Uploaded images are limited to maxUploadSize bytes.
+func NewHandler(logger logger.Logger, bookService Service, maxUploadSize int64) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		bookService:   bookService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, bookURL, h.GetBook)
+	router.HandlerFunc(http.MethodGet, booksURL, h.ListBooks)
+	router.HandlerFunc(http.MethodGet, bookEditionsURL, h.ListBookEditions)
+	router.HandlerFunc(http.MethodGet, isbnURL, h.GetBookByISBN)
+	router.HandlerFunc(http.MethodPost, booksURL, h.CreateBook)
+	router.HandlerFunc(http.MethodPut, bookURL, h.UpdateBook)
+	router.HandlerFunc(http.MethodDelete, bookURL, h.DeleteBook)
+	router.HandlerFunc(http.MethodPut, bookCoverURL, h.UploadBookCover)
+	router.HandlerFunc(http.MethodDelete, bookCoverURL, h.DeleteBookCover)
+}
+
+// GetBook godoc
+// @Summary Show book information
+// @Description Get book by id.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Book
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id} [get]
+func (h *Handler) GetBook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET BOOK")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	book, err := h.bookService.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, book)
+}
+
+// GetBookByISBN godoc
+// @Summary Show book information by ISBN
+// @Description Get book by ISBN-10 or ISBN-13. Hyphens are allowed.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param isbn path string true "Book ISBN"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Book
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /isbn/{isbn} [get]
+func (h *Handler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET BOOK BY ISBN")
+
+	number := httprouter.ParamsFromContext(r.Context()).ByName("isbn")
+	if !isbn.IsValid(number) {
+		response.BadRequest(w, "invalid isbn", "isbn must be a valid ISBN-10 or ISBN-13")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	book, err := h.bookService.GetByISBN(ctx, number)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, book)
+}
+
+// ListBookEditions godoc
+// @Summary List book editions
+// @Description Get all editions of the same work as the book with specified id.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} Book
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/editions [get]
+func (h *Handler) ListBookEditions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BOOK EDITIONS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	books, err := h.bookService.GetEditions(ctx, id, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, books)
+}
+
+// ListBooks godoc
+// @Summary List books
+// @Description Get catalog books filtered by title, author, genre and language.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param search query string false "Part of the book title"
+// @Param authorId query int64 false "Author id"
+// @Param genreId query int false "Genre id"
+// @Param languageId query int false "Language id"
+// @Param workId query int64 false "Work id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} Book
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books [get]
+func (h *Handler) ListBooks(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BOOKS")
+
+	filter, err := ReadFilter(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	books, err := h.bookService.GetAll(ctx, filter)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, books)
+}
+
+// CreateBook godoc
+// @Summary Create book
+// @Description Insert book with its contributors and genres in database.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param input body CreateBookDTO true "JSON input"
+// @Success 201 {object} Book
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books [post]
+func (h *Handler) CreateBook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE BOOK")
+
+	var input CreateBookDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	book, err := h.bookService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidReference) {
+			response.BadRequest(w, err.Error(), "check work, author, genre, language and publisher ids and currency")
+			return
+		}
+		if errors.Is(err, apperror.ErrISBNTaken) {
+			response.Conflict(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, fmt.Sprintf("cannot create book: %v", err), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, book)
+}
+
+// UpdateBook godoc
+// @Summary Update book
+// @Description Update book with specified id. Contributors and genres are replaced with given ones.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body UpdateBookDTO true "JSON input"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id} [put]
+func (h *Handler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE BOOK")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input UpdateBookDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), "please, fix your request body")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	input.Id = id
+
+	err = h.bookService.Update(r.Context(), &input)
+	if err != nil {
+		switch err {
+		case apperror.ErrNoRows:
+			response.NotFound(w)
+		case apperror.ErrInvalidReference:
+			response.BadRequest(w, err.Error(), "check work, author, genre, language and publisher ids")
+		case apperror.ErrISBNTaken:
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteBook godoc
+// @Summary Delete book
+// @Description Delete book with specified id.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Success 200
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id} [delete]
+func (h *Handler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE BOOK")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	err = h.bookService.Delete(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "something went wrong on the server side")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UploadBookCover godoc
+// @Summary Upload book cover
+// @Description Upload JPEG, PNG or GIF image as the book cover. Medium and thumbnail
+// @Description variants are generated from it. The previous cover is removed.
+// @Tags books
+// @Accept mpfd
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param image formData file true "Cover image"
+// @Success 200 {object} media.Image
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/cover [put]
+func (h *Handler) UploadBookCover(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPLOAD BOOK COVER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	data, err := handler.ReadImage(w, r, h.maxUploadSize)
+	if err != nil {
+		if errors.Is(err, handler.ErrUploadTooLarge) {
+			response.TooLarge(w, err.Error(), fmt.Sprintf("maximum size is %d bytes", h.maxUploadSize))
+			return
+		}
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	cover, err := h.bookService.SetCover(r.Context(), id, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, media.ErrUnsupportedType):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, media.ErrTooLarge):
+			response.TooLarge(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, cover)
+}
+
+// DeleteBookCover godoc
+// @Summary Delete book cover
+// @Description Delete cover of the book with specified id.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Success 200
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/cover [delete]
+func (h *Handler) DeleteBookCover(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE BOOK COVER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	err = h.bookService.DeleteCover(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReadFilter reads catalog filter and pagination from query parameters.
+// Returns an error if some of the parameters are invalid.
+func ReadFilter(r *http.Request) (*Filter, error) {
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.URL.Query()
+
+	filter := Filter{
+		Search: query.Get("search"),
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	}
+
+	if authorId := query.Get("authorId"); authorId != "" {
+		id, err := strconv.ParseInt(authorId, 10, 64)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("authorId must have type int64")
+		}
+		filter.AuthorId = &id
+	}
+
+	if genreId := query.Get("genreId"); genreId != "" {
+		id, err := strconv.ParseInt(genreId, 10, 16)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("genreId must have type int16")
+		}
+		genre := int16(id)
+		filter.GenreId = &genre
+	}
+
+	if languageId := query.Get("languageId"); languageId != "" {
+		id, err := strconv.ParseInt(languageId, 10, 16)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("languageId must have type int16")
+		}
+		language := int16(id)
+		filter.LanguageId = &language
+	}
+
+	if workId := query.Get("workId"); workId != "" {
+		id, err := strconv.ParseInt(workId, 10, 64)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("workId must have type int64")
+		}
+		filter.WorkId = &id
+	}
+
+	return &filter, nil
+}
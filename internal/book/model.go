@@ -0,0 +1,306 @@
+package book
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RoleAuthor is a role of the person who wrote the book.
+	RoleAuthor = "author"
+	// RoleTranslator is a role of the person who translated the book.
+	RoleTranslator = "translator"
+	// RoleIllustrator is a role of the person who illustrated the book.
+	RoleIllustrator = "illustrator"
+	// RoleEditor is a role of the person who edited the book.
+	RoleEditor = "editor"
+
+	// FormatHardcover is a format of the book in hard cover.
+	FormatHardcover = "hardcover"
+	// FormatPaperback is a format of the book in soft cover.
+	FormatPaperback = "paperback"
+	// FormatEbook is a format of the electronic book.
+	FormatEbook = "ebook"
+
+	// dateLayout is a layout of the publication date.
+	dateLayout = "2006-01-02"
+)
+
+// Book represents the book model.
+// Every book is an edition of some work. Editions of the same work
+// share the workId. Count is the number of copies on hand, it's cached
+// from the stock ledger and changed by stock movements only. Available is
+// the number of copies which are not reserved and can be sold. Price is the
+// current list price, SalePrice is set while the book is on sale. Prices are
+// in the currency of the book unless another one is requested.
+// PriceNotConverted is set if the prices are left in the currency of the
+// book, because it has no exchange rate. CreatedAt is when the book was
+// added to the catalog.
+type Book struct {
+	Id                int64            `json:"id" example:"123"`
+	WorkId            int64            `json:"workId" example:"100"`
+	Title             string           `json:"title" example:"War and Peace"`
+	Description       string           `json:"description" example:"Novel about the French invasion of Russia."`
+	Year              *int16           `json:"year,omitempty" example:"1869"`
+	Price             decimal.Decimal  `json:"price" swaggertype:"string" example:"12.99"`
+	SalePrice         *decimal.Decimal `json:"salePrice,omitempty" swaggertype:"string" example:"9.99"`
+	Currency          string           `json:"currency" example:"USD"`
+	PriceNotConverted bool             `json:"priceNotConverted,omitempty" example:"false"`
+	SaleEndsAt        *time.Time       `json:"saleEndsAt,omitempty" example:"2022-03-31T00:00:00Z"`
+	PageCount         *int16           `json:"pageCount,omitempty" example:"1225"`
+	Count             int32            `json:"count" example:"10"`
+	Available         int32            `json:"available" example:"8"`
+	ISBN13            *string          `json:"isbn13,omitempty" example:"9780140447934"`
+	ISBN10            *string          `json:"isbn10,omitempty" example:"0140447938"`
+	Format            string           `json:"format" example:"paperback"`
+	Edition           *string          `json:"edition,omitempty" example:"Penguin Classics"`
+	PublicationDate   *string          `json:"publicationDate,omitempty" example:"2007-10-30"`
+	WidthMm           *int16           `json:"widthMm,omitempty" example:"129"`
+	HeightMm          *int16           `json:"heightMm,omitempty" example:"198"`
+	ThicknessMm       *int16           `json:"thicknessMm,omitempty" example:"72"`
+	WeightGrams       *int32           `json:"weightGrams,omitempty" example:"1040"`
+	Cover             *media.Image     `json:"cover,omitempty"`
+	Publisher         *BookPublisher   `json:"publisher,omitempty"`
+	Authors           []BookAuthor     `json:"authors"`
+	Genres            []BookGenre      `json:"genres"`
+	Language          *BookLanguage    `json:"language"`
+	Rating            BookRating       `json:"rating"`
+	CreatedAt         time.Time        `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+} // @name Book
+
+// BookAuthor represents a contributor of the book.
+type BookAuthor struct {
+	Id      int64  `json:"id" example:"123"`
+	Name    string `json:"name" example:"Lev"`
+	Surname string `json:"surname" example:"Tolstoy"`
+	Role    string `json:"role" example:"author"`
+} // @name BookAuthor
+
+// BookGenre represents a genre of the book.
+type BookGenre struct {
+	Id    int16  `json:"id" example:"12"`
+	Genre string `json:"genre" example:"novel"`
+} // @name BookGenre
+
+// BookPublisher represents a publisher of the book.
+type BookPublisher struct {
+	Id   int64  `json:"id" example:"1"`
+	Name string `json:"name" example:"Penguin Books"`
+} // @name BookPublisher
+
+// BookRating is the rating of the book by its published reviews.
+// Average is zero if the book has no reviews.
+type BookRating struct {
+	Average      decimal.Decimal    `json:"average" swaggertype:"string" example:"4.25"`
+	Count        int32              `json:"count" example:"4"`
+	Distribution RatingDistribution `json:"distribution"`
+} // @name BookRating
+
+// RatingDistribution is the number of reviews by stars.
+type RatingDistribution struct {
+	One   int32 `json:"1" example:"0"`
+	Two   int32 `json:"2" example:"0"`
+	Three int32 `json:"3" example:"1"`
+	Four  int32 `json:"4" example:"1"`
+	Five  int32 `json:"5" example:"2"`
+} // @name RatingDistribution
+
+// BookLanguage represents a language of the book.
+type BookLanguage struct {
+	Id       int16  `json:"id" example:"1"`
+	Language string `json:"language" example:"ru"`
+} // @name BookLanguage
+
+// Filter is used to filter and paginate books list.
+// Ids limits the list to books with given ids if it's not nil.
+type Filter struct {
+	Search     string
+	AuthorId   *int64
+	GenreId    *int16
+	LanguageId *int16
+	WorkId     *int64
+	Ids        []int64
+	Limit      int
+	Offset     int
+}
+
+// ContributorDTO is used to set a contributor of the book.
+// Contributors are ordered as they are listed.
+type ContributorDTO struct {
+	AuthorId int64  `json:"authorId" example:"123"`
+	Role     string `json:"role" example:"author"`
+} // @name ContributorInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c ContributorDTO) Validate() error {
+	return validation.ValidateStruct(
+		&c,
+		validation.Field(&c.AuthorId, validation.Required, validation.Min(1)),
+		validation.Field(
+			&c.Role,
+			validation.Required,
+			validation.In(RoleAuthor, RoleTranslator, RoleIllustrator, RoleEditor),
+		),
+	)
+}
+
+// CreateBookDTO is used to create book.
+// Price is the initial list price in the currency of the book, which is
+// the base currency if not specified. Count is the initial stock,
+// it's recorded as a stock receipt.
+type CreateBookDTO struct {
+	WorkId          *int64           `json:"workId,omitempty" example:"100"`
+	Title           string           `json:"title" example:"War and Peace"`
+	Description     string           `json:"description" example:"Novel about the French invasion of Russia."`
+	Year            *int16           `json:"year,omitempty" example:"1869"`
+	Price           decimal.Decimal  `json:"price" swaggertype:"string" example:"12.99"`
+	Currency        *string          `json:"currency,omitempty" example:"USD"`
+	PageCount       *int16           `json:"pageCount,omitempty" example:"1225"`
+	Count           int32            `json:"count" example:"10"`
+	ISBN            *string          `json:"isbn,omitempty" example:"978-0-14-044793-4"`
+	PublisherId     *int64           `json:"publisherId,omitempty" example:"1"`
+	Format          string           `json:"format" example:"paperback"`
+	Edition         *string          `json:"edition,omitempty" example:"Penguin Classics"`
+	PublicationDate *string          `json:"publicationDate,omitempty" example:"2007-10-30"`
+	WidthMm         *int16           `json:"widthMm,omitempty" example:"129"`
+	HeightMm        *int16           `json:"heightMm,omitempty" example:"198"`
+	ThicknessMm     *int16           `json:"thicknessMm,omitempty" example:"72"`
+	WeightGrams     *int32           `json:"weightGrams,omitempty" example:"1040"`
+	Authors         []ContributorDTO `json:"authors"`
+	GenreIds        []int16          `json:"genreIds" example:"1,2"`
+	LanguageId      int16            `json:"languageId" example:"1"`
+} // @name CreateBookInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (b *CreateBookDTO) Validate() error {
+	err := validation.ValidateStruct(
+		b,
+		validation.Field(&b.Title, validation.RuneLength(1, 200), validation.Required),
+		validation.Field(&b.Description, validation.RuneLength(1, 5000), validation.Required),
+		validation.Field(&b.Year, validation.Min(1)),
+		validation.Field(&b.Price, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&b.Currency, validator.CurrencyCode),
+		validation.Field(&b.PageCount, validation.Min(1)),
+		validation.Field(&b.Count, validation.Min(0)),
+		validation.Field(&b.WorkId, validation.Min(1)),
+		validation.Field(&b.ISBN, validator.ISBN),
+		validation.Field(&b.PublisherId, validation.Min(1)),
+		validation.Field(
+			&b.Format,
+			validation.Required,
+			validation.In(FormatHardcover, FormatPaperback, FormatEbook),
+		),
+		validation.Field(&b.Edition, validation.RuneLength(1, 100)),
+		validation.Field(&b.PublicationDate, validation.Date(dateLayout)),
+		validation.Field(&b.WidthMm, validation.Min(1)),
+		validation.Field(&b.HeightMm, validation.Min(1)),
+		validation.Field(&b.ThicknessMm, validation.Min(1)),
+		validation.Field(&b.WeightGrams, validation.Min(1)),
+		validation.Field(&b.Authors, validation.Required),
+		validation.Field(&b.GenreIds, validation.Required, validation.Each(validation.Required, validation.Min(1))),
+		validation.Field(&b.LanguageId, validation.Required, validation.Min(1)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return validateRelations(b.Authors, b.GenreIds)
+}
+
+// UpdateBookDTO is used to update book record.
+type UpdateBookDTO struct {
+	Id              int64            `json:"-"`
+	WorkId          *int64           `json:"workId" example:"100"`
+	Title           string           `json:"title" example:"War and Peace"`
+	Description     string           `json:"description" example:"Novel about the French invasion of Russia."`
+	Year            *int16           `json:"year" example:"1869"`
+	PageCount       *int16           `json:"pageCount" example:"1225"`
+	ISBN            *string          `json:"isbn" example:"978-0-14-044793-4"`
+	PublisherId     *int64           `json:"publisherId" example:"1"`
+	Format          string           `json:"format" example:"paperback"`
+	Edition         *string          `json:"edition" example:"Penguin Classics"`
+	PublicationDate *string          `json:"publicationDate" example:"2007-10-30"`
+	WidthMm         *int16           `json:"widthMm" example:"129"`
+	HeightMm        *int16           `json:"heightMm" example:"198"`
+	ThicknessMm     *int16           `json:"thicknessMm" example:"72"`
+	WeightGrams     *int32           `json:"weightGrams" example:"1040"`
+	Authors         []ContributorDTO `json:"authors"`
+	GenreIds        []int16          `json:"genreIds" example:"1,2"`
+	LanguageId      int16            `json:"languageId" example:"1"`
+} // @name UpdateBookInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (b *UpdateBookDTO) Validate() error {
+	err := validation.ValidateStruct(
+		b,
+		validation.Field(&b.Title, validation.RuneLength(1, 200), validation.Required),
+		validation.Field(&b.Description, validation.RuneLength(1, 5000), validation.Required),
+		validation.Field(&b.Year, validation.Min(1)),
+		validation.Field(&b.PageCount, validation.Min(1)),
+		validation.Field(&b.WorkId, validation.Min(1)),
+		validation.Field(&b.ISBN, validator.ISBN),
+		validation.Field(&b.PublisherId, validation.Min(1)),
+		validation.Field(
+			&b.Format,
+			validation.Required,
+			validation.In(FormatHardcover, FormatPaperback, FormatEbook),
+		),
+		validation.Field(&b.Edition, validation.RuneLength(1, 100)),
+		validation.Field(&b.PublicationDate, validation.Date(dateLayout)),
+		validation.Field(&b.WidthMm, validation.Min(1)),
+		validation.Field(&b.HeightMm, validation.Min(1)),
+		validation.Field(&b.ThicknessMm, validation.Min(1)),
+		validation.Field(&b.WeightGrams, validation.Min(1)),
+		validation.Field(&b.Authors, validation.Required),
+		validation.Field(&b.GenreIds, validation.Required, validation.Each(validation.Required, validation.Min(1))),
+		validation.Field(&b.LanguageId, validation.Required, validation.Min(1)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return validateRelations(b.Authors, b.GenreIds)
+}
+
+// validateRelations checks that book has at least one author
+// and that contributors and genres are not duplicated.
+func validateRelations(authors []ContributorDTO, genreIds []int16) error {
+	hasAuthor := false
+	contributors := make(map[ContributorDTO]bool, len(authors))
+	for _, a := range authors {
+		if contributors[a] {
+			return validation.Errors{
+				"authors": fmt.Errorf("author %d is listed as %s more than once", a.AuthorId, a.Role),
+			}
+		}
+		contributors[a] = true
+		hasAuthor = hasAuthor || a.Role == RoleAuthor
+	}
+
+	if !hasAuthor {
+		return validation.Errors{
+			"authors": errors.New("must contain at least one contributor with author role"),
+		}
+	}
+
+	genres := make(map[int16]bool, len(genreIds))
+	for _, id := range genreIds {
+		if genres[id] {
+			return validation.Errors{
+				"genreIds": fmt.Errorf("genre %d is listed more than once", id),
+			}
+		}
+		genres[id] = true
+	}
+
+	return nil
+}
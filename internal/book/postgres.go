@@ -0,0 +1,626 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/pkg/isbn"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName            = "books"
+	worksTableName       = "works"
+	bookAuthorsTableName = "book_authors"
+	bookGenresTableName  = "book_genres"
+	movementsTableName   = "stock_movements"
+	pricesTableName      = "book_prices"
+
+	// initialStockReason is a reason of the receipt recorded for a new book.
+	initialStockReason = "initial stock"
+	// initialPriceReason is a reason of the list price recorded for a new book.
+	initialPriceReason = "initial price"
+	// catalogActor is an actor of stock movements made by catalog changes.
+	catalogActor = "catalog"
+
+	// streamCursorName is a name of the cursor used to stream books.
+	streamCursorName = "books_stream"
+	// streamBatchSize is a number of books fetched from the cursor at once.
+	streamBatchSize = 500
+	// streamTimeout limits the whole streaming transaction, even if the
+	// client keeps reading.
+	streamTimeout = 10 * time.Minute
+
+	// selectQuery selects books with their contributors, genres and language.
+	// Contributors and genres are aggregated to JSON arrays. Available count
+	// is the on-hand one less copies held by active reservations. Prices are
+	// the list price and the sale price effective now. Rating is aggregated
+	// from published reviews.
+	selectQuery = `
+	SELECT b.id, b.work_id, b.title, b.description, b.year,
+		COALESCE(book_list_price(b.id, now()), b.price), s.price, s.effective_to, b.currency,
+		b.page_count, b.count,
+		b.count - COALESCE((
+			SELECT SUM(r.quantity) FROM stock_reservations r
+			WHERE r.book_id = b.id AND r.expires_at > now()
+		), 0)::int,
+		b.isbn, b.format, b.edition, TO_CHAR(b.publication_date, 'YYYY-MM-DD'),
+		b.width_mm, b.height_mm, b.thickness_mm, b.weight_g, b.cover, p.id, p.name,
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'id', a.id, 'name', a.name, 'surname', a.surname, 'role', ba.role
+			) ORDER BY ba.position)
+			FROM book_authors ba
+			JOIN authors a ON a.id = ba.author_id
+			WHERE ba.book_id = b.id
+		), '[]'),
+		COALESCE((
+			SELECT json_agg(json_build_object('id', g.id, 'genre', g.genre) ORDER BY g.genre)
+			FROM book_genres bg
+			JOIN genres g ON g.id = bg.genre_id
+			WHERE bg.book_id = b.id
+		), '[]'),
+		l.id, l.language,
+		(
+			SELECT json_build_object(
+				'average', COALESCE(ROUND(AVG(rv.rating), 2), 0), 'count', COUNT(*),
+				'distribution', json_build_object(
+					'1', COUNT(*) FILTER (WHERE rv.rating = 1), '2', COUNT(*) FILTER (WHERE rv.rating = 2),
+					'3', COUNT(*) FILTER (WHERE rv.rating = 3), '4', COUNT(*) FILTER (WHERE rv.rating = 4),
+					'5', COUNT(*) FILTER (WHERE rv.rating = 5)
+				)
+			)
+			FROM reviews rv
+			WHERE rv.book_id = b.id AND rv.status = 'published'
+		),
+		b.created_at
+	FROM books b
+	JOIN languages l ON l.id = b.language_id
+	LEFT JOIN publishers p ON p.id = b.publisher_id
+	LEFT JOIN LATERAL (
+		SELECT sp.price, sp.effective_to
+		FROM book_prices sp
+		WHERE sp.book_id = b.id AND sp.kind = 'sale' AND sp.effective_from <= now() AND sp.effective_to > now()
+		ORDER BY sp.effective_from DESC
+		LIMIT 1
+	) s ON true`
+)
+
+// Check whether db implements book storage interface.
+var _ Storage = &db{}
+
+// db implements book storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new book storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts a book record with its contributors and genres in the database.
+// If work id is not specified, a new work is created for the book.
+// The initial price starts the price history and the initial count
+// is recorded as a stock receipt. Books are priced in the base currency
+// if the currency is not specified.
+// Returns ErrInvalidReference if some of the related records don't exist,
+// ErrISBNTaken if there is a book with the same ISBN,
+// an error on failure or inserted book id on success.
+func (d *db) Create(book *CreateBookDTO) (int64, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (
+		work_id, title, description, year, price, page_count, count, language_id,
+		isbn, publisher_id, format, edition, publication_date,
+		width_mm, height_mm, thickness_mm, weight_g, currency
+	)
+	VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+		COALESCE($18, (SELECT code FROM currencies WHERE base))
+	)
+	RETURNING id`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	workId := book.WorkId
+	if workId == nil {
+		workQuery := fmt.Sprintf("INSERT INTO %s (title) VALUES ($1) RETURNING id", worksTableName)
+		workId = new(int64)
+		if err := tx.QueryRow(ctx, workQuery, book.Title).Scan(workId); err != nil {
+			return 0, fmt.Errorf("failed to create work: %v", err)
+		}
+	}
+
+	var id int64
+	err = tx.QueryRow(
+		ctx,
+		query,
+		workId,
+		book.Title,
+		book.Description,
+		book.Year,
+		book.Price,
+		book.PageCount,
+		book.Count,
+		book.LanguageId,
+		book.ISBN,
+		book.PublisherId,
+		book.Format,
+		book.Edition,
+		book.PublicationDate,
+		book.WidthMm,
+		book.HeightMm,
+		book.ThicknessMm,
+		book.WeightGrams,
+		book.Currency,
+	).Scan(&id)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return 0, apperror.ErrInvalidReference
+		}
+		if apperror.IsUniqueViolation(err) {
+			return 0, apperror.ErrISBNTaken
+		}
+		err = fmt.Errorf("failed to execute create book query: %v", err)
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	if err := insertRelations(ctx, tx, id, book.Authors, book.GenreIds); err != nil {
+		return 0, err
+	}
+
+	priceQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, kind, price, effective_from, reason)
+	VALUES ($1, 'list', $2, now(), $3)`, pricesTableName)
+	if _, err := tx.Exec(ctx, priceQuery, id, book.Price, initialPriceReason); err != nil {
+		return 0, fmt.Errorf("failed to record initial price: %v", err)
+	}
+
+	if book.Count > 0 {
+		receiptQuery := fmt.Sprintf(`
+		INSERT INTO %s (book_id, type, quantity, reason, actor)
+		VALUES ($1, 'receipt', $2, $3, $4)`, movementsTableName)
+		_, err = tx.Exec(ctx, receiptQuery, id, book.Count, initialStockReason, catalogActor)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record initial stock: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return id, nil
+}
+
+// FindById finds the book with specified id.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Book, error) {
+	query := selectQuery + " WHERE b.id = $1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	found, err := scanBook(d.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find book by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// FindByISBN finds the book with specified ISBN-13.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) FindByISBN(isbn13 string) (*Book, error) {
+	query := selectQuery + " WHERE b.isbn = $1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	found, err := scanBook(d.conn.QueryRow(ctx, query, isbn13))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find book by isbn query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// FindAll finds books which match the given filter.
+// Returns an error on failure.
+func (d *db) FindAll(filter *Filter) ([]*Book, error) {
+	where, args := filterConditions(filter)
+	argId := len(args) + 1
+
+	query := selectQuery + where
+	query += fmt.Sprintf(" ORDER BY b.id LIMIT $%d OFFSET $%d", argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all books query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	books := make([]*Book, 0)
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan book: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read books: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// Update updates the book with specified values and replaces its contributors and genres.
+// The book stays in its work if work id is not specified.
+// Returns ErrNoRows if book doesn't exist, ErrInvalidReference if some of
+// the related records don't exist, ErrISBNTaken if there is another book
+// with the same ISBN or an error on failure.
+func (d *db) Update(book *UpdateBookDTO) error {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET work_id=COALESCE($1, work_id), title=$2, description=$3, year=$4,
+		page_count=$5, language_id=$6, isbn=$7, publisher_id=$8, format=$9,
+		edition=$10, publication_date=$11, width_mm=$12, height_mm=$13, thickness_mm=$14, weight_g=$15
+	WHERE id = $16`, tableName)
+
+	args := []interface{}{
+		book.WorkId,
+		book.Title,
+		book.Description,
+		book.Year,
+		book.PageCount,
+		book.LanguageId,
+		book.ISBN,
+		book.PublisherId,
+		book.Format,
+		book.Edition,
+		book.PublicationDate,
+		book.WidthMm,
+		book.HeightMm,
+		book.ThicknessMm,
+		book.WeightGrams,
+		book.Id,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrInvalidReference
+		}
+		if apperror.IsUniqueViolation(err) {
+			return apperror.ErrISBNTaken
+		}
+		err = fmt.Errorf("failed to execute update book query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	for _, table := range []string{bookAuthorsTableName, bookGenresTableName} {
+		_, err = tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE book_id = $1", table), book.Id)
+		if err != nil {
+			return fmt.Errorf("failed to delete book relations: %v", err)
+		}
+	}
+
+	if err := insertRelations(ctx, tx, book.Id, book.Authors, book.GenreIds); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateCover sets the cover of the book with specified id.
+// Nil cover removes the current one.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) UpdateCover(id int64, cover *media.Image) error {
+	query := fmt.Sprintf("UPDATE %s SET cover = $1 WHERE id = $2", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, cover, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update book cover query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete deletes the book with specified id.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) Delete(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete book: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// Stream finds all books which match the given filter, ignoring its limit
+// and offset, and calls fn for every one of them in order of ids. Books are
+// fetched in batches from a server-side cursor, so memory usage doesn't
+// depend on the number of books. The stream is cancelled with ctx, for
+// example when the client disconnects, and is limited by streamTimeout.
+// Stops and returns the error returned by fn.
+func (d *db) Stream(ctx context.Context, filter *Filter, fn func(*Book) error) error {
+	where, args := filterConditions(filter)
+	query := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s%s ORDER BY b.id", streamCursorName, selectQuery, where)
+
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+
+	tx, err := d.conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		err = fmt.Errorf("failed to declare books cursor: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	fetchQuery := fmt.Sprintf("FETCH %d FROM %s", streamBatchSize, streamCursorName)
+	for {
+		rows, err := tx.Query(ctx, fetchQuery)
+		if err != nil {
+			return fmt.Errorf("failed to fetch books: %v", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			fetched++
+
+			book, err := scanBook(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan book: %v", err)
+			}
+
+			if err := fn(book); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read books: %v", err)
+		}
+
+		if fetched < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// filterConditions returns WHERE clause of the query selecting
+// books which match the filter and the arguments of the clause.
+func filterConditions(filter *Filter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("b.title ILIKE '%%' || $%d || '%%'", argId))
+		args = append(args, filter.Search)
+		argId++
+	}
+
+	if filter.AuthorId != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s WHERE book_id = b.id AND author_id = $%d)",
+			bookAuthorsTableName, argId,
+		))
+		args = append(args, *filter.AuthorId)
+		argId++
+	}
+
+	if filter.GenreId != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM %s WHERE book_id = b.id AND genre_id = $%d)",
+			bookGenresTableName, argId,
+		))
+		args = append(args, *filter.GenreId)
+		argId++
+	}
+
+	if filter.LanguageId != nil {
+		conditions = append(conditions, fmt.Sprintf("b.language_id = $%d", argId))
+		args = append(args, *filter.LanguageId)
+		argId++
+	}
+
+	if filter.WorkId != nil {
+		conditions = append(conditions, fmt.Sprintf("b.work_id = $%d", argId))
+		args = append(args, *filter.WorkId)
+		argId++
+	}
+
+	if filter.Ids != nil {
+		conditions = append(conditions, fmt.Sprintf("b.id = ANY($%d)", argId))
+		args = append(args, filter.Ids)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// insertRelations inserts book contributors and genres within given transaction.
+// Contributors positions are taken from their order.
+func insertRelations(ctx context.Context, tx pgx.Tx, bookId int64, authors []ContributorDTO, genreIds []int16) error {
+	batch := &pgx.Batch{}
+
+	for i, a := range authors {
+		batch.Queue(
+			fmt.Sprintf("INSERT INTO %s (book_id, author_id, role, position) VALUES ($1, $2, $3, $4)", bookAuthorsTableName),
+			bookId, a.AuthorId, a.Role, i,
+		)
+	}
+
+	for _, genreId := range genreIds {
+		batch.Queue(
+			fmt.Sprintf("INSERT INTO %s (book_id, genre_id) VALUES ($1, $2)", bookGenresTableName),
+			bookId, genreId,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			if apperror.IsForeignKeyViolation(err) {
+				return apperror.ErrInvalidReference
+			}
+			return fmt.Errorf("failed to insert book relations: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// scanBook scans a row selected with selectQuery into a book.
+// ISBN-10 is derived from stored ISBN-13 when possible.
+func scanBook(row pgx.Row) (*Book, error) {
+	book := Book{
+		Language: &BookLanguage{},
+	}
+
+	var publisherId *int64
+	var publisherName *string
+
+	err := row.Scan(
+		&book.Id,
+		&book.WorkId,
+		&book.Title,
+		&book.Description,
+		&book.Year,
+		&book.Price,
+		&book.SalePrice,
+		&book.SaleEndsAt,
+		&book.Currency,
+		&book.PageCount,
+		&book.Count,
+		&book.Available,
+		&book.ISBN13,
+		&book.Format,
+		&book.Edition,
+		&book.PublicationDate,
+		&book.WidthMm,
+		&book.HeightMm,
+		&book.ThicknessMm,
+		&book.WeightGrams,
+		&book.Cover,
+		&publisherId,
+		&publisherName,
+		&book.Authors,
+		&book.Genres,
+		&book.Language.Id,
+		&book.Language.Language,
+		&book.Rating,
+		&book.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if publisherId != nil && publisherName != nil {
+		book.Publisher = &BookPublisher{
+			Id:   *publisherId,
+			Name: *publisherName,
+		}
+	}
+
+	if book.ISBN13 != nil {
+		if isbn10, err := isbn.To10(*book.ISBN13); err == nil {
+			book.ISBN10 = &isbn10
+		}
+	}
+
+	return &book, nil
+}
@@ -0,0 +1,294 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/media"
+	"github.com/juicyluv/ReadyRead/pkg/isbn"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes book service functionality.
+type Service interface {
+	Create(ctx context.Context, book *CreateBookDTO) (*Book, error)
+	GetById(ctx context.Context, id int64) (*Book, error)
+	GetByISBN(ctx context.Context, number string) (*Book, error)
+	GetAll(ctx context.Context, filter *Filter) ([]*Book, error)
+	GetEditions(ctx context.Context, id int64, limit, offset int) ([]*Book, error)
+	Stream(ctx context.Context, filter *Filter, fn func(*Book) error) error
+	Update(ctx context.Context, book *UpdateBookDTO) error
+	SetCover(ctx context.Context, id int64, data []byte) (*media.Image, error)
+	DeleteCover(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	logger          logger.Logger
+	storage         Storage
+	images          *media.Processor
+	currencyService currency.Service
+}
+
+// NewService returns a new instance that implements Service interface.
+// Prices of found books are converted to the currency accepted by the
+// client if the context carries accepted currencies.
+func NewService(storage Storage, images *media.Processor, currencyService currency.Service, logger logger.Logger) Service {
+	return &service{
+		logger:          logger,
+		storage:         storage,
+		images:          images,
+		currencyService: currencyService,
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *CreateBookDTO) (*Book, error) {
+	if input.ISBN != nil {
+		isbn13, err := isbn.Normalize(*input.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		input.ISBN = &isbn13
+	}
+
+	id, err := s.storage.Create(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetById(ctx, id)
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Book, error) {
+	book, err := s.storage.FindById(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return nil, err
+		}
+		s.logger.Warnf("cannot find book by id: %v", err)
+		return nil, err
+	}
+
+	if err := s.localize(ctx, book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+// GetByISBN finds a book by ISBN-10 or ISBN-13.
+// Returns ErrNoRows if book with this ISBN doesn't exist or an error on failure.
+func (s *service) GetByISBN(ctx context.Context, number string) (*Book, error) {
+	isbn13, err := isbn.Normalize(number)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.storage.FindByISBN(isbn13)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return nil, err
+		}
+		s.logger.Warnf("cannot find book by isbn: %v", err)
+		return nil, err
+	}
+
+	if err := s.localize(ctx, book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter *Filter) ([]*Book, error) {
+	books, err := s.storage.FindAll(filter)
+	if err != nil {
+		s.logger.Warnf("cannot find books: %v", err)
+		return nil, err
+	}
+
+	if err := s.localize(ctx, books...); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// GetEditions finds all editions of the same work as the book with specified id.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (s *service) GetEditions(ctx context.Context, id int64, limit, offset int) ([]*Book, error) {
+	book, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAll(ctx, &Filter{
+		WorkId: &book.WorkId,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// Stream calls fn for every book which matches the filter.
+// Limit and offset of the filter are ignored. Books are streamed
+// with prices in their own currency.
+func (s *service) Stream(ctx context.Context, filter *Filter, fn func(*Book) error) error {
+	err := s.storage.Stream(ctx, filter, fn)
+	if err != nil {
+		s.logger.Warnf("cannot stream books: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Update(ctx context.Context, book *UpdateBookDTO) error {
+	if book.ISBN != nil {
+		isbn13, err := isbn.Normalize(*book.ISBN)
+		if err != nil {
+			return err
+		}
+		book.ISBN = &isbn13
+	}
+
+	err := s.storage.Update(book)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) &&
+			!errors.Is(err, apperror.ErrInvalidReference) &&
+			!errors.Is(err, apperror.ErrISBNTaken) {
+			s.logger.Errorf("failed to update book: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SetCover saves uploaded image as the book cover and removes the previous one.
+// Returns ErrNoRows if book doesn't exist, media errors if the image
+// is not acceptable or an error on failure.
+func (s *service) SetCover(ctx context.Context, id int64, data []byte) (*media.Image, error) {
+	book, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cover, err := s.images.Save(ctx, fmt.Sprintf("books/%d/cover", id), data)
+	if err != nil {
+		if !errors.Is(err, media.ErrUnsupportedType) && !errors.Is(err, media.ErrTooLarge) {
+			s.logger.Errorf("failed to save book cover: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.storage.UpdateCover(id, cover); err != nil {
+		s.removeImage(ctx, cover)
+		return nil, err
+	}
+
+	if book.Cover != nil {
+		s.removeImage(ctx, book.Cover)
+	}
+
+	return cover, nil
+}
+
+// DeleteCover removes the book cover.
+// Returns ErrNoRows if book or its cover doesn't exist or an error on failure.
+func (s *service) DeleteCover(ctx context.Context, id int64) error {
+	book, err := s.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if book.Cover == nil {
+		return apperror.ErrNoRows
+	}
+
+	if err := s.storage.UpdateCover(id, nil); err != nil {
+		return err
+	}
+
+	s.removeImage(ctx, book.Cover)
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	book, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("failed to find book: %v", err)
+		}
+		return err
+	}
+
+	err = s.storage.Delete(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("failed to delete book: %v", err)
+		}
+		return err
+	}
+
+	if book.Cover != nil {
+		s.removeImage(ctx, book.Cover)
+	}
+
+	return nil
+}
+
+// removeImage deletes image files. Failures are only logged since
+// the image is not referenced anymore.
+func (s *service) removeImage(ctx context.Context, img *media.Image) {
+	if err := s.images.Delete(ctx, img); err != nil {
+		s.logger.Warnf("failed to remove image %s: %v", img.Key, err)
+	}
+}
+
+// localize converts prices of the books to the first currency accepted by
+// the client which has an exchange rate, or to the base currency if there
+// is none. Books are left as is if the context carries no accepted
+// currencies. Books which own currency has no exchange rate keep their
+// prices and are flagged with PriceNotConverted.
+func (s *service) localize(ctx context.Context, books ...*Book) error {
+	codes := currency.FromContext(ctx)
+	if len(codes) == 0 {
+		return nil
+	}
+
+	rates, err := s.currencyService.Rates(ctx)
+	if err != nil {
+		return err
+	}
+	target := rates.Select(codes...)
+
+	for _, book := range books {
+		if _, ok := rates.Rate(book.Currency); !ok {
+			s.logger.Warnf("cannot convert prices of book %d: no exchange rate of %s", book.Id, book.Currency)
+			book.PriceNotConverted = true
+			continue
+		}
+
+		price, err := rates.Convert(book.Price, book.Currency, target.Code)
+		if err != nil {
+			return err
+		}
+
+		if book.SalePrice != nil {
+			salePrice, err := rates.Convert(*book.SalePrice, book.Currency, target.Code)
+			if err != nil {
+				return err
+			}
+			book.SalePrice = &salePrice
+		}
+
+		book.Price = price
+		book.Currency = target.Code
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package book
+
+import (
+	"context"
+
+	"github.com/juicyluv/ReadyRead/internal/media"
+)
+
+// Storage describes book storage functionality.
+type Storage interface {
+	Create(book *CreateBookDTO) (int64, error)
+	FindById(id int64) (*Book, error)
+	FindByISBN(isbn13 string) (*Book, error)
+	FindAll(filter *Filter) ([]*Book, error)
+	Stream(ctx context.Context, filter *Filter, fn func(*Book) error) error
+	Update(book *UpdateBookDTO) error
+	UpdateCover(id int64, cover *media.Image) error
+	Delete(id int64) error
+}
@@ -0,0 +1,84 @@
+package collection
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// cache keeps ids of books of collections for ttl, so showing popular
+// collections doesn't aggregate orders on every request. Books themselves
+// aren't cached, so their prices and stock are always current.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is ids of books cached until expiresAt.
+type cacheEntry struct {
+	ids       []int64
+	expiresAt time.Time
+}
+
+// newCache returns a new cache which keeps entries for ttl.
+// Nothing is cached if ttl isn't positive.
+func newCache(ttl time.Duration) *cache {
+	return &cache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns cached ids by the key if they haven't expired.
+func (c *cache) get(key string) ([]int64, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.ids, true
+}
+
+// set caches ids by the key. Expired entries are dropped on the way, so
+// the cache doesn't grow with keys which aren't requested anymore.
+func (c *cache) set(key string, ids []int64) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = cacheEntry{ids: ids, expiresAt: now.Add(c.ttl)}
+}
+
+// clear drops cached entries of the collection with specified slug.
+func (c *cache) clear(slug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, slug+"|") {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	collectionsURL = "/api/collections"
+	collectionURL  = "/api/collections/:slug"
+	booksURL       = "/api/collections/:slug/books"
+)
+
+// Handler handles requests specified to collection service.
+type Handler struct {
+	logger            logger.Logger
+	collectionService Service
+	maxAge            time.Duration
+}
+
+// NewHandler returns a new collection Handler instance. Clients may
+// cache shown collections for maxAge, they aren't cached if it's zero.
+func NewHandler(logger logger.Logger, collectionService Service, maxAge time.Duration) handler.Handling {
+	return &Handler{
+		logger:            logger,
+		collectionService: collectionService,
+		maxAge:            maxAge,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, collectionsURL, h.ListCollections)
+	router.HandlerFunc(http.MethodPost, collectionsURL, h.CreateCollection)
+	router.HandlerFunc(http.MethodGet, collectionURL, h.GetCollection)
+	router.HandlerFunc(http.MethodPut, collectionURL, h.UpdateCollection)
+	router.HandlerFunc(http.MethodDelete, collectionURL, h.DeleteCollection)
+	router.HandlerFunc(http.MethodPut, booksURL, h.SetCollectionBooks)
+}
+
+// ListCollections godoc
+// @Summary List collections
+// @Description Get computed collections (bestsellers, new arrivals and trending books) followed by collections curated by staff ordered by name, without their books.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Success 200 {array} Collection
+// @Failure 500 {object} apperror.AppError
+// @Router /collections [get]
+func (h *Handler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST COLLECTIONS")
+
+	collections, err := h.collectionService.GetAll(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, collections)
+}
+
+// CreateCollection godoc
+// @Summary Create collection
+// @Description Create the curated collection without books. Slugs of computed collections are reserved. Admin only.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param input body CreateCollectionDTO true "JSON input"
+// @Success 201 {object} Collection
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /collections [post]
+func (h *Handler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE COLLECTION")
+
+	var input CreateCollectionDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	collection, err := h.collectionService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrCollectionExists) {
+			response.Conflict(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, collection)
+}
+
+// GetCollection godoc
+// @Summary Show collection
+// @Description Get the collection with a page of its books. Bestsellers are books with the most copies sold in paid orders of the period, the last month by default. New arrivals are books added to the catalog last, of all time by default. Trending books are books selling faster in the last 7 days than in an average week of the 4 weeks before, the period doesn't apply to them. Computed collections may be limited to the genre. Books of curated collections are listed in the order set by staff. Collections are cached for a few minutes, so the latest orders may not be counted yet.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param slug path string true "Collection slug, e.g. bestsellers, new-arrivals or trending"
+// @Param period query string false "Period of computed collections" Enums(week, month, year, all)
+// @Param genreId query int false "Genre id of computed collections"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Collection
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /collections/{slug} [get]
+func (h *Handler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET COLLECTION")
+
+	query, err := readQuery(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	collection, err := h.collectionService.Get(ctx, query)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	if h.maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	}
+
+	response.JSON(w, http.StatusOK, collection)
+}
+
+// UpdateCollection godoc
+// @Summary Update collection
+// @Description Update the name and the description of the curated collection. Admin only.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param slug path string true "Collection slug"
+// @Param input body UpdateCollectionDTO true "JSON input"
+// @Success 200 {object} Collection
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /collections/{slug} [put]
+func (h *Handler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE COLLECTION")
+
+	var input UpdateCollectionDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Slug = httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	collection, err := h.collectionService.Update(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, collection)
+}
+
+// DeleteCollection godoc
+// @Summary Delete collection
+// @Description Delete the curated collection. Books of the collection are kept in the catalog. Admin only.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param slug path string true "Collection slug"
+// @Success 200
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /collections/{slug} [delete]
+func (h *Handler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE COLLECTION")
+
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	if err := h.collectionService.Delete(r.Context(), slug); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetCollectionBooks godoc
+// @Summary Set collection books
+// @Description Replace books of the curated collection with the books in the order they are listed. An empty list clears the collection. Admin only.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param slug path string true "Collection slug"
+// @Param input body SetBooksDTO true "JSON input"
+// @Success 200 {object} Collection
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /collections/{slug}/books [put]
+func (h *Handler) SetCollectionBooks(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET COLLECTION BOOKS")
+
+	var input SetBooksDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Slug = httprouter.ParamsFromContext(r.Context()).ByName("slug")
+
+	collection, err := h.collectionService.SetBooks(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, collection)
+}
+
+// readQuery reads the slug, the period, the genre and pagination of
+// the collection to show. Returns an error if some of them are invalid.
+func readQuery(r *http.Request) (*Query, error) {
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		return nil, err
+	}
+
+	values := r.URL.Query()
+
+	query := Query{
+		Slug:   httprouter.ParamsFromContext(r.Context()).ByName("slug"),
+		Period: values.Get("period"),
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	}
+
+	if genreId := values.Get("genreId"); genreId != "" {
+		id, err := strconv.ParseInt(genreId, 10, 16)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("genreId must have type int16")
+		}
+		genre := int16(id)
+		query.GenreId = &genre
+	}
+
+	return &query, nil
+}
@@ -0,0 +1,180 @@
+package collection
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/book"
+)
+
+const (
+	// SlugBestsellers is the slug of books sold the most in the period.
+	SlugBestsellers = "bestsellers"
+	// SlugNewArrivals is the slug of books added to the catalog last.
+	SlugNewArrivals = "new-arrivals"
+	// SlugTrending is the slug of books selling faster this week than before.
+	SlugTrending = "trending"
+
+	// PeriodWeek limits computed collections to the last 7 days.
+	PeriodWeek = "week"
+	// PeriodMonth limits computed collections to the last 30 days.
+	PeriodMonth = "month"
+	// PeriodYear limits computed collections to the last 365 days.
+	PeriodYear = "year"
+	// PeriodAll doesn't limit computed collections.
+	PeriodAll = "all"
+
+	// maxBooks is the maximum number of books of the curated collection.
+	maxBooks = 200
+)
+
+// slugPattern matches slugs of collections, e.g. "summer-reads".
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// periods are durations of periods, zero is unlimited.
+var periods = map[string]time.Duration{
+	PeriodWeek:  7 * 24 * time.Hour,
+	PeriodMonth: 30 * 24 * time.Hour,
+	PeriodYear:  365 * 24 * time.Hour,
+	PeriodAll:   0,
+}
+
+// computed are collections computed from orders and the catalog, listed
+// before curated ones. Bestsellers are counted for the last month and
+// new arrivals for all time unless another period is requested.
+var computed = []struct {
+	collection Collection
+	period     string
+}{
+	{Collection{Slug: SlugBestsellers, Name: "Bestsellers", Computed: true}, PeriodMonth},
+	{Collection{Slug: SlugNewArrivals, Name: "New arrivals", Computed: true}, PeriodAll},
+	{Collection{Slug: SlugTrending, Name: "Trending", Computed: true}, PeriodAll},
+}
+
+// Collection represents the list of books shown on the home page. Computed
+// collections are bestsellers, new arrivals and trending books, others are
+// curated by staff who order their books manually. Books are listed when
+// the collection itself is shown.
+type Collection struct {
+	Id          int64        `json:"id,omitempty" example:"1"`
+	Slug        string       `json:"slug" example:"summer-reads"`
+	Name        string       `json:"name" example:"Summer reads"`
+	Description *string      `json:"description,omitempty" example:"Light books for the beach."`
+	Computed    bool         `json:"computed" example:"false"`
+	BookCount   *int32       `json:"bookCount,omitempty" example:"12"`
+	Books       []*book.Book `json:"books,omitempty"`
+	CreatedAt   *time.Time   `json:"createdAt,omitempty" example:"2022-03-01T12:00:00Z"`
+	UpdatedAt   *time.Time   `json:"updatedAt,omitempty" example:"2022-03-01T12:00:00Z"`
+} // @name Collection
+
+// Filter is used to filter and paginate books of computed collections.
+// Since limits them to books sold or added since the moment if it's set.
+type Filter struct {
+	Since   *time.Time
+	GenreId *int16
+	Limit   int
+	Offset  int
+}
+
+// Query is used to show the collection. Period and genre apply to
+// computed collections only, the default period of the collection is
+// used if period is empty.
+type Query struct {
+	Slug    string
+	Period  string
+	GenreId *int16
+	Limit   int
+	Offset  int
+}
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (q *Query) Validate() error {
+	return validation.ValidateStruct(
+		q,
+		validation.Field(&q.Period, validation.In(PeriodWeek, PeriodMonth, PeriodYear, PeriodAll)),
+	)
+}
+
+// key returns the key the books of the collection are cached by.
+func (q *Query) key() string {
+	genreId := int16(0)
+	if q.GenreId != nil {
+		genreId = *q.GenreId
+	}
+	return fmt.Sprintf("%s|%s|%d|%d|%d", q.Slug, q.Period, genreId, q.Limit, q.Offset)
+}
+
+// CreateCollectionDTO is used to create the curated collection.
+type CreateCollectionDTO struct {
+	Slug        string  `json:"slug" example:"summer-reads"`
+	Name        string  `json:"name" example:"Summer reads"`
+	Description *string `json:"description,omitempty" example:"Light books for the beach."`
+} // @name CreateCollectionInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c *CreateCollectionDTO) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(
+			&c.Slug,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(slugPattern).Error("must contain only lowercase letters, digits and hyphens"),
+			validation.NotIn(SlugBestsellers, SlugNewArrivals, SlugTrending).Error("is reserved"),
+		),
+		validation.Field(&c.Name, validation.Required, validation.RuneLength(1, 100)),
+		validation.Field(&c.Description, validation.RuneLength(1, 1000)),
+	)
+}
+
+// UpdateCollectionDTO is used to update the curated collection.
+type UpdateCollectionDTO struct {
+	Slug        string  `json:"-"`
+	Name        string  `json:"name" example:"Summer reads"`
+	Description *string `json:"description,omitempty" example:"Light books for the beach."`
+} // @name UpdateCollectionInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c *UpdateCollectionDTO) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Name, validation.Required, validation.RuneLength(1, 100)),
+		validation.Field(&c.Description, validation.RuneLength(1, 1000)),
+	)
+}
+
+// SetBooksDTO is used to set books of the curated collection in the order
+// they are shown. An empty list clears the collection.
+type SetBooksDTO struct {
+	Slug    string  `json:"-"`
+	BookIds []int64 `json:"bookIds" example:"123,124,125"`
+} // @name SetCollectionBooksInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (s *SetBooksDTO) Validate() error {
+	err := validation.ValidateStruct(
+		s,
+		validation.Field(&s.BookIds, validation.Length(0, maxBooks), validation.Each(validation.Required, validation.Min(1))),
+	)
+	if err != nil {
+		return err
+	}
+
+	books := make(map[int64]bool, len(s.BookIds))
+	for _, id := range s.BookIds {
+		if books[id] {
+			return validation.Errors{
+				"bookIds": fmt.Errorf("book %d is listed more than once", id),
+			}
+		}
+		books[id] = true
+	}
+
+	return nil
+}
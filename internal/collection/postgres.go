@@ -0,0 +1,391 @@
+package collection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName      = "collections"
+	booksTableName = "collection_books"
+
+	// selectQuery selects curated collections with the number of their books.
+	selectQuery = `
+	SELECT c.id, c.slug, c.name, c.description,
+		(SELECT COUNT(*) FROM collection_books cb WHERE cb.collection_id = c.id)::int,
+		c.created_at, c.updated_at
+	FROM collections c`
+
+	// salesQuery selects lines of paid orders with dates of orders.
+	salesQuery = `
+	SELECT i.book_id, i.quantity, o.date
+	FROM order_items i
+	JOIN orders o ON o.id = i.order_id
+	WHERE o.status IN ('paid', 'shipped', 'delivered') AND i.book_id IS NOT NULL`
+
+	// genreCondition matches books of the genre.
+	genreCondition = "EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = %s AND bg.genre_id = $%d)"
+)
+
+// Check whether db implements collection storage interface.
+var _ Storage = &db{}
+
+// db implements collection storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new collection storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the curated collection. Returns ErrCollectionExists if
+// the slug is taken, an error on failure or the inserted collection on success.
+func (d *db) Create(input *CreateCollectionDTO) (*Collection, error) {
+	query := fmt.Sprintf("INSERT INTO %s (slug, name, description) VALUES ($1, $2, $3)", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, input.Slug, input.Name, input.Description); err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrCollectionExists
+		}
+		err = fmt.Errorf("failed to execute create collection query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return d.FindBySlug(input.Slug)
+}
+
+// FindAll finds curated collections ordered by name.
+// Returns an error on failure.
+func (d *db) FindAll() ([]*Collection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, selectQuery+" ORDER BY c.name, c.id")
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all collections query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := make([]*Collection, 0)
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan collection: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read collections: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+// FindBySlug finds the curated collection with specified slug.
+// Returns ErrNoRows if there is no such collection or an error on failure.
+func (d *db) FindBySlug(slug string) (*Collection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	c, err := scanCollection(d.conn.QueryRow(ctx, selectQuery+" WHERE c.slug = $1", slug))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find collection by slug query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Update updates the name and the description of the curated collection.
+// Returns ErrNoRows if there is no such collection, an error on failure
+// or the updated collection on success.
+func (d *db) Update(input *UpdateCollectionDTO) (*Collection, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET name = $2, description = $3, updated_at = now()
+	WHERE slug = $1`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, input.Slug, input.Name, input.Description)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update collection query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return d.FindBySlug(input.Slug)
+}
+
+// Delete deletes the curated collection.
+// Returns ErrNoRows if there is no such collection or an error on failure.
+func (d *db) Delete(slug string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE slug = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, slug)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete collection query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetBooks replaces books of the curated collection with the books
+// in the given order. Returns ErrNoRows if there is no such collection
+// or some book doesn't exist, or an error on failure.
+func (d *db) SetBooks(input *SetBooksDTO) error {
+	touchQuery := fmt.Sprintf(`
+	UPDATE %s SET updated_at = now()
+	WHERE slug = $1
+	RETURNING id`, tableName)
+
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE collection_id = $1", booksTableName)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (collection_id, book_id, position)
+	SELECT $1, t.book_id, t.position
+	FROM unnest($2::bigint[]) WITH ORDINALITY AS t(book_id, position)`, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	if err := tx.QueryRow(ctx, touchQuery, input.Slug).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find collection query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, deleteQuery, id); err != nil {
+		err = fmt.Errorf("failed to execute delete collection books query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, insertQuery, id, input.BookIds); err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute insert collection books query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// FindBookIds finds ids of books of the curated collection in their order.
+// Returns an error on failure.
+func (d *db) FindBookIds(collectionId int64, limit, offset int) ([]int64, error) {
+	query := fmt.Sprintf(`
+	SELECT book_id FROM %s
+	WHERE collection_id = $1
+	ORDER BY position
+	LIMIT $2 OFFSET $3`, booksTableName)
+
+	return d.findIds("collection books", query, collectionId, limit, offset)
+}
+
+// Bestsellers finds ids of books ordered by the number of copies sold
+// in paid orders. Returns an error on failure.
+func (d *db) Bestsellers(filter *Filter) ([]int64, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.Since != nil {
+		conditions = append(conditions, fmt.Sprintf("s.date >= $%d", argId))
+		args = append(args, *filter.Since)
+		argId++
+	}
+
+	if filter.GenreId != nil {
+		conditions = append(conditions, fmt.Sprintf(genreCondition, "s.book_id", argId))
+		args = append(args, *filter.GenreId)
+		argId++
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+	SELECT s.book_id
+	FROM (%s) s%s
+	GROUP BY s.book_id
+	ORDER BY SUM(s.quantity) DESC, s.book_id
+	LIMIT $%d OFFSET $%d`, salesQuery, where, argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	return d.findIds("bestsellers", query, args...)
+}
+
+// NewArrivals finds ids of books ordered by the time they were added
+// to the catalog, latest first. Returns an error on failure.
+func (d *db) NewArrivals(filter *Filter) ([]int64, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.Since != nil {
+		conditions = append(conditions, fmt.Sprintf("b.created_at >= $%d", argId))
+		args = append(args, *filter.Since)
+		argId++
+	}
+
+	if filter.GenreId != nil {
+		conditions = append(conditions, fmt.Sprintf(genreCondition, "b.id", argId))
+		args = append(args, *filter.GenreId)
+		argId++
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+	SELECT b.id
+	FROM books b%s
+	ORDER BY b.created_at DESC, b.id DESC
+	LIMIT $%d OFFSET $%d`, where, argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	return d.findIds("new arrivals", query, args...)
+}
+
+// Trending finds ids of books sold in the last 7 days, ordered by how
+// many more copies were sold in the last 7 days than in an average week
+// of the 4 weeks before. Returns an error on failure.
+func (d *db) Trending(filter *Filter) ([]int64, error) {
+	args := make([]interface{}, 0)
+	argId := 1
+
+	where := " WHERE s.date >= now() - interval '35 days'"
+	if filter.GenreId != nil {
+		where += " AND " + fmt.Sprintf(genreCondition, "s.book_id", argId)
+		args = append(args, *filter.GenreId)
+		argId++
+	}
+
+	query := fmt.Sprintf(`
+	SELECT t.book_id
+	FROM (
+		SELECT s.book_id,
+			COALESCE(SUM(s.quantity) FILTER (WHERE s.date >= now() - interval '7 days'), 0) AS recent,
+			COALESCE(SUM(s.quantity) FILTER (WHERE s.date < now() - interval '7 days'), 0) AS earlier
+		FROM (%s) s%s
+		GROUP BY s.book_id
+	) t
+	WHERE t.recent > 0
+	ORDER BY t.recent - t.earlier / 4.0 DESC, t.recent DESC, t.book_id
+	LIMIT $%d OFFSET $%d`, salesQuery, where, argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	return d.findIds("trending", query, args...)
+}
+
+// findIds finds ids of books by the query.
+func (d *db) findIds(name, query string, args ...interface{}) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find %s query: %v", name, err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			err = fmt.Errorf("failed to scan %s: %v", name, err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read %s: %v", name, err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// scanCollection scans the row of selectQuery.
+func scanCollection(row pgx.Row) (*Collection, error) {
+	var c Collection
+	c.BookCount = new(int32)
+	c.CreatedAt = new(time.Time)
+	c.UpdatedAt = new(time.Time)
+
+	err := row.Scan(&c.Id, &c.Slug, &c.Name, &c.Description, c.BookCount, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
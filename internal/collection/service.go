@@ -0,0 +1,236 @@
+package collection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes collection service functionality.
+type Service interface {
+	GetAll(ctx context.Context) ([]*Collection, error)
+	Get(ctx context.Context, query *Query) (*Collection, error)
+	Create(ctx context.Context, input *CreateCollectionDTO) (*Collection, error)
+	Update(ctx context.Context, input *UpdateCollectionDTO) (*Collection, error)
+	Delete(ctx context.Context, slug string) error
+	SetBooks(ctx context.Context, input *SetBooksDTO) (*Collection, error)
+}
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	bookService book.Service
+	cache       *cache
+}
+
+// NewService returns a new instance that implements Service interface.
+// Ids of books of collections are cached for cacheTTL, nothing is cached
+// if it's zero.
+func NewService(storage Storage, bookService book.Service, cacheTTL time.Duration, logger logger.Logger) Service {
+	return &service{
+		logger:      logger,
+		storage:     storage,
+		bookService: bookService,
+		cache:       newCache(cacheTTL),
+	}
+}
+
+// GetAll returns computed collections followed by curated ones,
+// without their books.
+func (s *service) GetAll(ctx context.Context) ([]*Collection, error) {
+	curated, err := s.storage.FindAll()
+	if err != nil {
+		s.logger.Warnf("cannot find collections: %v", err)
+		return nil, err
+	}
+
+	collections := make([]*Collection, 0, len(computed)+len(curated))
+	for _, c := range computed {
+		collection := c.collection
+		collections = append(collections, &collection)
+	}
+
+	return append(collections, curated...), nil
+}
+
+// Get returns the collection with a page of its books. Books of computed
+// collections are counted for the period of the query and may be limited
+// to the genre. Returns ErrNoRows if there is no such collection.
+func (s *service) Get(ctx context.Context, query *Query) (*Collection, error) {
+	for _, c := range computed {
+		if c.collection.Slug != query.Slug {
+			continue
+		}
+
+		q := *query
+		if q.Period == "" {
+			q.Period = c.period
+		}
+
+		ids, err := s.findIds(&q, func() ([]int64, error) {
+			return s.findComputed(&q)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		collection := c.collection
+		return s.loadBooks(ctx, &collection, ids)
+	}
+
+	collection, err := s.storage.FindBySlug(query.Slug)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find collection by slug: %v", err)
+		}
+		return nil, err
+	}
+
+	q := Query{Slug: query.Slug, Limit: query.Limit, Offset: query.Offset}
+	ids, err := s.findIds(&q, func() ([]int64, error) {
+		return s.storage.FindBookIds(collection.Id, q.Limit, q.Offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadBooks(ctx, collection, ids)
+}
+
+// Create creates the curated collection without books.
+// Returns ErrCollectionExists if the slug is taken.
+func (s *service) Create(ctx context.Context, input *CreateCollectionDTO) (*Collection, error) {
+	collection, err := s.storage.Create(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrCollectionExists) {
+			s.logger.Errorf("failed to create collection: %v", err)
+		}
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// Update updates the name and the description of the curated collection.
+// Returns ErrNoRows if there is no such collection.
+func (s *service) Update(ctx context.Context, input *UpdateCollectionDTO) (*Collection, error) {
+	collection, err := s.storage.Update(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to update collection: %v", err)
+		}
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// Delete deletes the curated collection.
+// Returns ErrNoRows if there is no such collection.
+func (s *service) Delete(ctx context.Context, slug string) error {
+	if err := s.storage.Delete(slug); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete collection: %v", err)
+		}
+		return err
+	}
+
+	s.cache.clear(slug)
+	return nil
+}
+
+// SetBooks replaces books of the curated collection with the books in
+// the given order. Returns ErrNoRows if there is no such collection or
+// some book doesn't exist.
+func (s *service) SetBooks(ctx context.Context, input *SetBooksDTO) (*Collection, error) {
+	if err := s.storage.SetBooks(input); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set collection books: %v", err)
+		}
+		return nil, err
+	}
+
+	s.cache.clear(input.Slug)
+
+	collection, err := s.storage.FindBySlug(input.Slug)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find collection by slug: %v", err)
+		}
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// findIds returns cached ids of books of the query or finds them
+// and caches them.
+func (s *service) findIds(query *Query, find func() ([]int64, error)) ([]int64, error) {
+	key := query.key()
+	if ids, ok := s.cache.get(key); ok {
+		return ids, nil
+	}
+
+	ids, err := find()
+	if err != nil {
+		s.logger.Warnf("cannot find collection books: %v", err)
+		return nil, err
+	}
+
+	s.cache.set(key, ids)
+	return ids, nil
+}
+
+// findComputed finds ids of books of the computed collection.
+func (s *service) findComputed(query *Query) ([]int64, error) {
+	filter := Filter{
+		GenreId: query.GenreId,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}
+
+	if period := periods[query.Period]; period > 0 {
+		since := time.Now().Add(-period)
+		filter.Since = &since
+	}
+
+	switch query.Slug {
+	case SlugBestsellers:
+		return s.storage.Bestsellers(&filter)
+	case SlugNewArrivals:
+		return s.storage.NewArrivals(&filter)
+	default:
+		return s.storage.Trending(&filter)
+	}
+}
+
+// loadBooks sets books of the collection in the order of ids. Books which
+// are deleted since ids were cached are left out.
+func (s *service) loadBooks(ctx context.Context, collection *Collection, ids []int64) (*Collection, error) {
+	collection.Books = make([]*book.Book, 0, len(ids))
+	if len(ids) == 0 {
+		return collection, nil
+	}
+
+	books, err := s.bookService.GetAll(ctx, &book.Filter{Ids: ids, Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int64]*book.Book, len(books))
+	for _, b := range books {
+		byId[b.Id] = b
+	}
+
+	for _, id := range ids {
+		if b, ok := byId[id]; ok {
+			collection.Books = append(collection.Books, b)
+		}
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,15 @@
+package collection
+
+// Storage describes a collection storage functionality.
+type Storage interface {
+	Create(input *CreateCollectionDTO) (*Collection, error)
+	FindAll() ([]*Collection, error)
+	FindBySlug(slug string) (*Collection, error)
+	Update(input *UpdateCollectionDTO) (*Collection, error)
+	Delete(slug string) error
+	SetBooks(input *SetBooksDTO) error
+	FindBookIds(collectionId int64, limit, offset int) ([]int64, error)
+	Bestsellers(filter *Filter) ([]int64, error)
+	NewArrivals(filter *Filter) ([]int64, error)
+	Trending(filter *Filter) ([]int64, error)
+}
@@ -0,0 +1,74 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptHeader is a request header listing currencies the client wants
+// prices in, e.g. "EUR, GBP;q=0.5". Its syntax follows Accept-Language.
+const AcceptHeader = "Accept-Currency"
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying currencies accepted
+// by the client of the request. It marks the response as varying by
+// the accepted currencies.
+func NewContext(w http.ResponseWriter, r *http.Request) context.Context {
+	w.Header().Add("Vary", AcceptHeader)
+	return context.WithValue(r.Context(), contextKey{}, ParseAccept(r.Header.Get(AcceptHeader)))
+}
+
+// FromContext returns currencies accepted by the client, the most
+// preferred first.
+func FromContext(ctx context.Context) []string {
+	codes, _ := ctx.Value(contextKey{}).([]string)
+	return codes
+}
+
+// ParseAccept parses the value of Accept-Currency header. Returns codes of
+// accepted currencies in upper case ordered by their quality. Currencies
+// with zero quality and the wildcard are skipped.
+func ParseAccept(header string) []string {
+	type accepted struct {
+		code    string
+		quality float64
+	}
+
+	var list []accepted
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		code := strings.ToUpper(strings.TrimSpace(params[0]))
+		if code == "" || code == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > 0 {
+			list = append(list, accepted{code: code, quality: quality})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].quality > list[j].quality
+	})
+
+	codes := make([]string, 0, len(list))
+	for _, a := range list {
+		codes = append(codes, a.code)
+	}
+	return codes
+}
@@ -0,0 +1,214 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	currenciesURL  = "/api/currencies"
+	currencyURL    = "/api/currencies/:code"
+	ratesURL       = "/api/exchange-rates"
+	importRatesURL = "/api/exchange-rates/import"
+
+	// maxRatesFileSize limits the size of the uploaded exchange rates file.
+	maxRatesFileSize = 1 << 20
+)
+
+// Handler handles requests specified to currency service.
+type Handler struct {
+	logger          logger.Logger
+	currencyService Service
+}
+
+// NewHandler returns a new currency Handler instance.
+func NewHandler(logger logger.Logger, currencyService Service) handler.Handling {
+	return &Handler{
+		logger:          logger,
+		currencyService: currencyService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, currenciesURL, h.ListCurrencies)
+	router.HandlerFunc(http.MethodPut, currencyURL, h.SaveCurrency)
+	router.HandlerFunc(http.MethodGet, ratesURL, h.ListRates)
+	router.HandlerFunc(http.MethodPut, ratesURL, h.SetRates)
+	router.HandlerFunc(http.MethodPost, importRatesURL, h.ImportRates)
+}
+
+// ListCurrencies godoc
+// @Summary List currencies
+// @Description Get supported currencies with their rounding rules.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Success 200 {array} Currency
+// @Failure 500 {object} apperror.AppError
+// @Router /currencies [get]
+func (h *Handler) ListCurrencies(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST CURRENCIES")
+
+	currencies, err := h.currencyService.GetCurrencies(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, currencies)
+}
+
+// SaveCurrency godoc
+// @Summary Save currency
+// @Description Create the currency or update its name and rounding rules. Amounts are rounded to the number of decimals with the rounding mode, or to a multiple of the increment if it's set. Admin only.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Param code path string true "Currency code"
+// @Param input body CurrencyDTO true "JSON input"
+// @Success 200 {object} Currency
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /currencies/{code} [put]
+func (h *Handler) SaveCurrency(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SAVE CURRENCY")
+
+	var input CurrencyDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Code = httprouter.ParamsFromContext(r.Context()).ByName("code")
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	currency, err := h.currencyService.SaveCurrency(r.Context(), &input)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, currency)
+}
+
+// ListRates godoc
+// @Summary List exchange rates
+// @Description Get exchange rates relative to the base currency.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Success 200 {array} Rate
+// @Failure 500 {object} apperror.AppError
+// @Router /exchange-rates [get]
+func (h *Handler) ListRates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST EXCHANGE RATES")
+
+	rates, err := h.currencyService.GetRates(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rates)
+}
+
+// SetRates godoc
+// @Summary Set exchange rates
+// @Description Create or update exchange rates. Rate is the number of units of the currency for one unit of the base currency. Either all of the rates are saved or none of them. Admin only.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Param input body []RateDTO true "JSON input"
+// @Success 200 {array} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /exchange-rates [put]
+func (h *Handler) SetRates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET EXCHANGE RATES")
+
+	var input []*RateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if len(input) == 0 {
+		response.BadRequest(w, "no exchange rates given", apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	for i, rate := range input {
+		if err := rate.Validate(); err != nil {
+			response.BadRequest(w, fmt.Sprintf("%d: %v", i, err), apperror.ErrValidationFailed.Error())
+			return
+		}
+	}
+
+	rates, err := h.currencyService.SetRates(r.Context(), input)
+	if err != nil {
+		h.writeRatesError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rates)
+}
+
+// ImportRates godoc
+// @Summary Import exchange rates
+// @Description Set exchange rates from CSV file sent as request body. Lines have currency, rate and optional source columns, e.g. "EUR,0.9132,ECB". The header line is optional. Either all of the rates are saved or none of them. Admin only.
+// @Tags currencies
+// @Accept plain
+// @Produce json
+// @Param source query string false "Source of the rates without one"
+// @Param file body string true "CSV file"
+// @Success 200 {array} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /exchange-rates/import [post]
+func (h *Handler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("IMPORT EXCHANGE RATES")
+
+	source := strings.TrimSpace(r.URL.Query().Get("source"))
+
+	handler.LimitBody(w, r, maxRatesFileSize)
+
+	rates, err := h.currencyService.ImportRates(r.Context(), r.Body, source)
+	if err != nil {
+		if errors.Is(err, handler.ErrBodyTooLarge) {
+			response.TooLarge(w, "exchange rates file is too large", "")
+			return
+		}
+		h.writeRatesError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rates)
+}
+
+// writeRatesError writes the response for the error of setting exchange rates.
+func (h *Handler) writeRatesError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidRatesFile):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrUnknownCurrency):
+		response.BadRequest(w, err.Error(), "create the currency first")
+	case errors.Is(err, apperror.ErrBaseCurrency):
+		response.BadRequest(w, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "")
+	}
+}
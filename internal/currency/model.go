@@ -0,0 +1,121 @@
+package currency
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RoundHalfUp rounds halves away from zero.
+	RoundHalfUp = "half_up"
+	// RoundHalfEven rounds halves to the nearest even digit.
+	RoundHalfEven = "half_even"
+	// RoundUp rounds away from zero.
+	RoundUp = "up"
+	// RoundDown rounds towards zero.
+	RoundDown = "down"
+)
+
+// Currency represents the currency with its rounding rules.
+// Amounts are rounded to the number of decimals with the rounding mode,
+// or to a multiple of the increment if it's set. Exchange rates are
+// relative to the base currency.
+type Currency struct {
+	Code      string           `json:"code" example:"CHF"`
+	Name      string           `json:"name" example:"Swiss Franc"`
+	Decimals  int32            `json:"decimals" example:"2"`
+	Rounding  string           `json:"rounding" example:"half_up"`
+	Increment *decimal.Decimal `json:"increment,omitempty" swaggertype:"string" example:"0.05"`
+	Base      bool             `json:"base" example:"false"`
+} // @name Currency
+
+// Round rounds the amount by the rules of the currency.
+func (c *Currency) Round(amount decimal.Decimal) decimal.Decimal {
+	if c.Increment != nil && c.Increment.IsPositive() {
+		return c.round(amount.Div(*c.Increment), 0).Mul(*c.Increment)
+	}
+
+	return c.round(amount, c.Decimals)
+}
+
+// MinorUnit returns the smallest amount of the currency, e.g. 0.01.
+func (c *Currency) MinorUnit() decimal.Decimal {
+	return decimal.New(1, -c.Decimals)
+}
+
+func (c *Currency) round(amount decimal.Decimal, places int32) decimal.Decimal {
+	switch c.Rounding {
+	case RoundHalfEven:
+		return amount.RoundBank(places)
+	case RoundUp:
+		return amount.RoundUp(places)
+	case RoundDown:
+		return amount.RoundDown(places)
+	default:
+		return amount.Round(places)
+	}
+}
+
+// Rate represents the exchange rate of the currency. Rate is the number
+// of units of the currency for one unit of the base currency.
+type Rate struct {
+	Currency  string          `json:"currency" example:"EUR"`
+	Rate      decimal.Decimal `json:"rate" swaggertype:"string" example:"0.9132"`
+	Source    *string         `json:"source,omitempty" example:"ECB"`
+	UpdatedAt time.Time       `json:"updatedAt" example:"2022-03-01T12:00:00Z"`
+} // @name ExchangeRate
+
+// CurrencyDTO is used to create or update the currency.
+type CurrencyDTO struct {
+	Code      string           `json:"-"`
+	Name      string           `json:"name" example:"Swiss Franc"`
+	Decimals  int32            `json:"decimals" example:"2"`
+	Rounding  string           `json:"rounding" example:"half_up"`
+	Increment *decimal.Decimal `json:"increment,omitempty" swaggertype:"string" example:"0.05"`
+} // @name CurrencyInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c *CurrencyDTO) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Code, validation.Required, validator.CurrencyCode),
+		validation.Field(&c.Name, validation.Required, validation.RuneLength(1, 100)),
+		validation.Field(&c.Decimals, validation.Min(0), validation.Max(2)),
+		validation.Field(
+			&c.Rounding,
+			validation.Required,
+			validation.In(RoundHalfUp, RoundHalfEven, RoundUp, RoundDown),
+		),
+		validation.Field(
+			&c.Increment,
+			validator.MinDecimal(decimal.New(1, -2)),
+			validator.MaxDecimal(decimal.New(1000, 0)),
+		),
+	)
+}
+
+// RateDTO is used to set the exchange rate of the currency.
+type RateDTO struct {
+	Currency string          `json:"currency" example:"EUR"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"0.9132"`
+	Source   *string         `json:"source,omitempty" example:"ECB"`
+} // @name ExchangeRateInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *RateDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Currency, validation.Required, validator.CurrencyCode),
+		validation.Field(
+			&r.Rate,
+			validator.MinDecimal(decimal.New(1, -10)),
+			validator.MaxDecimal(decimal.New(1, 9)),
+		),
+		validation.Field(&r.Source, validation.RuneLength(1, 100)),
+	)
+}
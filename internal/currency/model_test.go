@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(v string) decimal.Decimal { return decimal.RequireFromString(v) }
+
+func decPtr(v string) *decimal.Decimal {
+	d := dec(v)
+	return &d
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		amount   string
+		want     string
+	}{
+		{name: "half up", currency: Currency{Decimals: 2, Rounding: RoundHalfUp}, amount: "1.005", want: "1.01"},
+		{name: "half up negative", currency: Currency{Decimals: 2, Rounding: RoundHalfUp}, amount: "-1.005", want: "-1.01"},
+		{name: "half even down", currency: Currency{Decimals: 2, Rounding: RoundHalfEven}, amount: "1.005", want: "1"},
+		{name: "half even up", currency: Currency{Decimals: 2, Rounding: RoundHalfEven}, amount: "1.015", want: "1.02"},
+		{name: "up", currency: Currency{Decimals: 2, Rounding: RoundUp}, amount: "1.001", want: "1.01"},
+		{name: "down", currency: Currency{Decimals: 2, Rounding: RoundDown}, amount: "1.009", want: "1"},
+		{name: "no decimals", currency: Currency{Decimals: 0, Rounding: RoundHalfUp}, amount: "149.5", want: "150"},
+		{name: "increment", currency: Currency{Decimals: 2, Rounding: RoundHalfUp, Increment: decPtr("0.05")}, amount: "1.024", want: "1"},
+		{name: "increment half up", currency: Currency{Decimals: 2, Rounding: RoundHalfUp, Increment: decPtr("0.05")}, amount: "1.025", want: "1.05"},
+		{name: "increment up", currency: Currency{Decimals: 2, Rounding: RoundUp, Increment: decPtr("0.05")}, amount: "1.01", want: "1.05"},
+		{name: "zero increment is ignored", currency: Currency{Decimals: 2, Rounding: RoundHalfUp, Increment: decPtr("0")}, amount: "1.024", want: "1.02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.currency.Round(dec(tt.amount)); !got.Equal(dec(tt.want)) {
+				t.Errorf("Round(%s) = %s, want %s", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinorUnit(t *testing.T) {
+	tests := []struct {
+		decimals int32
+		want     string
+	}{
+		{decimals: 2, want: "0.01"},
+		{decimals: 0, want: "1"},
+		{decimals: 3, want: "0.001"},
+	}
+
+	for _, tt := range tests {
+		c := Currency{Decimals: tt.decimals}
+		if got := c.MinorUnit(); !got.Equal(dec(tt.want)) {
+			t.Errorf("MinorUnit() with %d decimals = %s, want %s", tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName      = "currencies"
+	ratesTableName = "exchange_rates"
+)
+
+// Check whether db implements currency storage interface.
+var _ Storage = &db{}
+
+// db implements currency storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new currency storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// FindCurrencies finds all currencies ordered by code.
+// Returns an error on failure.
+func (d *db) FindCurrencies() ([]*Currency, error) {
+	query := fmt.Sprintf(`
+	SELECT code, name, decimals, rounding, increment, base
+	FROM %s
+	ORDER BY code`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find currencies query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	currencies := make([]*Currency, 0)
+	for rows.Next() {
+		var c Currency
+		if err := rows.Scan(&c.Code, &c.Name, &c.Decimals, &c.Rounding, &c.Increment, &c.Base); err != nil {
+			err = fmt.Errorf("failed to scan currency: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		currencies = append(currencies, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read currencies: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+// SaveCurrency creates the currency or updates its name and rounding rules.
+// Returns an error on failure or the saved currency on success.
+func (d *db) SaveCurrency(currency *Currency) (*Currency, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (code, name, decimals, rounding, increment)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (code) DO UPDATE
+	SET name = excluded.name, decimals = excluded.decimals,
+		rounding = excluded.rounding, increment = excluded.increment
+	RETURNING base`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(
+		ctx,
+		query,
+		currency.Code,
+		currency.Name,
+		currency.Decimals,
+		currency.Rounding,
+		currency.Increment,
+	).Scan(&currency.Base)
+	if err != nil {
+		err = fmt.Errorf("failed to execute save currency query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return currency, nil
+}
+
+// FindRates finds exchange rates ordered by currency.
+// Returns an error on failure.
+func (d *db) FindRates() ([]*Rate, error) {
+	query := fmt.Sprintf(`
+	SELECT currency, rate, source, updated_at
+	FROM %s
+	ORDER BY currency`, ratesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find exchange rates query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]*Rate, 0)
+	for rows.Next() {
+		var r Rate
+		if err := rows.Scan(&r.Currency, &r.Rate, &r.Source, &r.UpdatedAt); err != nil {
+			err = fmt.Errorf("failed to scan exchange rate: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		rates = append(rates, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read exchange rates: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// SaveRates creates or updates exchange rates in a single transaction.
+// Returns ErrUnknownCurrency if some currency doesn't exist, ErrBaseCurrency
+// if some rate is of the base currency or an error on failure.
+func (d *db) SaveRates(rates []*Rate) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (currency, rate, source, updated_at)
+	SELECT code, $2, $3, now()
+	FROM %s
+	WHERE code = $1 AND NOT base
+	ON CONFLICT (currency) DO UPDATE
+	SET rate = excluded.rate, source = excluded.source, updated_at = excluded.updated_at
+	RETURNING updated_at`, ratesTableName, tableName)
+
+	baseQuery := fmt.Sprintf("SELECT base FROM %s WHERE code = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, rate := range rates {
+		err := tx.QueryRow(ctx, query, rate.Currency, rate.Rate, rate.Source).Scan(&rate.UpdatedAt)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			err = fmt.Errorf("failed to execute save exchange rate query: %v", err)
+			d.logger.Error(err)
+			return err
+		}
+
+		// The rate is not saved either for the base or for an unknown currency.
+		var base bool
+		if err := tx.QueryRow(ctx, baseQuery, rate.Currency).Scan(&base); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", apperror.ErrUnknownCurrency, rate.Currency)
+			}
+			return fmt.Errorf("failed to find currency: %v", err)
+		}
+		return fmt.Errorf("%w: %s", apperror.ErrBaseCurrency, rate.Currency)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package currency
+
+import (
+	"fmt"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/shopspring/decimal"
+)
+
+// ratePrecision is the number of decimal places kept when rates are divided.
+const ratePrecision = 16
+
+// Rates converts amounts between currencies with stored exchange rates.
+type Rates struct {
+	base       *Currency
+	currencies map[string]*Currency
+	rates      map[string]decimal.Decimal
+}
+
+// NewRates returns Rates of the currencies. Currencies without a rate
+// can't be converted to, except for the base currency.
+func NewRates(currencies []*Currency, rates []*Rate) *Rates {
+	r := &Rates{
+		currencies: make(map[string]*Currency, len(currencies)),
+		rates:      make(map[string]decimal.Decimal, len(rates)+1),
+	}
+
+	for _, c := range currencies {
+		r.currencies[c.Code] = c
+		if c.Base {
+			r.base = c
+			r.rates[c.Code] = decimal.New(1, 0)
+		}
+	}
+
+	for _, rate := range rates {
+		if c, ok := r.currencies[rate.Currency]; ok && !c.Base {
+			r.rates[rate.Currency] = rate.Rate
+		}
+	}
+
+	return r
+}
+
+// Base returns the base currency.
+func (r *Rates) Base() *Currency {
+	return r.base
+}
+
+// Currency returns the currency with the code if it can be converted to.
+func (r *Rates) Currency(code string) (*Currency, bool) {
+	if _, ok := r.rates[code]; !ok {
+		return nil, false
+	}
+	return r.currencies[code], true
+}
+
+// Rate returns the exchange rate of the currency relative to the base currency.
+func (r *Rates) Rate(code string) (decimal.Decimal, bool) {
+	rate, ok := r.rates[code]
+	return rate, ok
+}
+
+// Select returns the first of the currencies which can be converted to
+// or the base currency if there is none.
+func (r *Rates) Select(codes ...string) *Currency {
+	for _, code := range codes {
+		if c, ok := r.Currency(code); ok {
+			return c
+		}
+	}
+	return r.base
+}
+
+// Convert converts the amount from one currency to another and rounds it
+// by the rules of the target currency. Returns ErrUnknownCurrency if
+// some of the currencies has no exchange rate.
+func (r *Rates) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", apperror.ErrUnknownCurrency, from)
+	}
+
+	target, ok := r.Currency(to)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: %s", apperror.ErrUnknownCurrency, to)
+	}
+
+	if from == to {
+		return target.Round(amount), nil
+	}
+
+	converted := amount.Mul(r.rates[to]).DivRound(fromRate, ratePrecision)
+	return target.Round(converted), nil
+}
@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+)
+
+// newTestRates returns rates with USD as the base currency,
+// EUR and JPY with exchange rates and CHF without one.
+func newTestRates() *Rates {
+	return NewRates(
+		[]*Currency{
+			{Code: "USD", Decimals: 2, Rounding: RoundHalfUp, Base: true},
+			{Code: "EUR", Decimals: 2, Rounding: RoundHalfUp},
+			{Code: "JPY", Decimals: 0, Rounding: RoundHalfUp},
+			{Code: "CHF", Decimals: 2, Rounding: RoundHalfUp, Increment: decPtr("0.05")},
+		},
+		[]*Rate{
+			{Currency: "EUR", Rate: dec("0.9")},
+			{Currency: "JPY", Rate: dec("150")},
+			{Currency: "USD", Rate: dec("2")},
+			{Currency: "GBP", Rate: dec("0.8")},
+		},
+	)
+}
+
+func TestConvert(t *testing.T) {
+	rates := newTestRates()
+
+	tests := []struct {
+		name    string
+		amount  string
+		from    string
+		to      string
+		want    string
+		wantErr error
+	}{
+		{name: "from base", amount: "10", from: "USD", to: "EUR", want: "9"},
+		{name: "to base", amount: "9", from: "EUR", to: "USD", want: "10"},
+		{name: "between non-base currencies", amount: "10", from: "EUR", to: "JPY", want: "1667"},
+		{name: "rounded by target currency", amount: "0.01", from: "JPY", to: "EUR", want: "0"},
+		{name: "repeating fraction", amount: "1", from: "JPY", to: "USD", want: "0.01"},
+		{name: "same currency is rounded", amount: "10.005", from: "USD", to: "USD", want: "10.01"},
+		{name: "base rate is not overridden", amount: "10", from: "USD", to: "USD", want: "10"},
+		{name: "target without rate", amount: "10", from: "USD", to: "CHF", wantErr: apperror.ErrUnknownCurrency},
+		{name: "source without rate", amount: "10", from: "CHF", to: "USD", wantErr: apperror.ErrUnknownCurrency},
+		{name: "rate of unknown currency is ignored", amount: "10", from: "GBP", to: "USD", wantErr: apperror.ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(dec(tt.amount), tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(dec(tt.want)) {
+				t.Errorf("Convert() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	rates := newTestRates()
+
+	tests := []struct {
+		name  string
+		codes []string
+		want  string
+	}{
+		{name: "first with rate", codes: []string{"CHF", "JPY", "EUR"}, want: "JPY"},
+		{name: "base when none has rate", codes: []string{"CHF", "GBP"}, want: "USD"},
+		{name: "base when nothing accepted", want: "USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rates.Select(tt.codes...); got.Code != tt.want {
+				t.Errorf("Select() = %s, want %s", got.Code, tt.want)
+			}
+		})
+	}
+}
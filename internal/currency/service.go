@@ -0,0 +1,172 @@
+package currency
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidRatesFile is returned when the exchange rates file cannot be imported.
+var ErrInvalidRatesFile = errors.New("invalid exchange rates file")
+
+// Service describes currency service functionality.
+type Service interface {
+	GetCurrencies(ctx context.Context) ([]*Currency, error)
+	SaveCurrency(ctx context.Context, input *CurrencyDTO) (*Currency, error)
+	GetRates(ctx context.Context) ([]*Rate, error)
+	SetRates(ctx context.Context, input []*RateDTO) ([]*Rate, error)
+	ImportRates(ctx context.Context, r io.Reader, source string) ([]*Rate, error)
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) GetCurrencies(ctx context.Context) ([]*Currency, error) {
+	currencies, err := s.storage.FindCurrencies()
+	if err != nil {
+		s.logger.Warnf("cannot find currencies: %v", err)
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+func (s *service) SaveCurrency(ctx context.Context, input *CurrencyDTO) (*Currency, error) {
+	c := Currency{
+		Code:      input.Code,
+		Name:      input.Name,
+		Decimals:  input.Decimals,
+		Rounding:  input.Rounding,
+		Increment: input.Increment,
+	}
+
+	currency, err := s.storage.SaveCurrency(&c)
+	if err != nil {
+		s.logger.Errorf("failed to save currency: %v", err)
+		return nil, err
+	}
+
+	return currency, nil
+}
+
+func (s *service) GetRates(ctx context.Context) ([]*Rate, error) {
+	rates, err := s.storage.FindRates()
+	if err != nil {
+		s.logger.Warnf("cannot find exchange rates: %v", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// SetRates creates or updates exchange rates. Either all of the rates are
+// saved or none of them. Returns ErrUnknownCurrency if some currency
+// doesn't exist and ErrBaseCurrency if some rate is of the base currency.
+func (s *service) SetRates(ctx context.Context, input []*RateDTO) ([]*Rate, error) {
+	rates := make([]*Rate, 0, len(input))
+	for _, r := range input {
+		rates = append(rates, &Rate{
+			Currency: r.Currency,
+			Rate:     r.Rate,
+			Source:   r.Source,
+		})
+	}
+
+	if err := s.storage.SaveRates(rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// ImportRates sets exchange rates from the CSV file with currency and rate
+// columns and an optional source column, e.g. "EUR,0.9132,ECB". The header
+// line is optional. The source of the rates without one is set to source.
+// Returns ErrInvalidRatesFile if some line cannot be read.
+func (s *service) ImportRates(ctx context.Context, r io.Reader, source string) ([]*Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var input []*RateDTO
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRatesFile, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read rates file: %w", err)
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("%w: line %d: expected currency, rate and optional source", ErrInvalidRatesFile, line)
+		}
+
+		rate, err := decimal.NewFromString(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: rate must be a number", ErrInvalidRatesFile, line)
+		}
+
+		dto := RateDTO{
+			Currency: strings.ToUpper(strings.TrimSpace(record[0])),
+			Rate:     rate,
+		}
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			recordSource := strings.TrimSpace(record[2])
+			dto.Source = &recordSource
+		} else if source != "" {
+			dto.Source = &source
+		}
+
+		if err := dto.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRatesFile, line, err)
+		}
+		input = append(input, &dto)
+	}
+
+	if len(input) == 0 {
+		return nil, fmt.Errorf("%w: file has no rates", ErrInvalidRatesFile)
+	}
+
+	return s.SetRates(ctx, input)
+}
+
+// Rates loads currencies and their exchange rates to convert amounts.
+func (s *service) Rates(ctx context.Context) (*Rates, error) {
+	currencies, err := s.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.GetRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRates(currencies, rates), nil
+}
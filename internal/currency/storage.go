@@ -0,0 +1,9 @@
+package currency
+
+// Storage describes a currency storage functionality.
+type Storage interface {
+	FindCurrencies() ([]*Currency, error)
+	SaveCurrency(currency *Currency) (*Currency, error)
+	FindRates() ([]*Rate, error)
+	SaveRates(rates []*Rate) error
+}
@@ -0,0 +1,327 @@
+package ebook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	filesURL    = "/api/books/:id/files"
+	fileURL     = "/api/books/:id/files/:format"
+	libraryURL  = "/api/users/:id/library"
+	linksURL    = "/api/users/:id/library/:entitlementId/links"
+	downloadURL = "/api/downloads/:token"
+
+	// fileField is a name of the multipart form field with uploaded file.
+	fileField = "file"
+)
+
+// Handler handles requests specified to ebook service.
+type Handler struct {
+	logger        logger.Logger
+	ebookService  Service
+	maxUploadSize int64
+}
+
+// NewHandler returns a new ebook Handler instance.
+// Uploaded files are limited to maxUploadSize bytes.
+func NewHandler(logger logger.Logger, ebookService Service, maxUploadSize int64) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		ebookService:  ebookService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, filesURL, h.ListBookFiles)
+	router.HandlerFunc(http.MethodPut, fileURL, h.UploadBookFile)
+	router.HandlerFunc(http.MethodDelete, fileURL, h.DeleteBookFile)
+	router.HandlerFunc(http.MethodGet, libraryURL, h.ListLibrary)
+	router.HandlerFunc(http.MethodPost, linksURL, h.CreateDownloadLink)
+	router.HandlerFunc(http.MethodGet, downloadURL, h.Download)
+}
+
+// ListBookFiles godoc
+// @Summary List ebook files
+// @Description Get formats and sizes of files the ebook is delivered in.
+// @Tags ebooks
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Success 200 {array} File
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/files [get]
+func (h *Handler) ListBookFiles(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BOOK FILES")
+
+	bookId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	files, err := h.ebookService.GetFiles(r.Context(), bookId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, files)
+}
+
+// UploadBookFile godoc
+// @Summary Upload ebook file
+// @Description Upload EPUB or PDF file of the ebook, the previous file of the format is removed. Only books of the ebook format can have files. Files are not public, customers download them with signed links. Admin only.
+// @Tags ebooks
+// @Accept mpfd
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param format path string true "File format" Enums(epub, pdf)
+// @Param file formData file true "Ebook file"
+// @Success 200 {object} File
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/files/{format} [put]
+func (h *Handler) UploadBookFile(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPLOAD BOOK FILE")
+
+	bookId, format, err := readFileParams(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	data, err := handler.ReadFile(w, r, fileField, h.maxUploadSize)
+	if err != nil {
+		if errors.Is(err, handler.ErrUploadTooLarge) {
+			response.TooLarge(w, err.Error(), fmt.Sprintf("maximum size is %d bytes", h.maxUploadSize))
+			return
+		}
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	file, err := h.ebookService.SetFile(r.Context(), bookId, format, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrNotEbook):
+			response.Conflict(w, err.Error(), "change the format of the book to ebook first")
+		case errors.Is(err, ErrUnsupportedFile):
+			response.BadRequest(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, file)
+}
+
+// DeleteBookFile godoc
+// @Summary Delete ebook file
+// @Description Delete the file of the ebook in the format. Customers can't download the ebook in the format anymore. Admin only.
+// @Tags ebooks
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param format path string true "File format" Enums(epub, pdf)
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/files/{format} [delete]
+func (h *Handler) DeleteBookFile(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE BOOK FILE")
+
+	bookId, format, err := readFileParams(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.ebookService.DeleteFile(r.Context(), bookId, format); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListLibrary godoc
+// @Summary List user library
+// @Description Get ebooks the user has paid for, latest first. Every ebook lists formats it can be downloaded in and downloads left. Ebooks of cancelled orders are left out.
+// @Tags ebooks
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Success 200 {array} Entitlement
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/library [get]
+func (h *Handler) ListLibrary(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST LIBRARY")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	entitlements, err := h.ebookService.GetLibrary(r.Context(), userId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, entitlements)
+}
+
+// CreateDownloadLink godoc
+// @Summary Create download link
+// @Description Create the signed link to download the ebook of the user library in the format. The link expires in a few minutes, every use of it counts towards the download limit of the ebook.
+// @Tags ebooks
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param entitlementId path int64 true "Entitlement id"
+// @Param input body LinkDTO true "JSON input"
+// @Success 201 {object} Link
+// @Failure 400 {object} apperror.AppError
+// @Failure 403 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/library/{entitlementId}/links [post]
+func (h *Handler) CreateDownloadLink(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE DOWNLOAD LINK")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	entitlementId, err := handler.ReadParam64(r, "entitlementId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input LinkDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+	input.EntitlementId = entitlementId
+
+	link, err := h.ebookService.CreateLink(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrDownloadLimitReached):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, link)
+}
+
+// Download godoc
+// @Summary Download ebook
+// @Description Download the ebook file by the signed link. The file is streamed from the storage, the download is counted towards the download limit of the ebook once the file is opened.
+// @Tags ebooks
+// @Produce application/epub+zip,application/pdf
+// @Param token path string true "Signed token of the link"
+// @Success 200 {string} string "Ebook file"
+// @Failure 403 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 410 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /downloads/{token} [get]
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DOWNLOAD EBOOK")
+
+	token := httprouter.ParamsFromContext(r.Context()).ByName("token")
+
+	download, err := h.ebookService.Download(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidLink),
+			errors.Is(err, apperror.ErrDownloadLimitReached):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		case errors.Is(err, apperror.ErrLinkExpired):
+			response.Error(w, http.StatusGone, err.Error(), "create a new download link")
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+	defer download.Body.Close()
+
+	w.Header().Set("Content-Type", download.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		"attachment; filename*=UTF-8''%s", url.PathEscape(download.FileName),
+	))
+	w.Header().Set("Content-Length", strconv.FormatInt(download.Size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, download.Body); err != nil {
+		h.logger.Warnf("failed to send ebook file: %v", err)
+	}
+}
+
+// readFileParams reads the book id and the file format path parameters.
+// Returns an error if the format is not supported.
+func readFileParams(r *http.Request) (int64, string, error) {
+	bookId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		return 0, "", err
+	}
+
+	format := httprouter.ParamsFromContext(r.Context()).ByName("format")
+	if _, ok := fileTypes[format]; !ok {
+		return 0, "", fmt.Errorf("unknown format %q, use %s or %s", format, FormatEPUB, FormatPDF)
+	}
+
+	return bookId, format, nil
+}
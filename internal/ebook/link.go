@@ -0,0 +1,73 @@
+package ebook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+)
+
+// signToken returns the token of the link to download the file of the
+// entitlement in the format until expiresAt. The token is the payload
+// followed by its HMAC-SHA256 signature, both encoded with URL-safe base64,
+// so it can't be changed or made up without the key.
+func signToken(key []byte, entitlementId int64, format string, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%s.%d", entitlementId, format, expiresAt.Unix())
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(signature(key, payload))
+}
+
+// verifyToken checks the signature of the token and returns the entitlement
+// id and the format it was signed for. Returns ErrInvalidLink if the token
+// is malformed or its signature doesn't match and ErrLinkExpired if it has
+// expired by now.
+func verifyToken(key []byte, token string, now time.Time) (int64, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	sum, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sum, signature(key, string(payload))) {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	fields := strings.Split(string(payload), ".")
+	if len(fields) != 3 {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	entitlementId, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	expiresAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, "", apperror.ErrInvalidLink
+	}
+
+	if now.Unix() >= expiresAt {
+		return 0, "", apperror.ErrLinkExpired
+	}
+
+	return entitlementId, fields[1], nil
+}
+
+// signature returns HMAC-SHA256 of the payload with the key.
+func signature(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package ebook
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+)
+
+func TestVerifyToken(t *testing.T) {
+	key := []byte("secret")
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(15 * time.Minute)
+
+	token := signToken(key, 42, FormatEPUB, expiresAt)
+	payload := strings.Split(token, ".")[0]
+	sum := strings.Split(token, ".")[1]
+
+	// forged signs the payload with the right key but breaks its fields.
+	forged := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+			base64.RawURLEncoding.EncodeToString(signature(key, payload))
+	}
+
+	tests := []struct {
+		name       string
+		key        []byte
+		token      string
+		now        time.Time
+		wantId     int64
+		wantFormat string
+		wantErr    error
+	}{
+		{name: "valid", key: key, token: token, now: now, wantId: 42, wantFormat: FormatEPUB},
+		{name: "last second", key: key, token: token, now: expiresAt.Add(-time.Second), wantId: 42, wantFormat: FormatEPUB},
+		{name: "expired", key: key, token: token, now: expiresAt, wantErr: apperror.ErrLinkExpired},
+		{name: "another key", key: []byte("another"), token: token, now: now, wantErr: apperror.ErrInvalidLink},
+		{
+			name:    "changed payload",
+			key:     key,
+			token:   base64.RawURLEncoding.EncodeToString([]byte("43.epub.1646136900")) + "." + sum,
+			now:     now,
+			wantErr: apperror.ErrInvalidLink,
+		},
+		{name: "no signature", key: key, token: payload, now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "extra part", key: key, token: token + ".x", now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "not base64", key: key, token: "***." + sum, now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "empty", key: key, token: "", now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "missing field", key: key, token: forged("42.epub"), now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "invalid id", key: key, token: forged("x.epub.1646136900"), now: now, wantErr: apperror.ErrInvalidLink},
+		{name: "invalid expiry", key: key, token: forged("42.epub.soon"), now: now, wantErr: apperror.ErrInvalidLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, format, err := verifyToken(tt.key, tt.token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verifyToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if id != tt.wantId || format != tt.wantFormat {
+				t.Errorf("verifyToken() = %d, %q, want %d, %q", id, format, tt.wantId, tt.wantFormat)
+			}
+		})
+	}
+}
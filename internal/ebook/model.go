@@ -0,0 +1,105 @@
+package ebook
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// FormatEPUB is a format of reflowable ebook files.
+	FormatEPUB = "epub"
+	// FormatPDF is a format of fixed layout ebook files.
+	FormatPDF = "pdf"
+)
+
+// ErrUnsupportedFile is returned when uploaded file doesn't match its format.
+var ErrUnsupportedFile = errors.New("file content doesn't match its format")
+
+// fileType describes files of the format.
+type fileType struct {
+	contentType string
+	// matches reports whether the data is a file of the format.
+	matches func(data []byte) bool
+}
+
+// fileTypes are supported formats of ebook files. EPUB files are ZIP
+// archives which start with the uncompressed "mimetype" entry.
+var fileTypes = map[string]fileType{
+	FormatEPUB: {
+		contentType: "application/epub+zip",
+		matches: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("PK\x03\x04")) &&
+				len(data) > 58 && string(data[30:58]) == "mimetypeapplication/epub+zip"
+		},
+	},
+	FormatPDF: {
+		contentType: "application/pdf",
+		matches: func(data []byte) bool {
+			return bytes.HasPrefix(data, []byte("%PDF-"))
+		},
+	},
+}
+
+// File represents the file of the ebook in one of formats. Files are kept
+// in the blob store under unguessable keys and are downloaded by customers
+// with signed links only.
+type File struct {
+	BookId    int64     `json:"bookId" example:"123"`
+	Format    string    `json:"format" example:"epub"`
+	Size      int64     `json:"size" example:"524288"`
+	CreatedAt time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+	Key       string    `json:"-"`
+} // @name EbookFile
+
+// Entitlement lets the customer download the ebook bought with the order.
+// Every paid digital order line gets one. Formats are formats of files the
+// ebook can be downloaded in, BookId is missing if the book has been deleted.
+// Entitlements of cancelled orders are not listed and can't be downloaded.
+type Entitlement struct {
+	Id            int64     `json:"id" example:"1"`
+	UserId        int64     `json:"userId" example:"7"`
+	OrderId       int64     `json:"orderId" example:"1001"`
+	BookId        *int64    `json:"bookId,omitempty" example:"123"`
+	Title         string    `json:"title" example:"War and Peace"`
+	Formats       []string  `json:"formats" example:"epub,pdf"`
+	Downloads     int32     `json:"downloads" example:"1"`
+	DownloadLimit int32     `json:"downloadLimit" example:"5"`
+	CreatedAt     time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+} // @name Entitlement
+
+// Link is the signed link to download the ebook file. It can be used until
+// it expires while the entitlement has downloads left.
+type Link struct {
+	Format    string    `json:"format" example:"epub"`
+	URL       string    `json:"url" example:"http://localhost:8080/api/downloads/MS5lcHViLjE2NDYxMzYwMDA.3q2-7w"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2022-03-01T12:15:00Z"`
+} // @name DownloadLink
+
+// Download is the ebook file to send to the customer.
+// Body is read from the blob store and must be closed.
+type Download struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// LinkDTO is used to create the download link of the entitlement.
+type LinkDTO struct {
+	UserId        int64  `json:"-"`
+	EntitlementId int64  `json:"-"`
+	Format        string `json:"format" example:"epub"`
+} // @name DownloadLinkInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (l *LinkDTO) Validate() error {
+	return validation.ValidateStruct(
+		l,
+		validation.Field(&l.Format, validation.Required, validation.In(FormatEPUB, FormatPDF)),
+	)
+}
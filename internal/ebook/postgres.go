@@ -0,0 +1,323 @@
+package ebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	filesTableName        = "book_files"
+	entitlementsTableName = "entitlements"
+
+	// selectQuery selects entitlements with formats of files of their books.
+	// Entitlements of orders which are not paid anymore are left out.
+	selectQuery = `
+	SELECT e.id, e.user_id, i.order_id, e.book_id, e.title,
+		ARRAY(SELECT f.format FROM book_files f WHERE f.book_id = e.book_id ORDER BY f.format),
+		e.download_count, e.download_limit, e.created_at
+	FROM entitlements e
+	JOIN order_items i ON i.id = e.order_item_id
+	JOIN orders o ON o.id = i.order_id
+	WHERE o.status IN ('paid', 'shipped', 'delivered')`
+)
+
+// Check whether db implements ebook storage interface.
+var _ Storage = &db{}
+
+// db implements ebook storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new ebook storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// SaveFile saves the file of the ebook, replacing the file of the same format.
+// Returns ErrNoRows if the book doesn't exist or an error on failure.
+func (d *db) SaveFile(file *File) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (book_id, format, object_key, size)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (book_id, format) DO UPDATE
+	SET object_key = EXCLUDED.object_key, size = EXCLUDED.size, created_at = now()
+	RETURNING created_at`, filesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(ctx, query, file.BookId, file.Format, file.Key, file.Size).Scan(&file.CreatedAt)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute save book file query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindFile finds the file of the book in the format.
+// Returns ErrNoRows if there is no such file or an error on failure.
+func (d *db) FindFile(bookId int64, format string) (*File, error) {
+	query := fmt.Sprintf(`
+	SELECT book_id, format, size, created_at, object_key
+	FROM %s
+	WHERE book_id = $1 AND format = $2`, filesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	var file File
+	err := d.conn.QueryRow(ctx, query, bookId, format).
+		Scan(&file.BookId, &file.Format, &file.Size, &file.CreatedAt, &file.Key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find book file query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// FindFiles finds files of the book ordered by format.
+// Returns an error on failure.
+func (d *db) FindFiles(bookId int64) ([]*File, error) {
+	query := fmt.Sprintf(`
+	SELECT book_id, format, size, created_at, object_key
+	FROM %s
+	WHERE book_id = $1
+	ORDER BY format`, filesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, bookId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find book files query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]*File, 0)
+	for rows.Next() {
+		var file File
+		if err := rows.Scan(&file.BookId, &file.Format, &file.Size, &file.CreatedAt, &file.Key); err != nil {
+			err = fmt.Errorf("failed to scan book file: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		files = append(files, &file)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read book files: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// DeleteFile deletes the file of the book in the format.
+// Returns ErrNoRows if there is no such file or an error on failure.
+func (d *db) DeleteFile(bookId int64, format string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE book_id = $1 AND format = $2", filesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, bookId, format)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete book file query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// Grant creates entitlements of digital lines of the order which
+// don't have them yet, each allowing downloadLimit downloads. Lines of
+// deleted books are skipped. Returns the number of created entitlements
+// or an error on failure.
+func (d *db) Grant(orderId int64, downloadLimit int32) (int64, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (user_id, order_item_id, book_id, title, download_limit)
+	SELECT o.user_id, i.id, i.book_id, i.title, $2
+	FROM order_items i
+	JOIN orders o ON o.id = i.order_id
+	WHERE i.order_id = $1 AND i.digital AND i.book_id IS NOT NULL
+	ON CONFLICT (order_item_id) DO NOTHING`, entitlementsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, orderId, downloadLimit)
+	if err != nil {
+		err = fmt.Errorf("failed to execute grant entitlements query: %v", err)
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// FindById finds the entitlement of the user with specified id.
+// Returns ErrNoRows if there is no such entitlement or an error on failure.
+func (d *db) FindById(userId, id int64) (*Entitlement, error) {
+	return d.findOne(selectQuery+" AND e.user_id = $1 AND e.id = $2", userId, id)
+}
+
+// FindByUser finds entitlements of the user, latest first.
+// Returns an error on failure.
+func (d *db) FindByUser(userId int64) ([]*Entitlement, error) {
+	query := selectQuery + " AND e.user_id = $1 ORDER BY e.id DESC"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find entitlements query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entitlements := make([]*Entitlement, 0)
+	for rows.Next() {
+		e, err := scanEntitlement(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan entitlement: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		entitlements = append(entitlements, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read entitlements: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return entitlements, nil
+}
+
+// FindEntitlement finds the entitlement with specified id.
+// Returns ErrNoRows if there is no such entitlement or its order
+// is not paid anymore or an error on failure.
+func (d *db) FindEntitlement(id int64) (*Entitlement, error) {
+	return d.findOne(selectQuery+" AND e.id = $1", id)
+}
+
+// CountDownload counts the download of the entitlement. The count is
+// checked against the limit with the entitlement locked, so concurrent
+// downloads can't exceed it. Returns ErrNoRows if there is no such
+// entitlement or its order is not paid anymore, ErrDownloadLimitReached
+// if it has no downloads left, an error on failure or the entitlement
+// on success.
+func (d *db) CountDownload(id int64) (*Entitlement, error) {
+	countQuery := fmt.Sprintf(`
+	UPDATE %s SET download_count = download_count + 1
+	WHERE id = $1`, entitlementsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	e, err := scanEntitlement(tx.QueryRow(ctx, selectQuery+" AND e.id = $1 FOR UPDATE OF e", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find entitlement query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if e.Downloads >= e.DownloadLimit {
+		return nil, apperror.ErrDownloadLimitReached
+	}
+
+	if _, err := tx.Exec(ctx, countQuery, id); err != nil {
+		err = fmt.Errorf("failed to execute count download query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	e.Downloads++
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return e, nil
+}
+
+// findOne finds the entitlement by the query.
+func (d *db) findOne(query string, args ...interface{}) (*Entitlement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	e, err := scanEntitlement(d.conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find entitlement query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// scanEntitlement scans the row of selectQuery.
+func scanEntitlement(row pgx.Row) (*Entitlement, error) {
+	var e Entitlement
+	err := row.Scan(
+		&e.Id,
+		&e.UserId,
+		&e.OrderId,
+		&e.BookId,
+		&e.Title,
+		&e.Formats,
+		&e.Downloads,
+		&e.DownloadLimit,
+		&e.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
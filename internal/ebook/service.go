@@ -0,0 +1,303 @@
+package ebook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/blob"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes ebook service functionality.
+type Service interface {
+	SetFile(ctx context.Context, bookId int64, format string, data []byte) (*File, error)
+	GetFiles(ctx context.Context, bookId int64) ([]*File, error)
+	DeleteFile(ctx context.Context, bookId int64, format string) error
+	Grant(ctx context.Context, orderId int64) (int64, error)
+	GetLibrary(ctx context.Context, userId int64) ([]*Entitlement, error)
+	CreateLink(ctx context.Context, input *LinkDTO) (*Link, error)
+	Download(ctx context.Context, token string) (*Download, error)
+}
+
+type service struct {
+	logger        logger.Logger
+	storage       Storage
+	store         blob.Store
+	bookService   book.Service
+	signingKey    []byte
+	downloadURL   string
+	linkTTL       time.Duration
+	downloadLimit int32
+}
+
+// NewService returns a new instance that implements Service interface.
+// Download links are signed with signingKey, they point under downloadURL
+// and expire after linkTTL. Every entitlement allows downloadLimit downloads.
+func NewService(
+	storage Storage,
+	store blob.Store,
+	bookService book.Service,
+	signingKey []byte,
+	downloadURL string,
+	linkTTL time.Duration,
+	downloadLimit int32,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:        logger,
+		storage:       storage,
+		store:         store,
+		bookService:   bookService,
+		signingKey:    signingKey,
+		downloadURL:   strings.TrimSuffix(downloadURL, "/"),
+		linkTTL:       linkTTL,
+		downloadLimit: downloadLimit,
+	}
+}
+
+// SetFile saves the file of the ebook in the format to the blob store and
+// removes the previous file of the format. Every upload gets its own random
+// key, so files can't be downloaded by guessing their URLs. Returns ErrNoRows
+// if the book doesn't exist, ErrNotEbook if it's not an ebook and
+// ErrUnsupportedFile if the data is not a file of the format.
+func (s *service) SetFile(ctx context.Context, bookId int64, format string, data []byte) (*File, error) {
+	b, err := s.bookService.GetById(ctx, bookId)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.Format != book.FormatEbook {
+		return nil, apperror.ErrNotEbook
+	}
+
+	ft, ok := fileTypes[format]
+	if !ok || !ft.matches(data) {
+		return nil, ErrUnsupportedFile
+	}
+
+	previous, err := s.storage.FindFile(bookId, format)
+	if err != nil && !errors.Is(err, apperror.ErrNoRows) {
+		s.logger.Warnf("cannot find book file: %v", err)
+		return nil, err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("cannot generate file key: %v", err)
+	}
+
+	file := File{
+		BookId: bookId,
+		Format: format,
+		Size:   int64(len(data)),
+		Key:    fmt.Sprintf("books/%d/files/%s.%s", bookId, hex.EncodeToString(token), format),
+	}
+
+	if err := s.store.Put(ctx, file.Key, data, ft.contentType); err != nil {
+		s.logger.Errorf("failed to save book file: %v", err)
+		return nil, err
+	}
+
+	if err := s.storage.SaveFile(&file); err != nil {
+		s.removeObject(ctx, file.Key)
+		return nil, err
+	}
+
+	if previous != nil {
+		s.removeObject(ctx, previous.Key)
+	}
+
+	return &file, nil
+}
+
+// GetFiles returns files of the book. Returns ErrNoRows if the book
+// doesn't exist.
+func (s *service) GetFiles(ctx context.Context, bookId int64) ([]*File, error) {
+	if _, err := s.bookService.GetById(ctx, bookId); err != nil {
+		return nil, err
+	}
+
+	files, err := s.storage.FindFiles(bookId)
+	if err != nil {
+		s.logger.Warnf("cannot find book files: %v", err)
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// DeleteFile removes the file of the book in the format.
+// Returns ErrNoRows if there is no such file.
+func (s *service) DeleteFile(ctx context.Context, bookId int64, format string) error {
+	file, err := s.storage.FindFile(bookId, format)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find book file: %v", err)
+		}
+		return err
+	}
+
+	if err := s.storage.DeleteFile(bookId, format); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete book file: %v", err)
+		}
+		return err
+	}
+
+	s.removeObject(ctx, file.Key)
+
+	return nil
+}
+
+// Grant adds ebooks of the paid order to the library of the customer.
+// It's safe to call again, lines which have entitlements already are
+// skipped. Returns the number of new entitlements.
+func (s *service) Grant(ctx context.Context, orderId int64) (int64, error) {
+	granted, err := s.storage.Grant(orderId, s.downloadLimit)
+	if err != nil {
+		s.logger.Errorf("failed to grant entitlements: %v", err)
+		return 0, err
+	}
+
+	return granted, nil
+}
+
+// GetLibrary returns entitlements of the user to ebooks of paid orders,
+// latest first.
+func (s *service) GetLibrary(ctx context.Context, userId int64) ([]*Entitlement, error) {
+	entitlements, err := s.storage.FindByUser(userId)
+	if err != nil {
+		s.logger.Warnf("cannot find entitlements: %v", err)
+		return nil, err
+	}
+
+	return entitlements, nil
+}
+
+// CreateLink returns the signed link to download the ebook of the
+// entitlement in the format, which expires after linkTTL. Returns ErrNoRows
+// if the user has no such entitlement or the ebook has no file in the
+// format and ErrDownloadLimitReached if the entitlement has no downloads left.
+func (s *service) CreateLink(ctx context.Context, input *LinkDTO) (*Link, error) {
+	e, err := s.storage.FindById(input.UserId, input.EntitlementId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find entitlement by id: %v", err)
+		}
+		return nil, err
+	}
+
+	found := false
+	for _, format := range e.Formats {
+		found = found || format == input.Format
+	}
+	if !found {
+		return nil, apperror.ErrNoRows
+	}
+
+	if e.Downloads >= e.DownloadLimit {
+		return nil, apperror.ErrDownloadLimitReached
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL)
+	token := signToken(s.signingKey, e.Id, input.Format, expiresAt)
+
+	return &Link{
+		Format:    input.Format,
+		URL:       s.downloadURL + "/" + token,
+		ExpiresAt: expiresAt.Truncate(time.Second),
+	}, nil
+}
+
+// Download verifies the token of the download link, opens the file and
+// counts the download. The download is counted only once the file is
+// opened, so failures to read it don't use up the limit. Returns
+// ErrInvalidLink or ErrLinkExpired if the link can't be used, ErrNoRows
+// if the entitlement or the file doesn't exist anymore and
+// ErrDownloadLimitReached if the entitlement has no downloads left.
+func (s *service) Download(ctx context.Context, token string) (*Download, error) {
+	entitlementId, format, err := verifyToken(s.signingKey, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.storage.FindEntitlement(entitlementId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to find entitlement: %v", err)
+		}
+		return nil, err
+	}
+
+	if e.BookId == nil {
+		return nil, apperror.ErrNoRows
+	}
+
+	if e.Downloads >= e.DownloadLimit {
+		return nil, apperror.ErrDownloadLimitReached
+	}
+
+	file, err := s.storage.FindFile(*e.BookId, format)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find book file: %v", err)
+		}
+		return nil, err
+	}
+
+	body, err := s.store.Open(ctx, file.Key)
+	if err != nil {
+		s.logger.Errorf("failed to open book file %s: %v", file.Key, err)
+		return nil, err
+	}
+
+	if _, err := s.storage.CountDownload(e.Id); err != nil {
+		body.Close()
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrDownloadLimitReached) {
+			s.logger.Errorf("failed to count download: %v", err)
+		}
+		return nil, err
+	}
+
+	return &Download{
+		FileName:    fileName(e.Title, format),
+		ContentType: fileTypes[format].contentType,
+		Size:        file.Size,
+		Body:        body,
+	}, nil
+}
+
+// removeObject removes the object from the blob store. Failures are only
+// logged, the object is not referenced anymore.
+func (s *service) removeObject(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		s.logger.Warnf("failed to remove book file %s: %v", key, err)
+	}
+}
+
+// fileName returns the name of the downloaded file made of the title,
+// e.g. "War and Peace.epub". Characters which are not letters, digits,
+// spaces or hyphens are left out.
+func fileName(title, format string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' {
+			return r
+		}
+		return -1
+	}, title)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "ebook"
+	}
+
+	return name + "." + format
+}
@@ -0,0 +1,14 @@
+package ebook
+
+// Storage describes an ebook storage functionality.
+type Storage interface {
+	SaveFile(file *File) error
+	FindFile(bookId int64, format string) (*File, error)
+	FindFiles(bookId int64) ([]*File, error)
+	DeleteFile(bookId int64, format string) error
+	Grant(orderId int64, downloadLimit int32) (int64, error)
+	FindById(userId, id int64) (*Entitlement, error)
+	FindByUser(userId int64) ([]*Entitlement, error)
+	FindEntitlement(id int64) (*Entitlement, error)
+	CountDownload(id int64) (*Entitlement, error)
+}
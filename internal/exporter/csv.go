@@ -0,0 +1,96 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+)
+
+// csvHeader lists columns of the CSV file. Columns shared with
+// import have the same names, so the file can be imported back.
+var csvHeader = []string{
+	"id", "workId", "isbn", "isbn10", "title", "description", "authors", "genres", "language",
+	"publisher", "format", "edition", "publicationDate", "year", "pageCount", "price", "currency", "count", "coverUrl",
+}
+
+// csvEncoder writes books as CSV rows with a header line.
+// Authors and genres are separated with semicolons.
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Begin() error {
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvEncoder) Encode(b *book.Book) error {
+	authors := make([]string, len(b.Authors))
+	for i, a := range b.Authors {
+		authors[i] = formatContributor(a)
+	}
+
+	genres := make([]string, len(b.Genres))
+	for i, g := range b.Genres {
+		genres[i] = g.Genre
+	}
+
+	var language, publisher, coverURL string
+	if b.Language != nil {
+		language = b.Language.Language
+	}
+	if b.Publisher != nil {
+		publisher = b.Publisher.Name
+	}
+	if b.Cover != nil {
+		coverURL = b.Cover.OriginalURL
+	}
+
+	return e.w.Write([]string{
+		strconv.FormatInt(b.Id, 10),
+		strconv.FormatInt(b.WorkId, 10),
+		stringValue(b.ISBN13),
+		stringValue(b.ISBN10),
+		b.Title,
+		b.Description,
+		joinList(authors),
+		joinList(genres),
+		language,
+		publisher,
+		b.Format,
+		stringValue(b.Edition),
+		stringValue(b.PublicationDate),
+		int16Value(b.Year),
+		int16Value(b.PageCount),
+		b.Price.StringFixed(2),
+		b.Currency,
+		strconv.FormatInt(int64(b.Count), 10),
+		coverURL,
+	})
+}
+
+func (e *csvEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// stringValue returns the value of the optional string or an empty string.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// int16Value returns the value of the optional number or an empty string.
+func int16Value(n *int16) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*n), 10)
+}
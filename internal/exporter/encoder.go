@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+)
+
+const (
+	// FormatCSV is a format of comma separated values with a header line.
+	FormatCSV = "csv"
+	// FormatNDJSON is a format of newline delimited JSON objects.
+	FormatNDJSON = "ndjson"
+	// FormatONIX is a format of ONIX for Books 3.0 XML feed.
+	FormatONIX = "onix"
+)
+
+// formats describes content types and file extensions of export formats.
+var formats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	FormatCSV:    {contentType: "text/csv; charset=utf-8", extension: "csv"},
+	FormatNDJSON: {contentType: "application/x-ndjson", extension: "ndjson"},
+	FormatONIX:   {contentType: "application/xml; charset=utf-8", extension: "xml"},
+}
+
+// Encoder writes books to the export file one by one.
+type Encoder interface {
+	// Begin writes the beginning of the file, e.g. a header line.
+	Begin() error
+	// Encode writes a single book.
+	Encode(b *book.Book) error
+	// End writes the end of the file and flushes buffered data.
+	End() error
+}
+
+// NewEncoder returns an Encoder which writes books in the given format.
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w), nil
+	case FormatNDJSON:
+		return newNDJSONEncoder(w), nil
+	case FormatONIX:
+		return newONIXEncoder(w), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, use %s, %s or %s", format, FormatCSV, FormatNDJSON, FormatONIX)
+	}
+}
+
+// formatContributor formats the contributor as "Name Surname (role)".
+// The role is omitted for authors, which is the same format import accepts.
+func formatContributor(a book.BookAuthor) string {
+	if a.Role == book.RoleAuthor {
+		return a.Name + " " + a.Surname
+	}
+	return fmt.Sprintf("%s %s (%s)", a.Name, a.Surname, a.Role)
+}
+
+// joinList joins values with the separator of list fields.
+func joinList(values []string) string {
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	exportBooksURL = "/api/export/books"
+)
+
+// Handler handles catalog export requests.
+type Handler struct {
+	logger      logger.Logger
+	bookService book.Service
+}
+
+// NewHandler returns a new export Handler instance.
+func NewHandler(logger logger.Logger, bookService book.Service) handler.Handling {
+	return &Handler{
+		logger:      logger,
+		bookService: bookService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, exportBooksURL, h.ExportBooks)
+}
+
+// ExportBooks godoc
+// @Summary Export books
+// @Description Export catalog books with their contributors, genres, language, price and stock.
+// @Description Books are filtered like in the books list, but all matching books are exported at once.
+// @Description CSV can be imported back, NDJSON objects are the same as in the books API,
+// @Description ONIX is a basic ONIX for Books 3.0 feed.
+// @Tags export
+// @Produce plain
+// @Param format query string true "File format" Enums(csv, ndjson, onix)
+// @Param search query string false "Part of the book title"
+// @Param authorId query int64 false "Author id"
+// @Param genreId query int false "Genre id"
+// @Param languageId query int false "Language id"
+// @Param workId query int64 false "Work id"
+// @Success 200 {string} string "Export file"
+// @Failure 400 {object} apperror.AppError
+// @Router /export/books [get]
+func (h *Handler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("EXPORT BOOKS")
+
+	format := r.URL.Query().Get("format")
+	encoder, err := NewEncoder(format, w)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	filter, err := book.ReadFilter(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	fileName := fmt.Sprintf("books-%s.%s", time.Now().UTC().Format("20060102-150405"), formats[format].extension)
+	w.Header().Set("Content-Type", formats[format].contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+
+	err = encoder.Begin()
+	if err == nil {
+		err = h.bookService.Stream(r.Context(), filter, encoder.Encode)
+	}
+	if err == nil {
+		err = encoder.End()
+	}
+
+	// The status is already sent, so the response is aborted
+	// to let the client know the file is incomplete.
+	if err != nil {
+		h.logger.Errorf("failed to export books: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+}
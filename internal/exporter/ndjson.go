@@ -0,0 +1,36 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+)
+
+// ndjsonEncoder writes every book as a JSON object on its own line.
+// Objects have the same structure as in the books API.
+type ndjsonEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	buffered := bufio.NewWriter(w)
+	return &ndjsonEncoder{
+		w:   buffered,
+		enc: json.NewEncoder(buffered),
+	}
+}
+
+func (e *ndjsonEncoder) Begin() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) Encode(b *book.Book) error {
+	return e.enc.Encode(b)
+}
+
+func (e *ndjsonEncoder) End() error {
+	return e.w.Flush()
+}
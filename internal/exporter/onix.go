@@ -0,0 +1,360 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+)
+
+const (
+	// onixNamespace is a namespace of ONIX 3.0 reference tags.
+	onixNamespace = "http://ns.editeur.org/onix/3.0/reference"
+
+	// onixSender is a name of the feed sender.
+	onixSender = "ReadyRead"
+)
+
+// onixProductForms maps book formats to ONIX product form codes (list 150).
+var onixProductForms = map[string]string{
+	book.FormatHardcover: "BB",
+	book.FormatPaperback: "BC",
+	book.FormatEbook:     "ED",
+}
+
+// onixContributorRoles maps contributor roles to ONIX codes (list 17).
+var onixContributorRoles = map[string]string{
+	book.RoleAuthor:      "A01",
+	book.RoleIllustrator: "A12",
+	book.RoleEditor:      "B01",
+	book.RoleTranslator:  "B06",
+}
+
+// onixLanguages maps two-letter language codes to ISO 639-2/B codes
+// used by ONIX (list 74). Languages stored with three-letter codes
+// are exported as is, others are omitted.
+var onixLanguages = map[string]string{
+	"ar": "ara", "be": "bel", "cs": "cze", "de": "ger", "en": "eng", "es": "spa",
+	"fr": "fre", "it": "ita", "ja": "jpn", "kk": "kaz", "ko": "kor", "nl": "dut",
+	"pl": "pol", "pt": "por", "ru": "rus", "sv": "swe", "tr": "tur", "uk": "ukr",
+	"zh": "chi",
+}
+
+// onixEncoder writes books as products of ONIX for Books 3.0 message.
+// Only the basic blocks are filled: identifiers, descriptive details,
+// description and cover, publisher, and price with stock.
+type onixEncoder struct {
+	w   *bufio.Writer
+	enc *xml.Encoder
+	now func() time.Time
+}
+
+func newONIXEncoder(w io.Writer) *onixEncoder {
+	buffered := bufio.NewWriter(w)
+	return &onixEncoder{
+		w:   buffered,
+		enc: xml.NewEncoder(buffered),
+		now: time.Now,
+	}
+}
+
+func (e *onixEncoder) Begin() error {
+	header := struct {
+		XMLName      xml.Name `xml:"Header"`
+		SenderName   string   `xml:"Sender>SenderName"`
+		SentDateTime string   `xml:"SentDateTime"`
+	}{
+		SenderName:   onixSender,
+		SentDateTime: e.now().UTC().Format("20060102T1504Z"),
+	}
+
+	_, err := fmt.Fprintf(e.w, "%s<ONIXMessage release=\"3.0\" xmlns=\"%s\">", xml.Header, onixNamespace)
+	if err != nil {
+		return err
+	}
+
+	return e.enc.Encode(header)
+}
+
+func (e *onixEncoder) Encode(b *book.Book) error {
+	return e.enc.Encode(newONIXProduct(b))
+}
+
+func (e *onixEncoder) End() error {
+	if err := e.enc.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := e.w.WriteString("</ONIXMessage>\n"); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+type onixProduct struct {
+	XMLName            xml.Name                `xml:"Product"`
+	RecordReference    string                  `xml:"RecordReference"`
+	NotificationType   string                  `xml:"NotificationType"`
+	ProductIdentifiers []onixProductIdentifier `xml:"ProductIdentifier"`
+	DescriptiveDetail  onixDescriptiveDetail   `xml:"DescriptiveDetail"`
+	CollateralDetail   onixCollateralDetail    `xml:"CollateralDetail"`
+	PublishingDetail   *onixPublishingDetail   `xml:"PublishingDetail,omitempty"`
+	ProductSupply      onixProductSupply       `xml:"ProductSupply"`
+}
+
+type onixProductIdentifier struct {
+	ProductIDType string `xml:"ProductIDType"`
+	IDTypeName    string `xml:"IDTypeName,omitempty"`
+	IDValue       string `xml:"IDValue"`
+}
+
+type onixDescriptiveDetail struct {
+	ProductComposition string            `xml:"ProductComposition"`
+	ProductForm        string            `xml:"ProductForm"`
+	Measures           []onixMeasure     `xml:"Measure"`
+	TitleDetail        onixTitleDetail   `xml:"TitleDetail"`
+	Contributors       []onixContributor `xml:"Contributor"`
+	EditionStatement   string            `xml:"EditionStatement,omitempty"`
+	Languages          []onixLanguage    `xml:"Language"`
+	Extents            []onixExtent      `xml:"Extent"`
+	Subjects           []onixSubject     `xml:"Subject"`
+}
+
+type onixTitleDetail struct {
+	TitleType         string `xml:"TitleType"`
+	TitleElementLevel string `xml:"TitleElement>TitleElementLevel"`
+	TitleText         string `xml:"TitleElement>TitleText"`
+}
+
+type onixMeasure struct {
+	MeasureType     string `xml:"MeasureType"`
+	Measurement     string `xml:"Measurement"`
+	MeasureUnitCode string `xml:"MeasureUnitCode"`
+}
+
+type onixContributor struct {
+	SequenceNumber  int    `xml:"SequenceNumber"`
+	ContributorRole string `xml:"ContributorRole"`
+	NamesBeforeKey  string `xml:"NamesBeforeKey"`
+	KeyNames        string `xml:"KeyNames"`
+}
+
+type onixLanguage struct {
+	LanguageRole string `xml:"LanguageRole"`
+	LanguageCode string `xml:"LanguageCode"`
+}
+
+type onixExtent struct {
+	ExtentType  string `xml:"ExtentType"`
+	ExtentValue string `xml:"ExtentValue"`
+	ExtentUnit  string `xml:"ExtentUnit"`
+}
+
+type onixSubject struct {
+	SubjectSchemeIdentifier string `xml:"SubjectSchemeIdentifier"`
+	SubjectHeadingText      string `xml:"SubjectHeadingText"`
+}
+
+type onixCollateralDetail struct {
+	TextContent        []onixTextContent        `xml:"TextContent"`
+	SupportingResource []onixSupportingResource `xml:"SupportingResource"`
+}
+
+type onixTextContent struct {
+	TextType        string `xml:"TextType"`
+	ContentAudience string `xml:"ContentAudience"`
+	Text            string `xml:"Text"`
+}
+
+type onixSupportingResource struct {
+	ResourceContentType string `xml:"ResourceContentType"`
+	ContentAudience     string `xml:"ContentAudience"`
+	ResourceMode        string `xml:"ResourceMode"`
+	ResourceForm        string `xml:"ResourceVersion>ResourceForm"`
+	ResourceLink        string `xml:"ResourceVersion>ResourceLink"`
+}
+
+type onixPublishingDetail struct {
+	Publisher       *onixPublisher       `xml:"Publisher,omitempty"`
+	PublishingDates []onixPublishingDate `xml:"PublishingDate"`
+}
+
+type onixPublisher struct {
+	PublishingRole string `xml:"PublishingRole"`
+	PublisherName  string `xml:"PublisherName"`
+}
+
+type onixPublishingDate struct {
+	PublishingDateRole string `xml:"PublishingDateRole"`
+	Date               string `xml:"Date"`
+}
+
+type onixProductSupply struct {
+	SupplierRole        string `xml:"SupplyDetail>Supplier>SupplierRole"`
+	SupplierName        string `xml:"SupplyDetail>Supplier>SupplierName"`
+	ProductAvailability string `xml:"SupplyDetail>ProductAvailability"`
+	OnHand              int32  `xml:"SupplyDetail>Stock>OnHand"`
+	PriceType           string `xml:"SupplyDetail>Price>PriceType"`
+	PriceAmount         string `xml:"SupplyDetail>Price>PriceAmount"`
+	CurrencyCode        string `xml:"SupplyDetail>Price>CurrencyCode"`
+}
+
+// newONIXProduct converts the book to ONIX product record.
+func newONIXProduct(b *book.Book) onixProduct {
+	product := onixProduct{
+		RecordReference:  fmt.Sprintf("readyread.book.%d", b.Id),
+		NotificationType: "03",
+		ProductIdentifiers: []onixProductIdentifier{{
+			ProductIDType: "01",
+			IDTypeName:    onixSender,
+			IDValue:       strconv.FormatInt(b.Id, 10),
+		}},
+		DescriptiveDetail: onixDescriptiveDetail{
+			ProductComposition: "00",
+			ProductForm:        onixProductForms[b.Format],
+			TitleDetail: onixTitleDetail{
+				TitleType:         "01",
+				TitleElementLevel: "01",
+				TitleText:         b.Title,
+			},
+		},
+		CollateralDetail: onixCollateralDetail{
+			TextContent: []onixTextContent{{
+				TextType:        "03",
+				ContentAudience: "00",
+				Text:            b.Description,
+			}},
+		},
+		ProductSupply: onixProductSupply{
+			SupplierRole:        "00",
+			SupplierName:        onixSender,
+			ProductAvailability: "21",
+			OnHand:              b.Count,
+			PriceType:           "01",
+			PriceAmount:         b.Price.StringFixed(2),
+			CurrencyCode:        b.Currency,
+		},
+	}
+
+	if b.ISBN13 != nil {
+		product.ProductIdentifiers = append(product.ProductIdentifiers, onixProductIdentifier{
+			ProductIDType: "15",
+			IDValue:       *b.ISBN13,
+		})
+	}
+
+	if b.Count <= 0 {
+		product.ProductSupply.ProductAvailability = "31"
+	}
+
+	detail := &product.DescriptiveDetail
+	if b.Edition != nil {
+		detail.EditionStatement = *b.Edition
+	}
+
+	measures := []struct {
+		measureType string
+		value       *int16
+	}{
+		{measureType: "01", value: b.HeightMm},
+		{measureType: "02", value: b.WidthMm},
+		{measureType: "03", value: b.ThicknessMm},
+	}
+	for _, m := range measures {
+		if m.value != nil {
+			detail.Measures = append(detail.Measures, onixMeasure{
+				MeasureType:     m.measureType,
+				Measurement:     strconv.FormatInt(int64(*m.value), 10),
+				MeasureUnitCode: "mm",
+			})
+		}
+	}
+	if b.WeightGrams != nil {
+		detail.Measures = append(detail.Measures, onixMeasure{
+			MeasureType:     "08",
+			Measurement:     strconv.FormatInt(int64(*b.WeightGrams), 10),
+			MeasureUnitCode: "gr",
+		})
+	}
+
+	for i, a := range b.Authors {
+		detail.Contributors = append(detail.Contributors, onixContributor{
+			SequenceNumber:  i + 1,
+			ContributorRole: onixContributorRoles[a.Role],
+			NamesBeforeKey:  a.Name,
+			KeyNames:        a.Surname,
+		})
+	}
+
+	if b.Language != nil {
+		if code := onixLanguageCode(b.Language.Language); code != "" {
+			detail.Languages = append(detail.Languages, onixLanguage{
+				LanguageRole: "01",
+				LanguageCode: code,
+			})
+		}
+	}
+
+	if b.PageCount != nil {
+		detail.Extents = append(detail.Extents, onixExtent{
+			ExtentType:  "00",
+			ExtentValue: strconv.FormatInt(int64(*b.PageCount), 10),
+			ExtentUnit:  "03",
+		})
+	}
+
+	for _, g := range b.Genres {
+		detail.Subjects = append(detail.Subjects, onixSubject{
+			SubjectSchemeIdentifier: "20",
+			SubjectHeadingText:      g.Genre,
+		})
+	}
+
+	if b.Cover != nil {
+		product.CollateralDetail.SupportingResource = append(product.CollateralDetail.SupportingResource, onixSupportingResource{
+			ResourceContentType: "01",
+			ContentAudience:     "00",
+			ResourceMode:        "03",
+			ResourceForm:        "02",
+			ResourceLink:        b.Cover.OriginalURL,
+		})
+	}
+
+	if b.Publisher != nil || b.PublicationDate != nil {
+		publishing := onixPublishingDetail{}
+		if b.Publisher != nil {
+			publishing.Publisher = &onixPublisher{
+				PublishingRole: "01",
+				PublisherName:  b.Publisher.Name,
+			}
+		}
+		if b.PublicationDate != nil {
+			publishing.PublishingDates = append(publishing.PublishingDates, onixPublishingDate{
+				PublishingDateRole: "01",
+				Date:               strings.ReplaceAll(*b.PublicationDate, "-", ""),
+			})
+		}
+		product.PublishingDetail = &publishing
+	}
+
+	return product
+}
+
+// onixLanguageCode returns ISO 639-2/B code of the language
+// or an empty string if the language is unknown.
+func onixLanguageCode(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if code, ok := onixLanguages[language]; ok {
+		return code
+	}
+	if len(language) == 3 {
+		return language
+	}
+	return ""
+}
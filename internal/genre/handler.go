@@ -34,6 +34,7 @@ func NewHandler(logger logger.Logger, genreService Service) handler.Handling {
 // Register registers new routes for router.
 func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodGet, genreURL, h.GetGenre)
+	router.HandlerFunc(http.MethodGet, genresURL, h.ListGenres)
 	router.HandlerFunc(http.MethodPost, genresURL, h.CreateGenre)
 	router.HandlerFunc(http.MethodPut, genreURL, h.UpdateGenre)
 	router.HandlerFunc(http.MethodDelete, genreURL, h.DeleteGenre)
@@ -73,6 +74,39 @@ func (h *Handler) GetGenre(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, author)
 }
 
+// ListGenres godoc
+// @Summary List genres
+// @Description Get genres with the number of books of each one. Optionally filtered by name.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param search query string false "Part of the genre name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Genre
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /genres [get]
+func (h *Handler) ListGenres(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST GENRES")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+
+	genres, err := h.genreService.GetAll(r.Context(), search, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, genres)
+}
+
 // CreateGenre godoc
 // @Summary Create genre
 // @Description Insert genre in database.
@@ -171,6 +205,7 @@ func (h *Handler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
 // @Param id path int64 true "Genre id"
 // @Success 200
 // @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
 // @Failure 500 {object} apperror.AppError
 // @Router /genres/{id} [delete]
 func (h *Handler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
@@ -188,6 +223,10 @@ func (h *Handler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrReferenced) {
+			response.Conflict(w, err.Error(), "delete or reassign books of this genre first")
+			return
+		}
 		response.InternalError(w, err.Error(), "something went wrong on the server side")
 		return
 	}
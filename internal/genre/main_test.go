@@ -0,0 +1,12 @@
+package genre
+
+import (
+	"os"
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
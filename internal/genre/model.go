@@ -7,8 +7,9 @@ import (
 
 // Genre represents the genre model.
 type Genre struct {
-	Id    int16  `json:"id" example:"123"`
-	Genre string `json:"genre" example:"fantasy"`
+	Id         int16  `json:"id" example:"123"`
+	Genre      string `json:"genre" example:"fantasy"`
+	BooksCount *int64 `json:"booksCount,omitempty" example:"42"`
 } // @name Genre
 
 // CreateGenreDTO is used to create genre.
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/internal/apperror"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 )
 
 const (
-	tableName = "genres"
+	tableName           = "genres"
+	bookGenresTableName = "book_genres"
 )
 
 // Check whether db implements genre storage interface.
@@ -21,12 +23,12 @@ var _ Storage = &db{}
 // db implements genre storage interface.
 type db struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 // NewStorage returns a new genre storage instance.
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &db{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -119,15 +121,69 @@ func (d *db) Update(genre *UpdateGenreDTO) error {
 	return nil
 }
 
+// FindAll returns genres which names contain given search string
+// along with the number of books for each of them.
+// Returns an error on failure.
+func (d *db) FindAll(search string, limit, offset int) ([]*Genre, error) {
+	query := fmt.Sprintf(`
+	SELECT t.id, t.genre, COUNT(b.book_id)
+	FROM %s t
+	LEFT JOIN %s b ON b.genre_id = t.id
+	WHERE t.genre ILIKE '%%' || $1 || '%%'
+	GROUP BY t.id
+	ORDER BY t.genre
+	LIMIT $2 OFFSET $3`, tableName, bookGenresTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, search, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all genres query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := make([]*Genre, 0)
+	for rows.Next() {
+		var genre Genre
+		if err := rows.Scan(&genre.Id, &genre.Genre, &genre.BooksCount); err != nil {
+			err = fmt.Errorf("failed to scan genre: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		genres = append(genres, &genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read genres: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// Delete deletes the genre with specified id.
+// Returns ErrNoRows if genre doesn't exist, ErrReferenced if
+// there are books of this genre or an error on failure.
 func (d *db) Delete(id int16) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
-	_, err := d.conn.Exec(ctx, query, id)
+	result, err := d.conn.Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete author: %v", err)
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrReferenced
+		}
+		return fmt.Errorf("failed to delete genre: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
 	}
 
 	return nil
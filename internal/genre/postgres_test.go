@@ -0,0 +1,186 @@
+package genre
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+// newStorage returns the storage backed by a new test database.
+func newStorage(t *testing.T) (Storage, *pgxpool.Pool) {
+	t.Helper()
+
+	conn := testdb.New(t)
+	return NewStorage(conn, 5), conn
+}
+
+// mustCreate creates genres with specified names.
+func mustCreate(t *testing.T, s Storage, names ...string) []*Genre {
+	t.Helper()
+
+	genres := make([]*Genre, 0, len(names))
+	for _, name := range names {
+		genre, err := s.Create(&Genre{Genre: name})
+		if err != nil {
+			t.Fatalf("cannot create genre %q: %v", name, err)
+		}
+		genres = append(genres, genre)
+	}
+	return genres
+}
+
+// mustCreateBook creates a book of the genre.
+func mustCreateBook(t *testing.T, conn *pgxpool.Pool, genreId int16) {
+	t.Helper()
+
+	_, err := conn.Exec(context.Background(), `
+	WITH w AS (INSERT INTO works (title) VALUES ('Dune') RETURNING id),
+		l AS (INSERT INTO languages (language) VALUES ('en') RETURNING id),
+		b AS (
+			INSERT INTO books (title, description, price, count, language_id, work_id)
+			SELECT 'Dune', 'Desert planet', 9.99, 1, l.id, w.id FROM w, l
+			RETURNING id
+		)
+	INSERT INTO book_genres (book_id, genre_id) SELECT b.id, $1 FROM b`, genreId)
+	if err != nil {
+		t.Fatalf("cannot create book: %v", err)
+	}
+}
+
+func TestFindById(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "fantasy")[0]
+
+	tests := []struct {
+		name    string
+		id      int16
+		want    *Genre
+		wantErr error
+	}{
+		{name: "existing", id: created.Id, want: &Genre{Id: created.Id, Genre: "fantasy"}},
+		{name: "not found", id: created.Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindById(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FindById() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindById() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	s, conn := newStorage(t)
+	genres := mustCreate(t, s, "horror", "fantasy", "poetry")
+	mustCreateBook(t, conn, genres[1].Id)
+
+	tests := []struct {
+		name   string
+		search string
+		limit  int
+		offset int
+		want   []string
+		counts []int64
+	}{
+		{name: "all ordered by name", limit: 10, want: []string{"fantasy", "horror", "poetry"}, counts: []int64{1, 0, 0}},
+		{name: "search is case insensitive", search: "HOR", limit: 10, want: []string{"horror"}, counts: []int64{0}},
+		{name: "limit and offset", limit: 1, offset: 1, want: []string{"horror"}, counts: []int64{0}},
+		{name: "nothing found", search: "drama", limit: 10, want: []string{}, counts: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindAll(tt.search, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+
+			names := make([]string, 0, len(got))
+			counts := make([]int64, 0, len(got))
+			for _, genre := range got {
+				names = append(names, genre.Genre)
+				counts = append(counts, *genre.BooksCount)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(counts, tt.counts) {
+				t.Errorf("FindAll() books counts = %v, want %v", counts, tt.counts)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "fantasy")[0]
+
+	tests := []struct {
+		name    string
+		input   *UpdateGenreDTO
+		wantErr error
+	}{
+		{name: "existing", input: &UpdateGenreDTO{Id: created.Id, Genre: "fairytale"}},
+		{name: "not found", input: &UpdateGenreDTO{Id: created.Id + 1, Genre: "drama"}, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Update(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if got.Genre != tt.input.Genre {
+				t.Errorf("genre = %q, want %q", got.Genre, tt.input.Genre)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s, conn := newStorage(t)
+	genres := mustCreate(t, s, "fantasy", "horror")
+	mustCreateBook(t, conn, genres[1].Id)
+
+	tests := []struct {
+		name    string
+		id      int16
+		wantErr error
+	}{
+		{name: "unused", id: genres[0].Id},
+		{name: "already deleted", id: genres[0].Id, wantErr: apperror.ErrNoRows},
+		{name: "referenced by books", id: genres[1].Id, wantErr: apperror.ErrReferenced},
+		{name: "not found", id: genres[1].Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Delete(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := s.FindById(tt.id)
+			if tt.wantErr == nil && !errors.Is(err, apperror.ErrNoRows) {
+				t.Errorf("FindById() error = %v, want %v", err, apperror.ErrNoRows)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 type Service interface {
 	Create(ctx context.Context, genre *CreateGenreDTO) (*Genre, error)
 	GetById(ctx context.Context, id int16) (*Genre, error)
+	GetAll(ctx context.Context, search string, limit, offset int) ([]*Genre, error)
 	Update(ctx context.Context, genre *UpdateGenreDTO) error
 	Delete(ctx context.Context, id int16) error
 }
@@ -55,6 +56,16 @@ func (s *service) GetById(ctx context.Context, id int16) (*Genre, error) {
 	return genre, nil
 }
 
+func (s *service) GetAll(ctx context.Context, search string, limit, offset int) ([]*Genre, error) {
+	genres, err := s.storage.FindAll(search, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find genres: %v", err)
+		return nil, err
+	}
+
+	return genres, nil
+}
+
 func (s *service) Update(ctx context.Context, genre *UpdateGenreDTO) error {
 	a, err := s.GetById(ctx, genre.Id)
 	if err != nil {
@@ -80,7 +91,7 @@ func (s *service) Update(ctx context.Context, genre *UpdateGenreDTO) error {
 func (s *service) Delete(ctx context.Context, id int16) error {
 	err := s.storage.Delete(id)
 	if err != nil {
-		if !errors.Is(err, apperror.ErrNoRows) {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrReferenced) {
 			s.logger.Warnf("failed to delete genre: %v", err)
 		}
 		return err
@@ -4,6 +4,7 @@ package genre
 type Storage interface {
 	Create(genre *Genre) (*Genre, error)
 	FindById(id int16) (*Genre, error)
+	FindAll(search string, limit, offset int) ([]*Genre, error)
 	Update(genre *UpdateGenreDTO) error
 	Delete(id int16) error
 }
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned when reading the request body limited
+// with LimitBody takes more bytes than allowed.
+var ErrBodyTooLarge = errors.New("request body is too large")
+
+// LimitBody limits the request body to n bytes. Reading past the limit
+// returns ErrBodyTooLarge and the connection is closed after the response,
+// so the rest of the body is not read.
+func LimitBody(w http.ResponseWriter, r *http.Request, n int64) {
+	r.Body = &limitedBody{w: w, body: r.Body, remaining: n}
+}
+
+// limitedBody is a request body which can't be read past the limit.
+type limitedBody struct {
+	w         http.ResponseWriter
+	body      io.ReadCloser
+	remaining int64
+	err       error
+}
+
+// Read reads up to len(p) bytes of the body. One byte more than
+// remaining is requested to find out whether the body exceeds the limit.
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.body.Read(p)
+	if int64(n) <= l.remaining {
+		l.remaining -= int64(n)
+		l.err = err
+		return n, err
+	}
+
+	n = int(l.remaining)
+	l.remaining = 0
+	l.err = ErrBodyTooLarge
+	l.w.Header().Set("Connection", "close")
+
+	return n, l.err
+}
+
+// Close closes the underlying body.
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
+
+// exceeded reports whether the body has been read past the limit.
+func (l *limitedBody) exceeded() bool {
+	return errors.Is(l.err, ErrBodyTooLarge)
+}
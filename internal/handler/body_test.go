@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		limit   int64
+		want    string
+		wantErr error
+	}{
+		{name: "shorter", body: "abc", limit: 5, want: "abc"},
+		{name: "exactly the limit", body: "abcde", limit: 5, want: "abcde"},
+		{name: "longer", body: "abcdef", limit: 5, want: "abcde", wantErr: ErrBodyTooLarge},
+		{name: "empty limit", body: "a", limit: 0, want: "", wantErr: ErrBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			LimitBody(w, r, tt.limit)
+			got, err := ioutil.ReadAll(r.Body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadAll() error = %v, want %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReadAll() = %q, want %q", got, tt.want)
+			}
+
+			closes := w.Header().Get("Connection") == "close"
+			if closes != (tt.wantErr != nil) {
+				t.Errorf("Connection header = %q", w.Header().Get("Connection"))
+			}
+		})
+	}
+}
@@ -19,6 +19,18 @@ func ReadIdParam64(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+// ReadParam64 reads the positive int64 path parameter with specified name.
+func ReadParam64(r *http.Request, name string) (int64, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+
+	value, err := strconv.ParseInt(params.ByName(name), 10, 64)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("%s must have type int64", name)
+	}
+
+	return value, nil
+}
+
 func ReadIdParam32(r *http.Request) (int32, error) {
 	params := httprouter.ParamsFromContext(r.Context())
 
@@ -40,3 +52,55 @@ func ReadIdParam16(r *http.Request) (int16, error) {
 
 	return int16(id), nil
 }
+
+const (
+	defaultPage     = 1
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Pagination describes page-based listing parameters.
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Limit returns the number of records to be fetched.
+func (p *Pagination) Limit() int {
+	return p.PageSize
+}
+
+// Offset returns the number of records to be skipped.
+func (p *Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// ReadPagination reads page and pageSize query parameters.
+// Missing parameters are set to their defaults. Returns an error
+// if parameters are not positive integers or pageSize is too big.
+func ReadPagination(r *http.Request) (*Pagination, error) {
+	p := Pagination{
+		Page:     defaultPage,
+		PageSize: defaultPageSize,
+	}
+
+	query := r.URL.Query()
+
+	if page := query.Get("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("page must be a positive integer")
+		}
+		p.Page = value
+	}
+
+	if pageSize := query.Get("pageSize"); pageSize != "" {
+		value, err := strconv.Atoi(pageSize)
+		if err != nil || value < 1 || value > maxPageSize {
+			return nil, fmt.Errorf("pageSize must be an integer between 1 and %d", maxPageSize)
+		}
+		p.PageSize = value
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// imageField is a name of the multipart form field with uploaded image.
+const imageField = "image"
+
+// ErrUploadTooLarge is returned when request body exceeds the upload limit.
+var ErrUploadTooLarge = errors.New("uploaded file is too large")
+
+// ReadImage reads an image uploaded as multipart form field "image".
+// The request body is limited to maxSize bytes plus some room for the form
+// itself. Returns ErrUploadTooLarge if the file is bigger than maxSize.
+func ReadImage(w http.ResponseWriter, r *http.Request, maxSize int64) ([]byte, error) {
+	return ReadFile(w, r, imageField, maxSize)
+}
+
+// ReadFile reads a file uploaded as multipart form field with specified
+// name. The request body is limited the same way as by ReadImage.
+func ReadFile(w http.ResponseWriter, r *http.Request, field string, maxSize int64) ([]byte, error) {
+	body := &limitedBody{w: w, body: r.Body, remaining: maxSize + 1<<20}
+	r.Body = body
+
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		// Multipart reader doesn't wrap some read errors,
+		// so the body is asked whether it was too large.
+		if body.exceeded() {
+			return nil, ErrUploadTooLarge
+		}
+		return nil, fmt.Errorf("invalid multipart form: %v", err)
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return nil, fmt.Errorf("form field %q with %s must be provided", field, field)
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read uploaded file: %v", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	importBooksURL = "/api/import/books"
+
+	// maxImportSize limits the size of the uploaded import file.
+	maxImportSize = 64 << 20
+)
+
+// contentTypeFormats maps content types to import formats.
+var contentTypeFormats = map[string]string{
+	"text/csv":             FormatCSV,
+	"application/x-ndjson": FormatNDJSON,
+	"application/ndjson":   FormatNDJSON,
+}
+
+// Handler handles requests specified to import service.
+type Handler struct {
+	logger        logger.Logger
+	importService Service
+}
+
+// NewHandler returns a new import Handler instance.
+func NewHandler(logger logger.Logger, importService Service) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		importService: importService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, importBooksURL, h.ImportBooks)
+}
+
+// ImportBooks godoc
+// @Summary Import books
+// @Description Import books from CSV or NDJSON file sent as request body. Books are upserted by ISBN,
+// @Description authors, genres, languages and publishers are found by name or created.
+// @Description CSV must have a header line, authors and genres are separated with semicolons.
+// @Description Invalid rows are skipped and listed in the report. Dry run reports changes without saving them.
+// @Description Prices are in the currency column or the base currency, prices of existing books must be in their currency.
+// @Tags import
+// @Accept plain
+// @Produce json
+// @Param format query string false "File format, detected from Content-Type if omitted" Enums(csv, ndjson)
+// @Param dryRun query bool false "Validate and report changes without saving them"
+// @Param file body string true "CSV or NDJSON file"
+// @Success 200 {object} Report
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /import/books [post]
+func (h *Handler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("IMPORT BOOKS")
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = contentTypeFormats[contentType]
+	}
+	if format == "" {
+		response.BadRequest(w, "unknown file format", "set format query parameter to csv or ndjson")
+		return
+	}
+
+	dryRun := false
+	if value := query.Get("dryRun"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			response.BadRequest(w, "dryRun must have type bool", "")
+			return
+		}
+	}
+
+	handler.LimitBody(w, r, maxImportSize)
+
+	report, err := h.importService.Import(r.Context(), r.Body, format, dryRun)
+	if err != nil {
+		if errors.Is(err, ErrInvalidFile) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		if errors.Is(err, ErrCurrencyMismatch) {
+			response.Conflict(w, err.Error(), "prices of existing books must be in the currency of the book")
+			return
+		}
+		if errors.Is(err, handler.ErrBodyTooLarge) {
+			response.TooLarge(w, "import file is too large", "split the file into smaller ones")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/juicyluv/ReadyRead/pkg/isbn"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FormatCSV is a format of comma separated values with a header line.
+	FormatCSV = "csv"
+	// FormatNDJSON is a format of newline delimited JSON objects.
+	FormatNDJSON = "ndjson"
+
+	// dateLayout is a layout of the publication date.
+	dateLayout = "2006-01-02"
+)
+
+// ErrInvalidFile is returned when the file cannot be imported at all,
+// e.g. it has an unknown format or a broken CSV header.
+var ErrInvalidFile = errors.New("invalid import file")
+
+// ErrCurrencyMismatch is returned when the imported price of the existing
+// book is in another currency than the book is priced in.
+var ErrCurrencyMismatch = errors.New("currency differs from the currency of the book")
+
+// Row represents a single book in the import file.
+// Authors are written as "Name Surname" with an optional role
+// in parentheses, e.g. "Richard Pevear (translator)".
+// Related records are referenced by name and created when missing.
+// The price is in the currency of the book. Currency defaults to the base
+// one for new books and must match the currency of existing books.
+type Row struct {
+	Line            int             `json:"-"`
+	ISBN            string          `json:"isbn" example:"978-0-14-044793-4"`
+	Title           string          `json:"title" example:"War and Peace"`
+	Description     string          `json:"description" example:"Novel about the French invasion of Russia."`
+	Year            *int16          `json:"year,omitempty" example:"1869"`
+	Price           decimal.Decimal `json:"price" swaggertype:"string" example:"12.99"`
+	Currency        *string         `json:"currency,omitempty" example:"USD"`
+	PageCount       *int16          `json:"pageCount,omitempty" example:"1225"`
+	Count           int32           `json:"count" example:"10"`
+	Format          string          `json:"format,omitempty" example:"paperback"`
+	Edition         *string         `json:"edition,omitempty" example:"Penguin Classics"`
+	PublicationDate *string         `json:"publicationDate,omitempty" example:"2007-10-30"`
+	Publisher       *string         `json:"publisher,omitempty" example:"Penguin Books"`
+	Language        string          `json:"language" example:"en"`
+	Authors         []string        `json:"authors" example:"Lev Tolstoy,Richard Pevear (translator)"`
+	Genres          []string        `json:"genres" example:"novel,historical fiction"`
+
+	// contributors are parsed from authors during validation.
+	contributors []contributor
+	// publishedAt is parsed from publication date during validation.
+	publishedAt *time.Time
+} // @name ImportRow
+
+// contributor is an author of the imported book with its role.
+type contributor struct {
+	Name    string
+	Surname string
+	Role    string
+}
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+// On success ISBN is normalized to ISBN-13 and authors are parsed.
+func (r *Row) Validate() error {
+	if r.Format == "" {
+		r.Format = book.FormatPaperback
+	}
+
+	err := validation.ValidateStruct(
+		r,
+		validation.Field(&r.ISBN, validation.Required, validator.ISBN),
+		validation.Field(&r.Title, validation.RuneLength(1, 200), validation.Required),
+		validation.Field(&r.Description, validation.RuneLength(1, 5000), validation.Required),
+		validation.Field(&r.Year, validation.Min(1)),
+		validation.Field(&r.Price, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&r.Currency, validator.CurrencyCode),
+		validation.Field(&r.PageCount, validation.Min(1)),
+		validation.Field(&r.Count, validation.Min(0)),
+		validation.Field(&r.Format, validation.In(book.FormatHardcover, book.FormatPaperback, book.FormatEbook)),
+		validation.Field(&r.Edition, validation.RuneLength(1, 100)),
+		validation.Field(&r.PublicationDate, validation.Date(dateLayout)),
+		validation.Field(&r.Publisher, validation.RuneLength(1, 200)),
+		validation.Field(&r.Language, validation.Required, validation.RuneLength(1, 50)),
+		validation.Field(&r.Authors, validation.Required, validation.Each(validation.Required)),
+		validation.Field(&r.Genres, validation.Required, validation.Each(validation.Required, validation.RuneLength(1, 100))),
+	)
+	if err != nil {
+		return err
+	}
+
+	contributors, err := parseContributors(r.Authors)
+	if err != nil {
+		return validation.Errors{"authors": err}
+	}
+
+	genres := make(map[string]bool, len(r.Genres))
+	for _, g := range r.Genres {
+		key := strings.ToLower(g)
+		if genres[key] {
+			return validation.Errors{"genres": fmt.Errorf("genre %q is listed more than once", g)}
+		}
+		genres[key] = true
+	}
+
+	if r.PublicationDate != nil {
+		date, _ := time.Parse(dateLayout, *r.PublicationDate)
+		r.publishedAt = &date
+	}
+
+	r.ISBN, _ = isbn.Normalize(r.ISBN)
+	r.contributors = contributors
+
+	return nil
+}
+
+// parseContributors parses authors written as "Name Surname (role)".
+// The last word is a surname and the rest is a name. Role defaults to author.
+func parseContributors(authors []string) ([]contributor, error) {
+	contributors := make([]contributor, 0, len(authors))
+	seen := make(map[contributor]bool, len(authors))
+	hasAuthor := false
+
+	for _, a := range authors {
+		c := contributor{Role: book.RoleAuthor}
+
+		fullName := strings.TrimSpace(a)
+		if open := strings.LastIndex(fullName, "("); open != -1 && strings.HasSuffix(fullName, ")") {
+			c.Role = strings.TrimSpace(fullName[open+1 : len(fullName)-1])
+			fullName = strings.TrimSpace(fullName[:open])
+		}
+
+		space := strings.LastIndex(fullName, " ")
+		if space == -1 {
+			return nil, fmt.Errorf("%q must contain name and surname", a)
+		}
+		c.Name = strings.TrimSpace(fullName[:space])
+		c.Surname = fullName[space+1:]
+
+		err := validation.Errors{
+			"name":    validation.Validate(c.Name, validator.Name, validation.RuneLength(1, 50)),
+			"surname": validation.Validate(c.Surname, validator.Name, validation.RuneLength(1, 50)),
+			"role": validation.Validate(
+				c.Role,
+				validation.In(book.RoleAuthor, book.RoleTranslator, book.RoleIllustrator, book.RoleEditor),
+			),
+		}.Filter()
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", a, err)
+		}
+
+		key := contributor{
+			Name:    strings.ToLower(c.Name),
+			Surname: strings.ToLower(c.Surname),
+			Role:    c.Role,
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%q is listed more than once", a)
+		}
+		seen[key] = true
+
+		hasAuthor = hasAuthor || c.Role == book.RoleAuthor
+		contributors = append(contributors, c)
+	}
+
+	if !hasAuthor {
+		return nil, errors.New("must contain at least one contributor with author role")
+	}
+
+	return contributors, nil
+}
+
+// Report describes the result of the import.
+type Report struct {
+	DryRun  bool       `json:"dryRun" example:"false"`
+	Total   int        `json:"total" example:"1000"`
+	Created int64      `json:"created" example:"950"`
+	Updated int64      `json:"updated" example:"48"`
+	Failed  int        `json:"failed" example:"2"`
+	Errors  []RowError `json:"errors"`
+} // @name ImportReport
+
+// RowError describes why the row of the import file was skipped.
+type RowError struct {
+	Line   int               `json:"line" example:"17"`
+	ISBN   string            `json:"isbn,omitempty" example:"978-0-14-044793-4"`
+	Errors map[string]string `json:"errors"`
+} // @name ImportRowError
+
+// Result is a number of created and updated books.
+type Result struct {
+	Created int64
+	Updated int64
+}
+
+// newRowError returns a RowError of the row with messages of the given error.
+// Field errors of validation are reported separately.
+func newRowError(line int, isbn string, err error) RowError {
+	rowErr := RowError{
+		Line:   line,
+		ISBN:   isbn,
+		Errors: make(map[string]string),
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		for field, fieldErr := range fieldErrs {
+			rowErr.Errors[field] = fieldErr.Error()
+		}
+		return rowErr
+	}
+
+	rowErr.Errors["row"] = err.Error()
+	return rowErr
+}
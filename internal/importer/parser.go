@@ -0,0 +1,269 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// listSeparator separates authors and genres in a CSV field.
+	listSeparator = ";"
+
+	// maxLineSize is the maximum size of a single NDJSON line.
+	maxLineSize = 1 << 20
+)
+
+// csvColumns are the known columns of the CSV file.
+// Columns may go in any order, but the header line is required.
+var csvColumns = map[string]bool{
+	"isbn":            true,
+	"title":           true,
+	"description":     true,
+	"year":            true,
+	"price":           true,
+	"currency":        true,
+	"pageCount":       true,
+	"count":           true,
+	"format":          true,
+	"edition":         true,
+	"publicationDate": true,
+	"publisher":       true,
+	"language":        true,
+	"authors":         true,
+	"genres":          true,
+}
+
+// ignoredColumns are written by export, but cannot be imported.
+// They are skipped, so exported files can be imported back.
+var ignoredColumns = map[string]bool{
+	"id":       true,
+	"workId":   true,
+	"isbn10":   true,
+	"coverUrl": true,
+}
+
+// Parse reads rows of the file in the given format. Rows which cannot be
+// decoded are reported as row errors, so a single broken line doesn't fail
+// the whole import. Returns ErrInvalidFile if the file cannot be read at all.
+func Parse(r io.Reader, format string) ([]*Row, []RowError, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatNDJSON:
+		return parseNDJSON(r)
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown format %q, use %s or %s", ErrInvalidFile, format, FormatCSV, FormatNDJSON)
+	}
+}
+
+// parseCSV reads rows of the CSV file with a header line.
+// Authors and genres are separated with semicolons.
+func parseCSV(r io.Reader) ([]*Row, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: file is empty", ErrInvalidFile)
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidFile, err)
+		}
+		return nil, nil, fmt.Errorf("cannot read file: %w", err)
+	}
+
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !csvColumns[column] && !ignoredColumns[column] {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, column)
+		}
+		header[i] = column
+	}
+	reader.FieldsPerRecord = len(header)
+
+	rows := make([]*Row, 0)
+	rowErrs := make([]RowError, 0)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("cannot read file: %w", err)
+			}
+			rowErrs = append(rowErrs, newRowError(parseErr.StartLine, "", parseErr.Err))
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line}
+		fieldErrs := make(map[string]string)
+
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if err := setField(&row, header[i], value); err != nil {
+				fieldErrs[header[i]] = err.Error()
+			}
+		}
+
+		if len(fieldErrs) > 0 {
+			rowErrs = append(rowErrs, RowError{Line: line, ISBN: row.ISBN, Errors: fieldErrs})
+			continue
+		}
+
+		rows = append(rows, &row)
+	}
+
+	return rows, rowErrs, nil
+}
+
+// setField sets the row field from the CSV column value.
+// Empty values leave optional fields unset.
+func setField(row *Row, column, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch column {
+	case "isbn":
+		row.ISBN = value
+	case "title":
+		row.Title = value
+	case "description":
+		row.Description = value
+	case "year":
+		year, err := strconv.ParseInt(value, 10, 16)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		y := int16(year)
+		row.Year = &y
+	case "price":
+		price, err := decimal.NewFromString(value)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		row.Price = price
+	case "currency":
+		row.Currency = &value
+	case "pageCount":
+		count, err := strconv.ParseInt(value, 10, 16)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		c := int16(count)
+		row.PageCount = &c
+	case "count":
+		count, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		row.Count = int32(count)
+	case "format":
+		row.Format = value
+	case "edition":
+		row.Edition = &value
+	case "publicationDate":
+		row.PublicationDate = &value
+	case "publisher":
+		row.Publisher = &value
+	case "language":
+		row.Language = value
+	case "authors":
+		row.Authors = splitList(value)
+	case "genres":
+		row.Genres = splitList(value)
+	}
+
+	return nil
+}
+
+// splitList splits the list of values separated with semicolons.
+func splitList(value string) []string {
+	items := strings.Split(value, listSeparator)
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// parseNDJSON reads rows of the file where every line is a JSON object.
+// Empty lines are skipped.
+func parseNDJSON(r io.Reader) ([]*Row, []RowError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	rows := make([]*Row, 0)
+	rowErrs := make([]RowError, 0)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		row := Row{Line: line}
+		if err := decoder.Decode(&row); err != nil {
+			rowErr := RowError{Line: line, Errors: make(map[string]string)}
+
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				rowErr.Errors[typeErr.Field] = "must be " + jsonTypeName(typeErr.Type)
+			} else {
+				rowErr.Errors["row"] = err.Error()
+			}
+
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+
+		rows = append(rows, &row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, nil, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidFile, line+1, maxLineSize)
+		}
+		return nil, nil, fmt.Errorf("cannot read file: %w", err)
+	}
+
+	return rows, rowErrs, nil
+}
+
+// jsonTypeName returns a JSON name of the expected field type.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "an array"
+	default:
+		return t.String()
+	}
+}
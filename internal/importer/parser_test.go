@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseCSV(t *testing.T) {
+	file := "\ufeffid,isbn,title,description,price,count,authors,genres,year,publisher\n" +
+		`1,978-0-14-044793-4,War and Peace,Novel.,12.99,10,Lev Tolstoy; Richard Pevear (translator),novel;history,1869,Penguin Books` + "\n" +
+		`2,9780140449136,"Crime, and Punishment",Novel.,9.50,0,Fyodor Dostoevsky,novel,,` + "\n" +
+		`3,9780140449266,Anna Karenina,Novel.,cheap,1,Lev Tolstoy,novel,long ago,` + "\n" +
+		`4,9780140447927,Short row` + "\n"
+
+	rows, rowErrs, err := Parse(strings.NewReader(file), FormatCSV)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("Parse() rows = %d, want 2", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 2 || first.ISBN != "978-0-14-044793-4" || first.Title != "War and Peace" {
+		t.Errorf("first row = %+v", first)
+	}
+	if !first.Price.Equal(decimal.RequireFromString("12.99")) || first.Count != 10 {
+		t.Errorf("first row price = %s, count = %d", first.Price, first.Count)
+	}
+	if first.Year == nil || *first.Year != 1869 || first.Publisher == nil || *first.Publisher != "Penguin Books" {
+		t.Errorf("first row year = %v, publisher = %v", first.Year, first.Publisher)
+	}
+	if !reflect.DeepEqual(first.Authors, []string{"Lev Tolstoy", "Richard Pevear (translator)"}) {
+		t.Errorf("first row authors = %q", first.Authors)
+	}
+	if !reflect.DeepEqual(first.Genres, []string{"novel", "history"}) {
+		t.Errorf("first row genres = %q", first.Genres)
+	}
+
+	second := rows[1]
+	if second.Title != "Crime, and Punishment" || second.Year != nil || second.Publisher != nil {
+		t.Errorf("second row = %+v", second)
+	}
+
+	if len(rowErrs) != 2 {
+		t.Fatalf("Parse() row errors = %+v, want 2", rowErrs)
+	}
+	if rowErrs[0].Line != 4 || rowErrs[0].ISBN != "9780140449266" ||
+		rowErrs[0].Errors["price"] == "" || rowErrs[0].Errors["year"] == "" {
+		t.Errorf("invalid values error = %+v", rowErrs[0])
+	}
+	if rowErrs[1].Line != 5 || rowErrs[1].Errors["row"] == "" {
+		t.Errorf("short row error = %+v", rowErrs[1])
+	}
+}
+
+func TestParseNDJSON(t *testing.T) {
+	file := `{"isbn":"9780140447934","title":"War and Peace","description":"Novel.","price":"12.99","count":3,"language":"en","authors":["Lev Tolstoy"],"genres":["novel"]}` + "\n" +
+		"\n" +
+		`{"isbn":"9780140449136","count":"many"}` + "\n" +
+		`{"isbn":"9780140449266","cover":"anna.jpg"}` + "\n" +
+		`not json` + "\n"
+
+	rows, rowErrs, err := Parse(strings.NewReader(file), FormatNDJSON)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(rows) != 1 {
+		t.Fatalf("Parse() rows = %d, want 1", len(rows))
+	}
+	if row := rows[0]; row.Line != 1 || row.Title != "War and Peace" || row.Count != 3 || row.Language != "en" {
+		t.Errorf("row = %+v", row)
+	}
+
+	want := []struct {
+		line  int
+		field string
+	}{
+		{line: 3, field: "count"},
+		{line: 4, field: "row"},
+		{line: 5, field: "row"},
+	}
+	if len(rowErrs) != len(want) {
+		t.Fatalf("Parse() row errors = %+v, want %d", rowErrs, len(want))
+	}
+	for i, w := range want {
+		if rowErrs[i].Line != w.line || rowErrs[i].Errors[w.field] == "" {
+			t.Errorf("row error %d = %+v, want %s error on line %d", i, rowErrs[i], w.field, w.line)
+		}
+	}
+	if got := rowErrs[0].Errors["count"]; got != "must be an integer" {
+		t.Errorf("count error = %q", got)
+	}
+}
+
+func TestParseInvalidFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		file   string
+	}{
+		{name: "unknown format", format: "xml", file: "<books/>"},
+		{name: "empty csv", format: FormatCSV, file: ""},
+		{name: "unknown column", format: FormatCSV, file: "isbn,cover\n9780140447934,war.jpg\n"},
+		{name: "broken header", format: FormatCSV, file: "isbn,\"title\n"},
+		{name: "too long line", format: FormatNDJSON, file: `{"title":"` + strings.Repeat("a", maxLineSize) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(strings.NewReader(tt.file), tt.format)
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Parse() error = %v, want %v", err, ErrInvalidFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	// stagingTableName is a temporary table the rows are copied to.
+	stagingTableName = "import_books"
+
+	// batchSize is a number of rows copied to the staging table at once.
+	batchSize = 1000
+
+	// importTimeout limits the transaction of the import.
+	// Imports are much longer than regular requests.
+	importTimeout = 10 * time.Minute
+)
+
+// stagingColumns are columns of the staging table filled with CopyFrom.
+var stagingColumns = []string{
+	"isbn", "title", "description", "year", "price", "currency", "page_count", "count", "format",
+	"edition", "publication_date", "publisher_id", "language_id",
+	"author_ids", "author_roles", "genre_ids",
+}
+
+// Queries below move staged rows to books. Existing books are matched by ISBN
+// and keep their work, new books get a new work each. Contributors and
+// genres of imported books are replaced with the imported ones. Imported
+// counts are recorded in the stock ledger: as adjustments by the difference
+// for existing books and as receipts for new ones. Changed prices become
+// effective immediately and cancel scheduled list prices of the book.
+// New books are priced in the imported currency or the base one, existing
+// books keep their currency.
+const (
+	matchBooksQuery = `
+	UPDATE import_books i SET book_id = b.id FROM books b WHERE b.isbn = i.isbn`
+
+	currencyMismatchQuery = `
+	SELECT i.isbn, b.currency
+	FROM import_books i JOIN books b ON b.id = i.book_id
+	WHERE i.currency <> b.currency
+	LIMIT 1`
+
+	reserveWorksQuery = `
+	UPDATE import_books SET work_id = nextval(pg_get_serial_sequence('works', 'id'))
+	WHERE book_id IS NULL`
+
+	createWorksQuery = `
+	INSERT INTO works (id, title) SELECT work_id, title FROM import_books WHERE book_id IS NULL`
+
+	adjustStockQuery = `
+	INSERT INTO stock_movements (book_id, type, quantity, reason, actor)
+	SELECT b.id, 'adjustment', i.count - b.count, 'catalog import', 'import'
+	FROM import_books i JOIN books b ON b.id = i.book_id
+	WHERE i.count <> b.count`
+
+	cancelScheduledPricesQuery = `
+	DELETE FROM book_prices p
+	USING import_books i
+	WHERE p.book_id = i.book_id AND p.kind = 'list' AND p.effective_from > now()
+		AND i.price <> COALESCE(book_list_price(i.book_id, now()), -1)`
+
+	closePricesQuery = `
+	UPDATE book_prices p SET effective_to = now()
+	FROM import_books i
+	WHERE p.book_id = i.book_id AND p.kind = 'list' AND p.effective_from <= now()
+		AND (p.effective_to IS NULL OR p.effective_to > now())
+		AND i.price <> p.price`
+
+	changePricesQuery = `
+	INSERT INTO book_prices (book_id, kind, price, effective_from, reason)
+	SELECT i.book_id, 'list', i.price, now(), 'catalog import'
+	FROM import_books i
+	WHERE i.book_id IS NOT NULL AND NOT EXISTS (
+		SELECT 1 FROM book_prices p
+		WHERE p.book_id = i.book_id AND p.kind = 'list' AND p.effective_from <= now()
+			AND (p.effective_to IS NULL OR p.effective_to > now())
+	)`
+
+	updateBooksQuery = `
+	UPDATE books b
+	SET title=i.title, description=i.description, year=i.year, price=i.price,
+		page_count=i.page_count, count=i.count, format=i.format, edition=i.edition,
+		publication_date=i.publication_date, publisher_id=i.publisher_id, language_id=i.language_id
+	FROM import_books i
+	WHERE b.id = i.book_id`
+
+	createBooksQuery = `
+	INSERT INTO books (
+		work_id, isbn, title, description, year, price, currency, page_count, count, format,
+		edition, publication_date, publisher_id, language_id
+	)
+	SELECT work_id, isbn, title, description, year, price,
+		COALESCE(currency, (SELECT code FROM currencies WHERE base)), page_count, count, format,
+		edition, publication_date, publisher_id, language_id
+	FROM import_books
+	WHERE book_id IS NULL`
+
+	matchCreatedBooksQuery = `
+	UPDATE import_books i SET book_id = b.id, created = true
+	FROM books b WHERE i.book_id IS NULL AND b.isbn = i.isbn`
+
+	receiveStockQuery = `
+	INSERT INTO stock_movements (book_id, type, quantity, reason, actor)
+	SELECT book_id, 'receipt', count, 'catalog import', 'import'
+	FROM import_books
+	WHERE created AND count > 0`
+
+	initialPricesQuery = `
+	INSERT INTO book_prices (book_id, kind, price, effective_from, reason)
+	SELECT book_id, 'list', price, now(), 'catalog import'
+	FROM import_books
+	WHERE created`
+
+	deleteAuthorsQuery = `
+	DELETE FROM book_authors WHERE book_id IN (SELECT book_id FROM import_books)`
+
+	insertAuthorsQuery = `
+	INSERT INTO book_authors (book_id, author_id, role, position)
+	SELECT i.book_id, a.author_id, a.role, a.position - 1
+	FROM import_books i, unnest(i.author_ids, i.author_roles) WITH ORDINALITY AS a(author_id, role, position)`
+
+	deleteGenresQuery = `
+	DELETE FROM book_genres WHERE book_id IN (SELECT book_id FROM import_books)`
+
+	insertGenresQuery = `
+	INSERT INTO book_genres (book_id, genre_id)
+	SELECT i.book_id, unnest(i.genre_ids) FROM import_books i`
+)
+
+// Check whether db implements import storage interface.
+var _ Storage = &db{}
+
+// db implements import storage interface.
+type db struct {
+	logger logger.Logger
+	conn   *pgxpool.Pool
+}
+
+// NewStorage returns a new import storage instance.
+func NewStorage(storage *pgxpool.Pool) Storage {
+	return &db{
+		logger: logger.GetLogger(),
+		conn:   storage,
+	}
+}
+
+// FindCurrencies finds currencies of existing books by their ISBNs.
+// Books which don't exist are left out. Returns an error on failure.
+func (d *db) FindCurrencies(isbns []string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, "SELECT isbn, currency FROM books WHERE isbn = ANY($1)", isbns)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find book currencies query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	currencies := make(map[string]string)
+	for rows.Next() {
+		var isbn, currency string
+		if err := rows.Scan(&isbn, &currency); err != nil {
+			err = fmt.Errorf("failed to scan book currency: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		currencies[isbn] = currency
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read book currencies: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+// Import upserts books by ISBN within a single transaction, so a failed
+// import changes nothing and can be run again. Authors, genres, languages
+// and publishers are resolved by name and created when missing. Rows are
+// copied to a staging table in batches of batchSize rows and then moved to
+// books at once. Returns ErrCurrencyMismatch if the price of an existing
+// book is in another currency, an error on failure or the number of created
+// and updated books on success.
+func (d *db) Import(rows []*Row) (*Result, error) {
+	result, err := d.importRows(rows)
+	if err != nil {
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			d.logger.Error(err)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// importRows imports the rows within a single transaction.
+func (d *db) importRows(rows []*Row) (*Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	refs, err := resolveReferences(ctx, tx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve import references: %v", err)
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+	CREATE TEMP TABLE %s (
+		isbn text primary key,
+		title text not null,
+		description text not null,
+		year smallint,
+		price decimal(10,2) not null,
+		currency char(3),
+		page_count smallint,
+		count int not null,
+		format text not null,
+		edition text,
+		publication_date date,
+		publisher_id bigint,
+		language_id smallint not null,
+		author_ids bigint[] not null,
+		author_roles text[] not null,
+		genre_ids smallint[] not null,
+		book_id bigint,
+		work_id bigint,
+		created boolean not null default false
+	) ON COMMIT DROP`, stagingTableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging table: %v", err)
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if err := stageRows(ctx, tx, rows[start:end], refs); err != nil {
+			return nil, fmt.Errorf("failed to copy rows from line %d: %v", rows[start].Line, err)
+		}
+	}
+
+	created, updated, err := upsertStaged(ctx, tx)
+	if err != nil {
+		if errors.Is(err, ErrCurrencyMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to upsert imported books: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &Result{Created: created, Updated: updated}, nil
+}
+
+// stageRows copies the rows to the staging table.
+func stageRows(ctx context.Context, tx pgx.Tx, rows []*Row, refs *references) error {
+	values := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		authorIds := make([]int64, len(row.contributors))
+		authorRoles := make([]string, len(row.contributors))
+		for i, c := range row.contributors {
+			authorIds[i] = refs.authors[authorKey(c.Name, c.Surname)]
+			authorRoles[i] = c.Role
+		}
+
+		genreIds := make([]int16, len(row.Genres))
+		for i, g := range row.Genres {
+			genreIds[i] = int16(refs.genres[strings.ToLower(g)])
+		}
+
+		var publisherId *int64
+		if row.Publisher != nil {
+			id := refs.publishers[strings.ToLower(*row.Publisher)]
+			publisherId = &id
+		}
+
+		values = append(values, []interface{}{
+			row.ISBN,
+			row.Title,
+			row.Description,
+			row.Year,
+			row.Price,
+			row.Currency,
+			row.PageCount,
+			row.Count,
+			row.Format,
+			row.Edition,
+			row.publishedAt,
+			publisherId,
+			int16(refs.languages[strings.ToLower(row.Language)]),
+			authorIds,
+			authorRoles,
+			genreIds,
+		})
+	}
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{stagingTableName}, stagingColumns, pgx.CopyFromRows(values))
+	return err
+}
+
+// upsertStaged moves staged rows to books.
+// Returns the number of created and updated books.
+func upsertStaged(ctx context.Context, tx pgx.Tx) (int64, int64, error) {
+	if _, err := tx.Exec(ctx, matchBooksQuery); err != nil {
+		return 0, 0, err
+	}
+
+	var isbn, currency string
+	err := tx.QueryRow(ctx, currencyMismatchQuery).Scan(&isbn, &currency)
+	if err == nil {
+		return 0, 0, fmt.Errorf("%w: book %s is priced in %s", ErrCurrencyMismatch, isbn, currency)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, err
+	}
+
+	for _, query := range []string{reserveWorksQuery, createWorksQuery} {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	updateQueries := []string{
+		adjustStockQuery,
+		cancelScheduledPricesQuery,
+		closePricesQuery,
+		changePricesQuery,
+	}
+	for _, query := range updateQueries {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	updated, err := tx.Exec(ctx, updateBooksQuery)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	created, err := tx.Exec(ctx, createBooksQuery)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	queries := []string{
+		matchCreatedBooksQuery,
+		receiveStockQuery,
+		initialPricesQuery,
+		deleteAuthorsQuery,
+		deleteGenresQuery,
+		insertAuthorsQuery,
+		insertGenresQuery,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return created.RowsAffected(), updated.RowsAffected(), nil
+}
+
+// references holds ids of related records by their lower-cased names.
+type references struct {
+	authors    map[string]int64
+	genres     map[string]int64
+	languages  map[string]int64
+	publishers map[string]int64
+}
+
+// authorKey returns a case-insensitive key of the author.
+func authorKey(name, surname string) string {
+	return strings.ToLower(name) + "\x00" + strings.ToLower(surname)
+}
+
+// resolveReferences finds or creates authors, genres,
+// languages and publishers mentioned in the rows.
+func resolveReferences(ctx context.Context, tx pgx.Tx, rows []*Row) (*references, error) {
+	var authors []contributor
+	var genres, languages, publishers []string
+
+	for _, row := range rows {
+		authors = append(authors, row.contributors...)
+		genres = append(genres, row.Genres...)
+		languages = append(languages, row.Language)
+		if row.Publisher != nil {
+			publishers = append(publishers, *row.Publisher)
+		}
+	}
+
+	var refs references
+	var err error
+
+	if refs.authors, err = resolveAuthors(ctx, tx, authors); err != nil {
+		return nil, err
+	}
+	if refs.genres, err = resolveNames(ctx, tx, "genres", "genre", genres); err != nil {
+		return nil, err
+	}
+	if refs.languages, err = resolveNames(ctx, tx, "languages", "language", languages); err != nil {
+		return nil, err
+	}
+	if refs.publishers, err = resolveNames(ctx, tx, "publishers", "name", publishers); err != nil {
+		return nil, err
+	}
+
+	return &refs, nil
+}
+
+// resolveNames finds ids of the records in the table by the name column and
+// creates the missing ones. Names are compared case-insensitively and the
+// oldest record wins if there are several with the same name.
+func resolveNames(ctx context.Context, tx pgx.Tx, table, column string, names []string) (map[string]int64, error) {
+	ids := make(map[string]int64)
+
+	unique := make([]string, 0)
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, name)
+			keys = append(keys, key)
+		}
+	}
+
+	if len(unique) == 0 {
+		return ids, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT DISTINCT ON (lower(%[2]s)) id, lower(%[2]s)
+	FROM %[1]s
+	WHERE lower(%[2]s) = ANY($1)
+	ORDER BY lower(%[2]s), id`, table, column)
+
+	rows, err := tx.Query(ctx, query, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s: %v", table, err)
+	}
+
+	for rows.Next() {
+		var id int64
+		var key string
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan %s: %v", table, err)
+		}
+		ids[key] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", table, err)
+	}
+
+	batch := &pgx.Batch{}
+	missing := make([]string, 0)
+	for i, name := range unique {
+		if _, ok := ids[keys[i]]; !ok {
+			batch.Queue(fmt.Sprintf("INSERT INTO %s (%s) VALUES ($1) RETURNING id", table, column), name)
+			missing = append(missing, keys[i])
+		}
+	}
+
+	if batch.Len() == 0 {
+		return ids, nil
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, key := range missing {
+		var id int64
+		if err := results.QueryRow().Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %v", table, err)
+		}
+		ids[key] = id
+	}
+
+	return ids, nil
+}
+
+// resolveAuthors finds ids of the authors by name and surname and creates
+// the missing ones. Names are compared case-insensitively and the oldest
+// author wins if there are several with the same name.
+func resolveAuthors(ctx context.Context, tx pgx.Tx, authors []contributor) (map[string]int64, error) {
+	ids := make(map[string]int64)
+
+	unique := make([]contributor, 0)
+	names := make([]string, 0)
+	surnames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, a := range authors {
+		key := authorKey(a.Name, a.Surname)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, a)
+			names = append(names, strings.ToLower(a.Name))
+			surnames = append(surnames, strings.ToLower(a.Surname))
+		}
+	}
+
+	if len(unique) == 0 {
+		return ids, nil
+	}
+
+	query := `
+	SELECT DISTINCT ON (lower(name), lower(surname)) id, name, surname
+	FROM authors
+	WHERE (lower(name), lower(surname)) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+	ORDER BY lower(name), lower(surname), id`
+
+	rows, err := tx.Query(ctx, query, names, surnames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find authors: %v", err)
+	}
+
+	for rows.Next() {
+		var id int64
+		var name, surname string
+		if err := rows.Scan(&id, &name, &surname); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan author: %v", err)
+		}
+		ids[authorKey(name, surname)] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read authors: %v", err)
+	}
+
+	batch := &pgx.Batch{}
+	missing := make([]string, 0)
+	for _, a := range unique {
+		key := authorKey(a.Name, a.Surname)
+		if _, ok := ids[key]; !ok {
+			batch.Queue("INSERT INTO authors (name, surname) VALUES ($1, $2) RETURNING id", a.Name, a.Surname)
+			missing = append(missing, key)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return ids, nil
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for _, key := range missing {
+		var id int64
+		if err := results.QueryRow().Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create author: %v", err)
+		}
+		ids[key] = id
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,142 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes import service functionality.
+type Service interface {
+	Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*Report, error)
+}
+
+type service struct {
+	logger          logger.Logger
+	storage         Storage
+	currencyService currency.Service
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, currencyService currency.Service, logger logger.Logger) Service {
+	return &service{
+		logger:          logger,
+		storage:         storage,
+		currencyService: currencyService,
+	}
+}
+
+// Import reads books from the file in the given format and upserts them
+// by ISBN. Rows which cannot be decoded or fail validation are skipped and
+// listed in the report, the rest are imported. In dry-run mode nothing is
+// written, the report counts books which would be created and updated.
+// Returns ErrInvalidFile if
+// the file cannot be read at all, ErrCurrencyMismatch if the price of the
+// existing book is in another currency or an error on failure.
+func (s *service) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*Report, error) {
+	rows, rowErrs, err := Parse(r, format)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidFile) {
+			s.logger.Warnf("cannot parse import file: %v", err)
+		}
+		return nil, err
+	}
+
+	currencies, err := s.currencyService.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	supported := make(map[string]bool, len(currencies))
+	for _, c := range currencies {
+		supported[c.Code] = true
+	}
+
+	valid := make([]*Row, 0, len(rows))
+	lines := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if err := row.Validate(); err != nil {
+			rowErrs = append(rowErrs, newRowError(row.Line, row.ISBN, err))
+			continue
+		}
+
+		if row.Currency != nil && !supported[*row.Currency] {
+			rowErrs = append(rowErrs, newRowError(
+				row.Line,
+				row.ISBN,
+				validation.Errors{"currency": apperror.ErrUnknownCurrency},
+			))
+			continue
+		}
+
+		if line, ok := lines[row.ISBN]; ok {
+			rowErrs = append(rowErrs, newRowError(
+				row.Line,
+				row.ISBN,
+				fmt.Errorf("book with the same isbn is already on line %d", line),
+			))
+			continue
+		}
+		lines[row.ISBN] = row.Line
+
+		valid = append(valid, row)
+	}
+
+	sort.Slice(rowErrs, func(i, j int) bool {
+		return rowErrs[i].Line < rowErrs[j].Line
+	})
+
+	report := Report{
+		DryRun: dryRun,
+		Total:  len(valid) + len(rowErrs),
+		Failed: len(rowErrs),
+		Errors: rowErrs,
+	}
+
+	if len(valid) == 0 {
+		return &report, nil
+	}
+
+	isbns := make([]string, len(valid))
+	for i, row := range valid {
+		isbns[i] = row.ISBN
+	}
+
+	existing, err := s.storage.FindCurrencies(isbns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range valid {
+		currency, ok := existing[row.ISBN]
+		if !ok {
+			report.Created++
+			continue
+		}
+		if row.Currency != nil && *row.Currency != currency {
+			return nil, fmt.Errorf("%w: book %s is priced in %s", ErrCurrencyMismatch, row.ISBN, currency)
+		}
+		report.Updated++
+	}
+
+	if dryRun {
+		return &report, nil
+	}
+
+	result, err := s.storage.Import(valid)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Created = result.Created
+	report.Updated = result.Updated
+
+	return &report, nil
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryStorage knows currencies of existing books and keeps imported rows.
+type memoryStorage struct {
+	currencies map[string]string
+	imported   []*Row
+}
+
+func (s *memoryStorage) FindCurrencies(isbns []string) (map[string]string, error) {
+	found := make(map[string]string)
+	for _, isbn := range isbns {
+		if c, ok := s.currencies[isbn]; ok {
+			found[isbn] = c
+		}
+	}
+	return found, nil
+}
+
+func (s *memoryStorage) Import(rows []*Row) (*Result, error) {
+	s.imported = append(s.imported, rows...)
+
+	var result Result
+	for _, row := range rows {
+		if _, ok := s.currencies[row.ISBN]; ok {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+	return &result, nil
+}
+
+// fixedCurrencies supports EUR and USD. Methods of currency.Service
+// which are not used by Import are not implemented.
+type fixedCurrencies struct {
+	currency.Service
+}
+
+func (fixedCurrencies) GetCurrencies(ctx context.Context) ([]*currency.Currency, error) {
+	return []*currency.Currency{{Code: "EUR", Base: true}, {Code: "USD"}}, nil
+}
+
+func newTestLogger() logger.Logger {
+	l := logrus.New()
+	l.Out = ioutil.Discard
+	return logger.Logger{Entry: logrus.NewEntry(l)}
+}
+
+// importFile has two valid rows, one of an existing book,
+// and three rows which are skipped.
+const importFile = `isbn,title,description,price,currency,count,language,authors,genres
+978-0-14-044793-4,War and Peace,Novel.,12.99,EUR,10,en,Lev Tolstoy,novel
+9780140449136,Crime and Punishment,Novel.,9.50,,0,en,Fyodor Dostoevsky,novel
+9780140449266,Anna Karenina,Novel.,8.00,JPY,1,en,Lev Tolstoy,novel
+9780140449136,Crime and Punishment,Duplicate.,9.50,,0,en,Fyodor Dostoevsky,novel
+9780140447927,The Idiot,,7.00,,1,en,Fyodor Dostoevsky,novel
+`
+
+func TestImport(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		storage := &memoryStorage{currencies: map[string]string{"9780140447934": "EUR"}}
+		s := NewService(storage, fixedCurrencies{}, newTestLogger())
+
+		report, err := s.Import(context.Background(), strings.NewReader(importFile), FormatCSV, dryRun)
+		if err != nil {
+			t.Fatalf("Import(dryRun=%v) error = %v", dryRun, err)
+		}
+
+		if report.DryRun != dryRun || report.Total != 5 || report.Failed != 3 ||
+			report.Created != 1 || report.Updated != 1 {
+			t.Errorf("Import(dryRun=%v) report = %+v", dryRun, report)
+		}
+
+		lines := make([]int, 0, len(report.Errors))
+		for _, e := range report.Errors {
+			lines = append(lines, e.Line)
+		}
+		if len(lines) != 3 || lines[0] != 4 || lines[1] != 5 || lines[2] != 6 {
+			t.Errorf("Import(dryRun=%v) error lines = %v, want [4 5 6]", dryRun, lines)
+		}
+		if report.Errors[0].Errors["currency"] == "" {
+			t.Errorf("unknown currency error = %+v", report.Errors[0])
+		}
+		if report.Errors[2].Errors["description"] == "" {
+			t.Errorf("invalid row error = %+v", report.Errors[2])
+		}
+
+		wantImported := 2
+		if dryRun {
+			wantImported = 0
+		}
+		if len(storage.imported) != wantImported {
+			t.Errorf("Import(dryRun=%v) imported %d rows, want %d", dryRun, len(storage.imported), wantImported)
+		}
+		if !dryRun && storage.imported[0].ISBN != "9780140447934" {
+			t.Errorf("imported ISBN = %q, want normalized ISBN-13", storage.imported[0].ISBN)
+		}
+	}
+}
+
+func TestImportCurrencyMismatch(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		storage := &memoryStorage{currencies: map[string]string{"9780140447934": "USD"}}
+		s := NewService(storage, fixedCurrencies{}, newTestLogger())
+
+		_, err := s.Import(context.Background(), strings.NewReader(importFile), FormatCSV, dryRun)
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Import(dryRun=%v) error = %v, want %v", dryRun, err, ErrCurrencyMismatch)
+		}
+		if len(storage.imported) != 0 {
+			t.Errorf("Import(dryRun=%v) imported %d rows", dryRun, len(storage.imported))
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package importer
+
+// Storage describes import storage functionality.
+type Storage interface {
+	FindCurrencies(isbns []string) (map[string]string, error)
+	Import(rows []*Row) (*Result, error)
+}
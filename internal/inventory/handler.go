@@ -0,0 +1,240 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	stockURL          = "/api/books/:id/stock"
+	movementsURL      = "/api/books/:id/stock/movements"
+	receiptsURL       = "/api/books/:id/stock/receipts"
+	adjustmentsURL    = "/api/books/:id/stock/adjustments"
+	reconciliationURL = "/api/inventory/reconciliation"
+)
+
+// Handler handles requests specified to inventory service.
+type Handler struct {
+	logger           logger.Logger
+	inventoryService Service
+}
+
+// NewHandler returns a new inventory Handler instance.
+func NewHandler(logger logger.Logger, inventoryService Service) handler.Handling {
+	return &Handler{
+		logger:           logger,
+		inventoryService: inventoryService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, stockURL, h.GetStock)
+	router.HandlerFunc(http.MethodGet, movementsURL, h.ListMovements)
+	router.HandlerFunc(http.MethodPost, receiptsURL, h.CreateReceipt)
+	router.HandlerFunc(http.MethodPost, adjustmentsURL, h.CreateAdjustment)
+	router.HandlerFunc(http.MethodGet, reconciliationURL, h.GetReconciliation)
+}
+
+// GetStock godoc
+// @Summary Show book stock
+// @Description Get on-hand, reserved and available quantities of the book derived from the stock ledger.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Success 200 {object} Stock
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/stock [get]
+func (h *Handler) GetStock(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET STOCK")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	stock, err := h.inventoryService.GetStock(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stock)
+}
+
+// ListMovements godoc
+// @Summary List stock movements
+// @Description Get stock ledger of the book, newest movements first.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Movement
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/stock/movements [get]
+func (h *Handler) ListMovements(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST STOCK MOVEMENTS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	movements, err := h.inventoryService.GetMovements(r.Context(), id, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, movements)
+}
+
+// CreateReceipt godoc
+// @Summary Record stock receipt
+// @Description Record copies of the book received from a supplier. The actor is stated by the client and is not verified.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body ReceiptDTO true "JSON input"
+// @Success 201 {object} Movement
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/stock/receipts [post]
+func (h *Handler) CreateReceipt(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE STOCK RECEIPT")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ReceiptDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.BookId = id
+
+	movement, err := h.inventoryService.Receive(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, movement)
+}
+
+// CreateAdjustment godoc
+// @Summary Record stock adjustment
+// @Description Correct on-hand quantity of the book, e.g. after a stocktake. Positive quantity adds copies, negative one takes them. The actor is stated by the client and is not verified.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body AdjustmentDTO true "JSON input"
+// @Success 201 {object} Movement
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/stock/adjustments [post]
+func (h *Handler) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE STOCK ADJUSTMENT")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input AdjustmentDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.BookId = id
+
+	movement, err := h.inventoryService.Adjust(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInsufficientStock):
+			response.Conflict(w, err.Error(), "on-hand quantity cannot become negative")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, movement)
+}
+
+// GetReconciliation godoc
+// @Summary Reconcile stock
+// @Description Compare cached count of every book with on-hand quantity derived from the stock ledger. Lists books which counts differ.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} Reconciliation
+// @Failure 500 {object} apperror.AppError
+// @Router /inventory/reconciliation [get]
+func (h *Handler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET STOCK RECONCILIATION")
+
+	report, err := h.inventoryService.Reconcile(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,119 @@
+package inventory
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// MovementReceipt adds copies received from a supplier.
+	MovementReceipt = "receipt"
+	// MovementSale takes copies sold to a customer.
+	MovementSale = "sale"
+	// MovementReturn adds copies returned by a customer.
+	MovementReturn = "return"
+	// MovementAdjustment corrects on-hand quantity, e.g. after a stocktake.
+	MovementAdjustment = "adjustment"
+	// MovementReservation holds copies for a customer. Positive quantity
+	// reserves copies and negative one releases them. Reservations don't
+	// change on-hand quantity, only the available one.
+	MovementReservation = "reservation"
+)
+
+// Movement represents a single record of the stock ledger.
+// Quantity is signed: positive movements add copies, negative ones take them.
+// Movements are never changed, mistakes are corrected with new movements.
+type Movement struct {
+	Id       int64  `json:"id" example:"1"`
+	BookId   int64  `json:"bookId" example:"123"`
+	Type     string `json:"type" example:"receipt"`
+	Quantity int32  `json:"quantity" example:"10"`
+	Reason   string `json:"reason" example:"delivery from the supplier"`
+	// Actor is who made the movement. Actors of receipts and adjustments
+	// are stated by the client and are not verified.
+	Actor     string    `json:"actor" example:"warehouse@readyread.com"`
+	Reference *string   `json:"reference,omitempty" example:"INV-2022-0042"`
+	CreatedAt time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+} // @name StockMovement
+
+// Stock represents quantities of the book derived from the stock ledger.
+// Available quantity is the on-hand one less copies held by reservations
+// which haven't expired yet.
+type Stock struct {
+	BookId    int64 `json:"bookId" example:"123"`
+	OnHand    int32 `json:"onHand" example:"10"`
+	Reserved  int32 `json:"reserved" example:"2"`
+	Available int32 `json:"available" example:"8"`
+} // @name Stock
+
+// Reservation holds copies of the book for the basket till it expires.
+// Reservations of deleted baskets stay until they expire.
+type Reservation struct {
+	Id        int64     `json:"id" example:"1"`
+	BookId    int64     `json:"bookId" example:"123"`
+	BasketId  *int64    `json:"basketId,omitempty" example:"42"`
+	Quantity  int32     `json:"quantity" example:"2"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2022-03-01T12:15:00Z"`
+	CreatedAt time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+} // @name StockReservation
+
+// Discrepancy describes the book which cached count differs from the ledger.
+type Discrepancy struct {
+	BookId      int64  `json:"bookId" example:"123"`
+	Title       string `json:"title" example:"War and Peace"`
+	CachedCount int32  `json:"cachedCount" example:"12"`
+	LedgerCount int32  `json:"ledgerCount" example:"10"`
+	Difference  int32  `json:"difference" example:"2"`
+} // @name StockDiscrepancy
+
+// Reconciliation is a report of the ledger reconciled against cached counts.
+type Reconciliation struct {
+	BooksChecked  int64         `json:"booksChecked" example:"1500"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+} // @name StockReconciliation
+
+// ReceiptDTO is used to record copies received from a supplier.
+type ReceiptDTO struct {
+	BookId   int64  `json:"-"`
+	Quantity int32  `json:"quantity" example:"10"`
+	Reason   string `json:"reason" example:"delivery from the supplier"`
+	// Actor is not verified, the API has no authentication.
+	Actor     string  `json:"actor" example:"warehouse@readyread.com"`
+	Reference *string `json:"reference,omitempty" example:"INV-2022-0042"`
+} // @name StockReceiptInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *ReceiptDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Quantity, validation.Required, validation.Min(1)),
+		validation.Field(&r.Reason, validation.Required, validation.RuneLength(1, 500)),
+		validation.Field(&r.Actor, validation.Required, validation.RuneLength(1, 100)),
+		validation.Field(&r.Reference, validation.RuneLength(1, 100)),
+	)
+}
+
+// AdjustmentDTO is used to correct on-hand quantity of the book.
+// Quantity is the difference: positive adds copies, negative takes them.
+type AdjustmentDTO struct {
+	BookId   int64  `json:"-"`
+	Quantity int32  `json:"quantity" example:"-2"`
+	Reason   string `json:"reason" example:"damaged copies"`
+	// Actor is not verified, the API has no authentication.
+	Actor     string  `json:"actor" example:"warehouse@readyread.com"`
+	Reference *string `json:"reference,omitempty" example:"STOCKTAKE-2022-03"`
+} // @name StockAdjustmentInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (a *AdjustmentDTO) Validate() error {
+	return validation.ValidateStruct(
+		a,
+		validation.Field(&a.Quantity, validation.Required),
+		validation.Field(&a.Reason, validation.Required, validation.RuneLength(1, 500)),
+		validation.Field(&a.Actor, validation.Required, validation.RuneLength(1, 100)),
+		validation.Field(&a.Reference, validation.RuneLength(1, 100)),
+	)
+}
@@ -0,0 +1,765 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName             = "stock_movements"
+	booksTableName        = "books"
+	reservationsTableName = "stock_reservations"
+
+	// Reasons and actors of reservation movements.
+	reservedReason = "reserved in basket"
+	releasedReason = "released from basket"
+	expiredReason  = "reservation expired"
+	customerActor  = "customer"
+	systemActor    = "system"
+
+	// stockQuery derives on-hand quantity of the book from the ledger and
+	// reserved quantity from its active reservations. Expired reservations
+	// hold no copies, even if the sweeper hasn't released them yet.
+	stockQuery = `
+	SELECT b.id,
+		COALESCE((
+			SELECT SUM(m.quantity) FROM stock_movements m
+			WHERE m.book_id = b.id AND m.type <> 'reservation'
+		), 0)::int,
+		COALESCE((
+			SELECT SUM(r.quantity) FROM stock_reservations r
+			WHERE r.book_id = b.id AND r.expires_at > now()
+		), 0)::int
+	FROM books b
+	WHERE b.id = $1`
+)
+
+// Check whether db implements inventory storage interface.
+var _ Storage = &db{}
+
+// db implements inventory storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new inventory storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Record appends the movements to the ledger within a single transaction
+// and updates cached counts of the books. Returns ErrNoRows if some book
+// doesn't exist, ErrInsufficientStock if there are not enough copies,
+// an error on failure or recorded movements on success.
+func (d *db) Record(movements []*Movement) ([]*Movement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyMovements(ctx, tx, movements); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			d.logger.Error(err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return movements, nil
+}
+
+// RecordOnce records the movements like Record unless the ledger has
+// movements of their types with the reference already, so recording them
+// can be retried. Movements with the same reference are recorded one at
+// a time. Returns no movements if they have been recorded already.
+func (d *db) RecordOnce(reference string, movements []*Movement) ([]*Movement, error) {
+	existsQuery := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s WHERE reference = $1 AND type = ANY($2))",
+		tableName,
+	)
+
+	types := make([]string, 0, len(movements))
+	for _, m := range movements {
+		types = append(types, m.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", reference); err != nil {
+		err = fmt.Errorf("failed to lock stock movement reference: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, existsQuery, reference, types).Scan(&exists); err != nil {
+		err = fmt.Errorf("failed to execute find stock movements by reference query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	if exists {
+		return []*Movement{}, nil
+	}
+
+	for _, m := range movements {
+		m.Reference = &reference
+	}
+
+	if err := applyMovements(ctx, tx, movements); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			d.logger.Error(err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return movements, nil
+}
+
+// Sell records sales of the books reserved for the basket within a single
+// transaction: the books are locked, reservations of the basket for them
+// are released and sales are recorded with the reference, so copies held
+// for the basket can't be taken by other baskets in between. Sales are
+// recorded once like RecordOnce does. Returns ErrNoRows if some book
+// doesn't exist, ErrInsufficientStock if there are not enough copies and
+// the reservations are kept then, an error on failure or recorded
+// movements on success.
+func (d *db) Sell(reference string, basketId int64, movements []*Movement) ([]*Movement, error) {
+	existsQuery := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s WHERE reference = $1 AND type = $2)",
+		tableName,
+	)
+
+	releaseQuery := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE basket_id = $1 AND book_id = ANY($2)
+	RETURNING book_id, quantity`, reservationsTableName)
+
+	seen := make(map[int64]bool)
+	bookIds := make([]int64, 0, len(movements))
+	for _, m := range movements {
+		if !seen[m.BookId] {
+			seen[m.BookId] = true
+			bookIds = append(bookIds, m.BookId)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", reference); err != nil {
+		err = fmt.Errorf("failed to lock stock movement reference: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, existsQuery, reference, MovementSale).Scan(&exists); err != nil {
+		err = fmt.Errorf("failed to execute find stock movements by reference query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	if exists {
+		return []*Movement{}, nil
+	}
+
+	if err := lockBooks(ctx, tx, bookIds); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			d.logger.Error(err)
+		}
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, releaseQuery, basketId, bookIds)
+	if err != nil {
+		err = fmt.Errorf("failed to execute release stock query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	released := make([]*Movement, 0, len(bookIds))
+	for rows.Next() {
+		var bookId int64
+		var quantity int32
+		if err := rows.Scan(&bookId, &quantity); err != nil {
+			rows.Close()
+			err = fmt.Errorf("failed to scan released reservation: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		released = append(released, newReservationMovement(bookId, &basketId, -quantity, releasedReason, customerActor))
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read released reservations: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	for _, m := range movements {
+		m.Reference = &reference
+	}
+
+	if err := applyMovements(ctx, tx, append(released, movements...)); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			d.logger.Error(err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return movements, nil
+}
+
+// Reserve sets the number of copies of the book reserved for the basket and
+// prolongs the reservation till its expiry time. The difference with the
+// current reservation is recorded in the ledger. Expired reservations of the
+// book are released first, so they don't hold copies until the next sweep.
+// Ebooks need no stock, nothing is reserved for them and the reservation
+// is returned as is. Returns ErrNoRows if the book doesn't exist,
+// ErrInsufficientStock if there are not enough available copies, an error
+// on failure or the reservation on success.
+func (d *db) Reserve(reservation *Reservation) (*Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockBooks(ctx, tx, []int64{reservation.BookId}); err != nil {
+		return nil, err
+	}
+
+	var digital bool
+	digitalQuery := fmt.Sprintf("SELECT format = 'ebook' FROM %s WHERE id = $1", booksTableName)
+	if err := tx.QueryRow(ctx, digitalQuery, reservation.BookId).Scan(&digital); err != nil {
+		err = fmt.Errorf("failed to find book format: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if digital {
+		return reservation, nil
+	}
+
+	if _, err := releaseExpired(ctx, tx, []int64{reservation.BookId}); err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	var current int32
+	currentQuery := fmt.Sprintf(
+		"SELECT quantity FROM %s WHERE basket_id = $1 AND book_id = $2",
+		reservationsTableName,
+	)
+	err = tx.QueryRow(ctx, currentQuery, reservation.BasketId, reservation.BookId).Scan(&current)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		err = fmt.Errorf("failed to find current reservation: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	upsertQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, basket_id, quantity, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (basket_id, book_id) DO UPDATE
+	SET quantity = EXCLUDED.quantity, expires_at = EXCLUDED.expires_at
+	RETURNING id, created_at`, reservationsTableName)
+
+	err = tx.QueryRow(
+		ctx,
+		upsertQuery,
+		reservation.BookId,
+		reservation.BasketId,
+		reservation.Quantity,
+		reservation.ExpiresAt,
+	).Scan(&reservation.Id, &reservation.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute reserve stock query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if delta := reservation.Quantity - current; delta != 0 {
+		movement := newReservationMovement(reservation.BookId, reservation.BasketId, delta, reservedReason, customerActor)
+		if err := applyMovements(ctx, tx, []*Movement{movement}); err != nil {
+			if !errors.Is(err, apperror.ErrInsufficientStock) {
+				d.logger.Error(err)
+			}
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return reservation, nil
+}
+
+// Release deletes the reservation of the book for the basket
+// and returns reserved copies to available stock. The book is locked
+// before the reservation, in the same order as Reserve takes the locks.
+// Does nothing if there is no such reservation. Returns an error on failure.
+func (d *db) Release(basketId, bookId int64) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE basket_id = $1 AND book_id = $2
+	RETURNING quantity`, reservationsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockBooks(ctx, tx, []int64{bookId}); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return nil
+		}
+		d.logger.Error(err)
+		return err
+	}
+
+	var quantity int32
+	if err := tx.QueryRow(ctx, query, basketId, bookId).Scan(&quantity); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		err = fmt.Errorf("failed to execute release stock query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	movement := newReservationMovement(bookId, &basketId, -quantity, releasedReason, customerActor)
+	if err := applyMovements(ctx, tx, []*Movement{movement}); err != nil {
+		d.logger.Error(err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ReleaseExpired deletes all expired reservations and returns their copies
+// to available stock. Books of the reservations are locked before they are
+// deleted, in the same order as Reserve takes the locks, so the sweeper
+// can't deadlock with baskets. Returns an error on failure or the number
+// of released reservations on success.
+func (d *db) ReleaseExpired() (int64, error) {
+	booksQuery := fmt.Sprintf(
+		"SELECT DISTINCT book_id FROM %s WHERE expires_at <= now() ORDER BY book_id",
+		reservationsTableName,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, booksQuery)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find books of expired reservations query: %v", err)
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	bookIds := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			err = fmt.Errorf("failed to scan book of expired reservation: %v", err)
+			d.logger.Error(err)
+			return 0, err
+		}
+		bookIds = append(bookIds, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read books of expired reservations: %v", err)
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	if len(bookIds) == 0 {
+		return 0, nil
+	}
+
+	if err := lockBooks(ctx, tx, bookIds); err != nil {
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	released, err := releaseExpired(ctx, tx, bookIds)
+	if err != nil {
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return released, nil
+}
+
+// FindStock derives stock quantities of the book with specified id from the
+// ledger and active reservations.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) FindStock(bookId int64) (*Stock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	stock, err := scanStock(d.conn.QueryRow(ctx, stockQuery, bookId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find stock query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// FindMovements finds movements of the book with specified id, newest first.
+// Returns an error on failure.
+func (d *db) FindMovements(bookId int64, limit, offset int) ([]*Movement, error) {
+	query := fmt.Sprintf(`
+	SELECT id, book_id, type, quantity, reason, actor, reference, created_at
+	FROM %s
+	WHERE book_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3`, tableName)
+
+	return d.findMovements(query, bookId, limit, offset)
+}
+
+// FindByReference finds movements with the reference in the order they
+// were recorded. Returns an error on failure.
+func (d *db) FindByReference(reference string) ([]*Movement, error) {
+	query := fmt.Sprintf(`
+	SELECT id, book_id, type, quantity, reason, actor, reference, created_at
+	FROM %s
+	WHERE reference = $1
+	ORDER BY id`, tableName)
+
+	return d.findMovements(query, reference)
+}
+
+// findMovements finds movements with the query and its arguments.
+func (d *db) findMovements(query string, args ...interface{}) ([]*Movement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find stock movements query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]*Movement, 0)
+	for rows.Next() {
+		var m Movement
+		err := rows.Scan(
+			&m.Id,
+			&m.BookId,
+			&m.Type,
+			&m.Quantity,
+			&m.Reason,
+			&m.Actor,
+			&m.Reference,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan stock movement: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		movements = append(movements, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read stock movements: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// Reconcile compares cached counts of all books with on-hand quantities
+// derived from the ledger. Returns an error on failure or the report with
+// books which counts differ on success.
+func (d *db) Reconcile() (*Reconciliation, error) {
+	query := fmt.Sprintf(`
+	SELECT b.id, b.title, b.count, COALESCE(l.on_hand, 0)::int
+	FROM %s b
+	LEFT JOIN (
+		SELECT book_id, SUM(quantity) AS on_hand
+		FROM %s
+		WHERE type <> 'reservation'
+		GROUP BY book_id
+	) l ON l.book_id = b.id
+	WHERE b.count <> COALESCE(l.on_hand, 0)
+	ORDER BY b.id`, booksTableName, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	report := Reconciliation{Discrepancies: make([]Discrepancy, 0)}
+
+	countQuery := fmt.Sprintf("SELECT count(*) FROM %s", booksTableName)
+	if err := tx.QueryRow(ctx, countQuery).Scan(&report.BooksChecked); err != nil {
+		err = fmt.Errorf("failed to count books: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("failed to execute reconcile stock query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var found Discrepancy
+		if err := rows.Scan(&found.BookId, &found.Title, &found.CachedCount, &found.LedgerCount); err != nil {
+			err = fmt.Errorf("failed to scan stock discrepancy: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		found.Difference = found.CachedCount - found.LedgerCount
+		report.Discrepancies = append(report.Discrepancies, found)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read stock discrepancies: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// scanStock scans the row of stockQuery.
+func scanStock(row pgx.Row) (*Stock, error) {
+	var stock Stock
+	if err := row.Scan(&stock.BookId, &stock.OnHand, &stock.Reserved); err != nil {
+		return nil, err
+	}
+	stock.Available = stock.OnHand - stock.Reserved
+	return &stock, nil
+}
+
+// applyMovements appends the movements to the ledger within the transaction
+// and updates cached counts of the books. Books are locked, so concurrent
+// movements of the same book are applied one by one. Returns ErrNoRows if
+// some book doesn't exist, ErrInsufficientStock if on-hand quantity
+// becomes negative or if sales and reservations take more copies than
+// available, or an error on failure.
+func applyMovements(ctx context.Context, tx pgx.Tx, movements []*Movement) error {
+	// takesAvailable marks books which movements need available copies.
+	takesAvailable := make(map[int64]bool)
+	for _, m := range movements {
+		takes := m.Type == MovementSale || (m.Type == MovementReservation && m.Quantity > 0)
+		takesAvailable[m.BookId] = takesAvailable[m.BookId] || takes
+	}
+
+	bookIds := make([]int64, 0, len(takesAvailable))
+	for id := range takesAvailable {
+		bookIds = append(bookIds, id)
+	}
+	sort.Slice(bookIds, func(i, j int) bool { return bookIds[i] < bookIds[j] })
+
+	if err := lockBooks(ctx, tx, bookIds); err != nil {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, type, quantity, reason, actor, reference)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at`, tableName)
+	countQuery := fmt.Sprintf("UPDATE %s SET count = count + $1 WHERE id = $2", booksTableName)
+
+	for _, m := range movements {
+		err := tx.QueryRow(
+			ctx,
+			insertQuery,
+			m.BookId,
+			m.Type,
+			m.Quantity,
+			m.Reason,
+			m.Actor,
+			m.Reference,
+		).Scan(&m.Id, &m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to execute create stock movement query: %v", err)
+		}
+
+		if m.Type == MovementReservation {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, countQuery, m.Quantity, m.BookId); err != nil {
+			return fmt.Errorf("failed to update cached book count: %v", err)
+		}
+	}
+
+	for _, id := range bookIds {
+		stock, err := scanStock(tx.QueryRow(ctx, stockQuery, id))
+		if err != nil {
+			return fmt.Errorf("failed to get stock of book %d: %v", id, err)
+		}
+
+		if stock.OnHand < 0 || (takesAvailable[id] && stock.Available < 0) {
+			return apperror.ErrInsufficientStock
+		}
+	}
+
+	return nil
+}
+
+// lockBooks locks the books with specified ids, sorted in ascending order
+// to avoid deadlocks, till the end of the transaction.
+// Returns ErrNoRows if some book doesn't exist or an error on failure.
+func lockBooks(ctx context.Context, tx pgx.Tx, bookIds []int64) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = ANY($1) ORDER BY id FOR UPDATE", booksTableName)
+
+	result, err := tx.Exec(ctx, query, bookIds)
+	if err != nil {
+		return fmt.Errorf("failed to lock books: %v", err)
+	}
+
+	if result.RowsAffected() != int64(len(bookIds)) {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// releaseExpired deletes expired reservations of the books, which must be
+// locked already, and records their release in the ledger.
+// Returns the number of released reservations or an error on failure.
+func releaseExpired(ctx context.Context, tx pgx.Tx, bookIds []int64) (int64, error) {
+	query := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE expires_at <= now() AND book_id = ANY($1)
+	RETURNING book_id, basket_id, quantity`, reservationsTableName)
+
+	rows, err := tx.Query(ctx, query, bookIds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute release expired reservations query: %v", err)
+	}
+	defer rows.Close()
+
+	movements := make([]*Movement, 0)
+	for rows.Next() {
+		var bookId int64
+		var basketId *int64
+		var quantity int32
+		if err := rows.Scan(&bookId, &basketId, &quantity); err != nil {
+			return 0, fmt.Errorf("failed to scan expired reservation: %v", err)
+		}
+		movements = append(movements, newReservationMovement(bookId, basketId, -quantity, expiredReason, systemActor))
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read expired reservations: %v", err)
+	}
+
+	if err := applyMovements(ctx, tx, movements); err != nil {
+		return 0, err
+	}
+
+	return int64(len(movements)), nil
+}
+
+// newReservationMovement returns a reservation movement of the book
+// which refers to the basket, if it's still there.
+func newReservationMovement(bookId int64, basketId *int64, quantity int32, reason, actor string) *Movement {
+	movement := Movement{
+		BookId:   bookId,
+		Type:     MovementReservation,
+		Quantity: quantity,
+		Reason:   reason,
+		Actor:    actor,
+	}
+
+	if basketId != nil {
+		reference := fmt.Sprintf("basket:%d", *basketId)
+		movement.Reference = &reference
+	}
+
+	return &movement
+}
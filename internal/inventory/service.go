@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes inventory service functionality.
+type Service interface {
+	Receive(ctx context.Context, input *ReceiptDTO) (*Movement, error)
+	Adjust(ctx context.Context, input *AdjustmentDTO) (*Movement, error)
+	Record(ctx context.Context, movements ...*Movement) ([]*Movement, error)
+	RecordOnce(ctx context.Context, reference string, movements ...*Movement) ([]*Movement, error)
+	Sell(ctx context.Context, reference string, basketId int64, movements ...*Movement) ([]*Movement, error)
+	Reserve(ctx context.Context, basketId, bookId int64, quantity int32, ttl time.Duration) (*Reservation, error)
+	Release(ctx context.Context, basketId, bookId int64) error
+	ReleaseExpired(ctx context.Context) (int64, error)
+	GetStock(ctx context.Context, bookId int64) (*Stock, error)
+	GetMovements(ctx context.Context, bookId int64, limit, offset int) ([]*Movement, error)
+	GetByReference(ctx context.Context, reference string) ([]*Movement, error)
+	Reconcile(ctx context.Context) (*Reconciliation, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) Receive(ctx context.Context, input *ReceiptDTO) (*Movement, error) {
+	return s.recordOne(ctx, &Movement{
+		BookId:    input.BookId,
+		Type:      MovementReceipt,
+		Quantity:  input.Quantity,
+		Reason:    input.Reason,
+		Actor:     input.Actor,
+		Reference: input.Reference,
+	})
+}
+
+func (s *service) Adjust(ctx context.Context, input *AdjustmentDTO) (*Movement, error) {
+	return s.recordOne(ctx, &Movement{
+		BookId:    input.BookId,
+		Type:      MovementAdjustment,
+		Quantity:  input.Quantity,
+		Reason:    input.Reason,
+		Actor:     input.Actor,
+		Reference: input.Reference,
+	})
+}
+
+// Record appends the movements to the ledger at once.
+// Either all of them are recorded or none.
+func (s *service) Record(ctx context.Context, movements ...*Movement) ([]*Movement, error) {
+	recorded, err := s.storage.Record(movements)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			s.logger.Errorf("failed to record stock movements: %v", err)
+		}
+		return nil, err
+	}
+
+	return recorded, nil
+}
+
+// RecordOnce appends the movements to the ledger with the reference at
+// once unless movements of their types with the reference are recorded
+// already. It's used by features which retry recording their movements.
+func (s *service) RecordOnce(ctx context.Context, reference string, movements ...*Movement) ([]*Movement, error) {
+	recorded, err := s.storage.RecordOnce(reference, movements)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			s.logger.Errorf("failed to record stock movements: %v", err)
+		}
+		return nil, err
+	}
+
+	return recorded, nil
+}
+
+// Sell turns reservations of the basket into sales of the books with the
+// reference at once. Sales are recorded once, so selling can be retried.
+func (s *service) Sell(ctx context.Context, reference string, basketId int64, movements ...*Movement) ([]*Movement, error) {
+	sold, err := s.storage.Sell(reference, basketId, movements)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			s.logger.Errorf("failed to sell books: %v", err)
+		}
+		return nil, err
+	}
+
+	return sold, nil
+}
+
+func (s *service) recordOne(ctx context.Context, movement *Movement) (*Movement, error) {
+	recorded, err := s.Record(ctx, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	return recorded[0], nil
+}
+
+// Reserve holds the quantity of the book for the basket for ttl from now.
+// Repeated calls change the quantity and prolong the reservation.
+func (s *service) Reserve(ctx context.Context, basketId, bookId int64, quantity int32, ttl time.Duration) (*Reservation, error) {
+	reservation := Reservation{
+		BookId:    bookId,
+		BasketId:  &basketId,
+		Quantity:  quantity,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	reserved, err := s.storage.Reserve(&reservation)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInsufficientStock) {
+			s.logger.Errorf("failed to reserve stock: %v", err)
+		}
+		return nil, err
+	}
+
+	return reserved, nil
+}
+
+func (s *service) Release(ctx context.Context, basketId, bookId int64) error {
+	if err := s.storage.Release(basketId, bookId); err != nil {
+		s.logger.Errorf("failed to release stock: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ReleaseExpired(ctx context.Context) (int64, error) {
+	released, err := s.storage.ReleaseExpired()
+	if err != nil {
+		s.logger.Errorf("failed to release expired reservations: %v", err)
+		return 0, err
+	}
+
+	return released, nil
+}
+
+func (s *service) GetStock(ctx context.Context, bookId int64) (*Stock, error) {
+	stock, err := s.storage.FindStock(bookId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find stock: %v", err)
+		}
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// GetMovements returns ErrNoRows if the book doesn't exist,
+// so missing books are not confused with books without movements.
+func (s *service) GetMovements(ctx context.Context, bookId int64, limit, offset int) ([]*Movement, error) {
+	if _, err := s.GetStock(ctx, bookId); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.storage.FindMovements(bookId, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find stock movements: %v", err)
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (s *service) GetByReference(ctx context.Context, reference string) ([]*Movement, error) {
+	movements, err := s.storage.FindByReference(reference)
+	if err != nil {
+		s.logger.Warnf("cannot find stock movements by reference: %v", err)
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func (s *service) Reconcile(ctx context.Context) (*Reconciliation, error) {
+	report, err := s.storage.Reconcile()
+	if err != nil {
+		s.logger.Warnf("cannot reconcile stock: %v", err)
+		return nil, err
+	}
+
+	if len(report.Discrepancies) > 0 {
+		s.logger.Warnf("stock ledger differs from cached count of %d books", len(report.Discrepancies))
+	}
+
+	return report, nil
+}
@@ -0,0 +1,15 @@
+package inventory
+
+// Storage describes a stock ledger storage functionality.
+type Storage interface {
+	Record(movements []*Movement) ([]*Movement, error)
+	RecordOnce(reference string, movements []*Movement) ([]*Movement, error)
+	Sell(reference string, basketId int64, movements []*Movement) ([]*Movement, error)
+	Reserve(reservation *Reservation) (*Reservation, error)
+	Release(basketId, bookId int64) error
+	ReleaseExpired() (int64, error)
+	FindStock(bookId int64) (*Stock, error)
+	FindMovements(bookId int64, limit, offset int) ([]*Movement, error)
+	FindByReference(reference string) ([]*Movement, error)
+	Reconcile() (*Reconciliation, error)
+}
@@ -0,0 +1,47 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Sweeper periodically releases expired reservations,
+// so their copies become available again.
+type Sweeper struct {
+	logger           logger.Logger
+	inventoryService Service
+	interval         time.Duration
+}
+
+// NewSweeper returns a new Sweeper which runs every interval.
+func NewSweeper(logger logger.Logger, inventoryService Service, interval time.Duration) *Sweeper {
+	return &Sweeper{
+		logger:           logger,
+		inventoryService: inventoryService,
+		interval:         interval,
+	}
+}
+
+// Run releases expired reservations until the context is done.
+// Failed sweeps are logged and retried on the next tick.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.inventoryService.ReleaseExpired(ctx)
+			if err != nil {
+				continue
+			}
+			if released > 0 {
+				s.logger.Infof("released %d expired reservations", released)
+			}
+		}
+	}
+}
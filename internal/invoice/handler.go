@@ -0,0 +1,244 @@
+package invoice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	orderInvoicesURL = "/api/orders/:id/invoices"
+	invoicesURL      = "/api/invoices"
+	invoiceURL       = "/api/invoices/:id"
+	documentURL      = "/api/invoices/:id/document"
+)
+
+// Handler handles requests specified to invoice service.
+type Handler struct {
+	logger         logger.Logger
+	invoiceService Service
+}
+
+// NewHandler returns a new invoice Handler instance.
+func NewHandler(logger logger.Logger, invoiceService Service) handler.Handling {
+	return &Handler{
+		logger:         logger,
+		invoiceService: invoiceService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, orderInvoicesURL, h.ListOrderInvoices)
+	router.HandlerFunc(http.MethodPost, orderInvoicesURL, h.IssueInvoice)
+	router.HandlerFunc(http.MethodGet, invoicesURL, h.ListInvoices)
+	router.HandlerFunc(http.MethodGet, invoiceURL, h.GetInvoice)
+	router.HandlerFunc(http.MethodGet, documentURL, h.DownloadInvoice)
+}
+
+// ListOrderInvoices godoc
+// @Summary List order invoices
+// @Description Get the invoice and credit notes of the order in order of issue.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 200 {array} Invoice
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/invoices [get]
+func (h *Handler) ListOrderInvoices(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST ORDER INVOICES")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	invoices, err := h.invoiceService.GetByOrder(r.Context(), orderId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invoices)
+}
+
+// IssueInvoice godoc
+// @Summary Issue invoice
+// @Description Issue the invoice of the paid order with the next invoice number now instead of waiting for the issuer. The invoice which has been issued already is returned instead of a new one. Admin only.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 200 {object} Invoice
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/invoices [post]
+func (h *Handler) IssueInvoice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ISSUE INVOICE")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	invoice, err := h.invoiceService.Issue(r.Context(), orderId)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrOrderNotInvoiceable):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invoice)
+}
+
+// ListInvoices godoc
+// @Summary List invoices
+// @Description Get invoices and credit notes of all orders, latest first. Admin only.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param kind query string false "Document kind" Enums(invoice, credit_note)
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Invoice
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /invoices [get]
+func (h *Handler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST INVOICES")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if _, ok := prefixes[kind]; kind != "" && !ok {
+		response.BadRequest(w, "kind must be invoice or credit_note", "")
+		return
+	}
+
+	invoices, err := h.invoiceService.GetAll(r.Context(), &Filter{
+		Kind:   kind,
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	})
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invoices)
+}
+
+// GetInvoice godoc
+// @Summary Show invoice
+// @Description Get the invoice or the credit note by id with its document.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Invoice id"
+// @Success 200 {object} Invoice
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /invoices/{id} [get]
+func (h *Handler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET INVOICE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	invoice, err := h.invoiceService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, invoice)
+}
+
+// DownloadInvoice godoc
+// @Summary Download invoice
+// @Description Download the invoice or the credit note as an HTML page or a PDF file.
+// @Description PDF is printed in a font covering Latin, Greek and Cyrillic scripts, other characters are replaced with question marks.
+// @Tags invoices
+// @Produce html
+// @Produce application/pdf
+// @Param id path int64 true "Invoice id"
+// @Param format query string true "File format" Enums(html, pdf)
+// @Success 200 {string} string "Document file"
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /invoices/{id}/document [get]
+func (h *Handler) DownloadInvoice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DOWNLOAD INVOICE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if _, ok := formats[format]; !ok {
+		response.BadRequest(w, fmt.Sprintf("unknown format %q, use %s or %s", format, FormatHTML, FormatPDF), "")
+		return
+	}
+
+	invoice, err := h.invoiceService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	var file bytes.Buffer
+	if err := Render(&file, invoice, format); err != nil {
+		h.logger.Errorf("failed to render invoice %d: %v", invoice.Id, err)
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	disposition := "attachment"
+	if format == FormatHTML {
+		disposition = "inline"
+	}
+
+	fileName := fmt.Sprintf("%s.%s", invoice.Number, formats[format].extension)
+	w.Header().Set("Content-Type", formats[format].contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, fileName))
+	w.WriteHeader(http.StatusOK)
+	file.WriteTo(w)
+}
@@ -0,0 +1,42 @@
+package invoice
+
+import (
+	"context"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Issuer periodically issues invoices of paid orders and credit notes of refunds.
+type Issuer struct {
+	logger         logger.Logger
+	invoiceService Service
+	interval       time.Duration
+}
+
+// NewIssuer returns a new Issuer which runs every interval.
+func NewIssuer(logger logger.Logger, invoiceService Service, interval time.Duration) *Issuer {
+	return &Issuer{
+		logger:         logger,
+		invoiceService: invoiceService,
+		interval:       interval,
+	}
+}
+
+// Run issues pending documents until the context is done.
+// Failed runs are logged and retried on the next tick.
+func (i *Issuer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.invoiceService.IssuePending(ctx); err != nil {
+				i.logger.Errorf("failed to issue invoices: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package invoice
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// KindInvoice is a kind of the invoice issued for the paid order.
+	KindInvoice = "invoice"
+	// KindCreditNote is a kind of the credit note issued for the refund
+	// of the order payment.
+	KindCreditNote = "credit_note"
+
+	// FormatHTML is a format of the HTML page of the document.
+	FormatHTML = "html"
+	// FormatPDF is a format of the printable PDF file of the document.
+	FormatPDF = "pdf"
+)
+
+// prefixes are prefixes of document numbers in series of their kinds.
+var prefixes = map[string]string{
+	KindInvoice:    "INV-",
+	KindCreditNote: "CN-",
+}
+
+// Invoice represents the invoice of the paid order or the credit note of
+// the refund. Documents are numbered sequentially in the series of their
+// kind and never change once issued. OrderId and RefundId are missing if
+// the order or the refund has been deleted. InvoiceId is the invoice the
+// credit note corrects.
+type Invoice struct {
+	Id        int64           `json:"id" example:"1"`
+	Kind      string          `json:"kind" example:"invoice"`
+	Number    string          `json:"number" example:"INV-000001"`
+	OrderId   *int64          `json:"orderId,omitempty" example:"1001"`
+	InvoiceId *int64          `json:"invoiceId,omitempty" example:"1"`
+	RefundId  *int64          `json:"refundId,omitempty" example:"1"`
+	Currency  string          `json:"currency" example:"EUR"`
+	Total     decimal.Decimal `json:"total" swaggertype:"string" example:"18.38"`
+	IssuedAt  time.Time       `json:"issuedAt" example:"2022-03-01T12:05:00Z"`
+	Document  Document        `json:"document"`
+} // @name Invoice
+
+// Document is the snapshot of everything printed on the invoice or the
+// credit note. Amounts are in the currency of the document with Decimals
+// digits after the decimal point. Lines are priced with the tax if
+// PricesIncludeTax, otherwise the tax is added to Total. Credit notes
+// refer to the invoice by InvoiceNumber and have the Reason of the refund.
+type Document struct {
+	Seller           Party           `json:"seller"`
+	Buyer            Party           `json:"buyer"`
+	OrderId          int64           `json:"orderId" example:"1001"`
+	OrderDate        time.Time       `json:"orderDate" example:"2022-03-01T12:00:00Z"`
+	InvoiceNumber    *string         `json:"invoiceNumber,omitempty" example:"INV-000001"`
+	Reason           *string         `json:"reason,omitempty" example:"return 1: damaged"`
+	Decimals         int32           `json:"decimals" example:"2"`
+	PricesIncludeTax bool            `json:"pricesIncludeTax" example:"false"`
+	Lines            []Line          `json:"lines"`
+	Discounts        []Discount      `json:"discounts"`
+	Shipping         *Shipping       `json:"shipping,omitempty"`
+	Taxes            []Tax           `json:"taxes"`
+	Subtotal         decimal.Decimal `json:"subtotal" swaggertype:"string" example:"22.98"`
+	Discount         decimal.Decimal `json:"discount" swaggertype:"string" example:"4.6"`
+	Tax              decimal.Decimal `json:"tax" swaggertype:"string" example:"1.2"`
+	Total            decimal.Decimal `json:"total" swaggertype:"string" example:"18.38"`
+} // @name InvoiceDocument
+
+// Party is the seller or the buyer named on the document.
+type Party struct {
+	Name    string   `json:"name" example:"ReadyRead GmbH"`
+	Address []string `json:"address" example:"Friedrichstraße 68,10117 Berlin,DE"`
+	TaxId   *string  `json:"taxId,omitempty" example:"DE123456789"`
+	Email   *string  `json:"email,omitempty" example:"billing@readyread.com"`
+} // @name InvoiceParty
+
+// Line is the line of the document. Amount is UnitPrice times Quantity,
+// Discount is taken off it and Tax is charged on the rest at TaxRate percent.
+type Line struct {
+	Description string          `json:"description" example:"War and Peace"`
+	Quantity    int32           `json:"quantity" example:"2"`
+	UnitPrice   decimal.Decimal `json:"unitPrice" swaggertype:"string" example:"11.49"`
+	Discount    decimal.Decimal `json:"discount" swaggertype:"string" example:"2.3"`
+	TaxRate     decimal.Decimal `json:"taxRate" swaggertype:"string" example:"7"`
+	Tax         decimal.Decimal `json:"tax" swaggertype:"string" example:"1.2"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string" example:"22.98"`
+} // @name InvoiceLine
+
+// Discount is the discount of the promo code taken off the lines.
+type Discount struct {
+	Code   string          `json:"code" example:"SPRING-2022"`
+	Amount decimal.Decimal `json:"amount" swaggertype:"string" example:"4.6"`
+} // @name InvoiceDiscount
+
+// Shipping is the shipping method and its cost, which is not taxed.
+type Shipping struct {
+	Method string          `json:"method" example:"Express courier"`
+	Cost   decimal.Decimal `json:"cost" swaggertype:"string" example:"4.99"`
+} // @name InvoiceShipping
+
+// Tax is the line of the tax breakdown: the tax charged at Rate percent
+// on the Base amount of lines.
+type Tax struct {
+	Rate decimal.Decimal `json:"rate" swaggertype:"string" example:"7"`
+	Base decimal.Decimal `json:"base" swaggertype:"string" example:"17.18"`
+	Tax  decimal.Decimal `json:"tax" swaggertype:"string" example:"1.2"`
+} // @name InvoiceTax
+
+// Seller describes the seller printed on documents.
+type Seller struct {
+	Name    string
+	Address []string
+	TaxId   string
+	Email   string
+}
+
+// Refund is the refund of the order payment the credit note is issued for.
+type Refund struct {
+	Id        int64
+	OrderId   int64
+	Amount    decimal.Decimal
+	Reason    *string
+	CreatedAt time.Time
+}
+
+// Filter is used to filter and paginate documents list.
+type Filter struct {
+	Kind   string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,291 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName          = "invoices"
+	sequencesTableName = "invoice_sequences"
+
+	selectQuery = `
+	SELECT id, kind, number, order_id, invoice_id, refund_id, currency, total, issued_at, document
+	FROM invoices`
+
+	// paidCondition matches orders which have been paid, even if they have
+	// been cancelled since.
+	paidCondition = `(
+		o.status IN ('paid', 'shipped', 'delivered') OR
+		EXISTS (
+			SELECT 1 FROM order_status_changes c
+			WHERE c.order_id = o.id AND c.event = 'status_changed' AND c.status = 'paid'
+		)
+	)`
+
+	// uninvoicedQuery selects paid orders which have no invoice yet.
+	uninvoicedQuery = `
+	SELECT o.id
+	FROM orders o
+	WHERE ` + paidCondition + `
+		AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.order_id = o.id AND i.kind = 'invoice')
+	ORDER BY o.id
+	LIMIT $1`
+
+	// uncreditedQuery selects succeeded refunds of paid orders which have
+	// no credit note yet.
+	uncreditedQuery = `
+	SELECT r.id, o.id, r.amount, r.reason, r.created_at
+	FROM payment_refunds r
+	JOIN payments p ON p.id = r.payment_id
+	JOIN orders o ON o.id = p.order_id
+	WHERE ` + paidCondition + `
+		AND r.status = 'succeeded'
+		AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.refund_id = r.id)
+	ORDER BY r.id
+	LIMIT $1`
+)
+
+// Check whether db implements invoice storage interface.
+var _ Storage = &db{}
+
+// db implements invoice storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new invoice storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create issues the invoice or the credit note with the next number of
+// its series. Returns ErrInvoiceExists if the order has the invoice or the
+// refund has the credit note already, ErrNoRows if the order, the refund
+// or the invoice doesn't exist, an error on failure or the issued document
+// on success.
+func (d *db) Create(invoice *Invoice) (*Invoice, error) {
+	sequenceQuery := fmt.Sprintf(`
+	UPDATE %s SET last_number = last_number + 1
+	WHERE kind = $1
+	RETURNING last_number`, sequencesTableName)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (kind, number, order_id, invoice_id, refund_id, currency, total, document)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id, issued_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var number int64
+	if err := tx.QueryRow(ctx, sequenceQuery, invoice.Kind).Scan(&number); err != nil {
+		err = fmt.Errorf("failed to execute next invoice number query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	invoice.Number = fmt.Sprintf("%s%06d", prefixes[invoice.Kind], number)
+
+	err = tx.QueryRow(
+		ctx,
+		insertQuery,
+		invoice.Kind,
+		invoice.Number,
+		invoice.OrderId,
+		invoice.InvoiceId,
+		invoice.RefundId,
+		invoice.Currency,
+		invoice.Total,
+		invoice.Document,
+	).Scan(&invoice.Id, &invoice.IssuedAt)
+	if err != nil {
+		switch {
+		case apperror.IsUniqueViolation(err):
+			return nil, apperror.ErrInvoiceExists
+		case apperror.IsForeignKeyViolation(err):
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create invoice query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return invoice, nil
+}
+
+// FindById finds the invoice or the credit note with specified id.
+// Returns ErrNoRows if the document doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	invoice, err := scanInvoice(d.conn.QueryRow(ctx, selectQuery+" WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find invoice by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// FindByOrder finds the invoice and credit notes of the order in order of
+// issue. Returns an error on failure.
+func (d *db) FindByOrder(orderId int64) ([]*Invoice, error) {
+	return d.find(selectQuery+" WHERE order_id = $1 ORDER BY id", orderId)
+}
+
+// FindAll finds documents which match the given filter, latest first.
+// Returns an error on failure.
+func (d *db) FindAll(filter *Filter) ([]*Invoice, error) {
+	query := selectQuery + " WHERE kind = $1 OR $1 = '' ORDER BY id DESC LIMIT $2 OFFSET $3"
+	return d.find(query, filter.Kind, filter.Limit, filter.Offset)
+}
+
+// FindUninvoiced finds ids of paid orders without the invoice, oldest
+// first. Returns an error on failure.
+func (d *db) FindUninvoiced(limit int) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, uninvoicedQuery, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find uninvoiced orders query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			err = fmt.Errorf("failed to scan order id: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read uninvoiced orders: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// FindUncredited finds refunds without the credit note, oldest first.
+// Returns an error on failure.
+func (d *db) FindUncredited(limit int) ([]*Refund, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, uncreditedQuery, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find uncredited refunds query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := make([]*Refund, 0)
+	for rows.Next() {
+		var r Refund
+		if err := rows.Scan(&r.Id, &r.OrderId, &r.Amount, &r.Reason, &r.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to scan refund: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		refunds = append(refunds, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read uncredited refunds: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// find finds documents with the query of selectQuery and its arguments.
+func (d *db) find(query string, args ...interface{}) ([]*Invoice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find invoices query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := make([]*Invoice, 0)
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan invoice: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read invoices: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// scanInvoice scans the row of selectQuery.
+func scanInvoice(row pgx.Row) (*Invoice, error) {
+	var i Invoice
+	err := row.Scan(
+		&i.Id,
+		&i.Kind,
+		&i.Number,
+		&i.OrderId,
+		&i.InvoiceId,
+		&i.RefundId,
+		&i.Currency,
+		&i.Total,
+		&i.IssuedAt,
+		&i.Document,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,106 @@
+package invoice
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/juicyluv/ReadyRead/pkg/pdf"
+	"github.com/shopspring/decimal"
+)
+
+// formats describes content types and file extensions of document formats.
+var formats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	FormatHTML: {contentType: "text/html; charset=utf-8", extension: "html"},
+	FormatPDF:  {contentType: "application/pdf", extension: "pdf"},
+}
+
+// titles are titles of documents by their kinds.
+var titles = map[string]string{
+	KindInvoice:    "Invoice",
+	KindCreditNote: "Credit note",
+}
+
+//go:embed templates
+var templates embed.FS
+
+// Templates are parsed with placeholders of functions which depend on the
+// document, they are bound by render.
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("invoice.html").
+			Funcs(htmltemplate.FuncMap(funcs(2))).
+			ParseFS(templates, "templates/invoice.html"))
+	textTemplate = template.Must(template.New("invoice.txt").
+			Funcs(funcs(2)).
+			ParseFS(templates, "templates/invoice.txt"))
+)
+
+// Render writes the document in the format to w. HTML is rendered from
+// the HTML template, PDF is printed from the text template.
+func Render(w io.Writer, invoice *Invoice, format string) error {
+	switch format {
+	case FormatHTML:
+		tmpl, err := htmlTemplate.Clone()
+		if err != nil {
+			return err
+		}
+		return tmpl.Funcs(htmltemplate.FuncMap(funcs(invoice.Document.Decimals))).Execute(w, invoice)
+	case FormatPDF:
+		tmpl, err := textTemplate.Clone()
+		if err != nil {
+			return err
+		}
+
+		var text bytes.Buffer
+		if err := tmpl.Funcs(funcs(invoice.Document.Decimals)).Execute(&text, invoice); err != nil {
+			return err
+		}
+
+		return pdf.Write(w, fmt.Sprintf("%s %s", titles[invoice.Kind], invoice.Number), text.String())
+	default:
+		return fmt.Errorf("unknown format %q, use %s or %s", format, FormatHTML, FormatPDF)
+	}
+}
+
+// funcs returns functions of templates which format amounts with decimals
+// digits after the decimal point.
+func funcs(decimals int32) template.FuncMap {
+	return template.FuncMap{
+		"title": func(kind string) string { return titles[kind] },
+		"upper": strings.ToUpper,
+		"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+		"money": func(amount decimal.Decimal) string {
+			return amount.StringFixed(decimals)
+		},
+		"negate": func(amount decimal.Decimal) decimal.Decimal { return amount.Neg() },
+		"rate": func(rate decimal.Decimal) string {
+			return rate.String() + "%"
+		},
+		"left": func(width int, v interface{}) string {
+			s := truncate(fmt.Sprint(v), width)
+			return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+		},
+		"right": func(width int, v interface{}) string {
+			s := truncate(fmt.Sprint(v), width)
+			return strings.Repeat(" ", width-utf8.RuneCountInString(s)) + s
+		},
+		"rule": func(width int) string { return strings.Repeat("-", width) },
+	}
+}
+
+// truncate cuts the string to width characters ending with an ellipsis.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-3]) + "..."
+}
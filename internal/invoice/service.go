@@ -0,0 +1,425 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/internal/user"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+// batchSize limits the number of documents issued by a single run.
+const batchSize = 100
+
+// Service describes invoice service functionality.
+type Service interface {
+	Issue(ctx context.Context, orderId int64) (*Invoice, error)
+	IssuePending(ctx context.Context) error
+	GetById(ctx context.Context, id int64) (*Invoice, error)
+	GetByOrder(ctx context.Context, orderId int64) ([]*Invoice, error)
+	GetAll(ctx context.Context, filter *Filter) ([]*Invoice, error)
+}
+
+type service struct {
+	logger          logger.Logger
+	storage         Storage
+	orderService    order.Service
+	userService     user.Service
+	currencyService currency.Service
+	seller          Party
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(
+	storage Storage,
+	orderService order.Service,
+	userService user.Service,
+	currencyService currency.Service,
+	seller Seller,
+	logger logger.Logger,
+) Service {
+	party := Party{Name: seller.Name, Address: append([]string{}, seller.Address...)}
+	if seller.TaxId != "" {
+		party.TaxId = &seller.TaxId
+	}
+	if seller.Email != "" {
+		party.Email = &seller.Email
+	}
+
+	return &service{
+		logger:          logger,
+		storage:         storage,
+		orderService:    orderService,
+		userService:     userService,
+		currencyService: currencyService,
+		seller:          party,
+	}
+}
+
+// Issue issues the invoice of the paid order with the next invoice number.
+// The invoice which has been issued already is returned instead of a new
+// one. Returns ErrNoRows if the order doesn't exist and
+// ErrOrderNotInvoiceable if it has never been paid.
+func (s *service) Issue(ctx context.Context, orderId int64) (*Invoice, error) {
+	if invoice, err := s.findInvoice(ctx, orderId); err != nil || invoice != nil {
+		return invoice, err
+	}
+
+	o, err := s.orderService.GetById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !paid(o) {
+		return nil, apperror.ErrOrderNotInvoiceable
+	}
+
+	document, err := s.invoiceDocument(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.storage.Create(&Invoice{
+		Kind:     KindInvoice,
+		OrderId:  &o.Id,
+		Currency: o.Currency,
+		Total:    document.Total,
+		Document: *document,
+	})
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvoiceExists) {
+			return s.findInvoice(ctx, orderId)
+		}
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to issue invoice: %v", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Infof("issued invoice %s for order %d", invoice.Number, orderId)
+	return invoice, nil
+}
+
+// IssuePending issues invoices of paid orders and credit notes of refunds
+// which have no documents yet. Failures of single documents are logged and
+// retried on the next run.
+func (s *service) IssuePending(ctx context.Context) error {
+	orderIds, err := s.storage.FindUninvoiced(batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, orderId := range orderIds {
+		if _, err := s.Issue(ctx, orderId); err != nil {
+			s.logger.Warnf("cannot issue invoice for order %d: %v", orderId, err)
+		}
+	}
+
+	refunds, err := s.storage.FindUncredited(batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, refund := range refunds {
+		if err := s.issueCreditNote(ctx, refund); err != nil {
+			s.logger.Warnf("cannot issue credit note for refund %d: %v", refund.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Invoice, error) {
+	invoice, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find invoice by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *service) GetByOrder(ctx context.Context, orderId int64) ([]*Invoice, error) {
+	invoices, err := s.storage.FindByOrder(orderId)
+	if err != nil {
+		s.logger.Warnf("cannot find order invoices: %v", err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter *Filter) ([]*Invoice, error) {
+	invoices, err := s.storage.FindAll(filter)
+	if err != nil {
+		s.logger.Warnf("cannot find invoices: %v", err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// issueCreditNote issues the credit note of the refund with the next credit
+// note number. The invoice of the order is issued first if it's missing.
+func (s *service) issueCreditNote(ctx context.Context, refund *Refund) error {
+	invoice, err := s.Issue(ctx, refund.OrderId)
+	if err != nil {
+		return err
+	}
+
+	creditNote, err := s.storage.Create(&Invoice{
+		Kind:      KindCreditNote,
+		OrderId:   &refund.OrderId,
+		InvoiceId: &invoice.Id,
+		RefundId:  &refund.Id,
+		Currency:  invoice.Currency,
+		Total:     refund.Amount,
+		Document:  creditDocument(invoice, refund),
+	})
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvoiceExists) || errors.Is(err, apperror.ErrNoRows) {
+			return nil
+		}
+		s.logger.Errorf("failed to issue credit note: %v", err)
+		return err
+	}
+
+	s.logger.Infof("issued credit note %s for refund %d of order %d", creditNote.Number, refund.Id, refund.OrderId)
+	return nil
+}
+
+// findInvoice returns the invoice of the order or nil if it's not issued.
+func (s *service) findInvoice(ctx context.Context, orderId int64) (*Invoice, error) {
+	invoices, err := s.GetByOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, invoice := range invoices {
+		if invoice.Kind == KindInvoice {
+			return invoice, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// invoiceDocument returns the invoice document of the order. The buyer is
+// the recipient of the billing address, or the shipping one if the order
+// has no billing address.
+func (s *service) invoiceDocument(ctx context.Context, o *order.Order) (*Document, error) {
+	u, err := s.userService.GetById(ctx, o.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.currencyService.Rates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := int32(2)
+	if cur, ok := rates.Currency(o.Currency); ok {
+		decimals = cur.Decimals
+	}
+
+	buyer := Party{Name: u.Username, Address: []string{}, Email: &u.Email}
+	if address := o.BillingAddress; address != nil || o.ShippingAddress != nil {
+		if address == nil {
+			address = o.ShippingAddress
+		}
+		buyer.Name = address.Recipient
+		buyer.Address = formatAddress(address)
+	}
+
+	document := &Document{
+		Seller:           s.seller,
+		Buyer:            buyer,
+		OrderId:          o.Id,
+		OrderDate:        o.Date,
+		Decimals:         decimals,
+		PricesIncludeTax: o.PricesIncludeTax,
+		Lines:            make([]Line, 0, len(o.Items)),
+		Discounts:        make([]Discount, 0, len(o.Discounts)),
+		Discount:         o.Discount,
+		Tax:              o.Tax,
+		Total:            o.TotalPrice,
+	}
+
+	taxes := make(map[string]*Tax)
+	for _, item := range o.Items {
+		amount := item.UnitPrice.Mul(decimal.New(int64(item.Quantity), 0))
+		document.Subtotal = document.Subtotal.Add(amount)
+		document.Lines = append(document.Lines, Line{
+			Description: item.Title,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			TaxRate:     item.TaxRate,
+			Tax:         item.Tax,
+			Amount:      amount,
+		})
+
+		base := amount.Sub(item.Discount)
+		if o.PricesIncludeTax {
+			base = base.Sub(item.Tax)
+		}
+
+		tax, ok := taxes[item.TaxRate.String()]
+		if !ok {
+			tax = &Tax{Rate: item.TaxRate}
+			taxes[item.TaxRate.String()] = tax
+		}
+		tax.Base = tax.Base.Add(base)
+		tax.Tax = tax.Tax.Add(item.Tax)
+	}
+
+	document.Taxes = sortTaxes(taxes)
+
+	for _, discount := range o.Discounts {
+		document.Discounts = append(document.Discounts, Discount{Code: discount.Code, Amount: discount.Amount})
+	}
+
+	if o.Shipping != nil {
+		document.Shipping = &Shipping{Method: o.Shipping.Method, Cost: o.Shipping.Cost}
+	}
+
+	return document, nil
+}
+
+// creditDocument returns the credit note document of the refund. The refund
+// is split between tax rates of the invoice in proportion to the amounts
+// paid at each rate, the part of the shipping cost is not taxed.
+func creditDocument(invoice *Invoice, refund *Refund) Document {
+	original := invoice.Document
+	document := Document{
+		Seller:           original.Seller,
+		Buyer:            original.Buyer,
+		OrderId:          original.OrderId,
+		OrderDate:        original.OrderDate,
+		InvoiceNumber:    &invoice.Number,
+		Reason:           refund.Reason,
+		Decimals:         original.Decimals,
+		PricesIncludeTax: original.PricesIncludeTax,
+		Lines:            make([]Line, 0, len(original.Taxes)+1),
+		Discounts:        []Discount{},
+		Taxes:            make([]Tax, 0, len(original.Taxes)),
+		Total:            refund.Amount,
+	}
+
+	hundred := decimal.New(100, 0)
+	left := refund.Amount
+	for i, tax := range original.Taxes {
+		var share decimal.Decimal
+		switch {
+		case i == len(original.Taxes)-1 && original.Shipping == nil, original.Total.IsZero():
+			share = left
+		default:
+			share = refund.Amount.Mul(tax.Base.Add(tax.Tax)).Div(original.Total).Round(original.Decimals)
+			if share.GreaterThan(left) {
+				share = left
+			}
+		}
+		left = left.Sub(share)
+
+		if share.IsZero() {
+			continue
+		}
+
+		taxAmount := share.Mul(tax.Rate).Div(hundred.Add(tax.Rate)).Round(original.Decimals)
+		base := share.Sub(taxAmount)
+
+		document.Taxes = append(document.Taxes, Tax{Rate: tax.Rate, Base: base, Tax: taxAmount})
+		document.Tax = document.Tax.Add(taxAmount)
+
+		price := base
+		if original.PricesIncludeTax {
+			price = share
+		}
+		document.Lines = append(document.Lines, Line{
+			Description: fmt.Sprintf("Refund, %s%% tax", tax.Rate),
+			Quantity:    1,
+			UnitPrice:   price,
+			Discount:    decimal.Zero,
+			TaxRate:     tax.Rate,
+			Tax:         taxAmount,
+			Amount:      price,
+		})
+	}
+
+	if left.IsPositive() {
+		description := "Refund, not taxed"
+		if original.Shipping != nil {
+			description = "Refund of shipping"
+		}
+		document.Lines = append(document.Lines, Line{
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   left,
+			Discount:    decimal.Zero,
+			TaxRate:     decimal.Zero,
+			Tax:         decimal.Zero,
+			Amount:      left,
+		})
+	}
+
+	for _, line := range document.Lines {
+		document.Subtotal = document.Subtotal.Add(line.Amount)
+	}
+
+	return document
+}
+
+// sortTaxes returns the tax breakdown sorted by rates.
+func sortTaxes(taxes map[string]*Tax) []Tax {
+	sorted := make([]Tax, 0, len(taxes))
+	for _, tax := range taxes {
+		sorted = append(sorted, *tax)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Rate.LessThan(sorted[j].Rate)
+	})
+	return sorted
+}
+
+// formatAddress returns lines of the address as printed on documents.
+func formatAddress(address *order.Address) []string {
+	lines := append([]string{}, address.Lines...)
+
+	city := address.City
+	if address.PostalCode != nil {
+		city = *address.PostalCode + " " + city
+	}
+	lines = append(lines, city)
+
+	if address.Region != nil && *address.Region != address.City {
+		lines = append(lines, *address.Region)
+	}
+
+	return append(lines, strings.ToUpper(address.Country))
+}
+
+// paid reports whether the order has been paid, even if it has been
+// cancelled since.
+func paid(o *order.Order) bool {
+	if o.Status == order.StatusPaid || o.Status == order.StatusShipped || o.Status == order.StatusDelivered {
+		return true
+	}
+
+	for _, change := range o.History {
+		if change.Event == order.EventStatusChanged && change.Status == order.StatusPaid {
+			return true
+		}
+	}
+
+	return false
+}
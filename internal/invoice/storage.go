@@ -0,0 +1,11 @@
+package invoice
+
+// Storage describes an invoice storage functionality.
+type Storage interface {
+	Create(invoice *Invoice) (*Invoice, error)
+	FindById(id int64) (*Invoice, error)
+	FindByOrder(orderId int64) ([]*Invoice, error)
+	FindAll(filter *Filter) ([]*Invoice, error)
+	FindUninvoiced(limit int) ([]int64, error)
+	FindUncredited(limit int) ([]*Refund, error)
+}
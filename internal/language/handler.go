@@ -34,8 +34,9 @@ func NewHandler(logger logger.Logger, languageService Service) handler.Handling
 // Register registers new routes for router.
 func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodGet, languageURL, h.GetLanguage)
+	router.HandlerFunc(http.MethodGet, languagesURL, h.ListLanguages)
 	router.HandlerFunc(http.MethodPost, languagesURL, h.CreateLanguage)
-	router.HandlerFunc(http.MethodPut, languagesURL, h.UpdateLanguage)
+	router.HandlerFunc(http.MethodPut, languageURL, h.UpdateLanguage)
 	router.HandlerFunc(http.MethodDelete, languageURL, h.DeleteLanguage)
 }
 
@@ -73,6 +74,39 @@ func (h *Handler) GetLanguage(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, author)
 }
 
+// ListLanguages godoc
+// @Summary List languages
+// @Description Get languages with the number of books of each one. Optionally filtered by name.
+// @Tags languages
+// @Accept json
+// @Produce json
+// @Param search query string false "Part of the language name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Language
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /languages [get]
+func (h *Handler) ListLanguages(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST LANGUAGES")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+
+	languages, err := h.languageService.GetAll(r.Context(), search, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, languages)
+}
+
 // CreateLanguage godoc
 // @Summary Create language
 // @Description Insert language in database.
@@ -171,6 +205,7 @@ func (h *Handler) UpdateLanguage(w http.ResponseWriter, r *http.Request) {
 // @Param id path int64 true "Language id"
 // @Success 200
 // @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
 // @Failure 500 {object} apperror.AppError
 // @Router /languages/{id} [delete]
 func (h *Handler) DeleteLanguage(w http.ResponseWriter, r *http.Request) {
@@ -188,6 +223,10 @@ func (h *Handler) DeleteLanguage(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrReferenced) {
+			response.Conflict(w, err.Error(), "delete or reassign books of this language first")
+			return
+		}
 		response.InternalError(w, err.Error(), "something went wrong on the server side")
 		return
 	}
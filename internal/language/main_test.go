@@ -0,0 +1,12 @@
+package language
+
+import (
+	"os"
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testdb.Main(m))
+}
@@ -7,8 +7,9 @@ import (
 
 // Language represents the language model.
 type Language struct {
-	Id       int16  `json:"id" example:"123"`
-	Language string `json:"language" example:"ru"`
+	Id         int16  `json:"id" example:"123"`
+	Language   string `json:"language" example:"ru"`
+	BooksCount *int64 `json:"booksCount,omitempty" example:"42"`
 } // @name Language
 
 // CreateLanguageDTO is used to create language.
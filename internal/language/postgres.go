@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/internal/apperror"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 )
 
 const (
-	tableName = "languages"
+	tableName      = "languages"
+	booksTableName = "books"
 )
 
 // Check whether db implements language storage interface.
@@ -21,12 +23,12 @@ var _ Storage = &db{}
 // db implements language storage interface.
 type db struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 // NewStorage returns a new language storage instance.
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &db{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -119,16 +121,70 @@ func (d *db) Update(language *UpdateLanguageDTO) error {
 	return nil
 }
 
+// FindAll returns languages which names contain given search string
+// along with the number of books for each of them.
+// Returns an error on failure.
+func (d *db) FindAll(search string, limit, offset int) ([]*Language, error) {
+	query := fmt.Sprintf(`
+	SELECT t.id, t.language, COUNT(b.id)
+	FROM %s t
+	LEFT JOIN %s b ON b.language_id = t.id
+	WHERE t.language ILIKE '%%' || $1 || '%%'
+	GROUP BY t.id
+	ORDER BY t.language
+	LIMIT $2 OFFSET $3`, tableName, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, search, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all languages query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	languages := make([]*Language, 0)
+	for rows.Next() {
+		var language Language
+		if err := rows.Scan(&language.Id, &language.Language, &language.BooksCount); err != nil {
+			err = fmt.Errorf("failed to scan language: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		languages = append(languages, &language)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read languages: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return languages, nil
+}
+
+// Delete deletes the language with specified id.
+// Returns ErrNoRows if language doesn't exist, ErrReferenced if
+// there are books of this language or an error on failure.
 func (d *db) Delete(id int16) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
 
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
-	_, err := d.conn.Exec(ctx, query, id)
+	result, err := d.conn.Exec(ctx, query, id)
 	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrReferenced
+		}
 		return fmt.Errorf("failed to delete language: %v", err)
 	}
 
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
 	return nil
 }
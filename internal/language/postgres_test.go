@@ -0,0 +1,181 @@
+package language
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+// newStorage returns the storage backed by a new test database.
+func newStorage(t *testing.T) (Storage, *pgxpool.Pool) {
+	t.Helper()
+
+	conn := testdb.New(t)
+	return NewStorage(conn, 5), conn
+}
+
+// mustCreate creates languages with specified names.
+func mustCreate(t *testing.T, s Storage, names ...string) []*Language {
+	t.Helper()
+
+	languages := make([]*Language, 0, len(names))
+	for _, name := range names {
+		language, err := s.Create(&Language{Language: name})
+		if err != nil {
+			t.Fatalf("cannot create language %q: %v", name, err)
+		}
+		languages = append(languages, language)
+	}
+	return languages
+}
+
+// mustCreateBook creates a book of the language.
+func mustCreateBook(t *testing.T, conn *pgxpool.Pool, languageId int16) {
+	t.Helper()
+
+	_, err := conn.Exec(context.Background(), `
+	WITH w AS (INSERT INTO works (title) VALUES ('Dune') RETURNING id)
+	INSERT INTO books (title, description, price, count, language_id, work_id)
+	SELECT 'Dune', 'Desert planet', 9.99, 1, $1, w.id FROM w`, languageId)
+	if err != nil {
+		t.Fatalf("cannot create book: %v", err)
+	}
+}
+
+func TestFindById(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "en")[0]
+
+	tests := []struct {
+		name    string
+		id      int16
+		want    *Language
+		wantErr error
+	}{
+		{name: "existing", id: created.Id, want: &Language{Id: created.Id, Language: "en"}},
+		{name: "not found", id: created.Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindById(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FindById() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindById() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	s, conn := newStorage(t)
+	languages := mustCreate(t, s, "ru", "en", "fr")
+	mustCreateBook(t, conn, languages[1].Id)
+
+	tests := []struct {
+		name   string
+		search string
+		limit  int
+		offset int
+		want   []string
+		counts []int64
+	}{
+		{name: "all ordered by name", limit: 10, want: []string{"en", "fr", "ru"}, counts: []int64{1, 0, 0}},
+		{name: "search is case insensitive", search: "U", limit: 10, want: []string{"ru"}, counts: []int64{0}},
+		{name: "limit and offset", limit: 1, offset: 1, want: []string{"fr"}, counts: []int64{0}},
+		{name: "nothing found", search: "es", limit: 10, want: []string{}, counts: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.FindAll(tt.search, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+
+			names := make([]string, 0, len(got))
+			counts := make([]int64, 0, len(got))
+			for _, language := range got {
+				names = append(names, language.Language)
+				counts = append(counts, *language.BooksCount)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(counts, tt.counts) {
+				t.Errorf("FindAll() books counts = %v, want %v", counts, tt.counts)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s, _ := newStorage(t)
+	created := mustCreate(t, s, "en")[0]
+
+	tests := []struct {
+		name    string
+		input   *UpdateLanguageDTO
+		wantErr error
+	}{
+		{name: "existing", input: &UpdateLanguageDTO{Id: created.Id, Language: "de"}},
+		{name: "not found", input: &UpdateLanguageDTO{Id: created.Id + 1, Language: "es"}, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Update(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if got.Language != tt.input.Language {
+				t.Errorf("language = %q, want %q", got.Language, tt.input.Language)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s, conn := newStorage(t)
+	languages := mustCreate(t, s, "en", "ru")
+	mustCreateBook(t, conn, languages[1].Id)
+
+	tests := []struct {
+		name    string
+		id      int16
+		wantErr error
+	}{
+		{name: "unused", id: languages[0].Id},
+		{name: "already deleted", id: languages[0].Id, wantErr: apperror.ErrNoRows},
+		{name: "referenced by books", id: languages[1].Id, wantErr: apperror.ErrReferenced},
+		{name: "not found", id: languages[1].Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Delete(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := s.FindById(tt.id)
+			if tt.wantErr == nil && !errors.Is(err, apperror.ErrNoRows) {
+				t.Errorf("FindById() error = %v, want %v", err, apperror.ErrNoRows)
+			}
+		})
+	}
+}
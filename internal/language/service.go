@@ -12,6 +12,7 @@ import (
 type Service interface {
 	Create(ctx context.Context, language *CreateLanguageDTO) (*Language, error)
 	GetById(ctx context.Context, id int16) (*Language, error)
+	GetAll(ctx context.Context, search string, limit, offset int) ([]*Language, error)
 	Update(ctx context.Context, language *UpdateLanguageDTO) error
 	Delete(ctx context.Context, id int16) error
 }
@@ -55,6 +56,16 @@ func (s *service) GetById(ctx context.Context, id int16) (*Language, error) {
 	return language, nil
 }
 
+func (s *service) GetAll(ctx context.Context, search string, limit, offset int) ([]*Language, error) {
+	languages, err := s.storage.FindAll(search, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find languages: %v", err)
+		return nil, err
+	}
+
+	return languages, nil
+}
+
 func (s *service) Update(ctx context.Context, genre *UpdateLanguageDTO) error {
 	l, err := s.GetById(ctx, genre.Id)
 	if err != nil {
@@ -80,7 +91,7 @@ func (s *service) Update(ctx context.Context, genre *UpdateLanguageDTO) error {
 func (s *service) Delete(ctx context.Context, id int16) error {
 	err := s.storage.Delete(id)
 	if err != nil {
-		if !errors.Is(err, apperror.ErrNoRows) {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrReferenced) {
 			s.logger.Warnf("failed to delete language: %v", err)
 		}
 		return err
@@ -4,6 +4,7 @@ package language
 type Storage interface {
 	Create(genre *Language) (*Language, error)
 	FindById(id int16) (*Language, error)
+	FindAll(search string, limit, offset int) ([]*Language, error)
 	Update(genre *UpdateLanguageDTO) error
 	Delete(id int16) error
 }
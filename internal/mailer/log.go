@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// logMailer writes emails to the log instead of sending them.
+// It's used when SMTP server is not configured.
+type logMailer struct {
+	logger logger.Logger
+}
+
+// NewLogMailer returns a new Mailer which writes emails to the log.
+func NewLogMailer(logger logger.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(to []string, subject, body string) error {
+	m.logger.Infof("email to %s: %s\n%s", strings.Join(to, ", "), subject, body)
+	return nil
+}
@@ -0,0 +1,6 @@
+package mailer
+
+// Mailer sends plain text emails.
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
@@ -0,0 +1,246 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+	"path"
+
+	// Register decoders of supported formats.
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/juicyluv/ReadyRead/internal/blob"
+)
+
+const (
+	// maxPixels limits decoded image size to protect against decompression bombs.
+	maxPixels = 40_000_000
+
+	// variantQuality is a JPEG quality of resized variants.
+	variantQuality = 85
+)
+
+var (
+	// ErrUnsupportedType is returned when uploaded file is not a supported image.
+	ErrUnsupportedType = errors.New("unsupported image type, only jpeg, png and gif are allowed")
+
+	// ErrTooLarge is returned when uploaded image exceeds size limits.
+	ErrTooLarge = errors.New("image is too large")
+)
+
+// allowedTypes maps supported MIME types to file extensions.
+var allowedTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// variant describes a resized copy of the uploaded image.
+type variant struct {
+	name  string
+	width int
+}
+
+// variants are generated for every uploaded image.
+var variants = []variant{
+	{name: "medium", width: 600},
+	{name: "thumbnail", width: 150},
+}
+
+// Image represents an uploaded image with its resized variants.
+type Image struct {
+	Key          string `json:"key" example:"books/1/cover/9f86d081884c7d65"`
+	OriginalURL  string `json:"originalUrl" example:"http://localhost:8080/media/books/1/cover/9f86d081884c7d65/original.jpg"`
+	MediumURL    string `json:"mediumUrl" example:"http://localhost:8080/media/books/1/cover/9f86d081884c7d65/medium.jpg"`
+	ThumbnailURL string `json:"thumbnailUrl" example:"http://localhost:8080/media/books/1/cover/9f86d081884c7d65/thumbnail.jpg"`
+	Width        int    `json:"width" example:"1200"`
+	Height       int    `json:"height" example:"1800"`
+} // @name Image
+
+// objectKeys returns keys of all stored files of the image. They are derived
+// from the key and the extension of the original, so storage keys never have
+// to be exposed in responses. Images without a key are not kept in the store.
+func (img *Image) objectKeys() []string {
+	if img.Key == "" {
+		return nil
+	}
+
+	keys := []string{img.Key + "/original" + path.Ext(img.OriginalURL)}
+	for _, v := range variants {
+		keys = append(keys, fmt.Sprintf("%s/%s.jpg", img.Key, v.name))
+	}
+	return keys
+}
+
+// Processor validates uploaded images, generates resized
+// variants and saves them to the blob store.
+type Processor struct {
+	store   blob.Store
+	maxSize int64
+}
+
+// NewProcessor returns a new image Processor which
+// accepts images up to maxSize bytes.
+func NewProcessor(store blob.Store, maxSize int64) *Processor {
+	return &Processor{
+		store:   store,
+		maxSize: maxSize,
+	}
+}
+
+// Save validates the image, resizes it and saves the original and its
+// variants under the given key prefix. Every upload gets its own random key,
+// so a new image never overwrites files of the previous one and cached
+// URLs stay valid until the old image is deleted. The content type is
+// detected from the data itself, so client provided type is never trusted.
+func (p *Processor) Save(ctx context.Context, prefix string, data []byte) (*Image, error) {
+	if int64(len(data)) > p.maxSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("cannot generate image key: %v", err)
+	}
+
+	img := Image{
+		Key:    prefix + "/" + hex.EncodeToString(token),
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+
+	originalKey := fmt.Sprintf("%s/original.%s", img.Key, ext)
+	if err := p.store.Put(ctx, originalKey, data, contentType); err != nil {
+		return nil, fmt.Errorf("cannot save original image: %v", err)
+	}
+	img.OriginalURL = p.store.URL(originalKey)
+
+	// Transparent areas are flattened once, every variant is resized from it.
+	flat := flatten(src)
+
+	for _, v := range variants {
+		var buf bytes.Buffer
+		resized := resize(flat, v.width)
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantQuality}); err != nil {
+			p.Delete(ctx, &img)
+			return nil, fmt.Errorf("cannot encode %s image: %v", v.name, err)
+		}
+
+		key := fmt.Sprintf("%s/%s.jpg", img.Key, v.name)
+		if err := p.store.Put(ctx, key, buf.Bytes(), "image/jpeg"); err != nil {
+			p.Delete(ctx, &img)
+			return nil, fmt.Errorf("cannot save %s image: %v", v.name, err)
+		}
+
+		switch v.name {
+		case "medium":
+			img.MediumURL = p.store.URL(key)
+		case "thumbnail":
+			img.ThumbnailURL = p.store.URL(key)
+		}
+	}
+
+	return &img, nil
+}
+
+// Delete removes all stored files of the image.
+func (p *Processor) Delete(ctx context.Context, img *Image) error {
+	for _, key := range img.objectKeys() {
+		if err := p.store.Delete(ctx, key); err != nil {
+			return fmt.Errorf("cannot delete image: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// flatten draws the image over white background since JPEG has no alpha
+// channel.
+func flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, src, bounds.Min, draw.Over)
+	return flat
+}
+
+// resize scales the flattened image down to the given width keeping its
+// aspect ratio. Images which are already narrower are not upscaled. Every
+// destination pixel is an average of the source pixels it covers.
+func resize(flat *image.RGBA, width int) *image.RGBA {
+	bounds := flat.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	if width > srcW {
+		width = srcW
+	}
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				offset := flat.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(flat.Pix[offset])
+					g += uint32(flat.Pix[offset+1])
+					b += uint32(flat.Pix[offset+2])
+					offset += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+
+	return dst
+}
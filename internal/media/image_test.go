@@ -0,0 +1,179 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// memoryStore keeps objects in memory.
+type memoryStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.objects[key], nil
+}
+
+func (s *memoryStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(s.objects[key])), nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStore) URL(key string) string {
+	return "http://localhost/media/" + key
+}
+
+// encodePNG returns the PNG image of the size with half transparent pixels.
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, A: uint8(255 * (x % 2))})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSave(t *testing.T) {
+	store := &memoryStore{objects: make(map[string][]byte)}
+	p := NewProcessor(store, 1<<20)
+	ctx := context.Background()
+
+	img, err := p.Save(ctx, "books/1/cover", encodePNG(t, 1200, 900))
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if img.Width != 1200 || img.Height != 900 {
+		t.Errorf("size = %dx%d, want 1200x900", img.Width, img.Height)
+	}
+	if !strings.HasSuffix(img.OriginalURL, "/original.png") || !strings.HasSuffix(img.ThumbnailURL, "/thumbnail.jpg") {
+		t.Errorf("urls = %q, %q", img.OriginalURL, img.ThumbnailURL)
+	}
+
+	keys := make([]string, 0, len(store.objects))
+	for key := range store.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	want := img.objectKeys()
+	sort.Strings(want)
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("stored keys = %v, want %v", keys, want)
+	}
+
+	medium, _, err := image.DecodeConfig(bytes.NewReader(store.objects[img.Key+"/medium.jpg"]))
+	if err != nil {
+		t.Fatalf("cannot decode medium image: %v", err)
+	}
+	if medium.Width != 600 || medium.Height != 450 {
+		t.Errorf("medium size = %dx%d, want 600x450", medium.Width, medium.Height)
+	}
+
+	data, _ := json.Marshal(img)
+	if strings.Contains(string(data), "objectKeys") {
+		t.Errorf("object keys are exposed in %s", data)
+	}
+
+	// Keys are derived from the image, so it can be deleted after a round
+	// trip through the database.
+	var stored Image
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Delete(ctx, &stored); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("objects left after Delete(): %v", store.objects)
+	}
+}
+
+func TestSaveRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int64
+		data    []byte
+		wantErr error
+	}{
+		{name: "too large", maxSize: 10, data: encodePNG(t, 10, 10), wantErr: ErrTooLarge},
+		{name: "not an image", maxSize: 1 << 20, data: []byte("%PDF-1.4"), wantErr: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryStore{objects: make(map[string][]byte)}
+			_, err := NewProcessor(store, tt.maxSize).Save(context.Background(), "books/1/cover", tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Save() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(store.objects) != 0 {
+				t.Errorf("objects saved: %d", len(store.objects))
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		src.Set(x, 0, color.NRGBA{A: 255})
+		src.Set(x, 1, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+
+	tests := []struct {
+		name   string
+		width  int
+		want   image.Point
+		center color.RGBA
+	}{
+		{name: "averages covered pixels", width: 2, want: image.Pt(2, 1), center: color.RGBA{R: 127, G: 127, B: 127, A: 255}},
+		{name: "never upscales", width: 10, want: image.Pt(4, 2), center: color.RGBA{A: 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := resize(flatten(src), tt.width)
+			if dst.Bounds().Size() != tt.want {
+				t.Fatalf("size = %v, want %v", dst.Bounds().Size(), tt.want)
+			}
+			if got := dst.RGBAAt(0, 0); got != tt.center {
+				t.Errorf("pixel = %v, want %v", got, tt.center)
+			}
+		})
+	}
+}
+
+func TestFlattenTransparent(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+
+	if got := flatten(src).RGBAAt(0, 0); got != (color.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("pixel = %v, want white", got)
+	}
+}
@@ -0,0 +1,166 @@
+package order
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	orderURL       = "/api/orders/:id"
+	orderStatusURL = "/api/orders/:id/status"
+	userOrdersURL  = "/api/users/:id/orders"
+)
+
+// Handler handles requests specified to order service.
+type Handler struct {
+	logger       logger.Logger
+	orderService Service
+}
+
+// NewHandler returns a new order Handler instance.
+func NewHandler(logger logger.Logger, orderService Service) handler.Handling {
+	return &Handler{
+		logger:       logger,
+		orderService: orderService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, orderURL, h.GetOrder)
+	router.HandlerFunc(http.MethodPatch, orderStatusURL, h.ChangeOrderStatus)
+	router.HandlerFunc(http.MethodGet, userOrdersURL, h.ListUserOrders)
+}
+
+// GetOrder godoc
+// @Summary Show order
+// @Description Get order by id with its lines.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 200 {object} Order
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id} [get]
+func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET ORDER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	order, err := h.orderService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// ChangeOrderStatus godoc
+// @Summary Change order status
+// @Description Move the paid order along its shipping flow: paid to shipped, shipped to delivered. Orders without shipping are delivered once paid.
+// @Description Orders are paid by their payments and cancelled with POST /orders/{id}/cancel, which refunds them.
+// @Description Orders shipped by courier or post need a tracking number. Every change is recorded in the order history. Admin only.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Param input body StatusDTO true "JSON input"
+// @Success 200 {object} Order
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/status [patch]
+func (h *Handler) ChangeOrderStatus(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CHANGE ORDER STATUS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input StatusDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	order, err := h.orderService.ChangeStatus(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrTrackingNumberRequired):
+			response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		case errors.Is(err, apperror.ErrInvalidStatusTransition):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// ListUserOrders godoc
+// @Summary List user orders
+// @Description Get orders of the user, newest first.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Order
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/orders [get]
+func (h *Handler) ListUserOrders(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST USER ORDERS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	orders, err := h.orderService.GetByUser(r.Context(), userId, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, orders)
+}
@@ -0,0 +1,189 @@
+package order
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/shipping"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// StatusPending is a status of the order which is placed, but not paid yet.
+	StatusPending = "pending"
+	// StatusPaid is a status of the paid order.
+	StatusPaid = "paid"
+	// StatusShipped is a status of the order handed over to the carrier.
+	StatusShipped = "shipped"
+	// StatusDelivered is a status of the order received by the customer.
+	StatusDelivered = "delivered"
+	// StatusCancelled is a status of the order which won't be paid.
+	StatusCancelled = "cancelled"
+)
+
+// EventStatusChanged is the event of the order history recorded when the
+// order changes its status. Other events are recorded by features acting on
+// the order, e.g. returns and refunds.
+const EventStatusChanged = "status_changed"
+
+// trackingPattern matches tracking numbers of carriers, e.g. "JD014600003828".
+var trackingPattern = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+
+// transitions are statuses the order can change to from its status.
+// Orders without shipping are delivered once paid. Pending orders are paid
+// by the payment service only, see Order.checkPaid.
+var transitions = map[string][]string{
+	StatusPending: {StatusCancelled},
+	StatusPaid:    {StatusShipped, StatusDelivered, StatusCancelled},
+	StatusShipped: {StatusDelivered},
+}
+
+// Order represents the order placed from the basket on checkout.
+// TotalPrice is the sum of order lines less the discount of promo codes,
+// plus the tax unless prices include it, plus the shipping cost. Shipping
+// is missing if the order has nothing to ship. All amounts are in the currency
+// of the order. ExchangeRate is the rate of the currency to the base one
+// at checkout. Destination is where the order is shipped, its tax rates
+// are applied. Addresses are copies of the user addresses made at checkout.
+type Order struct {
+	Id               int64            `json:"id" example:"1001"`
+	UserId           int64            `json:"userId" example:"7"`
+	BasketId         int64            `json:"basketId" example:"42"`
+	Status           string           `json:"status" example:"pending"`
+	Date             time.Time        `json:"date" example:"2022-03-01T12:00:00Z"`
+	Currency         string           `json:"currency" example:"EUR"`
+	ExchangeRate     decimal.Decimal  `json:"exchangeRate" swaggertype:"string" example:"0.9132"`
+	Destination      *tax.Destination `json:"destination,omitempty"`
+	ShippingAddress  *Address         `json:"shippingAddress,omitempty"`
+	BillingAddress   *Address         `json:"billingAddress,omitempty"`
+	Shipping         *Shipping        `json:"shipping,omitempty"`
+	PricesIncludeTax bool             `json:"pricesIncludeTax" example:"true"`
+	Discount         decimal.Decimal  `json:"discount" swaggertype:"string" example:"4.6"`
+	Tax              decimal.Decimal  `json:"tax" swaggertype:"string" example:"1.2"`
+	TotalPrice       decimal.Decimal  `json:"totalPrice" swaggertype:"string" example:"18.38"`
+	Discounts        []Discount       `json:"discounts"`
+	Items            []OrderItem      `json:"items"`
+	History          []StatusChange   `json:"history"`
+} // @name Order
+
+// checkStatus checks whether the order can change to the status.
+// Orders shipped by courier or post need a tracking number. Returns
+// ErrInvalidStatusTransition or ErrTrackingNumberRequired if not.
+func (o *Order) checkStatus(change *StatusChange) error {
+	allowed := false
+	for _, status := range transitions[o.Status] {
+		allowed = allowed || status == change.Status
+	}
+
+	switch {
+	case !allowed,
+		change.Status == StatusShipped && o.Shipping == nil,
+		change.Status == StatusDelivered && o.Status == StatusPaid && o.Shipping != nil:
+		return fmt.Errorf("%w: %s to %s", apperror.ErrInvalidStatusTransition, o.Status, change.Status)
+	case change.Status == StatusShipped && o.Shipping.Kind != shipping.KindPickup && change.TrackingNumber == nil:
+		return apperror.ErrTrackingNumberRequired
+	}
+
+	return nil
+}
+
+// checkPaid checks whether the order can be paid.
+// Returns ErrInvalidStatusTransition if it's not pending.
+func (o *Order) checkPaid(change *StatusChange) error {
+	if o.Status != StatusPending {
+		return fmt.Errorf("%w: %s to %s", apperror.ErrInvalidStatusTransition, o.Status, StatusPaid)
+	}
+	return nil
+}
+
+// Shipping is the way the order is delivered. Method is the name of the
+// shipping method at checkout, MethodId is missing if it has been deleted.
+type Shipping struct {
+	MethodId       *int64          `json:"methodId,omitempty" example:"1"`
+	Method         string          `json:"method" example:"Express courier"`
+	Kind           string          `json:"kind" example:"courier"`
+	Cost           decimal.Decimal `json:"cost" swaggertype:"string" example:"4.99"`
+	TrackingNumber *string         `json:"trackingNumber,omitempty" example:"JD014600003828"`
+} // @name OrderShipping
+
+// StatusChange is the entry of the order history. It records the change
+// of the order status or another event of the order, Status is the status
+// of the order after the event.
+type StatusChange struct {
+	Event          string    `json:"event" example:"status_changed"`
+	Status         string    `json:"status" example:"shipped"`
+	TrackingNumber *string   `json:"trackingNumber,omitempty" example:"JD014600003828"`
+	Note           *string   `json:"note,omitempty" example:"Shipped from the Berlin warehouse"`
+	CreatedAt      time.Time `json:"createdAt" example:"2022-03-02T09:30:00Z"`
+} // @name OrderStatusChange
+
+// StatusDTO is used to change the status of the order. Orders are paid
+// and cancelled through payments, so only shipping statuses can be set.
+type StatusDTO struct {
+	Id             int64   `json:"-"`
+	Status         string  `json:"status" example:"shipped"`
+	TrackingNumber *string `json:"trackingNumber,omitempty" example:"JD014600003828"`
+	Note           *string `json:"note,omitempty" example:"Shipped from the Berlin warehouse"`
+} // @name OrderStatusInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (s *StatusDTO) Validate() error {
+	return validation.ValidateStruct(
+		s,
+		validation.Field(
+			&s.Status,
+			validation.Required,
+			validation.In(StatusShipped, StatusDelivered),
+		),
+		validation.Field(
+			&s.TrackingNumber,
+			validation.Length(4, 40),
+			validation.Match(trackingPattern).Error("must contain only letters, digits and hyphens"),
+		),
+		validation.Field(&s.Note, validation.RuneLength(1, 500)),
+	)
+}
+
+// OrderItem represents an order line. Title and prices are recorded at
+// checkout, so the line doesn't change with the book. UnitPrice is the
+// price the customer pays, it's lower than ListPrice if the book was on sale.
+// Discount is the part of promo code discounts taken off the line, and
+// Tax is the tax of the line after the discount at TaxRate percent. Digital
+// lines are ebooks, they need no stock and the customer gets entitlements
+// to download them once the order is paid.
+type OrderItem struct {
+	Id        int64           `json:"id" example:"5001"`
+	BookId    *int64          `json:"bookId" example:"123"`
+	Title     string          `json:"title" example:"War and Peace"`
+	Quantity  int32           `json:"quantity" example:"2"`
+	ListPrice decimal.Decimal `json:"listPrice" swaggertype:"string" example:"12.99"`
+	UnitPrice decimal.Decimal `json:"unitPrice" swaggertype:"string" example:"11.49"`
+	Discount  decimal.Decimal `json:"discount" swaggertype:"string" example:"2.3"`
+	TaxRate   decimal.Decimal `json:"taxRate" swaggertype:"string" example:"7"`
+	Tax       decimal.Decimal `json:"tax" swaggertype:"string" example:"1.2"`
+	Digital   bool            `json:"digital" example:"false"`
+} // @name OrderItem
+
+// Address is the copy of the user address the order is shipped or billed to.
+type Address struct {
+	Recipient  string   `json:"recipient" example:"Лев Толстой"`
+	Lines      []string `json:"lines" example:"Malaya Semenovskaya 12,apt. 5"`
+	City       string   `json:"city" example:"Moscow"`
+	Region     *string  `json:"region,omitempty" example:"Moscow"`
+	PostalCode *string  `json:"postalCode,omitempty" example:"107023"`
+	Country    string   `json:"country" example:"RU"`
+	Phone      *string  `json:"phone,omitempty" example:"+7 (800) 555-35-35"`
+} // @name OrderAddress
+
+// Discount represents an amount taken off the order by the promo code.
+// PromoCodeId is missing if the promo code has been deleted.
+type Discount struct {
+	PromoCodeId *int64          `json:"promoCodeId,omitempty" example:"1"`
+	Code        string          `json:"code" example:"SPRING-2022"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string" example:"4.6"`
+} // @name OrderDiscount
@@ -0,0 +1,466 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	tableName            = "orders"
+	itemsTableName       = "order_items"
+	promoTableName       = "promo_codes"
+	redemptionsTableName = "promo_redemptions"
+	changesTableName     = "order_status_changes"
+	returnsTableName     = "return_requests"
+
+	// selectQuery selects orders with their discounts, items and history aggregated to JSON arrays.
+	selectQuery = `
+	SELECT o.id, o.user_id, o.basket_id, o.status, o.date, o.currency, o.exchange_rate,
+		o.tax_country, o.tax_region, o.shipping_address, o.billing_address,
+		o.shipping_method_id, o.shipping_method, o.shipping_kind, o.shipping_cost, o.tracking_number,
+		o.prices_include_tax, o.discount, o.tax, COALESCE(o.total_price, 0),
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'promoCodeId', r.promo_code_id, 'code', r.code, 'amount', r.amount
+			) ORDER BY r.id)
+			FROM promo_redemptions r
+			WHERE r.order_id = o.id
+		), '[]'),
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'id', i.id, 'bookId', i.book_id, 'title', i.title, 'quantity', i.quantity,
+				'listPrice', i.list_price, 'unitPrice', i.unit_price, 'discount', i.discount,
+				'taxRate', i.tax_rate, 'tax', i.tax, 'digital', i.digital
+			) ORDER BY i.id)
+			FROM order_items i
+			WHERE i.order_id = o.id
+		), '[]'),
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'event', c.event, 'status', c.status, 'trackingNumber', c.tracking_number, 'note', c.note, 'createdAt', c.created_at
+			) ORDER BY c.id)
+			FROM order_status_changes c
+			WHERE c.order_id = o.id
+		), '[]')
+	FROM orders o`
+)
+
+// Check whether db implements order storage interface.
+var _ Storage = &db{}
+
+// db implements order storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new order storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create places the pending order priced at checkout. Order lines record
+// titles and prices of books the customer pays. Discounts are recorded
+// as redemptions of their promo codes in the same transaction, usage
+// limits of the codes are checked with the codes locked.
+// Returns ErrPromoInactive if some code is deleted or not valid anymore,
+// ErrPromoLimitReached if it has been used the maximum number of times,
+// an error on failure or the order on success.
+func (d *db) Create(order *Order) (*Order, error) {
+	orderQuery := fmt.Sprintf(`
+	INSERT INTO %s (
+		user_id, basket_id, status, currency, exchange_rate,
+		tax_country, tax_region, shipping_address, billing_address,
+		shipping_method_id, shipping_method, shipping_kind, shipping_cost,
+		prices_include_tax, discount, tax, total_price
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	RETURNING id`, tableName)
+
+	changeQuery := fmt.Sprintf("INSERT INTO %s (order_id, status) VALUES ($1, $2)", changesTableName)
+
+	itemsQuery := fmt.Sprintf(`
+	INSERT INTO %s (order_id, book_id, title, quantity, list_price, unit_price, discount, tax_rate, tax, digital)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, itemsTableName)
+
+	// Redemptions of cancelled orders don't count towards usage limits.
+	limitsQuery := fmt.Sprintf(`
+	SELECT p.code, p.usage_limit, p.per_user_limit,
+		COUNT(r.id) FILTER (WHERE o.status <> 'cancelled'),
+		COUNT(r.id) FILTER (WHERE o.status <> 'cancelled' AND r.user_id = $2)
+	FROM (
+		SELECT id, code, usage_limit, per_user_limit
+		FROM %s
+		WHERE id = $1 AND starts_at <= now() AND (ends_at IS NULL OR ends_at > now())
+		FOR UPDATE
+	) p
+	LEFT JOIN %s r ON r.promo_code_id = p.id
+	LEFT JOIN orders o ON o.id = r.order_id
+	GROUP BY p.id, p.code, p.usage_limit, p.per_user_limit`, promoTableName, redemptionsTableName)
+
+	redeemQuery := fmt.Sprintf(`
+	INSERT INTO %s (promo_code_id, code, order_id, user_id, amount)
+	VALUES ($1, $2, $3, $4, $5)`, redemptionsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var country, region *string
+	if order.Destination != nil {
+		country = &order.Destination.Country
+		region = order.Destination.Region
+	}
+
+	var methodId *int64
+	var method, kind *string
+	shippingCost := decimal.Zero
+	if order.Shipping != nil {
+		methodId = order.Shipping.MethodId
+		method = &order.Shipping.Method
+		kind = &order.Shipping.Kind
+		shippingCost = order.Shipping.Cost
+	}
+
+	var id int64
+	err = tx.QueryRow(
+		ctx,
+		orderQuery,
+		order.UserId,
+		order.BasketId,
+		StatusPending,
+		order.Currency,
+		order.ExchangeRate,
+		country,
+		region,
+		order.ShippingAddress,
+		order.BillingAddress,
+		methodId,
+		method,
+		kind,
+		shippingCost,
+		order.PricesIncludeTax,
+		order.Discount,
+		order.Tax,
+		order.TotalPrice,
+	).Scan(&id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create order query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, changeQuery, id, StatusPending); err != nil {
+		err = fmt.Errorf("failed to record order status: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	for _, item := range order.Items {
+		_, err := tx.Exec(
+			ctx,
+			itemsQuery,
+			id,
+			item.BookId,
+			item.Title,
+			item.Quantity,
+			item.ListPrice,
+			item.UnitPrice,
+			item.Discount,
+			item.TaxRate,
+			item.Tax,
+			item.Digital,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to create order item: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	for _, dc := range order.Discounts {
+		var code string
+		var usageLimit, perUserLimit *int32
+		var total, byUser int64
+		err := tx.QueryRow(ctx, limitsQuery, *dc.PromoCodeId, order.UserId).
+			Scan(&code, &usageLimit, &perUserLimit, &total, &byUser)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("%w: %s", apperror.ErrPromoInactive, dc.Code)
+			}
+			err = fmt.Errorf("failed to check promo code limits: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+
+		if usageLimit != nil && total >= int64(*usageLimit) ||
+			perUserLimit != nil && byUser >= int64(*perUserLimit) {
+			return nil, fmt.Errorf("%w: %s", apperror.ErrPromoLimitReached, code)
+		}
+
+		if _, err := tx.Exec(ctx, redeemQuery, *dc.PromoCodeId, code, id, order.UserId, dc.Amount); err != nil {
+			err = fmt.Errorf("failed to redeem promo code: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	created, err := scanOrder(tx.QueryRow(ctx, selectQuery+" WHERE o.id = $1", id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find created order: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return created, nil
+}
+
+// ChangeStatus changes the status of the order and records the change.
+// The tracking number of the change becomes the tracking number of the
+// order. Returns ErrNoRows if order doesn't exist, ErrInvalidStatusTransition
+// or ErrTrackingNumberRequired if the order can't change to the status,
+// ErrOrderHasReturns if the order with returns is cancelled, an error on
+// failure or the changed order on success.
+func (d *db) ChangeStatus(id int64, change *StatusChange) (*Order, error) {
+	return d.changeStatus(id, change, (*Order).checkStatus)
+}
+
+// MarkPaid changes the status of the pending order to paid and records
+// the change with the note. Returns ErrNoRows if order doesn't exist,
+// ErrInvalidStatusTransition if it's not pending, an error on failure or
+// the paid order on success.
+func (d *db) MarkPaid(id int64, note *string) (*Order, error) {
+	return d.changeStatus(id, &StatusChange{Status: StatusPaid, Note: note}, (*Order).checkPaid)
+}
+
+// changeStatus changes the status of the order locked for update if the
+// check passes and records the change.
+func (d *db) changeStatus(id int64, change *StatusChange, check func(*Order, *StatusChange) error) (*Order, error) {
+	updateQuery := fmt.Sprintf(`
+	UPDATE %s SET status = $2, tracking_number = COALESCE($3, tracking_number)
+	WHERE id = $1`, tableName)
+
+	changeQuery := fmt.Sprintf(`
+	INSERT INTO %s (order_id, status, tracking_number, note)
+	VALUES ($1, $2, $3, $4)`, changesTableName)
+
+	returnsQuery := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s WHERE order_id = $1 AND status <> 'rejected')",
+		returnsTableName,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := scanOrder(tx.QueryRow(ctx, selectQuery+" WHERE o.id = $1 FOR UPDATE OF o", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find order by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := check(order, change); err != nil {
+		return nil, err
+	}
+
+	// Returned copies are restocked and refunded by their returns already,
+	// cancelling the order would do it once again. Returns lock the order
+	// when they are requested, so none can be requested meanwhile.
+	if change.Status == StatusCancelled {
+		var hasReturns bool
+		if err := tx.QueryRow(ctx, returnsQuery, id).Scan(&hasReturns); err != nil {
+			err = fmt.Errorf("failed to execute find order returns query: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		if hasReturns {
+			return nil, apperror.ErrOrderHasReturns
+		}
+	}
+
+	if _, err := tx.Exec(ctx, updateQuery, id, change.Status, change.TrackingNumber); err != nil {
+		err = fmt.Errorf("failed to execute change order status query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, changeQuery, id, change.Status, change.TrackingNumber, change.Note); err != nil {
+		err = fmt.Errorf("failed to record order status: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	changed, err := scanOrder(tx.QueryRow(ctx, selectQuery+" WHERE o.id = $1", id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find changed order: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return changed, nil
+}
+
+// RecordEvent records the event in the history of the order with the
+// current status of the order. Returns ErrNoRows if order doesn't exist
+// or an error on failure.
+func (d *db) RecordEvent(id int64, event string, note *string) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (order_id, event, status, note)
+	SELECT id, $2, status, $3 FROM %s WHERE id = $1`, changesTableName, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id, event, note)
+	if err != nil {
+		err = fmt.Errorf("failed to execute record order event query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindById finds the order with specified id.
+// Returns ErrNoRows if order doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Order, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	order, err := scanOrder(d.conn.QueryRow(ctx, selectQuery+" WHERE o.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find order by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// FindByUser finds orders of the user, newest first.
+// Returns an error on failure.
+func (d *db) FindByUser(userId int64, limit, offset int) ([]*Order, error) {
+	query := selectQuery + " WHERE o.user_id = $1 ORDER BY o.id DESC LIMIT $2 OFFSET $3"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, userId, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find orders query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]*Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan order: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read orders: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// scanOrder scans a row selected with selectQuery into an order.
+func scanOrder(row pgx.Row) (*Order, error) {
+	var order Order
+	var country, region *string
+	var methodId *int64
+	var method, kind, trackingNumber *string
+	var shippingCost decimal.Decimal
+	err := row.Scan(
+		&order.Id,
+		&order.UserId,
+		&order.BasketId,
+		&order.Status,
+		&order.Date,
+		&order.Currency,
+		&order.ExchangeRate,
+		&country,
+		&region,
+		&order.ShippingAddress,
+		&order.BillingAddress,
+		&methodId,
+		&method,
+		&kind,
+		&shippingCost,
+		&trackingNumber,
+		&order.PricesIncludeTax,
+		&order.Discount,
+		&order.Tax,
+		&order.TotalPrice,
+		&order.Discounts,
+		&order.Items,
+		&order.History,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if country != nil {
+		order.Destination = &tax.Destination{Country: *country, Region: region}
+	}
+
+	if method != nil {
+		order.Shipping = &Shipping{
+			MethodId:       methodId,
+			Method:         *method,
+			Kind:           *kind,
+			Cost:           shippingCost,
+			TrackingNumber: trackingNumber,
+		}
+	}
+
+	return &order, nil
+}
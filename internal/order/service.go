@@ -0,0 +1,113 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes order service functionality.
+type Service interface {
+	Create(ctx context.Context, order *Order) (*Order, error)
+	ChangeStatus(ctx context.Context, input *StatusDTO) (*Order, error)
+	MarkPaid(ctx context.Context, id int64, note string) (*Order, error)
+	RecordEvent(ctx context.Context, id int64, event, note string) error
+	GetById(ctx context.Context, id int64) (*Order, error)
+	GetByUser(ctx context.Context, userId int64, limit, offset int) ([]*Order, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *Order) (*Order, error) {
+	order, err := s.storage.Create(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrPromoInactive) && !errors.Is(err, apperror.ErrPromoLimitReached) {
+			s.logger.Errorf("failed to create order: %v", err)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *service) ChangeStatus(ctx context.Context, input *StatusDTO) (*Order, error) {
+	order, err := s.storage.ChangeStatus(input.Id, &StatusChange{
+		Status:         input.Status,
+		TrackingNumber: input.TrackingNumber,
+		Note:           input.Note,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) &&
+			!errors.Is(err, apperror.ErrInvalidStatusTransition) &&
+			!errors.Is(err, apperror.ErrTrackingNumberRequired) &&
+			!errors.Is(err, apperror.ErrOrderHasReturns) {
+			s.logger.Errorf("failed to change order status: %v", err)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// MarkPaid changes the status of the pending order to paid. It's used by
+// payments once the order total is captured. Returns ErrNoRows if the order
+// doesn't exist and ErrInvalidStatusTransition if it's not pending.
+func (s *service) MarkPaid(ctx context.Context, id int64, note string) (*Order, error) {
+	order, err := s.storage.MarkPaid(id, &note)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInvalidStatusTransition) {
+			s.logger.Errorf("failed to mark order paid: %v", err)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// RecordEvent records the event with the note in the history of the order.
+// Returns ErrNoRows if the order doesn't exist.
+func (s *service) RecordEvent(ctx context.Context, id int64, event, note string) error {
+	if err := s.storage.RecordEvent(id, event, &note); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to record order event: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Order, error) {
+	order, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find order by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *service) GetByUser(ctx context.Context, userId int64, limit, offset int) ([]*Order, error) {
+	orders, err := s.storage.FindByUser(userId, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find orders: %v", err)
+		return nil, err
+	}
+
+	return orders, nil
+}
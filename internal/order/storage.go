@@ -0,0 +1,11 @@
+package order
+
+// Storage describes an order storage functionality.
+type Storage interface {
+	Create(order *Order) (*Order, error)
+	ChangeStatus(id int64, change *StatusChange) (*Order, error)
+	MarkPaid(id int64, note *string) (*Order, error)
+	RecordEvent(id int64, event string, note *string) error
+	FindById(id int64) (*Order, error)
+	FindByUser(userId int64, limit, offset int) ([]*Order, error)
+}
@@ -0,0 +1,221 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/webhook"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FakeMethodSuccess is the payment method the fake provider captures at once.
+	FakeMethodSuccess = "fake_success"
+	// FakeMethodDecline is the payment method the fake provider declines.
+	FakeMethodDecline = "fake_decline"
+	// FakeMethodDelayed is the payment method the fake provider captures
+	// after the delay, the result is delivered by the webhook only.
+	FakeMethodDelayed = "fake_delayed"
+
+	// fakeName is the name payments of the fake provider are recorded with.
+	fakeName = "fake"
+)
+
+// Check whether fakeProvider implements payment provider interface.
+var _ Provider = &fakeProvider{}
+
+// fakeProvider is an in-process payment provider for development and
+// testing. The result of the payment depends on the payment method only,
+// ids of intents, events and refunds are sequential, so runs are
+// repeatable. Refunds are kept by their keys, so the refund sent again is
+// made once. Events are posted to the webhook URL signed with the secret
+// like webhook.Client does.
+type fakeProvider struct {
+	logger  logger.Logger
+	secret  string
+	webhook *webhook.Client
+	delay   time.Duration
+
+	mu      sync.Mutex
+	seq     int64
+	intents map[string]*fakeIntent
+	refunds map[string]string
+}
+
+// fakeIntent is the payment kept by the fake provider.
+type fakeIntent struct {
+	amount   decimal.Decimal
+	refunded decimal.Decimal
+	status   string
+}
+
+// fakeEvent is the data of the webhook event posted by the fake provider.
+type fakeEvent struct {
+	Id            string  `json:"id"`
+	IntentId      string  `json:"intentId"`
+	FailureReason *string `json:"failureReason,omitempty"`
+}
+
+// NewFakeProvider returns a new fake Provider. Events are posted to the
+// webhook URL, they are dropped if it's empty. Payments confirmed with
+// FakeMethodDelayed succeed after the delay.
+func NewFakeProvider(webhookURL, secret string, delay time.Duration, logger logger.Logger) Provider {
+	p := &fakeProvider{
+		logger:  logger,
+		secret:  secret,
+		delay:   delay,
+		intents: make(map[string]*fakeIntent),
+		refunds: make(map[string]string),
+	}
+	if webhookURL != "" {
+		p.webhook = webhook.NewClient(webhookURL, secret)
+	}
+	return p
+}
+
+func (p *fakeProvider) Name() string {
+	return fakeName
+}
+
+func (p *fakeProvider) CreateIntent(ctx context.Context, amount decimal.Decimal, currency, reference string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.nextId("pi")
+	p.intents[id] = &fakeIntent{amount: amount, status: StatusPending}
+
+	return &Intent{
+		Id:           id,
+		Status:       StatusPending,
+		ClientSecret: id + "_secret_" + p.sign(id)[:16],
+	}, nil
+}
+
+// Confirm captures the payment with FakeMethodSuccess, captures it after
+// the delay with FakeMethodDelayed and declines it with any other method.
+func (p *fakeProvider) Confirm(ctx context.Context, intentId, method string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentId]
+	if !ok {
+		return nil, fmt.Errorf("fake payment %s: %w", intentId, apperror.ErrNoRows)
+	}
+	if intent.status != StatusPending {
+		return nil, apperror.ErrPaymentNotConfirmable
+	}
+
+	result := Intent{Id: intentId}
+	switch method {
+	case FakeMethodSuccess:
+		intent.status = StatusSucceeded
+		p.post(EventSucceeded, intentId, nil)
+	case FakeMethodDelayed:
+		intent.status = StatusProcessing
+		time.AfterFunc(p.delay, func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			intent.status = StatusSucceeded
+			p.post(EventSucceeded, intentId, nil)
+		})
+	default:
+		reason := "card declined"
+		if method != FakeMethodDecline {
+			reason = fmt.Sprintf("unsupported payment method %q", method)
+		}
+		intent.status = StatusFailed
+		result.FailureReason = &reason
+		p.post(EventFailed, intentId, &reason)
+	}
+	result.Status = intent.status
+
+	return &result, nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, intentId string, amount decimal.Decimal, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.refunds[key]; ok {
+		return id, nil
+	}
+
+	intent, ok := p.intents[intentId]
+	if !ok {
+		return "", fmt.Errorf("fake payment %s: %w", intentId, apperror.ErrNoRows)
+	}
+	if intent.status != StatusSucceeded || intent.refunded.Add(amount).GreaterThan(intent.amount) {
+		return "", apperror.ErrRefundExceedsPayment
+	}
+	intent.refunded = intent.refunded.Add(amount)
+
+	id := p.nextId("re")
+	p.refunds[key] = id
+
+	return id, nil
+}
+
+func (p *fakeProvider) VerifyWebhook(header http.Header, body []byte) (*Event, error) {
+	if !webhook.Verify(p.secret, header, body) {
+		return nil, apperror.ErrInvalidSignature
+	}
+
+	var payload struct {
+		Event string    `json:"event"`
+		Data  fakeEvent `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode fake payment event: %v", err)
+	}
+
+	return &Event{
+		Id:            payload.Data.Id,
+		Type:          payload.Event,
+		IntentId:      payload.Data.IntentId,
+		FailureReason: payload.Data.FailureReason,
+	}, nil
+}
+
+// post sends the event of the intent to the webhook in the background,
+// like a real gateway does. Must be called with the lock held.
+func (p *fakeProvider) post(event, intentId string, reason *string) {
+	data := fakeEvent{
+		Id:            p.nextId("evt"),
+		IntentId:      intentId,
+		FailureReason: reason,
+	}
+
+	if p.webhook == nil {
+		p.logger.Warnf("fake payment webhook url is not set, dropped %s event %s", event, data.Id)
+		return
+	}
+
+	go func() {
+		if err := p.webhook.Post(context.Background(), event, data); err != nil {
+			p.logger.Errorf("failed to post fake payment event %s: %v", data.Id, err)
+		}
+	}()
+}
+
+// nextId returns the next sequential id with the prefix.
+// Must be called with the lock held.
+func (p *fakeProvider) nextId(prefix string) string {
+	p.seq++
+	return fmt.Sprintf("fake_%s_%06d", prefix, p.seq)
+}
+
+// sign returns the hex HMAC-SHA256 of the value with the secret.
+func (p *fakeProvider) sign(value string) string {
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,349 @@
+package payment
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	orderPaymentsURL = "/api/orders/:id/payments"
+	orderCancelURL   = "/api/orders/:id/cancel"
+	paymentURL       = "/api/payments/:id"
+	confirmURL       = "/api/payments/:id/confirm"
+	refundsURL       = "/api/payments/:id/refunds"
+	webhookURL       = "/api/webhooks/payments"
+
+	// maxWebhookSize limits the body of the webhook request.
+	maxWebhookSize = 64 << 10
+)
+
+// Handler handles requests specified to payment service.
+type Handler struct {
+	logger         logger.Logger
+	paymentService Service
+}
+
+// NewHandler returns a new payment Handler instance.
+func NewHandler(logger logger.Logger, paymentService Service) handler.Handling {
+	return &Handler{
+		logger:         logger,
+		paymentService: paymentService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, orderPaymentsURL, h.ListOrderPayments)
+	router.HandlerFunc(http.MethodPost, orderPaymentsURL, h.CreatePayment)
+	router.HandlerFunc(http.MethodGet, paymentURL, h.GetPayment)
+	router.HandlerFunc(http.MethodPost, confirmURL, h.ConfirmPayment)
+	router.HandlerFunc(http.MethodPost, refundsURL, h.RefundPayment)
+	router.HandlerFunc(http.MethodPost, orderCancelURL, h.CancelOrder)
+	router.HandlerFunc(http.MethodPost, webhookURL, h.HandleWebhook)
+}
+
+// ListOrderPayments godoc
+// @Summary List order payments
+// @Description Get payments of the order with their refunds, latest first.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 200 {array} Payment
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/payments [get]
+func (h *Handler) ListOrderPayments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST ORDER PAYMENTS")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	payments, err := h.paymentService.GetByOrder(r.Context(), orderId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, payments)
+}
+
+// CreatePayment godoc
+// @Summary Create payment
+// @Description Create the payment of the pending order total at the payment provider. The payment which is not confirmed or is still processing is returned instead of a new one.
+// @Description The client confirms the payment with the payment method of the customer.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 201 {object} Payment
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/payments [post]
+func (h *Handler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE PAYMENT")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	payment, err := h.paymentService.Create(r.Context(), orderId)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrOrderNotPayable):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, payment)
+}
+
+// GetPayment godoc
+// @Summary Show payment
+// @Description Get payment by id with its refunds.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Payment id"
+// @Success 200 {object} Payment
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /payments/{id} [get]
+func (h *Handler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET PAYMENT")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	payment, err := h.paymentService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, payment)
+}
+
+// ConfirmPayment godoc
+// @Summary Confirm payment
+// @Description Charge the payment method of the customer. The payment either succeeds or fails at once, or stays processing until the provider delivers the result by the webhook. The order is paid once the payment succeeds, the order which books are out of stock by then is cancelled and the payment is refunded.
+// @Description The fake provider captures "fake_success" at once, "fake_delayed" after the configured delay and declines "fake_decline" and any other method.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Payment id"
+// @Param input body ConfirmDTO true "JSON input"
+// @Success 200 {object} Payment
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /payments/{id}/confirm [post]
+func (h *Handler) ConfirmPayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CONFIRM PAYMENT")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ConfirmDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	payment, err := h.paymentService.Confirm(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrPaymentNotConfirmable):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, payment)
+}
+
+// RefundPayment godoc
+// @Summary Refund payment
+// @Description Return the amount of the captured payment to the customer through the payment provider. Payments can be refunded partially several times up to the paid amount. The refund stays pending until the provider makes it, sending the same amount again retries the pending refund instead of refunding twice. Admin only.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Payment id"
+// @Param input body RefundDTO true "JSON input"
+// @Success 200 {object} Payment
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /payments/{id}/refunds [post]
+func (h *Handler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REFUND PAYMENT")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input RefundDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.PaymentId = id
+
+	payment, err := h.paymentService.Refund(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrRefundExceedsPayment):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, payment)
+}
+
+// CancelOrder godoc
+// @Summary Cancel order
+// @Description Cancel the pending or paid order. Books reserved for the pending order are released. Books sold for the paid order are returned to stock and its captured payments are refunded in full. Orders with returns which are not rejected cannot be cancelled. Every change is recorded in the order history.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Param input body CancelDTO true "JSON input"
+// @Success 200 {object} order.Order
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/cancel [post]
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CANCEL ORDER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input CancelDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.OrderId = id
+
+	order, err := h.paymentService.Cancel(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidStatusTransition),
+			errors.Is(err, apperror.ErrOrderHasReturns):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// HandleWebhook godoc
+// @Summary Payment provider webhook
+// @Description Receive the event of the payment provider. The request must be signed by the provider. Events are applied once, redelivered ones are acknowledged without changes. The order is paid once its payment succeeds.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 401 {object} apperror.AppError
+// @Failure 413 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /webhooks/payments [post]
+func (h *Handler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLE PAYMENT WEBHOOK")
+
+	handler.LimitBody(w, r, maxWebhookSize)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		if errors.Is(err, handler.ErrBodyTooLarge) {
+			response.TooLarge(w, "webhook request is too large", "")
+			return
+		}
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(r.Context(), r.Header, body); err != nil {
+		if errors.Is(err, apperror.ErrInvalidSignature) {
+			response.Error(w, http.StatusUnauthorized, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,129 @@
+package payment
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+// Payment represents the payment of the order through the provider.
+// Amount is the total of the order in its currency. Refunded is the sum
+// of succeeded refunds of the captured payment. SettledAt is set once the order of
+// the captured payment is paid and its books are sold.
+type Payment struct {
+	Id            int64           `json:"id" example:"1"`
+	OrderId       int64           `json:"orderId" example:"1001"`
+	Provider      string          `json:"provider" example:"fake"`
+	IntentId      string          `json:"intentId" example:"fake_pi_000001"`
+	ClientSecret  *string         `json:"clientSecret,omitempty" example:"fake_pi_000001_secret_5d41402abc4b2a76"`
+	Amount        decimal.Decimal `json:"amount" swaggertype:"string" example:"18.38"`
+	Currency      string          `json:"currency" example:"EUR"`
+	Status        string          `json:"status" example:"succeeded"`
+	FailureReason *string         `json:"failureReason,omitempty" example:"card declined"`
+	Refunded      decimal.Decimal `json:"refunded" swaggertype:"string" example:"0"`
+	Refunds       []Refund        `json:"refunds"`
+	CreatedAt     time.Time       `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+	UpdatedAt     time.Time       `json:"updatedAt" example:"2022-03-01T12:01:00Z"`
+	SettledAt     *time.Time      `json:"settledAt,omitempty" example:"2022-03-01T12:01:00Z"`
+} // @name Payment
+
+// Refund represents the amount of the payment returned to the customer.
+// The refund is pending until the provider makes it, RefundId identifies
+// the refund at the provider then. Reference identifies refunds which are
+// retried, e.g. "return 5".
+type Refund struct {
+	Id        int64           `json:"id" example:"1"`
+	PaymentId int64           `json:"paymentId" example:"1"`
+	RefundId  *string         `json:"refundId,omitempty" example:"fake_re_000004"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"11.49"`
+	Reason    *string         `json:"reason,omitempty" example:"damaged book"`
+	Reference *string         `json:"reference,omitempty" example:"return 5"`
+	Status    string          `json:"status" example:"succeeded"`
+	CreatedAt time.Time       `json:"createdAt" example:"2022-03-05T10:00:00Z"`
+} // @name PaymentRefund
+
+// Refundable returns the amount of the captured payment which is neither
+// refunded nor pending to be refunded.
+func (p *Payment) Refundable() decimal.Decimal {
+	if p.Status != StatusSucceeded {
+		return decimal.Zero
+	}
+
+	amount := p.Amount.Sub(p.Refunded)
+	for _, r := range p.Refunds {
+		if r.Status == RefundPending {
+			amount = amount.Sub(r.Amount)
+		}
+	}
+	return amount
+}
+
+// FindRefund returns the refund of the payment with the reference
+// or nil if there is none.
+func (p *Payment) FindRefund(reference string) *Refund {
+	for i, r := range p.Refunds {
+		if r.Reference != nil && *r.Reference == reference {
+			return &p.Refunds[i]
+		}
+	}
+	return nil
+}
+
+// key returns the idempotency key of the refund at the provider.
+func (r *Refund) key() string {
+	return fmt.Sprintf("refund-%d", r.Id)
+}
+
+// ConfirmDTO is used to confirm the payment with the payment method
+// of the customer, e.g. "fake_success" for the fake provider.
+type ConfirmDTO struct {
+	Id     int64  `json:"-"`
+	Method string `json:"method" example:"fake_success"`
+} // @name PaymentConfirmInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c *ConfirmDTO) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Method, validation.Required, validation.Length(1, 255), validator.Text),
+	)
+}
+
+// RefundDTO is used to refund the amount of the captured payment.
+// Reference is set by refunds which are retried, the refund with the
+// same reference is resumed instead of a new one.
+type RefundDTO struct {
+	PaymentId int64           `json:"-"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"11.49"`
+	Reason    *string         `json:"reason,omitempty" example:"damaged book"`
+	Reference *string         `json:"-"`
+} // @name PaymentRefundInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *RefundDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Amount, validator.MinDecimal(decimal.New(1, -2))),
+		validation.Field(&r.Reason, validation.RuneLength(1, 500)),
+	)
+}
+
+// CancelDTO is used to cancel the order.
+type CancelDTO struct {
+	OrderId int64   `json:"-"`
+	Reason  *string `json:"reason,omitempty" example:"ordered by mistake"`
+} // @name OrderCancelInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (c *CancelDTO) Validate() error {
+	return validation.ValidateStruct(
+		c,
+		validation.Field(&c.Reason, validation.RuneLength(1, 500)),
+	)
+}
@@ -0,0 +1,491 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	tableName        = "payments"
+	refundsTableName = "payment_refunds"
+	eventsTableName  = "payment_events"
+
+	// selectQuery selects payments with their refunds aggregated to a JSON array.
+	selectQuery = `
+	SELECT p.id, p.order_id, p.provider, p.intent_id, p.client_secret, p.amount, p.currency,
+		p.status, p.failure_reason, p.refunded, p.created_at, p.updated_at, p.settled_at,
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'id', r.id, 'paymentId', r.payment_id, 'refundId', r.refund_id,
+				'amount', r.amount, 'reason', r.reason, 'reference', r.reference,
+				'status', r.status, 'createdAt', r.created_at
+			) ORDER BY r.id)
+			FROM payment_refunds r
+			WHERE r.payment_id = p.id
+		), '[]')
+	FROM payments p`
+)
+
+// Check whether db implements payment storage interface.
+var _ Storage = &db{}
+
+// db implements payment storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new payment storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the payment. Returns ErrNoRows if the order doesn't exist,
+// an error on failure or the inserted payment on success.
+func (d *db) Create(payment *Payment) (*Payment, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (order_id, provider, intent_id, client_secret, amount, currency, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, created_at, updated_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(
+		ctx,
+		query,
+		payment.OrderId,
+		payment.Provider,
+		payment.IntentId,
+		payment.ClientSecret,
+		payment.Amount,
+		payment.Currency,
+		payment.Status,
+	).Scan(&payment.Id, &payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create payment query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	payment.Refunds = make([]Refund, 0)
+
+	return payment, nil
+}
+
+// FindById finds the payment with specified id with its refunds.
+// Returns ErrNoRows if the payment doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Payment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	payment, err := scanPayment(d.conn.QueryRow(ctx, selectQuery+" WHERE p.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find payment by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// FindByOrder finds payments of the order, the latest first.
+// Returns an error on failure.
+func (d *db) FindByOrder(orderId int64) ([]*Payment, error) {
+	query := selectQuery + " WHERE p.order_id = $1 ORDER BY p.id DESC"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, orderId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find order payments query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]*Payment, 0)
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan payment: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read payments: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// UpdateStatus sets the status of the payment unless its status is final
+// already. Returns ErrNoRows if the payment doesn't exist, an error on
+// failure or the payment on success.
+func (d *db) UpdateStatus(id int64, status string, failureReason *string) (*Payment, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET status = $2, failure_reason = $3, updated_at = now()
+	WHERE id = $1 AND status IN ('pending', 'processing')`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, id, status, failureReason); err != nil {
+		err = fmt.Errorf("failed to execute update payment status query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return d.FindById(id)
+}
+
+// ApplyEvent records the webhook event of the provider and sets the
+// status of its payment unless its status is final already. Events
+// which have been recorded are not applied again. Returns ErrNoRows if
+// the payment of the event doesn't exist, an error on failure or the
+// payment and whether the event has been applied on success.
+func (d *db) ApplyEvent(provider string, event *Event, status string) (*Payment, bool, error) {
+	findQuery := fmt.Sprintf(
+		"SELECT id FROM %s WHERE provider = $1 AND intent_id = $2 FOR UPDATE",
+		tableName,
+	)
+
+	eventQuery := fmt.Sprintf(`
+	INSERT INTO %s (provider, event_id, type, payment_id)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT DO NOTHING`, eventsTableName)
+
+	updateQuery := fmt.Sprintf(`
+	UPDATE %s SET status = $2, failure_reason = $3, updated_at = now()
+	WHERE id = $1 AND status IN ('pending', 'processing')`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var paymentId int64
+	err = tx.QueryRow(ctx, findQuery, provider, event.IntentId).Scan(&paymentId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find payment by intent query: %v", err)
+		d.logger.Error(err)
+		return nil, false, err
+	}
+
+	result, err := tx.Exec(ctx, eventQuery, provider, event.Id, event.Type, paymentId)
+	if err != nil {
+		err = fmt.Errorf("failed to record payment event: %v", err)
+		d.logger.Error(err)
+		return nil, false, err
+	}
+
+	applied := result.RowsAffected() > 0
+	if applied {
+		if _, err := tx.Exec(ctx, updateQuery, paymentId, status, event.FailureReason); err != nil {
+			err = fmt.Errorf("failed to execute update payment status query: %v", err)
+			d.logger.Error(err)
+			return nil, false, err
+		}
+	}
+
+	payment, err := scanPayment(tx.QueryRow(ctx, selectQuery+" WHERE p.id = $1", paymentId))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find payment: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return payment, applied, nil
+}
+
+// SetSettled marks the payment settled unless it's marked already.
+// Returns ErrNoRows if the payment doesn't exist or an error on failure.
+func (d *db) SetSettled(id int64) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET settled_at = COALESCE(settled_at, now()) WHERE id = $1",
+		tableName,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute set payment settled query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// StartRefund records the pending refund of the captured payment. The
+// payment is locked while the refunded and pending amounts are checked,
+// so concurrent refunds can't exceed it. The refund with the same
+// reference or, without the reference, the pending refund of the same
+// amount is returned instead of a new one, so the refund which failed is
+// resumed. Returns ErrNoRows if the payment doesn't exist,
+// ErrRefundExceedsPayment if it's not captured or the refund exceeds the
+// amount which is neither refunded nor pending, an error on failure or
+// the refund on success.
+func (d *db) StartRefund(refund *Refund) (*Refund, error) {
+	lockQuery := fmt.Sprintf(
+		"SELECT status, amount, refunded FROM %s WHERE id = $1 FOR UPDATE",
+		tableName,
+	)
+
+	findQuery := fmt.Sprintf(`
+	SELECT id, payment_id, refund_id, amount, reason, reference, status, created_at
+	FROM %s
+	WHERE payment_id = $1 AND (
+		reference = $2::text OR
+		$2::text IS NULL AND reference IS NULL AND status = 'pending' AND amount = $3
+	)
+	ORDER BY id
+	LIMIT 1`, refundsTableName)
+
+	pendingQuery := fmt.Sprintf(
+		"SELECT COALESCE(sum(amount), 0) FROM %s WHERE payment_id = $1 AND status = 'pending'",
+		refundsTableName,
+	)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (payment_id, amount, reason, reference, status)
+	VALUES ($1, $2, $3, $4, 'pending')
+	RETURNING id, status, created_at`, refundsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		status           string
+		amount, refunded decimal.Decimal
+	)
+	err = tx.QueryRow(ctx, lockQuery, refund.PaymentId).Scan(&status, &amount, &refunded)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute lock payment query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	existing, err := scanRefund(tx.QueryRow(ctx, findQuery, refund.PaymentId, refund.Reference, refund.Amount))
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		err = fmt.Errorf("failed to execute find refund query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	var pending decimal.Decimal
+	if err := tx.QueryRow(ctx, pendingQuery, refund.PaymentId).Scan(&pending); err != nil {
+		err = fmt.Errorf("failed to execute find pending refunds query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if status != StatusSucceeded || refunded.Add(pending).Add(refund.Amount).GreaterThan(amount) {
+		return nil, apperror.ErrRefundExceedsPayment
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		insertQuery,
+		refund.PaymentId,
+		refund.Amount,
+		refund.Reason,
+		refund.Reference,
+	).Scan(&refund.Id, &refund.Status, &refund.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create refund query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return refund, nil
+}
+
+// CompleteRefund marks the pending refund succeeded with the id of the
+// refund at the provider and adds it to the refunded amount of the payment.
+// The payment is locked like StartRefund does. Returns ErrNoRows if the
+// refund doesn't exist, an error on failure or the payment and whether the
+// refund has been completed on success.
+func (d *db) CompleteRefund(id int64, refundId string) (*Payment, bool, error) {
+	lockQuery := fmt.Sprintf(`
+	SELECT p.id FROM %s p
+	JOIN %s r ON r.payment_id = p.id
+	WHERE r.id = $1
+	FOR UPDATE OF p`, tableName, refundsTableName)
+
+	completeQuery := fmt.Sprintf(`
+	UPDATE %s SET status = 'succeeded', refund_id = $2
+	WHERE id = $1 AND status = 'pending'
+	RETURNING amount`, refundsTableName)
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE %s SET refunded = refunded + $2, updated_at = now() WHERE id = $1",
+		tableName,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var paymentId int64
+	if err := tx.QueryRow(ctx, lockQuery, id).Scan(&paymentId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute lock refunded payment query: %v", err)
+		d.logger.Error(err)
+		return nil, false, err
+	}
+
+	var amount decimal.Decimal
+	completed := true
+	if err := tx.QueryRow(ctx, completeQuery, id, refundId).Scan(&amount); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			err = fmt.Errorf("failed to execute complete refund query: %v", err)
+			d.logger.Error(err)
+			return nil, false, err
+		}
+		completed = false
+	}
+
+	if completed {
+		if _, err := tx.Exec(ctx, updateQuery, paymentId, amount); err != nil {
+			err = fmt.Errorf("failed to execute update refunded amount query: %v", err)
+			d.logger.Error(err)
+			return nil, false, err
+		}
+	}
+
+	payment, err := scanPayment(tx.QueryRow(ctx, selectQuery+" WHERE p.id = $1", paymentId))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find refunded payment: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return payment, completed, nil
+}
+
+// CancelRefund deletes the pending refund the provider has refused.
+// Succeeded refunds are kept. Returns an error on failure.
+func (d *db) CancelRefund(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND status = 'pending'", refundsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, id); err != nil {
+		err = fmt.Errorf("failed to execute cancel refund query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// scanPayment scans the row of selectQuery.
+func scanPayment(row pgx.Row) (*Payment, error) {
+	var p Payment
+	err := row.Scan(
+		&p.Id,
+		&p.OrderId,
+		&p.Provider,
+		&p.IntentId,
+		&p.ClientSecret,
+		&p.Amount,
+		&p.Currency,
+		&p.Status,
+		&p.FailureReason,
+		&p.Refunded,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&p.SettledAt,
+		&p.Refunds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// scanRefund scans the row of the refund.
+func scanRefund(row pgx.Row) (*Refund, error) {
+	var r Refund
+	err := row.Scan(
+		&r.Id,
+		&r.PaymentId,
+		&r.RefundId,
+		&r.Amount,
+		&r.Reason,
+		&r.Reference,
+		&r.Status,
+		&r.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,70 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// StatusPending is a status of the payment waiting for confirmation.
+	StatusPending = "pending"
+	// StatusProcessing is a status of the confirmed payment
+	// which result is delivered later by the webhook.
+	StatusProcessing = "processing"
+	// StatusSucceeded is a status of the captured payment.
+	StatusSucceeded = "succeeded"
+	// StatusFailed is a status of the declined payment.
+	StatusFailed = "failed"
+
+	// RefundPending is a status of the refund which is not made by the
+	// provider yet.
+	RefundPending = "pending"
+	// RefundSucceeded is a status of the refund made by the provider.
+	RefundSucceeded = "succeeded"
+
+	// EventSucceeded is sent by the provider when the payment is captured.
+	EventSucceeded = "payment.succeeded"
+	// EventFailed is sent by the provider when the payment is declined.
+	EventFailed = "payment.failed"
+)
+
+// Provider describes a payment gateway functionality. Amounts are in
+// the major units of the currency, e.g. 18.38 EUR.
+type Provider interface {
+	// Name returns the name payments of the provider are recorded with.
+	Name() string
+	// CreateIntent creates a payment of the amount, which is confirmed
+	// later with the payment method chosen by the customer.
+	CreateIntent(ctx context.Context, amount decimal.Decimal, currency, reference string) (*Intent, error)
+	// Confirm charges the payment method of the customer for the intent.
+	// The result is either final or delivered later by the webhook.
+	Confirm(ctx context.Context, intentId, method string) (*Intent, error)
+	// Refund returns the amount of the captured payment to the customer.
+	// The key identifies the refund, the refund which has been made with
+	// the key already is returned instead of a new one. Returns the id of
+	// the refund at the provider.
+	Refund(ctx context.Context, intentId string, amount decimal.Decimal, key string) (string, error)
+	// VerifyWebhook checks the signature of the webhook request and
+	// returns its event. Returns ErrInvalidSignature if it doesn't match.
+	VerifyWebhook(header http.Header, body []byte) (*Event, error)
+}
+
+// Intent is the payment at the provider. ClientSecret lets the client
+// confirm the payment, FailureReason is set if the payment is declined.
+type Intent struct {
+	Id            string
+	Status        string
+	ClientSecret  string
+	FailureReason *string
+}
+
+// Event is the webhook event of the provider. Id is unique for the
+// provider and is the same if the event is delivered again.
+type Event struct {
+	Id            string
+	Type          string
+	IntentId      string
+	FailureReason *string
+}
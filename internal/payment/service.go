@@ -0,0 +1,542 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/ebook"
+	"github.com/juicyluv/ReadyRead/internal/inventory"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	// OrderEventRefunded is the event of the order history recorded
+	// when the payment of the order is refunded.
+	OrderEventRefunded = "payment_refunded"
+
+	// Reasons and actor of movements recorded for paid and cancelled orders.
+	soldReason      = "sold"
+	cancelledReason = "order cancelled"
+	customerActor   = "customer"
+
+	// deliveredNote is the note of orders delivered once paid.
+	deliveredNote = "ebooks added to the library"
+	// outOfStockNote is the note of paid orders cancelled because their
+	// books are out of stock.
+	outOfStockNote = "order cancelled: books are out of stock"
+)
+
+// Service describes payment service functionality.
+type Service interface {
+	Create(ctx context.Context, orderId int64) (*Payment, error)
+	Confirm(ctx context.Context, input *ConfirmDTO) (*Payment, error)
+	Refund(ctx context.Context, input *RefundDTO) (*Payment, error)
+	Cancel(ctx context.Context, input *CancelDTO) (*order.Order, error)
+	GetById(ctx context.Context, id int64) (*Payment, error)
+	GetByOrder(ctx context.Context, orderId int64) ([]*Payment, error)
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) error
+}
+
+type service struct {
+	logger           logger.Logger
+	storage          Storage
+	provider         Provider
+	orderService     order.Service
+	inventoryService inventory.Service
+	ebookService     ebook.Service
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(
+	storage Storage,
+	provider Provider,
+	orderService order.Service,
+	inventoryService inventory.Service,
+	ebookService ebook.Service,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:           logger,
+		storage:          storage,
+		provider:         provider,
+		orderService:     orderService,
+		inventoryService: inventoryService,
+		ebookService:     ebookService,
+	}
+}
+
+// Create creates the payment of the order total at the provider. The
+// payment which is not confirmed or is still processing is returned
+// instead of a new one. Returns ErrNoRows if the order doesn't exist and
+// ErrOrderNotPayable if it's not pending.
+func (s *service) Create(ctx context.Context, orderId int64) (*Payment, error) {
+	o, err := s.orderService.GetById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status != order.StatusPending {
+		return nil, apperror.ErrOrderNotPayable
+	}
+
+	payments, err := s.GetByOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if p.Status == StatusPending || p.Status == StatusProcessing {
+			return p, nil
+		}
+	}
+
+	intent, err := s.provider.CreateIntent(ctx, o.TotalPrice, o.Currency, fmt.Sprintf("order %d", o.Id))
+	if err != nil {
+		s.logger.Errorf("failed to create payment intent: %v", err)
+		return nil, err
+	}
+
+	payment, err := s.storage.Create(&Payment{
+		OrderId:      o.Id,
+		Provider:     s.provider.Name(),
+		IntentId:     intent.Id,
+		ClientSecret: &intent.ClientSecret,
+		Amount:       o.TotalPrice,
+		Currency:     o.Currency,
+		Status:       intent.Status,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to create payment: %v", err)
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Confirm charges the payment method of the customer. The order is paid
+// once the payment succeeds, either at once or when the webhook delivers
+// the result. Confirming the captured payment which order is not settled
+// retries settling it. Returns ErrNoRows if the payment doesn't exist and
+// ErrPaymentNotConfirmable if it has been confirmed already.
+func (s *service) Confirm(ctx context.Context, input *ConfirmDTO) (*Payment, error) {
+	payment, err := s.GetById(ctx, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status == StatusSucceeded && payment.SettledAt == nil {
+		return s.settleConfirmed(ctx, payment)
+	}
+
+	if payment.Status != StatusPending {
+		return nil, apperror.ErrPaymentNotConfirmable
+	}
+
+	intent, err := s.provider.Confirm(ctx, payment.IntentId, input.Method)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrPaymentNotConfirmable) {
+			s.logger.Errorf("failed to confirm payment: %v", err)
+		}
+		return nil, err
+	}
+
+	payment, err = s.storage.UpdateStatus(payment.Id, intent.Status, intent.FailureReason)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to update payment status: %v", err)
+		}
+		return nil, err
+	}
+
+	if payment.Status == StatusSucceeded {
+		return s.settleConfirmed(ctx, payment)
+	}
+
+	return payment, nil
+}
+
+// settleConfirmed settles the order of the confirmed payment and returns
+// the settled payment.
+func (s *service) settleConfirmed(ctx context.Context, payment *Payment) (*Payment, error) {
+	if err := s.settle(ctx, payment); err != nil {
+		s.logger.Errorf("failed to settle payment %d: %v", payment.Id, err)
+		return nil, err
+	}
+
+	return s.GetById(ctx, payment.Id)
+}
+
+// Refund returns the amount of the captured payment to the customer
+// through the provider. The refund is recorded as pending first and is
+// sent to the provider with its idempotency key, so the refund which
+// failed on the way is resumed when it's retried instead of refunding
+// twice: the refund with the same reference or, without the reference,
+// the pending refund of the same amount. The refund is recorded in the
+// order history. Returns ErrNoRows if the payment doesn't exist and
+// ErrRefundExceedsPayment if it's not captured or the amount exceeds the
+// amount which is neither refunded nor pending.
+func (s *service) Refund(ctx context.Context, input *RefundDTO) (*Payment, error) {
+	refund, err := s.storage.StartRefund(&Refund{
+		PaymentId: input.PaymentId,
+		Amount:    input.Amount,
+		Reason:    input.Reason,
+		Reference: input.Reference,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrRefundExceedsPayment) {
+			s.logger.Errorf("failed to start refund of payment %d: %v", input.PaymentId, err)
+		}
+		return nil, err
+	}
+
+	payment, err := s.GetById(ctx, refund.PaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.Status == RefundSucceeded {
+		return payment, nil
+	}
+
+	refundId, err := s.provider.Refund(ctx, payment.IntentId, refund.Amount, refund.key())
+	if err != nil {
+		if !errors.Is(err, apperror.ErrRefundExceedsPayment) {
+			s.logger.Errorf("failed to refund payment: %v", err)
+			return nil, err
+		}
+		if err := s.storage.CancelRefund(refund.Id); err != nil {
+			s.logger.Warnf("cannot cancel refund %d refused by the provider: %v", refund.Id, err)
+		}
+		return nil, err
+	}
+
+	payment, completed, err := s.storage.CompleteRefund(refund.Id, refundId)
+	if err != nil {
+		s.logger.Errorf("failed to record refund %s of payment %d: %v", refundId, refund.PaymentId, err)
+		return nil, err
+	}
+
+	if !completed {
+		return payment, nil
+	}
+
+	note := fmt.Sprintf("refunded %s %s with %s refund %s", refund.Amount, payment.Currency, payment.Provider, refundId)
+	if refund.Reason != nil {
+		note += ": " + *refund.Reason
+	}
+	if err := s.orderService.RecordEvent(ctx, payment.OrderId, OrderEventRefunded, note); err != nil {
+		s.logger.Warnf("cannot record refund %s in order history: %v", refundId, err)
+	}
+
+	return payment, nil
+}
+
+// Cancel cancels the pending or paid order. Reservations of the pending
+// order are released. Books sold for the paid order are returned to stock
+// and its captured payments are refunded in full, ebooks of the order leave
+// the library of the customer. Cancelling the cancelled order again retries
+// the steps which failed. Returns ErrNoRows if the order doesn't exist,
+// ErrInvalidStatusTransition if it can't be cancelled and ErrOrderHasReturns
+// if it has returns which are not rejected.
+func (s *service) Cancel(ctx context.Context, input *CancelDTO) (*order.Order, error) {
+	note := cancelledReason
+	if input.Reason != nil {
+		note += ": " + *input.Reason
+	}
+
+	o, err := s.orderService.GetById(ctx, input.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status != order.StatusCancelled {
+		o, err = s.orderService.ChangeStatus(ctx, &order.StatusDTO{
+			Id:     input.OrderId,
+			Status: order.StatusCancelled,
+			Note:   &note,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !wasPaid(o) {
+		if err := s.release(ctx, o); err != nil {
+			return nil, err
+		}
+		return o, nil
+	}
+
+	reference := fmt.Sprintf("order %d", o.Id)
+	recorded, err := s.inventoryService.GetByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	movements := make([]*inventory.Movement, 0, len(recorded))
+	for _, m := range recorded {
+		if m.Type != inventory.MovementSale {
+			continue
+		}
+		movements = append(movements, &inventory.Movement{
+			BookId:   m.BookId,
+			Type:     inventory.MovementReturn,
+			Quantity: -m.Quantity,
+			Reason:   cancelledReason,
+			Actor:    customerActor,
+		})
+	}
+
+	if len(movements) > 0 {
+		if _, err := s.inventoryService.RecordOnce(ctx, reference, movements...); err != nil {
+			return nil, err
+		}
+	}
+
+	payments, err := s.GetByOrder(ctx, o.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refundAll(ctx, payments, reference, note); err != nil {
+		return nil, err
+	}
+
+	return s.orderService.GetById(ctx, o.Id)
+}
+
+// release releases reservations of printed books of the order.
+func (s *service) release(ctx context.Context, o *order.Order) error {
+	for _, item := range o.Items {
+		if item.BookId == nil || item.Digital {
+			continue
+		}
+		if err := s.inventoryService.Release(ctx, o.BasketId, *item.BookId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundAll refunds the amount of captured payments which is neither
+// refunded nor pending. Refunds are made with the reference, so the
+// refund which failed is resumed when it's retried.
+func (s *service) refundAll(ctx context.Context, payments []*Payment, reference, reason string) error {
+	for _, p := range payments {
+		amount := p.Refundable()
+		if r := p.FindRefund(reference); r != nil {
+			if r.Status == RefundSucceeded {
+				continue
+			}
+			amount = r.Amount
+		}
+		if !amount.IsPositive() {
+			continue
+		}
+
+		if _, err := s.Refund(ctx, &RefundDTO{
+			PaymentId: p.Id,
+			Amount:    amount,
+			Reason:    &reason,
+			Reference: &reference,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wasPaid reports whether the order has been paid according to its history.
+func wasPaid(o *order.Order) bool {
+	for _, change := range o.History {
+		if change.Event == order.EventStatusChanged && change.Status == order.StatusPaid {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Payment, error) {
+	payment, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find payment by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (s *service) GetByOrder(ctx context.Context, orderId int64) ([]*Payment, error) {
+	payments, err := s.storage.FindByOrder(orderId)
+	if err != nil {
+		s.logger.Warnf("cannot find order payments: %v", err)
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// HandleWebhook verifies the webhook request of the provider and applies
+// its event to the payment. Redelivered events are not applied again, but
+// the order of the captured payment is settled anyway, so the request can
+// be retried if it failed. Events of unknown payments and types are
+// ignored. Returns ErrInvalidSignature if the signature doesn't match.
+func (s *service) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	event, err := s.provider.VerifyWebhook(header, body)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrInvalidSignature) {
+			s.logger.Warnf("cannot read payment webhook: %v", err)
+		}
+		return err
+	}
+
+	var status string
+	switch event.Type {
+	case EventSucceeded:
+		status = StatusSucceeded
+	case EventFailed:
+		status = StatusFailed
+	default:
+		s.logger.Infof("ignored payment event %s of type %s", event.Id, event.Type)
+		return nil
+	}
+
+	payment, applied, err := s.storage.ApplyEvent(s.provider.Name(), event, status)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("ignored payment event %s of unknown intent %s", event.Id, event.IntentId)
+			return nil
+		}
+		s.logger.Errorf("failed to apply payment event: %v", err)
+		return err
+	}
+
+	if !applied {
+		s.logger.Infof("payment event %s has been applied already", event.Id)
+	}
+
+	if payment.Status == StatusSucceeded {
+		return s.settle(ctx, payment)
+	}
+
+	return nil
+}
+
+// settle settles the order of the captured payment: the order is marked
+// paid and reservations of the basket are turned into sales of printed
+// books in the stock ledger at once. Ebooks are added to the library of the
+// customer, the order without shipping is delivered right away. The payment
+// captured for the cancelled order is refunded instead, the order which
+// books are out of stock is cancelled and refunded. Every step can be
+// repeated, so settling is retried until the payment is marked settled.
+func (s *service) settle(ctx context.Context, payment *Payment) error {
+	if payment.SettledAt != nil {
+		return nil
+	}
+
+	note := fmt.Sprintf("paid with %s payment %s", payment.Provider, payment.IntentId)
+	o, err := s.orderService.MarkPaid(ctx, payment.OrderId, note)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrInvalidStatusTransition) {
+			return err
+		}
+		if o, err = s.orderService.GetById(ctx, payment.OrderId); err != nil {
+			return err
+		}
+	}
+
+	reference := fmt.Sprintf("order %d", o.Id)
+	if o.Status == order.StatusCancelled {
+		s.logger.Warnf("payment %d is captured for cancelled order %d, refunding it", payment.Id, o.Id)
+		if err := s.refundAll(ctx, []*Payment{payment}, reference, cancelledReason); err != nil {
+			return err
+		}
+		return s.setSettled(payment)
+	}
+
+	movements := make([]*inventory.Movement, 0, len(o.Items))
+	for _, item := range o.Items {
+		if item.BookId == nil || item.Digital {
+			continue
+		}
+		movements = append(movements, &inventory.Movement{
+			BookId:   *item.BookId,
+			Type:     inventory.MovementSale,
+			Quantity: -item.Quantity,
+			Reason:   soldReason,
+			Actor:    customerActor,
+		})
+	}
+
+	if len(movements) > 0 {
+		_, err := s.inventoryService.Sell(ctx, reference, o.BasketId, movements...)
+		if err != nil {
+			if !errors.Is(err, apperror.ErrInsufficientStock) {
+				return err
+			}
+			return s.cancelOutOfStock(ctx, payment, o)
+		}
+	}
+
+	if _, err := s.ebookService.Grant(ctx, o.Id); err != nil {
+		return err
+	}
+
+	if o.Status == order.StatusPaid && o.Shipping == nil {
+		note := deliveredNote
+		_, err := s.orderService.ChangeStatus(ctx, &order.StatusDTO{
+			Id:     o.Id,
+			Status: order.StatusDelivered,
+			Note:   &note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.setSettled(payment)
+}
+
+// cancelOutOfStock cancels the paid order which books are out of stock,
+// releases its reservations and refunds the payment, so the payment is
+// settled instead of failing to sell the books on every retry.
+func (s *service) cancelOutOfStock(ctx context.Context, payment *Payment, o *order.Order) error {
+	s.logger.Warnf("books of order %d are out of stock, cancelling it and refunding payment %d", o.Id, payment.Id)
+
+	note := outOfStockNote
+	_, err := s.orderService.ChangeStatus(ctx, &order.StatusDTO{
+		Id:     o.Id,
+		Status: order.StatusCancelled,
+		Note:   &note,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.release(ctx, o); err != nil {
+		return err
+	}
+
+	if err := s.refundAll(ctx, []*Payment{payment}, fmt.Sprintf("order %d", o.Id), outOfStockNote); err != nil {
+		return err
+	}
+
+	return s.setSettled(payment)
+}
+
+// setSettled marks the payment settled.
+func (s *service) setSettled(payment *Payment) error {
+	if err := s.storage.SetSettled(payment.Id); err != nil {
+		s.logger.Errorf("failed to mark payment settled: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package payment
+
+// Storage describes a payment storage functionality.
+type Storage interface {
+	Create(payment *Payment) (*Payment, error)
+	FindById(id int64) (*Payment, error)
+	FindByOrder(orderId int64) ([]*Payment, error)
+	UpdateStatus(id int64, status string, failureReason *string) (*Payment, error)
+	ApplyEvent(provider string, event *Event, status string) (*Payment, bool, error)
+	SetSettled(id int64) error
+	StartRefund(refund *Refund) (*Refund, error)
+	CompleteRefund(id int64, refundId string) (*Payment, bool, error)
+	CancelRefund(id int64) error
+}
@@ -0,0 +1,223 @@
+package pricing
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	pricesURL = "/api/books/:id/prices"
+	priceURL  = "/api/books/:id/prices/:priceId"
+	salesURL  = "/api/books/:id/sales"
+)
+
+// Handler handles requests specified to pricing service.
+type Handler struct {
+	logger         logger.Logger
+	pricingService Service
+}
+
+// NewHandler returns a new pricing Handler instance.
+func NewHandler(logger logger.Logger, pricingService Service) handler.Handling {
+	return &Handler{
+		logger:         logger,
+		pricingService: pricingService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, pricesURL, h.ListPrices)
+	router.HandlerFunc(http.MethodPost, pricesURL, h.ChangeListPrice)
+	router.HandlerFunc(http.MethodPost, salesURL, h.StartSale)
+	router.HandlerFunc(http.MethodDelete, priceURL, h.CancelPrice)
+}
+
+// ListPrices godoc
+// @Summary List book prices
+// @Description Get price history of the book including scheduled list prices and sales, the latest first.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Price
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/prices [get]
+func (h *Handler) ListPrices(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST PRICES")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	prices, err := h.pricingService.GetHistory(r.Context(), id, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, prices)
+}
+
+// ChangeListPrice godoc
+// @Summary Change list price
+// @Description Change the list price of the book now or schedule it. Later scheduled list prices are cancelled. Admin only.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body ListPriceDTO true "JSON input"
+// @Success 201 {object} Price
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/prices [post]
+func (h *Handler) ChangeListPrice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CHANGE LIST PRICE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ListPriceDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.BookId = id
+
+	price, err := h.pricingService.ChangeListPrice(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, price)
+}
+
+// StartSale godoc
+// @Summary Put book on sale
+// @Description Set the sale price of the book for the given period. Admin only.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body SaleDTO true "JSON input"
+// @Success 201 {object} Price
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/sales [post]
+func (h *Handler) StartSale(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("START SALE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input SaleDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.BookId = id
+
+	sale, err := h.pricingService.StartSale(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidSalePrice):
+			response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		case errors.Is(err, apperror.ErrSaleOverlaps):
+			response.Conflict(w, err.Error(), "cancel the other sale or choose another period")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, sale)
+}
+
+// CancelPrice godoc
+// @Summary Cancel price
+// @Description Cancel the scheduled list price or sale of the book. The sale which is going on is ended now. Admin only.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param priceId path int64 true "Price id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/prices/{priceId} [delete]
+func (h *Handler) CancelPrice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CANCEL PRICE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	priceId, err := handler.ReadParam64(r, "priceId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.pricingService.Cancel(r.Context(), id, priceId); err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrPriceInEffect):
+			response.Conflict(w, err.Error(), "change the list price instead")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
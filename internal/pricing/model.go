@@ -0,0 +1,108 @@
+package pricing
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// KindList is a kind of the regular price of the book.
+	// List prices follow one another, each one is effective till the next.
+	KindList = "list"
+	// KindSale is a kind of the discounted price effective for a limited time.
+	KindSale = "sale"
+)
+
+// Price represents a record of the book price history.
+// Missing effectiveTo means that the price is effective until changed.
+// Prices are in the currency of the book.
+type Price struct {
+	Id            int64           `json:"id" example:"1"`
+	BookId        int64           `json:"bookId" example:"123"`
+	Kind          string          `json:"kind" example:"list"`
+	Price         decimal.Decimal `json:"price" swaggertype:"string" example:"12.99"`
+	Currency      string          `json:"currency" example:"USD"`
+	EffectiveFrom time.Time       `json:"effectiveFrom" example:"2022-03-01T00:00:00Z"`
+	EffectiveTo   *time.Time      `json:"effectiveTo,omitempty" example:"2022-04-01T00:00:00Z"`
+	Reason        *string         `json:"reason,omitempty" example:"publisher price change"`
+	CreatedAt     time.Time       `json:"createdAt" example:"2022-02-20T12:00:00Z"`
+} // @name BookPrice
+
+// ListPriceDTO is used to change the list price of the book.
+// The price is changed immediately if effectiveFrom is not specified.
+// The price is in the currency of the book.
+type ListPriceDTO struct {
+	BookId        int64           `json:"-"`
+	Price         decimal.Decimal `json:"price" swaggertype:"string" example:"14.99"`
+	EffectiveFrom *time.Time      `json:"effectiveFrom,omitempty" example:"2022-04-01T00:00:00Z"`
+	Reason        *string         `json:"reason,omitempty" example:"publisher price change"`
+} // @name ListPriceInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (l *ListPriceDTO) Validate() error {
+	return validation.ValidateStruct(
+		l,
+		validation.Field(&l.Price, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&l.EffectiveFrom, validation.By(notInPast)),
+		validation.Field(&l.Reason, validation.RuneLength(1, 500)),
+	)
+}
+
+// SaleDTO is used to put the book on sale.
+// The sale starts immediately if startsAt is not specified.
+// The price is in the currency of the book.
+type SaleDTO struct {
+	BookId   int64           `json:"-"`
+	Price    decimal.Decimal `json:"price" swaggertype:"string" example:"9.99"`
+	StartsAt *time.Time      `json:"startsAt,omitempty" example:"2022-03-01T00:00:00Z"`
+	EndsAt   time.Time       `json:"endsAt" example:"2022-03-31T00:00:00Z"`
+	Reason   *string         `json:"reason,omitempty" example:"spring sale"`
+} // @name SaleInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (s *SaleDTO) Validate() error {
+	err := validation.ValidateStruct(
+		s,
+		validation.Field(&s.Price, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&s.StartsAt, validation.By(notInPast)),
+		validation.Field(&s.EndsAt, validation.Required, validation.By(notInPast)),
+		validation.Field(&s.Reason, validation.RuneLength(1, 500)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.StartsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return validation.Errors{
+			"endsAt": errors.New("must be after the start of the sale"),
+		}
+	}
+
+	return nil
+}
+
+// notInPast checks that the time is not in the past.
+func notInPast(value interface{}) error {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		t = *v
+	}
+
+	if t.Before(time.Now()) {
+		return errors.New("must not be in the past")
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	tableName      = "book_prices"
+	booksTableName = "books"
+)
+
+// Check whether db implements pricing storage interface.
+var _ Storage = &db{}
+
+// db implements pricing storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new pricing storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// FindByBook finds price history of the book, the latest prices first.
+// Returns an error on failure.
+func (d *db) FindByBook(bookId int64, limit, offset int) ([]*Price, error) {
+	query := fmt.Sprintf(`
+	SELECT p.id, p.book_id, p.kind, p.price, b.currency, p.effective_from, p.effective_to, p.reason, p.created_at
+	FROM %s p
+	JOIN %s b ON b.id = p.book_id
+	WHERE p.book_id = $1
+	ORDER BY p.effective_from DESC, p.id DESC
+	LIMIT $2 OFFSET $3`, tableName, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, bookId, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find prices query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make([]*Price, 0)
+	for rows.Next() {
+		var p Price
+		err := rows.Scan(
+			&p.Id,
+			&p.BookId,
+			&p.Kind,
+			&p.Price,
+			&p.Currency,
+			&p.EffectiveFrom,
+			&p.EffectiveTo,
+			&p.Reason,
+			&p.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan price: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		prices = append(prices, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read prices: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// ScheduleListPrice makes the list price effective from the given time,
+// or from now if the time is not set. List prices scheduled at or after that time are cancelled, and the price
+// effective at that time ends when the new one starts. The cached price of
+// the book is changed at once if the new price is already effective.
+// Returns ErrNoRows if book doesn't exist, an error on failure or
+// the inserted price on success.
+func (d *db) ScheduleListPrice(price *Price) (*Price, error) {
+	cancelQuery := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE book_id = $1 AND kind = 'list' AND effective_from >= $2 AND effective_from > now()`, tableName)
+
+	endQuery := fmt.Sprintf(`
+	UPDATE %s SET effective_to = $2
+	WHERE book_id = $1 AND kind = 'list' AND effective_from < $2
+		AND (effective_to IS NULL OR effective_to > $2)`, tableName)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, kind, price, effective_from, reason)
+	VALUES ($1, 'list', $2, $3, $4)
+	RETURNING id, created_at`, tableName)
+
+	applyQuery := fmt.Sprintf("UPDATE %s SET price = $1 WHERE id = $2", booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if price.Currency, err = lockBook(ctx, tx, price.BookId); err != nil {
+		return nil, err
+	}
+
+	now, err := startNow(ctx, tx, price)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	for _, query := range []string{cancelQuery, endQuery} {
+		if _, err := tx.Exec(ctx, query, price.BookId, price.EffectiveFrom); err != nil {
+			err = fmt.Errorf("failed to end previous list prices: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		insertQuery,
+		price.BookId,
+		price.Price,
+		price.EffectiveFrom,
+		price.Reason,
+	).Scan(&price.Id, &price.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create list price query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if !price.EffectiveFrom.After(now) {
+		if _, err := tx.Exec(ctx, applyQuery, price.Price, price.BookId); err != nil {
+			return nil, fmt.Errorf("failed to apply list price: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return price, nil
+}
+
+// CreateSale inserts the sale price of the book. The sale starts now if
+// its start is not set. Returns ErrNoRows if book
+// doesn't exist, ErrSaleOverlaps if the book has another sale at that time,
+// ErrInvalidSalePrice if the price is not lower than the list price
+// effective at the start of the sale, an error on failure or the inserted
+// price on success.
+func (d *db) CreateSale(price *Price) (*Price, error) {
+	overlapQuery := fmt.Sprintf(`
+	SELECT EXISTS (
+		SELECT 1 FROM %s
+		WHERE book_id = $1 AND kind = 'sale' AND effective_from < $3 AND effective_to > $2
+	)`, tableName)
+
+	listPriceQuery := "SELECT book_list_price($1, $2)"
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, kind, price, effective_from, effective_to, reason)
+	VALUES ($1, 'sale', $2, $3, $4, $5)
+	RETURNING id, created_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if price.Currency, err = lockBook(ctx, tx, price.BookId); err != nil {
+		return nil, err
+	}
+
+	if _, err := startNow(ctx, tx, price); err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	var overlaps bool
+	err = tx.QueryRow(ctx, overlapQuery, price.BookId, price.EffectiveFrom, price.EffectiveTo).Scan(&overlaps)
+	if err != nil {
+		err = fmt.Errorf("failed to check sales overlap: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if overlaps {
+		return nil, apperror.ErrSaleOverlaps
+	}
+
+	var listPrice *decimal.Decimal
+	if err := tx.QueryRow(ctx, listPriceQuery, price.BookId, price.EffectiveFrom).Scan(&listPrice); err != nil {
+		err = fmt.Errorf("failed to find list price: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if listPrice != nil && !price.Price.LessThan(*listPrice) {
+		return nil, apperror.ErrInvalidSalePrice
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		insertQuery,
+		price.BookId,
+		price.Price,
+		price.EffectiveFrom,
+		price.EffectiveTo,
+		price.Reason,
+	).Scan(&price.Id, &price.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create sale query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return price, nil
+}
+
+// Cancel cancels the price of the book which hasn't become effective yet.
+// A cancelled list price is removed from the schedule, so the previous one
+// stays effective instead. The sale which is going on is ended now. Returns
+// ErrNoRows if there is no such price, ErrPriceInEffect if the price is
+// effective or in the past, or an error on failure.
+func (d *db) Cancel(bookId, priceId int64) error {
+	findQuery := fmt.Sprintf(`
+	SELECT kind, effective_from, effective_to, effective_from > now(), effective_to > now()
+	FROM %s
+	WHERE id = $1 AND book_id = $2
+	FOR UPDATE`, tableName)
+
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+
+	restoreQuery := fmt.Sprintf(`
+	UPDATE %s SET effective_to = $3
+	WHERE book_id = $1 AND kind = 'list' AND effective_to = $2`, tableName)
+
+	endQuery := fmt.Sprintf("UPDATE %s SET effective_to = now() WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockBook(ctx, tx, bookId); err != nil {
+		return err
+	}
+
+	var kind string
+	var effectiveFrom time.Time
+	var effectiveTo *time.Time
+	var scheduled bool
+	var ongoing *bool
+
+	err = tx.QueryRow(ctx, findQuery, priceId, bookId).Scan(&kind, &effectiveFrom, &effectiveTo, &scheduled, &ongoing)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find price query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	switch {
+	case scheduled:
+		if _, err := tx.Exec(ctx, deleteQuery, priceId); err != nil {
+			return fmt.Errorf("failed to delete price: %v", err)
+		}
+		if kind == KindList {
+			if _, err := tx.Exec(ctx, restoreQuery, bookId, effectiveFrom, effectiveTo); err != nil {
+				return fmt.Errorf("failed to restore previous list price: %v", err)
+			}
+		}
+	case kind == KindSale && ongoing != nil && *ongoing:
+		if _, err := tx.Exec(ctx, endQuery, priceId); err != nil {
+			return fmt.Errorf("failed to end sale: %v", err)
+		}
+	default:
+		return apperror.ErrPriceInEffect
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ApplyScheduled updates cached prices of books which scheduled list prices
+// have become effective. Returns an error on failure or the number of
+// updated books on success.
+func (d *db) ApplyScheduled() (int64, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s b SET price = p.price
+	FROM %s p
+	WHERE p.book_id = b.id AND p.kind = 'list' AND p.effective_from <= now()
+		AND (p.effective_to IS NULL OR p.effective_to > now())
+		AND b.price <> p.price`, booksTableName, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("failed to execute apply scheduled prices query: %v", err)
+		d.logger.Error(err)
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// lockBook locks the book till the end of the transaction, so its prices
+// are changed one by one. Returns ErrNoRows if book doesn't exist or
+// the currency of the book prices on success.
+func lockBook(ctx context.Context, tx pgx.Tx, bookId int64) (string, error) {
+	query := fmt.Sprintf("SELECT currency FROM %s WHERE id = $1 FOR UPDATE", booksTableName)
+
+	var currency string
+	if err := tx.QueryRow(ctx, query, bookId).Scan(&currency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", apperror.ErrNoRows
+		}
+		return "", fmt.Errorf("failed to lock book: %v", err)
+	}
+
+	return currency, nil
+}
+
+// startNow makes the price effective from the time of the transaction if
+// its start is not set or has already passed, so prices are always compared
+// with the database clock. Returns an error on failure or the time of the
+// transaction on success.
+func startNow(ctx context.Context, tx pgx.Tx, price *Price) (time.Time, error) {
+	var now time.Time
+	if err := tx.QueryRow(ctx, "SELECT now()").Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get current time: %v", err)
+	}
+
+	if price.EffectiveFrom.Before(now) {
+		price.EffectiveFrom = now
+	}
+
+	return now, nil
+}
@@ -0,0 +1,48 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Scheduler periodically applies scheduled list prices to cached book prices.
+// Book responses and orders read effective prices from the history, so the
+// cache only keeps books.price in line with them.
+type Scheduler struct {
+	logger         logger.Logger
+	pricingService Service
+	interval       time.Duration
+}
+
+// NewScheduler returns a new Scheduler which runs every interval.
+func NewScheduler(logger logger.Logger, pricingService Service, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		logger:         logger,
+		pricingService: pricingService,
+		interval:       interval,
+	}
+}
+
+// Run applies scheduled prices until the context is done.
+// Failed runs are logged and retried on the next tick.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			applied, err := s.pricingService.ApplyScheduled(ctx)
+			if err != nil {
+				continue
+			}
+			if applied > 0 {
+				s.logger.Infof("applied scheduled prices of %d books", applied)
+			}
+		}
+	}
+}
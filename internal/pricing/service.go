@@ -0,0 +1,111 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes pricing service functionality.
+type Service interface {
+	GetHistory(ctx context.Context, bookId int64, limit, offset int) ([]*Price, error)
+	ChangeListPrice(ctx context.Context, input *ListPriceDTO) (*Price, error)
+	StartSale(ctx context.Context, input *SaleDTO) (*Price, error)
+	Cancel(ctx context.Context, bookId, priceId int64) error
+	ApplyScheduled(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) GetHistory(ctx context.Context, bookId int64, limit, offset int) ([]*Price, error) {
+	prices, err := s.storage.FindByBook(bookId, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find prices: %v", err)
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func (s *service) ChangeListPrice(ctx context.Context, input *ListPriceDTO) (*Price, error) {
+	price := Price{
+		BookId: input.BookId,
+		Kind:   KindList,
+		Price:  input.Price,
+		Reason: input.Reason,
+	}
+	if input.EffectiveFrom != nil {
+		price.EffectiveFrom = *input.EffectiveFrom
+	}
+
+	scheduled, err := s.storage.ScheduleListPrice(&price)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to schedule list price: %v", err)
+		}
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+func (s *service) StartSale(ctx context.Context, input *SaleDTO) (*Price, error) {
+	endsAt := input.EndsAt
+	price := Price{
+		BookId:      input.BookId,
+		Kind:        KindSale,
+		Price:       input.Price,
+		EffectiveTo: &endsAt,
+		Reason:      input.Reason,
+	}
+	if input.StartsAt != nil {
+		price.EffectiveFrom = *input.StartsAt
+	}
+
+	sale, err := s.storage.CreateSale(&price)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) &&
+			!errors.Is(err, apperror.ErrSaleOverlaps) &&
+			!errors.Is(err, apperror.ErrInvalidSalePrice) {
+			s.logger.Errorf("failed to create sale: %v", err)
+		}
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *service) Cancel(ctx context.Context, bookId, priceId int64) error {
+	err := s.storage.Cancel(bookId, priceId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrPriceInEffect) {
+			s.logger.Errorf("failed to cancel price: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ApplyScheduled(ctx context.Context) (int64, error) {
+	applied, err := s.storage.ApplyScheduled()
+	if err != nil {
+		s.logger.Errorf("failed to apply scheduled prices: %v", err)
+		return 0, err
+	}
+
+	return applied, nil
+}
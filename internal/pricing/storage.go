@@ -0,0 +1,10 @@
+package pricing
+
+// Storage describes a price history storage functionality.
+type Storage interface {
+	FindByBook(bookId int64, limit, offset int) ([]*Price, error)
+	ScheduleListPrice(price *Price) (*Price, error)
+	CreateSale(price *Price) (*Price, error)
+	Cancel(bookId, priceId int64) error
+	ApplyScheduled() (int64, error)
+}
@@ -0,0 +1,270 @@
+package promo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+// Line represents copies of the book the discounts are calculated for.
+type Line struct {
+	BookId    int64
+	GenreIds  []int16
+	AuthorIds []int64
+	UnitPrice decimal.Decimal
+	Quantity  int32
+}
+
+// unit is a single copy of the line. Amount is the price of the copy
+// left after the discounts applied so far.
+type unit struct {
+	line   int
+	amount decimal.Decimal
+}
+
+// kindOrder is the order codes of different kinds are applied in.
+// Free copies are taken first, then percentages and fixed amounts are
+// taken off what is left, so the basket total never gets below zero.
+var kindOrder = map[string]int{
+	KindBuyXGetY:   0,
+	KindPercentage: 1,
+	KindFixed:      2,
+}
+
+// CheckStacking checks whether the code can be added to the codes
+// which are already applied. Returns ErrPromoNotStackable if not.
+func CheckStacking(applied []*PromoCode, code *PromoCode) error {
+	for _, a := range applied {
+		if a.Id == code.Id {
+			continue
+		}
+		if !a.Stackable || !code.Stackable {
+			return fmt.Errorf("%w: %s", apperror.ErrPromoNotStackable, a.Code)
+		}
+	}
+
+	return nil
+}
+
+// Convert returns copies of the codes with fixed values and minimum totals
+// converted from the base currency to the given one. Returns
+// ErrUnknownCurrency if the currency has no exchange rate.
+func Convert(codes []*PromoCode, rates *currency.Rates, to string) ([]*PromoCode, error) {
+	base := rates.Base().Code
+
+	converted := make([]*PromoCode, len(codes))
+	for i, code := range codes {
+		c := *code
+
+		if c.Kind == KindFixed && c.Value != nil {
+			value, err := rates.Convert(*c.Value, base, to)
+			if err != nil {
+				return nil, err
+			}
+			c.Value = &value
+		}
+
+		if c.MinTotal != nil {
+			minTotal, err := rates.Convert(*c.MinTotal, base, to)
+			if err != nil {
+				return nil, err
+			}
+			c.MinTotal = &minTotal
+		}
+
+		converted[i] = &c
+	}
+
+	return converted, nil
+}
+
+// Calculate calculates the discounts of the codes for the lines at the
+// given moment. Prices of the lines, fixed values and minimum totals of
+// the codes are in the given currency, and discounts are rounded by its
+// rules. Discounts are returned in the order of the codes along with the
+// total discount of every line. The minimum total of the code is compared
+// with the total of lines before discounts.
+func Calculate(codes []*PromoCode, lines []Line, at time.Time, cur *currency.Currency) ([]Discount, []decimal.Decimal) {
+	var units []unit
+	subtotal := decimal.Zero
+	for i, l := range lines {
+		for q := int32(0); q < l.Quantity; q++ {
+			units = append(units, unit{line: i, amount: l.UnitPrice})
+		}
+		subtotal = subtotal.Add(l.UnitPrice.Mul(decimal.New(int64(l.Quantity), 0)))
+	}
+
+	order := make([]int, len(codes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return kindOrder[codes[order[i]].Kind] < kindOrder[codes[order[j]].Kind]
+	})
+
+	discounts := make([]Discount, len(codes))
+	for _, i := range order {
+		code := codes[i]
+		discounts[i] = Discount{
+			PromoCodeId: code.Id,
+			Code:        code.Code,
+			Description: code.Description,
+		}
+
+		if !code.IsActive(at) {
+			discounts[i].Reason = apperror.ErrPromoInactive.Error()
+			continue
+		}
+
+		if code.MinTotal != nil && subtotal.LessThan(*code.MinTotal) {
+			discounts[i].Reason = fmt.Sprintf(
+				"basket total is less than %s %s",
+				code.MinTotal.StringFixed(cur.Decimals),
+				cur.Code,
+			)
+			continue
+		}
+
+		var eligible []*unit
+		for u := range units {
+			if units[u].amount.IsPositive() && code.matches(&lines[units[u].line]) {
+				eligible = append(eligible, &units[u])
+			}
+		}
+		if len(eligible) == 0 {
+			discounts[i].Reason = "no books in the basket match the promo code"
+			continue
+		}
+
+		amount := code.apply(eligible, cur)
+		if amount.IsZero() {
+			discounts[i].Reason = code.shortfall()
+		}
+		discounts[i].Amount = amount
+	}
+
+	lineDiscounts := make([]decimal.Decimal, len(lines))
+	for _, u := range units {
+		lineDiscounts[u.line] = lineDiscounts[u.line].Add(lines[u.line].UnitPrice.Sub(u.amount))
+	}
+
+	return discounts, lineDiscounts
+}
+
+// matches reports whether the code applies to the book of the line.
+func (p *PromoCode) matches(l *Line) bool {
+	if p.BookId != nil && *p.BookId != l.BookId {
+		return false
+	}
+
+	if p.GenreId != nil && !containsInt16(l.GenreIds, *p.GenreId) {
+		return false
+	}
+
+	if p.AuthorId != nil && !containsInt64(l.AuthorIds, *p.AuthorId) {
+		return false
+	}
+
+	return true
+}
+
+// apply takes the discount of the code off the units.
+// Returns the amount taken off.
+func (p *PromoCode) apply(units []*unit, cur *currency.Currency) decimal.Decimal {
+	total := decimal.Zero
+	for _, u := range units {
+		total = total.Add(u.amount)
+	}
+
+	switch p.Kind {
+	case KindBuyXGetY:
+		// The cheapest copies of every group are free, so the most
+		// expensive copies are grouped together.
+		sort.SliceStable(units, func(i, j int) bool {
+			return units[i].amount.GreaterThan(units[j].amount)
+		})
+
+		group := int(*p.BuyQuantity + *p.GetQuantity)
+		amount := decimal.Zero
+		for start := 0; start+group <= len(units); start += group {
+			for _, u := range units[start+int(*p.BuyQuantity) : start+group] {
+				amount = amount.Add(u.amount)
+				u.amount = decimal.Zero
+			}
+		}
+		return amount
+	case KindPercentage:
+		amount := cur.Round(total.Mul(*p.Value).Div(decimal.New(100, 0)))
+		if amount.GreaterThan(total) {
+			amount = total
+		}
+		distribute(units, amount, total, cur)
+		return amount
+	case KindFixed:
+		amount := *p.Value
+		if amount.GreaterThan(total) {
+			amount = total
+		}
+		distribute(units, amount, total, cur)
+		return amount
+	}
+
+	return decimal.Zero
+}
+
+// shortfall explains why the code gives no discount for matching books.
+func (p *PromoCode) shortfall() string {
+	if p.Kind == KindBuyXGetY {
+		return fmt.Sprintf("buy %d matching books to get %d free", *p.BuyQuantity+*p.GetQuantity, *p.GetQuantity)
+	}
+
+	return "matching books are already free"
+}
+
+// distribute takes the amount off the units in proportion to their amounts.
+// Shares are rounded down to the minor unit of the currency, and what is
+// left after rounding is taken by minor units from the first units.
+func distribute(units []*unit, amount, total decimal.Decimal, cur *currency.Currency) {
+	if total.IsZero() {
+		return
+	}
+
+	left := amount
+	for _, u := range units {
+		share := amount.Mul(u.amount).Div(total).RoundDown(cur.Decimals)
+		u.amount = u.amount.Sub(share)
+		left = left.Sub(share)
+	}
+
+	minor := cur.MinorUnit()
+	for _, u := range units {
+		if !left.IsPositive() {
+			break
+		}
+		step := decimal.Min(minor, left, u.amount)
+		u.amount = u.amount.Sub(step)
+		left = left.Sub(step)
+	}
+}
+
+func containsInt16(values []int16, v int16) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt64(values []int64, v int64) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package promo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	euro  = &currency.Currency{Code: "EUR", Decimals: 2, Rounding: currency.RoundHalfUp}
+	start = time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	now   = start.Add(24 * time.Hour)
+)
+
+func dec(v string) decimal.Decimal { return decimal.RequireFromString(v) }
+
+func decPtr(v string) *decimal.Decimal {
+	d := dec(v)
+	return &d
+}
+
+func int16Ptr(v int16) *int16 { return &v }
+
+func int32Ptr(v int32) *int32 { return &v }
+
+// percentage returns a percentage code with the given id.
+func percentage(id int64, value string) *PromoCode {
+	return &PromoCode{Id: id, Code: "PERCENT", Kind: KindPercentage, Value: decPtr(value), StartsAt: start, Stackable: true}
+}
+
+// fixed returns a fixed amount code with the given id.
+func fixed(id int64, value string) *PromoCode {
+	return &PromoCode{Id: id, Code: "FIXED", Kind: KindFixed, Value: decPtr(value), StartsAt: start, Stackable: true}
+}
+
+// buyGet returns a buy-x-get-y code with the given id.
+func buyGet(id int64, buy, get int32) *PromoCode {
+	return &PromoCode{
+		Id:          id,
+		Code:        "BUYGET",
+		Kind:        KindBuyXGetY,
+		BuyQuantity: int32Ptr(buy),
+		GetQuantity: int32Ptr(get),
+		StartsAt:    start,
+		Stackable:   true,
+	}
+}
+
+// line returns a line of the book without genres and authors.
+func line(bookId int64, price string, quantity int32) Line {
+	return Line{BookId: bookId, UnitPrice: dec(price), Quantity: quantity}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name         string
+		codes        []*PromoCode
+		lines        []Line
+		wantAmounts  []string
+		wantReasons  []string
+		wantLineDisc []string
+	}{
+		{
+			name:         "buy two get the cheapest one free",
+			codes:        []*PromoCode{buyGet(1, 2, 1)},
+			lines:        []Line{line(1, "10", 2), line(2, "5", 1)},
+			wantAmounts:  []string{"5"},
+			wantLineDisc: []string{"0", "5"},
+		},
+		{
+			name:         "buy one get one for every full group only",
+			codes:        []*PromoCode{buyGet(1, 1, 1)},
+			lines:        []Line{line(1, "10", 3)},
+			wantAmounts:  []string{"10"},
+			wantLineDisc: []string{"10"},
+		},
+		{
+			name:         "buy x get y short of copies",
+			codes:        []*PromoCode{buyGet(1, 2, 1)},
+			lines:        []Line{line(1, "10", 2)},
+			wantAmounts:  []string{"0"},
+			wantReasons:  []string{"buy 3 matching books to get 1 free"},
+			wantLineDisc: []string{"0"},
+		},
+		{
+			name:         "rounding remainder is taken from the first lines",
+			codes:        []*PromoCode{percentage(1, "10")},
+			lines:        []Line{line(1, "3.33", 1), line(2, "3.33", 1), line(3, "3.33", 1)},
+			wantAmounts:  []string{"1"},
+			wantLineDisc: []string{"0.34", "0.33", "0.33"},
+		},
+		{
+			name:         "percentage is rounded by the currency",
+			codes:        []*PromoCode{percentage(1, "15")},
+			lines:        []Line{line(1, "9.99", 1)},
+			wantAmounts:  []string{"1.5"},
+			wantLineDisc: []string{"1.5"},
+		},
+		{
+			name:         "fixed amount is capped at the total",
+			codes:        []*PromoCode{fixed(1, "50")},
+			lines:        []Line{line(1, "12.5", 1), line(2, "7.5", 1)},
+			wantAmounts:  []string{"20"},
+			wantLineDisc: []string{"12.5", "7.5"},
+		},
+		{
+			name:         "fixed amount is split in proportion to prices",
+			codes:        []*PromoCode{fixed(1, "5")},
+			lines:        []Line{line(1, "30", 1), line(2, "10", 1)},
+			wantAmounts:  []string{"5"},
+			wantLineDisc: []string{"3.75", "1.25"},
+		},
+		{
+			name:         "free copies are taken before percentages",
+			codes:        []*PromoCode{percentage(1, "10"), buyGet(2, 1, 1)},
+			lines:        []Line{line(1, "10", 2)},
+			wantAmounts:  []string{"1", "10"},
+			wantLineDisc: []string{"11"},
+		},
+		{
+			name:         "stacked codes never take more than the total",
+			codes:        []*PromoCode{fixed(1, "10"), percentage(2, "50")},
+			lines:        []Line{line(1, "15", 1)},
+			wantAmounts:  []string{"7.5", "7.5"},
+			wantLineDisc: []string{"15"},
+		},
+		{
+			name: "minimum total is not reached",
+			codes: []*PromoCode{{
+				Id: 1, Code: "MIN", Kind: KindFixed, Value: decPtr("5"), MinTotal: decPtr("30"), StartsAt: start,
+			}},
+			lines:        []Line{line(1, "20", 1)},
+			wantAmounts:  []string{"0"},
+			wantReasons:  []string{"basket total is less than 30.00 EUR"},
+			wantLineDisc: []string{"0"},
+		},
+		{
+			name: "no books match the scope",
+			codes: []*PromoCode{{
+				Id: 1, Code: "FANTASY", Kind: KindPercentage, Value: decPtr("20"), GenreId: int16Ptr(3), StartsAt: start,
+			}},
+			lines:        []Line{{BookId: 1, GenreIds: []int16{1, 2}, UnitPrice: dec("10"), Quantity: 1}},
+			wantAmounts:  []string{"0"},
+			wantReasons:  []string{"no books in the basket match the promo code"},
+			wantLineDisc: []string{"0"},
+		},
+		{
+			name: "only matching books are discounted",
+			codes: []*PromoCode{{
+				Id: 1, Code: "FANTASY", Kind: KindPercentage, Value: decPtr("20"), GenreId: int16Ptr(3), StartsAt: start,
+			}},
+			lines: []Line{
+				{BookId: 1, GenreIds: []int16{3}, UnitPrice: dec("10"), Quantity: 1},
+				{BookId: 2, GenreIds: []int16{1}, UnitPrice: dec("10"), Quantity: 1},
+			},
+			wantAmounts:  []string{"2"},
+			wantLineDisc: []string{"2", "0"},
+		},
+		{
+			name: "inactive code",
+			codes: []*PromoCode{{
+				Id: 1, Code: "LATER", Kind: KindFixed, Value: decPtr("5"), StartsAt: now.Add(time.Hour),
+			}},
+			lines:        []Line{line(1, "20", 1)},
+			wantAmounts:  []string{"0"},
+			wantReasons:  []string{apperror.ErrPromoInactive.Error()},
+			wantLineDisc: []string{"0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discounts, lineDiscounts := Calculate(tt.codes, tt.lines, now, euro)
+
+			if len(discounts) != len(tt.wantAmounts) {
+				t.Fatalf("got %d discounts, want %d", len(discounts), len(tt.wantAmounts))
+			}
+			for i, d := range discounts {
+				if d.PromoCodeId != tt.codes[i].Id {
+					t.Errorf("discount %d is of code %d, want %d", i, d.PromoCodeId, tt.codes[i].Id)
+				}
+				if !d.Amount.Equal(dec(tt.wantAmounts[i])) {
+					t.Errorf("discount %d amount = %s, want %s", i, d.Amount, tt.wantAmounts[i])
+				}
+				wantReason := ""
+				if tt.wantReasons != nil {
+					wantReason = tt.wantReasons[i]
+				}
+				if d.Reason != wantReason {
+					t.Errorf("discount %d reason = %q, want %q", i, d.Reason, wantReason)
+				}
+			}
+
+			for i, got := range lineDiscounts {
+				if !got.Equal(dec(tt.wantLineDisc[i])) {
+					t.Errorf("line %d discount = %s, want %s", i, got, tt.wantLineDisc[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckStacking(t *testing.T) {
+	exclusive := fixed(3, "5")
+	exclusive.Stackable = false
+
+	tests := []struct {
+		name    string
+		applied []*PromoCode
+		code    *PromoCode
+		wantErr error
+	}{
+		{name: "first code", code: exclusive},
+		{name: "stackable codes", applied: []*PromoCode{percentage(1, "10")}, code: fixed(2, "5")},
+		{name: "same code again", applied: []*PromoCode{exclusive}, code: exclusive},
+		{name: "applied code is exclusive", applied: []*PromoCode{exclusive}, code: fixed(2, "5"), wantErr: apperror.ErrPromoNotStackable},
+		{name: "new code is exclusive", applied: []*PromoCode{percentage(1, "10")}, code: exclusive, wantErr: apperror.ErrPromoNotStackable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckStacking(tt.applied, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckStacking() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package promo
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	promoCodesURL = "/api/promo-codes"
+	promoCodeURL  = "/api/promo-codes/:id"
+)
+
+// Handler handles requests specified to promo code service.
+type Handler struct {
+	logger       logger.Logger
+	promoService Service
+}
+
+// NewHandler returns a new promo code Handler instance.
+func NewHandler(logger logger.Logger, promoService Service) handler.Handling {
+	return &Handler{
+		logger:       logger,
+		promoService: promoService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, promoCodesURL, h.ListPromoCodes)
+	router.HandlerFunc(http.MethodPost, promoCodesURL, h.CreatePromoCode)
+	router.HandlerFunc(http.MethodGet, promoCodeURL, h.GetPromoCode)
+	router.HandlerFunc(http.MethodDelete, promoCodeURL, h.DeletePromoCode)
+}
+
+// GetPromoCode godoc
+// @Summary Show promo code
+// @Description Get promo code by id with the number of its redemptions. Admin only.
+// @Tags promo codes
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Promo code id"
+// @Success 200 {object} PromoCode
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /promo-codes/{id} [get]
+func (h *Handler) GetPromoCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET PROMO CODE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	code, err := h.promoService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, code)
+}
+
+// ListPromoCodes godoc
+// @Summary List promo codes
+// @Description Get promo codes with the number of their redemptions, the latest first. Admin only.
+// @Tags promo codes
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} PromoCode
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /promo-codes [get]
+func (h *Handler) ListPromoCodes(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST PROMO CODES")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	codes, err := h.promoService.GetAll(r.Context(), pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, codes)
+}
+
+// CreatePromoCode godoc
+// @Summary Create promo code
+// @Description Create promo code. Percentage and fixed codes require value, buy_x_get_y codes require buyQuantity and getQuantity. Codes are case-insensitive. Admin only.
+// @Tags promo codes
+// @Accept json
+// @Produce json
+// @Param input body CreatePromoCodeDTO true "JSON input"
+// @Success 201 {object} PromoCode
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /promo-codes [post]
+func (h *Handler) CreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE PROMO CODE")
+
+	var input CreatePromoCodeDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	code, err := h.promoService.Create(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrPromoCodeExists):
+			response.Conflict(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrInvalidReference):
+			response.BadRequest(w, err.Error(), "check genreId, authorId and bookId")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, code)
+}
+
+// DeletePromoCode godoc
+// @Summary Delete promo code
+// @Description Delete promo code with specified id. The code is removed from baskets, orders keep their discounts. Admin only.
+// @Tags promo codes
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Promo code id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /promo-codes/{id} [delete]
+func (h *Handler) DeletePromoCode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE PROMO CODE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.promoService.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
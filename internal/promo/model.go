@@ -0,0 +1,184 @@
+package promo
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// KindPercentage is a kind of the code which takes a percentage off the matching books.
+	KindPercentage = "percentage"
+	// KindFixed is a kind of the code which takes a fixed amount off the matching books.
+	KindFixed = "fixed"
+	// KindBuyXGetY is a kind of the code which makes the cheapest getQuantity
+	// of every buyQuantity + getQuantity matching copies free.
+	KindBuyXGetY = "buy_x_get_y"
+)
+
+// codePattern matches promo codes, e.g. SPRING-2022.
+var codePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PromoCode represents the promo code model.
+// Codes apply only to books matching all of the scopes set: genreId,
+// authorId and bookId. Codes which are not stackable can't be combined
+// with other codes. Redemptions is the number of orders the code is used in.
+// Fixed values and minimum totals are in the base currency.
+type PromoCode struct {
+	Id           int64            `json:"id" example:"1"`
+	Code         string           `json:"code" example:"SPRING-2022"`
+	Description  *string          `json:"description,omitempty" example:"20% off fantasy books"`
+	Kind         string           `json:"kind" example:"percentage"`
+	Value        *decimal.Decimal `json:"value,omitempty" swaggertype:"string" example:"20"`
+	BuyQuantity  *int32           `json:"buyQuantity,omitempty" example:"2"`
+	GetQuantity  *int32           `json:"getQuantity,omitempty" example:"1"`
+	GenreId      *int16           `json:"genreId,omitempty" example:"3"`
+	AuthorId     *int64           `json:"authorId,omitempty" example:"12"`
+	BookId       *int64           `json:"bookId,omitempty" example:"123"`
+	MinTotal     *decimal.Decimal `json:"minTotal,omitempty" swaggertype:"string" example:"30"`
+	UsageLimit   *int32           `json:"usageLimit,omitempty" example:"1000"`
+	PerUserLimit *int32           `json:"perUserLimit,omitempty" example:"1"`
+	StartsAt     time.Time        `json:"startsAt" example:"2022-03-01T00:00:00Z"`
+	EndsAt       *time.Time       `json:"endsAt,omitempty" example:"2022-04-01T00:00:00Z"`
+	Stackable    bool             `json:"stackable" example:"false"`
+	Redemptions  int64            `json:"redemptions" example:"42"`
+	CreatedAt    time.Time        `json:"createdAt" example:"2022-02-20T12:00:00Z"`
+} // @name PromoCode
+
+// IsActive reports whether the code is valid at the given moment.
+func (p *PromoCode) IsActive(at time.Time) bool {
+	return !at.Before(p.StartsAt) && (p.EndsAt == nil || at.Before(*p.EndsAt))
+}
+
+// Discount represents an amount taken off the basket by the promo code.
+// Amount is zero if the code doesn't apply to the basket, then Reason
+// explains why.
+type Discount struct {
+	PromoCodeId int64           `json:"promoCodeId" example:"1"`
+	Code        string          `json:"code" example:"SPRING-2022"`
+	Description *string         `json:"description,omitempty" example:"20% off fantasy books"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string" example:"5.2"`
+	Reason      string          `json:"reason,omitempty" example:"basket total is less than 30.00"`
+} // @name PromoDiscount
+
+// CreatePromoCodeDTO is used to create promo code.
+// The code is valid from now if startsAt is not specified.
+// Fixed values and minimum totals are in the base currency.
+type CreatePromoCodeDTO struct {
+	Code         string           `json:"code" example:"SPRING-2022"`
+	Description  *string          `json:"description,omitempty" example:"20% off fantasy books"`
+	Kind         string           `json:"kind" example:"percentage"`
+	Value        *decimal.Decimal `json:"value,omitempty" swaggertype:"string" example:"20"`
+	BuyQuantity  *int32           `json:"buyQuantity,omitempty" example:"2"`
+	GetQuantity  *int32           `json:"getQuantity,omitempty" example:"1"`
+	GenreId      *int16           `json:"genreId,omitempty" example:"3"`
+	AuthorId     *int64           `json:"authorId,omitempty" example:"12"`
+	BookId       *int64           `json:"bookId,omitempty" example:"123"`
+	MinTotal     *decimal.Decimal `json:"minTotal,omitempty" swaggertype:"string" example:"30"`
+	UsageLimit   *int32           `json:"usageLimit,omitempty" example:"1000"`
+	PerUserLimit *int32           `json:"perUserLimit,omitempty" example:"1"`
+	StartsAt     *time.Time       `json:"startsAt,omitempty" example:"2022-03-01T00:00:00Z"`
+	EndsAt       *time.Time       `json:"endsAt,omitempty" example:"2022-04-01T00:00:00Z"`
+	Stackable    bool             `json:"stackable" example:"false"`
+} // @name CreatePromoCodeInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (p *CreatePromoCodeDTO) Validate() error {
+	err := validation.ValidateStruct(
+		p,
+		validation.Field(
+			&p.Code,
+			validation.Required,
+			validation.Length(3, 32),
+			validation.Match(codePattern).Error("must contain only letters, digits, hyphens and underscores"),
+		),
+		validation.Field(&p.Description, validation.RuneLength(1, 500)),
+		validation.Field(
+			&p.Kind,
+			validation.Required,
+			validation.In(KindPercentage, KindFixed, KindBuyXGetY),
+		),
+		validation.Field(&p.Value, validator.MinDecimal(decimal.New(1, -2))),
+		validation.Field(&p.BuyQuantity, validation.Min(1), validation.Max(100)),
+		validation.Field(&p.GetQuantity, validation.Min(1), validation.Max(100)),
+		validation.Field(&p.GenreId, validation.Min(1)),
+		validation.Field(&p.AuthorId, validation.Min(1)),
+		validation.Field(&p.BookId, validation.Min(1)),
+		validation.Field(&p.MinTotal, validator.MinDecimal(decimal.New(1, -2))),
+		validation.Field(&p.UsageLimit, validation.Min(1)),
+		validation.Field(&p.PerUserLimit, validation.Min(1)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := p.validateKind(); err != nil {
+		return err
+	}
+
+	startsAt := time.Now()
+	if p.StartsAt != nil {
+		startsAt = *p.StartsAt
+	}
+	if p.EndsAt != nil && !p.EndsAt.After(startsAt) {
+		return validation.Errors{
+			"endsAt": errors.New("must be after the start of the promo code"),
+		}
+	}
+
+	return nil
+}
+
+// validateKind checks that the fields required by the kind of the code
+// are set and the others are not.
+func (p *CreatePromoCodeDTO) validateKind() error {
+	errs := validation.Errors{}
+	required := errors.New("cannot be blank")
+	forbidden := errors.New("must be blank for " + p.Kind + " codes")
+
+	switch p.Kind {
+	case KindPercentage, KindFixed:
+		if p.Value == nil {
+			errs["value"] = required
+		} else if p.Kind == KindPercentage && p.Value.GreaterThan(decimal.New(100, 0)) {
+			errs["value"] = errors.New("must be no greater than 100")
+		}
+		if p.BuyQuantity != nil {
+			errs["buyQuantity"] = forbidden
+		}
+		if p.GetQuantity != nil {
+			errs["getQuantity"] = forbidden
+		}
+	case KindBuyXGetY:
+		if p.Value != nil {
+			errs["value"] = forbidden
+		}
+		if p.BuyQuantity == nil {
+			errs["buyQuantity"] = required
+		}
+		if p.GetQuantity == nil {
+			errs["getQuantity"] = required
+		}
+	}
+
+	return errs.Filter()
+}
+
+// ApplyPromoCodeDTO is used to apply the promo code to the basket.
+type ApplyPromoCodeDTO struct {
+	Code string `json:"code" example:"SPRING-2022"`
+} // @name ApplyPromoCodeInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (a *ApplyPromoCodeDTO) Validate() error {
+	return validation.ValidateStruct(
+		a,
+		validation.Field(&a.Code, validation.Required, validation.Length(1, 32)),
+	)
+}
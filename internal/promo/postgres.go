@@ -0,0 +1,262 @@
+package promo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName            = "promo_codes"
+	redemptionsTableName = "promo_redemptions"
+
+	// selectQuery selects promo codes with the number of their redemptions.
+	// Redemptions of cancelled orders are not counted.
+	selectQuery = `
+	SELECT p.id, p.code, p.description, p.kind, p.value, p.buy_quantity, p.get_quantity,
+		p.genre_id, p.author_id, p.book_id, p.min_total, p.usage_limit, p.per_user_limit,
+		p.starts_at, p.ends_at, p.stackable, p.created_at,
+		(
+			SELECT COUNT(*)
+			FROM promo_redemptions r
+			JOIN orders o ON o.id = r.order_id
+			WHERE r.promo_code_id = p.id AND o.status <> 'cancelled'
+		)
+	FROM promo_codes p`
+)
+
+// Check whether db implements promo code storage interface.
+var _ Storage = &db{}
+
+// db implements promo code storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new promo code storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the promo code. The code is valid from now if the
+// start is not specified. Returns ErrPromoCodeExists if the code is
+// taken, ErrInvalidReference if the scope doesn't exist or an error on failure.
+func (d *db) Create(code *PromoCode) (*PromoCode, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (code, description, kind, value, buy_quantity, get_quantity,
+		genre_id, author_id, book_id, min_total, usage_limit, per_user_limit,
+		starts_at, ends_at, stackable)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13, now()), $14, $15)
+	RETURNING id, starts_at, created_at`, tableName)
+
+	var startsAt *time.Time
+	if !code.StartsAt.IsZero() {
+		startsAt = &code.StartsAt
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(
+		ctx,
+		query,
+		code.Code,
+		code.Description,
+		code.Kind,
+		code.Value,
+		code.BuyQuantity,
+		code.GetQuantity,
+		code.GenreId,
+		code.AuthorId,
+		code.BookId,
+		code.MinTotal,
+		code.UsageLimit,
+		code.PerUserLimit,
+		startsAt,
+		code.EndsAt,
+		code.Stackable,
+	).Scan(&code.Id, &code.StartsAt, &code.CreatedAt)
+
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrPromoCodeExists
+		}
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrInvalidReference
+		}
+		err = fmt.Errorf("failed to execute create promo code query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// FindById finds the promo code with specified id.
+// Returns ErrNoRows if promo code doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*PromoCode, error) {
+	return d.findOne(selectQuery+" WHERE p.id = $1", id)
+}
+
+// FindByCode finds the promo code ignoring the case of the code.
+// Returns ErrNoRows if promo code doesn't exist or an error on failure.
+func (d *db) FindByCode(code string) (*PromoCode, error) {
+	return d.findOne(selectQuery+" WHERE upper(p.code) = upper($1)", code)
+}
+
+// FindByIds finds promo codes with specified ids in the order of ids.
+// Returns an error on failure.
+func (d *db) FindByIds(ids []int64) ([]*PromoCode, error) {
+	query := selectQuery + " WHERE p.id = ANY($1) ORDER BY array_position($1, p.id)"
+
+	return d.findMany(query, ids)
+}
+
+// FindAll finds promo codes, the latest first.
+// Returns an error on failure.
+func (d *db) FindAll(limit, offset int) ([]*PromoCode, error) {
+	query := selectQuery + " ORDER BY p.id DESC LIMIT $1 OFFSET $2"
+
+	return d.findMany(query, limit, offset)
+}
+
+// CountRedemptions counts redemptions of the promo code in total and by
+// the user. Redemptions of cancelled orders are not counted.
+// Returns an error on failure.
+func (d *db) CountRedemptions(id, userId int64) (int64, int64, error) {
+	query := fmt.Sprintf(`
+	SELECT COUNT(*), COUNT(*) FILTER (WHERE r.user_id = $2)
+	FROM %s r
+	JOIN orders o ON o.id = r.order_id
+	WHERE r.promo_code_id = $1 AND o.status <> 'cancelled'`, redemptionsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	var total, byUser int64
+	if err := d.conn.QueryRow(ctx, query, id, userId).Scan(&total, &byUser); err != nil {
+		err = fmt.Errorf("failed to execute count redemptions query: %v", err)
+		d.logger.Error(err)
+		return 0, 0, err
+	}
+
+	return total, byUser, nil
+}
+
+// Delete deletes the promo code with specified id. Redemptions of the
+// code are kept. Returns ErrNoRows if promo code doesn't exist or an
+// error on failure.
+func (d *db) Delete(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete promo code query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// findOne finds a single promo code with the query.
+// Returns ErrNoRows if promo code doesn't exist or an error on failure.
+func (d *db) findOne(query string, args ...interface{}) (*PromoCode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	code, err := scanPromoCode(d.conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find promo code query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// findMany finds promo codes with the query.
+// Returns an error on failure.
+func (d *db) findMany(query string, args ...interface{}) ([]*PromoCode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find promo codes query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := make([]*PromoCode, 0)
+	for rows.Next() {
+		code, err := scanPromoCode(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan promo code: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read promo codes: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// scanPromoCode scans a row selected with selectQuery into a promo code.
+func scanPromoCode(row pgx.Row) (*PromoCode, error) {
+	var p PromoCode
+	err := row.Scan(
+		&p.Id,
+		&p.Code,
+		&p.Description,
+		&p.Kind,
+		&p.Value,
+		&p.BuyQuantity,
+		&p.GetQuantity,
+		&p.GenreId,
+		&p.AuthorId,
+		&p.BookId,
+		&p.MinTotal,
+		&p.UsageLimit,
+		&p.PerUserLimit,
+		&p.StartsAt,
+		&p.EndsAt,
+		&p.Stackable,
+		&p.CreatedAt,
+		&p.Redemptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
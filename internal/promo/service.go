@@ -0,0 +1,145 @@
+package promo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes promo code service functionality.
+type Service interface {
+	Create(ctx context.Context, input *CreatePromoCodeDTO) (*PromoCode, error)
+	GetById(ctx context.Context, id int64) (*PromoCode, error)
+	GetByIds(ctx context.Context, ids []int64) ([]*PromoCode, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*PromoCode, error)
+	Redeemable(ctx context.Context, code string, userId int64) (*PromoCode, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *CreatePromoCodeDTO) (*PromoCode, error) {
+	p := PromoCode{
+		Code:         strings.ToUpper(input.Code),
+		Description:  input.Description,
+		Kind:         input.Kind,
+		Value:        input.Value,
+		BuyQuantity:  input.BuyQuantity,
+		GetQuantity:  input.GetQuantity,
+		GenreId:      input.GenreId,
+		AuthorId:     input.AuthorId,
+		BookId:       input.BookId,
+		MinTotal:     input.MinTotal,
+		UsageLimit:   input.UsageLimit,
+		PerUserLimit: input.PerUserLimit,
+		EndsAt:       input.EndsAt,
+		Stackable:    input.Stackable,
+	}
+	if input.StartsAt != nil {
+		p.StartsAt = *input.StartsAt
+	}
+
+	code, err := s.storage.Create(&p)
+	if err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*PromoCode, error) {
+	code, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find promo code by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func (s *service) GetByIds(ctx context.Context, ids []int64) ([]*PromoCode, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	codes, err := s.storage.FindByIds(ids)
+	if err != nil {
+		s.logger.Warnf("cannot find promo codes: %v", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *service) GetAll(ctx context.Context, limit, offset int) ([]*PromoCode, error) {
+	codes, err := s.storage.FindAll(limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find promo codes: %v", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Redeemable finds the promo code which the user can redeem now.
+// Returns ErrNoRows if the code doesn't exist, ErrPromoInactive if it's
+// outside of its validity window and ErrPromoLimitReached if it has been
+// used the maximum number of times in total or by the user.
+func (s *service) Redeemable(ctx context.Context, code string, userId int64) (*PromoCode, error) {
+	p, err := s.storage.FindByCode(code)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find promo code: %v", err)
+		}
+		return nil, err
+	}
+
+	if !p.IsActive(time.Now()) {
+		return nil, apperror.ErrPromoInactive
+	}
+
+	if p.UsageLimit == nil && p.PerUserLimit == nil {
+		return p, nil
+	}
+
+	total, byUser, err := s.storage.CountRedemptions(p.Id, userId)
+	if err != nil {
+		s.logger.Warnf("cannot count promo code redemptions: %v", err)
+		return nil, err
+	}
+
+	if p.UsageLimit != nil && total >= int64(*p.UsageLimit) ||
+		p.PerUserLimit != nil && byUser >= int64(*p.PerUserLimit) {
+		return nil, apperror.ErrPromoLimitReached
+	}
+
+	return p, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	if err := s.storage.Delete(id); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete promo code: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package promo
+
+// Storage describes a promo code storage functionality.
+type Storage interface {
+	Create(code *PromoCode) (*PromoCode, error)
+	FindById(id int64) (*PromoCode, error)
+	FindByCode(code string) (*PromoCode, error)
+	FindByIds(ids []int64) ([]*PromoCode, error)
+	FindAll(limit, offset int) ([]*PromoCode, error)
+	CountRedemptions(id, userId int64) (total, byUser int64, err error)
+	Delete(id int64) error
+}
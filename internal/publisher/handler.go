@@ -0,0 +1,237 @@
+package publisher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	publishersURL = "/api/publishers"
+	publisherURL  = "/api/publishers/:id"
+)
+
+// Handler handles requests specified to publisher service.
+type Handler struct {
+	logger           logger.Logger
+	publisherService Service
+}
+
+// NewHandler returns a new publisher Handler instance.
+func NewHandler(logger logger.Logger, publisherService Service) handler.Handling {
+	return &Handler{
+		logger:           logger,
+		publisherService: publisherService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, publisherURL, h.GetPublisher)
+	router.HandlerFunc(http.MethodGet, publishersURL, h.ListPublishers)
+	router.HandlerFunc(http.MethodPost, publishersURL, h.CreatePublisher)
+	router.HandlerFunc(http.MethodPut, publisherURL, h.UpdatePublisher)
+	router.HandlerFunc(http.MethodDelete, publisherURL, h.DeletePublisher)
+}
+
+// GetPublisher godoc
+// @Summary Show publisher information
+// @Description Get publisher by id.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Publisher id"
+// @Success 200 {object} Publisher
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /publishers/{id} [get]
+func (h *Handler) GetPublisher(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET PUBLISHER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	publisher, err := h.publisherService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		h.logger.Error(err)
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, publisher)
+}
+
+// ListPublishers godoc
+// @Summary List publishers
+// @Description Get publishers with the number of books of each one. Optionally filtered by name.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param search query string false "Part of the publisher name"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Publisher
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /publishers [get]
+func (h *Handler) ListPublishers(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST PUBLISHERS")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+
+	publishers, err := h.publisherService.GetAll(r.Context(), search, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, publishers)
+}
+
+// CreatePublisher godoc
+// @Summary Create publisher
+// @Description Insert publisher in database.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param input body CreatePublisherDTO true "JSON input"
+// @Success 201 {object} Publisher
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /publishers [post]
+func (h *Handler) CreatePublisher(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE PUBLISHER")
+
+	var input CreatePublisherDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	publisher, err := h.publisherService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrPublisherExists) {
+			response.Conflict(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, fmt.Sprintf("cannot create publisher: %v", err), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, publisher)
+}
+
+// UpdatePublisher godoc
+// @Summary Update publisher
+// @Description Update publisher with specified id.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Publisher id"
+// @Param input body UpdatePublisherDTO true "JSON input"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /publishers/{id} [put]
+func (h *Handler) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE PUBLISHER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input UpdatePublisherDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), "please, fix your request body")
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	input.Id = id
+
+	err = h.publisherService.Update(r.Context(), &input)
+	if err != nil {
+		switch err {
+		case apperror.ErrNoRows:
+			response.NotFound(w)
+		case apperror.ErrPublisherExists:
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletePublisher godoc
+// @Summary Delete publisher
+// @Description Delete publisher with specified id.
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Publisher id"
+// @Success 200
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /publishers/{id} [delete]
+func (h *Handler) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE PUBLISHER")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	err = h.publisherService.Delete(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		if errors.Is(err, apperror.ErrReferenced) {
+			response.Conflict(w, err.Error(), "delete or reassign books of this publisher first")
+			return
+		}
+		response.InternalError(w, err.Error(), "something went wrong on the server side")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+)
+
+// Publisher represents the publisher model.
+type Publisher struct {
+	Id         int64   `json:"id" example:"123"`
+	Name       string  `json:"name" example:"Penguin Books"`
+	Country    *string `json:"country,omitempty" example:"United Kingdom"`
+	Website    *string `json:"website,omitempty" example:"https://www.penguin.co.uk"`
+	BooksCount *int64  `json:"booksCount,omitempty" example:"42"`
+} // @name Publisher
+
+// CreatePublisherDTO is used to create publisher.
+type CreatePublisherDTO struct {
+	Name    string  `json:"name" example:"Penguin Books"`
+	Country *string `json:"country,omitempty" example:"United Kingdom"`
+	Website *string `json:"website,omitempty" example:"https://www.penguin.co.uk"`
+} // @name CreatePublisherInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (p *CreatePublisherDTO) Validate() error {
+	return validation.ValidateStruct(
+		p,
+		validation.Field(
+			&p.Name,
+			validation.RuneLength(1, 100),
+			validation.Required,
+		),
+		validation.Field(&p.Country, validator.Name, validation.RuneLength(2, 60)),
+		validation.Field(&p.Website, is.URL, validation.Length(1, 500)),
+	)
+}
+
+// UpdatePublisherDTO is used to update publisher record.
+type UpdatePublisherDTO struct {
+	Id      int64   `json:"-"`
+	Name    string  `json:"name" example:"Penguin Books"`
+	Country *string `json:"country" example:"United Kingdom"`
+	Website *string `json:"website" example:"https://www.penguin.co.uk"`
+} // @name UpdatePublisherInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (p *UpdatePublisherDTO) Validate() error {
+	return validation.ValidateStruct(
+		p,
+		validation.Field(&p.Name, validation.RuneLength(1, 100), validation.Required),
+		validation.Field(&p.Country, validator.Name, validation.RuneLength(2, 60)),
+		validation.Field(&p.Website, is.URL, validation.Length(1, 500)),
+	)
+}
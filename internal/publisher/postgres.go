@@ -0,0 +1,211 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName      = "publishers"
+	booksTableName = "books"
+)
+
+// Check whether db implements publisher storage interface.
+var _ Storage = &db{}
+
+// db implements publisher storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new publisher storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts a publisher record in the database.
+// Returns ErrPublisherExists if publisher with this name already exists,
+// an error on failure or inserted publisher with it's id on success.
+func (d *db) Create(publisher *Publisher) (*Publisher, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (name, country, website)
+	VALUES ($1, $2, $3)
+	RETURNING id`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(
+		ctx,
+		query,
+		publisher.Name,
+		publisher.Country,
+		publisher.Website,
+	).Scan(&publisher.Id)
+
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrPublisherExists
+		}
+		err = fmt.Errorf("failed to execute create publisher query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+// FindById finds the publisher with specified id.
+// Returns ErrNoRows if publisher doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Publisher, error) {
+	query := fmt.Sprintf(`
+	SELECT id, name, country, website
+	FROM %s 
+	WHERE id = $1`, tableName)
+
+	var found Publisher
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(ctx, query, id).Scan(
+		&found.Id,
+		&found.Name,
+		&found.Country,
+		&found.Website,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find publisher by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+// Update updates the publisher with specified values.
+// Returns ErrNoRows if publisher doesn't exist, ErrPublisherExists if
+// the name is taken by another publisher or an error on failure.
+func (d *db) Update(publisher *UpdatePublisherDTO) error {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET name = $1, country = $2, website = $3
+	WHERE id = $4`, tableName)
+
+	args := []interface{}{
+		publisher.Name,
+		publisher.Country,
+		publisher.Website,
+		publisher.Id,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, args...)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return apperror.ErrPublisherExists
+		}
+		err = fmt.Errorf("failed to execute update publisher query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindAll returns publishers which names contain given search string
+// along with the number of books for each of them.
+// Returns an error on failure.
+func (d *db) FindAll(search string, limit, offset int) ([]*Publisher, error) {
+	query := fmt.Sprintf(`
+	SELECT t.id, t.name, t.country, t.website, COUNT(b.id)
+	FROM %s t
+	LEFT JOIN %s b ON b.publisher_id = t.id
+	WHERE t.name ILIKE '%%' || $1 || '%%'
+	GROUP BY t.id
+	ORDER BY t.name
+	LIMIT $2 OFFSET $3`, tableName, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, search, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all publishers query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	publishers := make([]*Publisher, 0)
+	for rows.Next() {
+		var publisher Publisher
+		if err := rows.Scan(
+			&publisher.Id,
+			&publisher.Name,
+			&publisher.Country,
+			&publisher.Website,
+			&publisher.BooksCount,
+		); err != nil {
+			err = fmt.Errorf("failed to scan publisher: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		publishers = append(publishers, &publisher)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read publishers: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return publishers, nil
+}
+
+// Delete deletes the publisher with specified id.
+// Returns ErrNoRows if publisher doesn't exist, ErrReferenced if
+// there are books of this publisher or an error on failure.
+func (d *db) Delete(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrReferenced
+		}
+		return fmt.Errorf("failed to delete publisher: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
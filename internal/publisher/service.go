@@ -0,0 +1,106 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes publisher service functionality.
+type Service interface {
+	Create(ctx context.Context, publisher *CreatePublisherDTO) (*Publisher, error)
+	GetById(ctx context.Context, id int64) (*Publisher, error)
+	GetAll(ctx context.Context, search string, limit, offset int) ([]*Publisher, error)
+	Update(ctx context.Context, publisher *UpdatePublisherDTO) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) Create(ctx context.Context, input *CreatePublisherDTO) (*Publisher, error) {
+	p := Publisher{
+		Name:    input.Name,
+		Country: input.Country,
+		Website: input.Website,
+	}
+
+	publisher, err := s.storage.Create(&p)
+	if err != nil {
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Publisher, error) {
+	publisher, err := s.storage.FindById(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			return nil, err
+		}
+		s.logger.Warnf("cannot find publisher by id: %v", err)
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+func (s *service) GetAll(ctx context.Context, search string, limit, offset int) ([]*Publisher, error) {
+	publishers, err := s.storage.FindAll(search, limit, offset)
+	if err != nil {
+		s.logger.Warnf("cannot find publishers: %v", err)
+		return nil, err
+	}
+
+	return publishers, nil
+}
+
+func (s *service) Update(ctx context.Context, publisher *UpdatePublisherDTO) error {
+	a, err := s.GetById(ctx, publisher.Id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to get publisher: %v", err)
+		}
+		return err
+	}
+
+	if a == nil {
+		return apperror.ErrNoRows
+	}
+
+	err = s.storage.Update(publisher)
+	if err != nil {
+		if errors.Is(err, apperror.ErrPublisherExists) {
+			return err
+		}
+		s.logger.Errorf("failed to update publisher: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	err := s.storage.Delete(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrReferenced) {
+			s.logger.Warnf("failed to delete publisher: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package publisher
+
+// Storage descibes a publisher storage functionality.
+type Storage interface {
+	Create(publisher *Publisher) (*Publisher, error)
+	FindById(id int64) (*Publisher, error)
+	FindAll(search string, limit, offset int) ([]*Publisher, error)
+	Update(publisher *UpdatePublisherDTO) error
+	Delete(id int64) error
+}
@@ -0,0 +1,153 @@
+package recommendation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	bookRecommendationsURL = "/api/books/:id/recommendations"
+	userRecommendationsURL = "/api/users/:id/recommendations"
+	refreshURL             = "/api/recommendations/refresh"
+)
+
+// Handler handles requests specified to recommendation service.
+type Handler struct {
+	logger                logger.Logger
+	recommendationService Service
+}
+
+// NewHandler returns a new recommendation Handler instance.
+func NewHandler(logger logger.Logger, recommendationService Service) handler.Handling {
+	return &Handler{
+		logger:                logger,
+		recommendationService: recommendationService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, bookRecommendationsURL, h.ListBookRecommendations)
+	router.HandlerFunc(http.MethodGet, userRecommendationsURL, h.ListUserRecommendations)
+	router.HandlerFunc(http.MethodPost, refreshURL, h.RefreshRecommendations)
+}
+
+// ListBookRecommendations godoc
+// @Summary List book recommendations
+// @Description Get books customers also bought with the book, ranked by the number of paid orders they share. Books sharing authors, genres and language with the book fill up the list, so books with few orders get recommendations too. Recommendations are refreshed periodically.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param limit query int false "Number of recommendations" default(10)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} Recommendation
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/recommendations [get]
+func (h *Handler) ListBookRecommendations(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BOOK RECOMMENDATIONS")
+
+	bookId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	limit, err := readLimit(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	recommendations, err := h.recommendationService.GetByBook(ctx, bookId, limit)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, recommendations)
+}
+
+// ListUserRecommendations godoc
+// @Summary List user recommendations
+// @Description Get the personal feed of the user built from books recommended with books the user bought. Books of works the user bought are left out. The feed is empty until the user has paid orders.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param limit query int false "Number of recommendations" default(10)
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {array} Recommendation
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/recommendations [get]
+func (h *Handler) ListUserRecommendations(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST USER RECOMMENDATIONS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	limit, err := readLimit(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	recommendations, err := h.recommendationService.GetForUser(ctx, userId, limit)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, recommendations)
+}
+
+// RefreshRecommendations godoc
+// @Summary Refresh recommendations
+// @Description Recompute recommendations of all books and personal feeds now instead of waiting for the refresher. Admin only.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Success 200 {object} Summary
+// @Failure 500 {object} apperror.AppError
+// @Router /recommendations/refresh [post]
+func (h *Handler) RefreshRecommendations(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REFRESH RECOMMENDATIONS")
+
+	summary, err := h.recommendationService.Refresh(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, summary)
+}
+
+// readLimit reads the limit query parameter, DefaultLimit if it's missing.
+// Returns an error if it's not a positive integer up to MaxLimit.
+func readLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be an integer from 1 to %d", MaxLimit)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,33 @@
+package recommendation
+
+import "github.com/juicyluv/ReadyRead/internal/book"
+
+const (
+	// KindBoughtTogether is a kind of recommendations of books bought
+	// in the same orders as the book.
+	KindBoughtTogether = "bought_together"
+	// KindSimilar is a kind of recommendations of books sharing authors,
+	// genres and language with the book.
+	KindSimilar = "similar"
+
+	// DefaultLimit is the number of recommendations returned by default.
+	DefaultLimit = 10
+	// MaxLimit is the maximum number of recommendations returned at once.
+	MaxLimit = 50
+)
+
+// Recommendation represents the recommended book. Kind tells why the book
+// is recommended with another book, it's missing in personal feeds.
+// Score ranks recommendations of the same kind, higher is better.
+type Recommendation struct {
+	BookId int64      `json:"bookId" example:"124"`
+	Kind   string     `json:"kind,omitempty" example:"bought_together"`
+	Score  float64    `json:"score" example:"12"`
+	Book   *book.Book `json:"book"`
+} // @name Recommendation
+
+// Summary describes the refresh of recommendations.
+type Summary struct {
+	Books int64 `json:"books" example:"820"`
+	Users int64 `json:"users" example:"140"`
+} // @name RecommendationSummary
@@ -0,0 +1,245 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	booksTableName = "book_recommendations"
+	usersTableName = "user_recommendations"
+
+	// purchasesQuery selects distinct books of paid orders with their buyers.
+	purchasesQuery = `
+	SELECT DISTINCT o.id AS order_id, o.user_id, i.book_id
+	FROM orders o
+	JOIN order_items i ON i.order_id = o.id
+	WHERE o.status IN ('paid', 'shipped', 'delivered') AND i.book_id IS NOT NULL`
+
+	// boughtTogetherQuery ranks books by the number of paid orders they
+	// are bought in together with the book.
+	boughtTogetherQuery = `
+	WITH purchases AS (%[2]s),
+	pairs AS (
+		SELECT a.book_id, b.book_id AS recommended_book_id, COUNT(*) AS score
+		FROM purchases a
+		JOIN purchases b ON b.order_id = a.order_id AND b.book_id <> a.book_id
+		GROUP BY a.book_id, b.book_id
+	),
+	ranked AS (
+		SELECT p.*, row_number() OVER (
+			PARTITION BY p.book_id ORDER BY p.score DESC, p.recommended_book_id
+		) AS position
+		FROM pairs p
+	)
+	INSERT INTO %[1]s (book_id, recommended_book_id, kind, score, position)
+	SELECT book_id, recommended_book_id, 'bought_together', score, position
+	FROM ranked
+	WHERE position <= $1`
+
+	// similarQuery fills up recommendations of books with books which
+	// share authors and genres with them, ranked by 3 points for every
+	// shared author, 2 for every shared genre and 1 for the same language.
+	// Other editions of the book are left out.
+	similarQuery = `
+	WITH candidates AS (
+		SELECT x.book_id, y.book_id AS recommended_book_id, 3 AS weight
+		FROM book_authors x
+		JOIN book_authors y ON y.author_id = x.author_id AND y.book_id <> x.book_id
+		WHERE x.role = 'author' AND y.role = 'author'
+		UNION ALL
+		SELECT x.book_id, y.book_id, 2
+		FROM book_genres x
+		JOIN book_genres y ON y.genre_id = x.genre_id AND y.book_id <> x.book_id
+	),
+	scored AS (
+		SELECT c.book_id, c.recommended_book_id,
+			SUM(c.weight) + CASE WHEN a.language_id = b.language_id THEN 1 ELSE 0 END AS score
+		FROM candidates c
+		JOIN books a ON a.id = c.book_id
+		JOIN books b ON b.id = c.recommended_book_id
+		WHERE a.work_id <> b.work_id
+		GROUP BY c.book_id, c.recommended_book_id, a.language_id, b.language_id
+	),
+	taken AS (
+		SELECT book_id, COUNT(*) AS count FROM %[1]s GROUP BY book_id
+	),
+	ranked AS (
+		SELECT s.*, COALESCE(t.count, 0) + row_number() OVER (
+			PARTITION BY s.book_id ORDER BY s.score DESC, s.recommended_book_id
+		) AS position
+		FROM scored s
+		LEFT JOIN taken t ON t.book_id = s.book_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM %[1]s r
+			WHERE r.book_id = s.book_id AND r.recommended_book_id = s.recommended_book_id
+		)
+	)
+	INSERT INTO %[1]s (book_id, recommended_book_id, kind, score, position)
+	SELECT book_id, recommended_book_id, 'similar', score, position
+	FROM ranked
+	WHERE position <= $1`
+
+	// feedQuery ranks recommendations of books the user bought, every
+	// recommendation scores more the higher it's ranked. Editions of works
+	// the user bought are left out.
+	feedQuery = `
+	WITH purchases AS (%[3]s),
+	bought AS (
+		SELECT DISTINCT p.user_id, b.work_id
+		FROM purchases p
+		JOIN books b ON b.id = p.book_id
+	),
+	scored AS (
+		SELECT p.user_id, r.recommended_book_id AS book_id, SUM($1 + 1 - r.position) AS score
+		FROM (SELECT DISTINCT user_id, book_id FROM purchases) p
+		JOIN %[2]s r ON r.book_id = p.book_id
+		JOIN books b ON b.id = r.recommended_book_id
+		WHERE NOT EXISTS (SELECT 1 FROM bought w WHERE w.user_id = p.user_id AND w.work_id = b.work_id)
+		GROUP BY p.user_id, r.recommended_book_id
+	),
+	ranked AS (
+		SELECT s.*, row_number() OVER (
+			PARTITION BY s.user_id ORDER BY s.score DESC, s.book_id
+		) AS position
+		FROM scored s
+	)
+	INSERT INTO %[1]s (user_id, book_id, score, position)
+	SELECT user_id, book_id, score, position
+	FROM ranked
+	WHERE position <= $1`
+)
+
+// Check whether db implements recommendation storage interface.
+var _ Storage = &db{}
+
+// db implements recommendation storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new recommendation storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Refresh recomputes up to size recommendations of every book and
+// personal feeds of size books of every user who has paid orders.
+// Recommendations are replaced at once, so they are read either old or new.
+// Returns an error on failure or the summary of the refresh on success.
+func (d *db) Refresh(size int) (*Summary, error) {
+	queries := []struct {
+		name  string
+		query string
+		args  []interface{}
+	}{
+		{"delete user recommendations", fmt.Sprintf("DELETE FROM %s", usersTableName), nil},
+		{"delete book recommendations", fmt.Sprintf("DELETE FROM %s", booksTableName), nil},
+		{"bought together", fmt.Sprintf(boughtTogetherQuery, booksTableName, purchasesQuery), []interface{}{size}},
+		{"similar books", fmt.Sprintf(similarQuery, booksTableName), []interface{}{size}},
+		{"personal feeds", fmt.Sprintf(feedQuery, usersTableName, booksTableName, purchasesQuery), []interface{}{size}},
+	}
+
+	summaryQuery := fmt.Sprintf(`
+	SELECT (SELECT COUNT(DISTINCT book_id) FROM %s), (SELECT COUNT(DISTINCT user_id) FROM %s)`,
+		booksTableName, usersTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, q := range queries {
+		if _, err := tx.Exec(ctx, q.query, q.args...); err != nil {
+			err = fmt.Errorf("failed to execute %s query: %v", q.name, err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	var summary Summary
+	if err := tx.QueryRow(ctx, summaryQuery).Scan(&summary.Books, &summary.Users); err != nil {
+		err = fmt.Errorf("failed to execute recommendations summary query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &summary, nil
+}
+
+// FindByBook finds up to limit recommendations of the book, best first.
+// Returns an error on failure.
+func (d *db) FindByBook(bookId int64, limit int) ([]*Recommendation, error) {
+	query := fmt.Sprintf(`
+	SELECT recommended_book_id, kind, score
+	FROM %s
+	WHERE book_id = $1
+	ORDER BY position
+	LIMIT $2`, booksTableName)
+
+	return d.find("book recommendations", query, bookId, limit)
+}
+
+// FindByUser finds up to limit books of the personal feed of the user,
+// best first. Returns an error on failure.
+func (d *db) FindByUser(userId int64, limit int) ([]*Recommendation, error) {
+	query := fmt.Sprintf(`
+	SELECT book_id, '', score
+	FROM %s
+	WHERE user_id = $1
+	ORDER BY position
+	LIMIT $2`, usersTableName)
+
+	return d.find("user recommendations", query, userId, limit)
+}
+
+// find finds recommendations by the query.
+func (d *db) find(name, query string, args ...interface{}) ([]*Recommendation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find %s query: %v", name, err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	recommendations := make([]*Recommendation, 0)
+	for rows.Next() {
+		var r Recommendation
+		if err := rows.Scan(&r.BookId, &r.Kind, &r.Score); err != nil {
+			err = fmt.Errorf("failed to scan recommendation: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		recommendations = append(recommendations, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read %s: %v", name, err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return recommendations, nil
+}
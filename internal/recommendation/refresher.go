@@ -0,0 +1,45 @@
+package recommendation
+
+import (
+	"context"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Refresher periodically recomputes recommendations, so reading them
+// costs a lookup.
+type Refresher struct {
+	logger                logger.Logger
+	recommendationService Service
+	interval              time.Duration
+}
+
+// NewRefresher returns a new Refresher which runs every interval.
+func NewRefresher(logger logger.Logger, recommendationService Service, interval time.Duration) *Refresher {
+	return &Refresher{
+		logger:                logger,
+		recommendationService: recommendationService,
+		interval:              interval,
+	}
+}
+
+// Run refreshes recommendations right away and then every interval until
+// the context is done. Failed runs are logged and retried on the next tick.
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		summary, err := r.recommendationService.Refresh(ctx)
+		if err == nil {
+			r.logger.Infof("refreshed recommendations of %d books and %d users", summary.Books, summary.Users)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
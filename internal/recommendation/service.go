@@ -0,0 +1,104 @@
+package recommendation
+
+import (
+	"context"
+
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes recommendation service functionality.
+type Service interface {
+	GetByBook(ctx context.Context, bookId int64, limit int) ([]*Recommendation, error)
+	GetForUser(ctx context.Context, userId int64, limit int) ([]*Recommendation, error)
+	Refresh(ctx context.Context) (*Summary, error)
+}
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	bookService book.Service
+	size        int
+}
+
+// NewService returns a new instance that implements Service interface.
+// Up to size recommendations of every book and books of every personal
+// feed are precomputed by Refresh.
+func NewService(storage Storage, bookService book.Service, size int, logger logger.Logger) Service {
+	return &service{
+		logger:      logger,
+		storage:     storage,
+		bookService: bookService,
+		size:        size,
+	}
+}
+
+// GetByBook returns up to limit books recommended with the book, books
+// bought together with it first. Books are returned with prices in the
+// currency accepted by the client.
+func (s *service) GetByBook(ctx context.Context, bookId int64, limit int) ([]*Recommendation, error) {
+	recommendations, err := s.storage.FindByBook(bookId, limit)
+	if err != nil {
+		s.logger.Warnf("cannot find book recommendations: %v", err)
+		return nil, err
+	}
+
+	return s.loadBooks(ctx, recommendations)
+}
+
+// GetForUser returns up to limit books of the personal feed of the user,
+// built from books recommended with books the user bought. The feed is
+// empty until the user has paid orders.
+func (s *service) GetForUser(ctx context.Context, userId int64, limit int) ([]*Recommendation, error) {
+	recommendations, err := s.storage.FindByUser(userId, limit)
+	if err != nil {
+		s.logger.Warnf("cannot find user recommendations: %v", err)
+		return nil, err
+	}
+
+	return s.loadBooks(ctx, recommendations)
+}
+
+// Refresh recomputes recommendations of all books and personal feeds.
+func (s *service) Refresh(ctx context.Context) (*Summary, error) {
+	summary, err := s.storage.Refresh(s.size)
+	if err != nil {
+		s.logger.Errorf("failed to refresh recommendations: %v", err)
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// loadBooks sets books of recommendations. Recommendations of books which
+// are deleted since the last refresh are left out.
+func (s *service) loadBooks(ctx context.Context, recommendations []*Recommendation) ([]*Recommendation, error) {
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+
+	ids := make([]int64, 0, len(recommendations))
+	for _, r := range recommendations {
+		ids = append(ids, r.BookId)
+	}
+
+	books, err := s.bookService.GetAll(ctx, &book.Filter{Ids: ids, Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int64]*book.Book, len(books))
+	for _, b := range books {
+		byId[b.Id] = b
+	}
+
+	loaded := make([]*Recommendation, 0, len(recommendations))
+	for _, r := range recommendations {
+		if b, ok := byId[r.BookId]; ok {
+			r.Book = b
+			loaded = append(loaded, r)
+		}
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,8 @@
+package recommendation
+
+// Storage describes a recommendation storage functionality.
+type Storage interface {
+	Refresh(size int) (*Summary, error)
+	FindByBook(bookId int64, limit int) ([]*Recommendation, error)
+	FindByUser(userId int64, limit int) ([]*Recommendation, error)
+}
@@ -25,8 +25,20 @@ func NotFound(w http.ResponseWriter) {
 	JSON(w, http.StatusNotFound, apperror.ErrNotFound)
 }
 
+// Conflict is a wrapper around Error method.
+// Responses with 409 Conflict status code and specified error message.
+func Conflict(w http.ResponseWriter, message, developerMessage string) {
+	Error(w, http.StatusConflict, message, developerMessage)
+}
+
 // InternalError is a wrapper around Error method.
 // Responses with 500 Internal Server Error status code and specified error message.
 func InternalError(w http.ResponseWriter, message, developerMessage string) {
 	Error(w, http.StatusInternalServerError, message, developerMessage)
 }
+
+// TooLarge is a wrapper around Error method.
+// Responses with 413 Request Entity Too Large status code and specified error message.
+func TooLarge(w http.ResponseWriter, message, developerMessage string) {
+	Error(w, http.StatusRequestEntityTooLarge, message, developerMessage)
+}
@@ -0,0 +1,251 @@
+package restock
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	thresholdURL     = "/api/books/:id/stock/threshold"
+	alertsURL        = "/api/inventory/alerts"
+	subscriptionsURL = "/api/users/:id/restock-subscriptions"
+	subscriptionURL  = "/api/users/:id/restock-subscriptions/:bookId"
+)
+
+// Handler handles requests specified to restock service.
+type Handler struct {
+	logger         logger.Logger
+	restockService Service
+}
+
+// NewHandler returns a new restock Handler instance.
+func NewHandler(logger logger.Logger, restockService Service) handler.Handling {
+	return &Handler{
+		logger:         logger,
+		restockService: restockService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPut, thresholdURL, h.SetThreshold)
+	router.HandlerFunc(http.MethodGet, alertsURL, h.ListAlerts)
+	router.HandlerFunc(http.MethodGet, subscriptionsURL, h.ListSubscriptions)
+	router.HandlerFunc(http.MethodPost, subscriptionsURL, h.CreateSubscription)
+	router.HandlerFunc(http.MethodDelete, subscriptionURL, h.DeleteSubscription)
+}
+
+// SetThreshold godoc
+// @Summary Set reorder threshold
+// @Description Set the reorder threshold of the book. Staff are alerted when on-hand quantity drops to it. Null threshold disables alerts. Admin only.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param input body ThresholdDTO true "JSON input"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/stock/threshold [put]
+func (h *Handler) SetThreshold(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SET REORDER THRESHOLD")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ThresholdDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.BookId = id
+
+	if err := h.restockService.SetThreshold(r.Context(), &input); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListAlerts godoc
+// @Summary List low-stock alerts
+// @Description Get low-stock alerts, newest first. Admin only.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param status query string false "Alert status" Enums(open, resolved)
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Alert
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /inventory/alerts [get]
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST STOCK ALERTS")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != AlertsOpen && status != AlertsResolved {
+		response.BadRequest(w, "status must be open or resolved", "")
+		return
+	}
+
+	alerts, err := h.restockService.GetAlerts(r.Context(), &AlertFilter{
+		Status: status,
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	})
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, alerts)
+}
+
+// ListSubscriptions godoc
+// @Summary List restock subscriptions
+// @Description Get books the user waits to be back in stock.
+// @Tags restock
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Success 200 {array} Subscription
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/restock-subscriptions [get]
+func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST RESTOCK SUBSCRIPTIONS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	subscriptions, err := h.restockService.GetSubscriptions(r.Context(), userId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, subscriptions)
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe to restock
+// @Description Notify the user by email when the out-of-stock book is available again.
+// @Tags restock
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param input body SubscriptionDTO true "JSON input"
+// @Success 201 {object} Subscription
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/restock-subscriptions [post]
+func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE RESTOCK SUBSCRIPTION")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input SubscriptionDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+
+	subscription, err := h.restockService.Subscribe(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInStock):
+			response.Conflict(w, err.Error(), "the book can be ordered right now")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, subscription)
+}
+
+// DeleteSubscription godoc
+// @Summary Unsubscribe from restock
+// @Description Delete the pending restock subscription of the user to the book.
+// @Tags restock
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param bookId path int64 true "Book id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/restock-subscriptions/{bookId} [delete]
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE RESTOCK SUBSCRIPTION")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	bookId, err := handler.ReadParam64(r, "bookId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.restockService.Unsubscribe(r.Context(), userId, bookId); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
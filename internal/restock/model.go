@@ -0,0 +1,89 @@
+package restock
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// EventLowStock is a webhook event sent when the book is running low.
+	EventLowStock = "stock.low"
+
+	// AlertsOpen filters alerts which are not resolved yet.
+	AlertsOpen = "open"
+	// AlertsResolved filters alerts of restocked books.
+	AlertsResolved = "resolved"
+)
+
+// Alert is raised when on-hand quantity of the book drops to its reorder
+// threshold. The alert is resolved when the book is restocked above it.
+// Staff are notified when the alert is sent through every configured channel.
+type Alert struct {
+	Id         int64      `json:"id" example:"1"`
+	BookId     int64      `json:"bookId" example:"123"`
+	Title      string     `json:"title" example:"War and Peace"`
+	OnHand     int32      `json:"onHand" example:"2"`
+	Threshold  int32      `json:"threshold" example:"5"`
+	CreatedAt  time.Time  `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+	EmailedAt  *time.Time `json:"emailedAt,omitempty" example:"2022-03-01T12:01:00Z"`
+	PostedAt   *time.Time `json:"postedAt,omitempty" example:"2022-03-01T12:01:00Z"`
+	NotifiedAt *time.Time `json:"notifiedAt,omitempty" example:"2022-03-01T12:01:00Z"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" example:"2022-03-05T09:00:00Z"`
+} // @name StockAlert
+
+// Subscription asks to notify the user when the book is back in stock.
+type Subscription struct {
+	Id        int64     `json:"id" example:"1"`
+	BookId    int64     `json:"bookId" example:"123"`
+	Title     string    `json:"title" example:"War and Peace"`
+	UserId    int64     `json:"userId" example:"7"`
+	CreatedAt time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+} // @name RestockSubscription
+
+// DueSubscription is a subscription to the book which is back in stock.
+type DueSubscription struct {
+	Id       int64
+	BookId   int64
+	Title    string
+	Username string
+	Email    string
+}
+
+// AlertFilter is used to filter and paginate alerts list.
+type AlertFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// ThresholdDTO is used to set the reorder threshold of the book.
+// Null threshold disables low-stock alerts of the book.
+type ThresholdDTO struct {
+	BookId    int64  `json:"-"`
+	Threshold *int32 `json:"threshold" example:"5"`
+} // @name ReorderThresholdInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (t *ThresholdDTO) Validate() error {
+	return validation.ValidateStruct(
+		t,
+		validation.Field(&t.Threshold, validation.Min(0)),
+	)
+}
+
+// SubscriptionDTO is used to subscribe the user to restock of the book.
+type SubscriptionDTO struct {
+	UserId int64 `json:"-"`
+	BookId int64 `json:"bookId" example:"123"`
+} // @name RestockSubscriptionInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (s *SubscriptionDTO) Validate() error {
+	return validation.ValidateStruct(
+		s,
+		validation.Field(&s.BookId, validation.Required, validation.Min(1)),
+	)
+}
@@ -0,0 +1,42 @@
+package restock
+
+import (
+	"context"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Notifier periodically sends low-stock alerts and restock notifications.
+type Notifier struct {
+	logger         logger.Logger
+	restockService Service
+	interval       time.Duration
+}
+
+// NewNotifier returns a new Notifier which runs every interval.
+func NewNotifier(logger logger.Logger, restockService Service, interval time.Duration) *Notifier {
+	return &Notifier{
+		logger:         logger,
+		restockService: restockService,
+		interval:       interval,
+	}
+}
+
+// Run sends notifications until the context is done.
+// Failed runs are logged and retried on the next tick.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.restockService.Notify(ctx); err != nil {
+				n.logger.Errorf("failed to send stock notifications: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package restock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	alertsTableName        = "stock_alerts"
+	subscriptionsTableName = "restock_subscriptions"
+	booksTableName         = "books"
+
+	// dueSubscriptionsLimit is the maximum number of subscriptions notified at once.
+	dueSubscriptionsLimit = 500
+
+	// availableExpr is the number of book copies which are not reserved.
+	availableExpr = `(b.count - COALESCE((
+		SELECT SUM(r.quantity) FROM stock_reservations r
+		WHERE r.book_id = b.id AND r.expires_at > now()
+	), 0))`
+)
+
+// Check whether db implements restock storage interface.
+var _ Storage = &db{}
+
+// db implements restock storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new restock storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// SetThreshold sets the reorder threshold of the book with specified id.
+// Returns ErrNoRows if book doesn't exist or an error on failure.
+func (d *db) SetThreshold(bookId int64, threshold *int32) error {
+	query := fmt.Sprintf("UPDATE %s SET reorder_threshold = $1 WHERE id = $2", booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, threshold, bookId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute set reorder threshold query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// RaiseAlerts resolves alerts of books restocked above their thresholds and
+// raises alerts for books which on-hand quantity dropped to the threshold.
+// Returns an error on failure or open alerts which staff are not notified
+// about yet on success.
+func (d *db) RaiseAlerts() ([]*Alert, error) {
+	resolveQuery := fmt.Sprintf(`
+	UPDATE %s a SET resolved_at = now()
+	FROM %s b
+	WHERE b.id = a.book_id AND a.resolved_at IS NULL
+		AND (b.reorder_threshold IS NULL OR b.count > b.reorder_threshold)`, alertsTableName, booksTableName)
+
+	raiseQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, on_hand, threshold)
+	SELECT b.id, b.count, b.reorder_threshold
+	FROM %s b
+	WHERE b.count <= b.reorder_threshold
+		AND NOT EXISTS (SELECT 1 FROM %s a WHERE a.book_id = b.id AND a.resolved_at IS NULL)`,
+		alertsTableName, booksTableName, alertsTableName)
+
+	pendingQuery := fmt.Sprintf(`
+	SELECT a.id, a.book_id, b.title, a.on_hand, a.threshold, a.created_at,
+		a.emailed_at, a.posted_at, a.notified_at, a.resolved_at
+	FROM %s a
+	JOIN %s b ON b.id = a.book_id
+	WHERE a.resolved_at IS NULL AND a.notified_at IS NULL
+	ORDER BY a.id`, alertsTableName, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, query := range []string{resolveQuery, raiseQuery} {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			err = fmt.Errorf("failed to execute raise stock alerts query: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, pendingQuery)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find pending alerts query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	alerts, err := scanAlerts(rows)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return alerts, nil
+}
+
+// MarkAlertEmailed marks the alert as mailed to staff.
+// Returns an error on failure.
+func (d *db) MarkAlertEmailed(id int64) error {
+	return d.markAlert(id, "emailed_at")
+}
+
+// MarkAlertPosted marks the alert as posted to the webhook.
+// Returns an error on failure.
+func (d *db) MarkAlertPosted(id int64) error {
+	return d.markAlert(id, "posted_at")
+}
+
+// MarkAlertNotified marks the alert as sent to staff through all channels.
+// Returns an error on failure.
+func (d *db) MarkAlertNotified(id int64) error {
+	return d.markAlert(id, "notified_at")
+}
+
+// markAlert sets the time column of the alert to now.
+// Returns an error on failure.
+func (d *db) markAlert(id int64, column string) error {
+	query := fmt.Sprintf("UPDATE %s SET %s = now() WHERE id = $1", alertsTableName, column)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to set %s of alert: %v", column, err)
+	}
+
+	return nil
+}
+
+// FindAlerts finds alerts which match the given filter, newest first.
+// Returns an error on failure.
+func (d *db) FindAlerts(filter *AlertFilter) ([]*Alert, error) {
+	var where string
+	switch filter.Status {
+	case AlertsOpen:
+		where = "WHERE a.resolved_at IS NULL"
+	case AlertsResolved:
+		where = "WHERE a.resolved_at IS NOT NULL"
+	}
+
+	query := fmt.Sprintf(`
+	SELECT a.id, a.book_id, b.title, a.on_hand, a.threshold, a.created_at,
+		a.emailed_at, a.posted_at, a.notified_at, a.resolved_at
+	FROM %s a
+	JOIN %s b ON b.id = a.book_id
+	%s
+	ORDER BY a.id DESC
+	LIMIT $1 OFFSET $2`, alertsTableName, booksTableName, where)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, filter.Limit, filter.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find alerts query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	alerts, err := scanAlerts(rows)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// CreateSubscription subscribes the user to restock of the book.
+// Subscribing twice returns the pending subscription. Returns ErrNoRows if
+// the user or the book doesn't exist, ErrInStock if the book is available,
+// an error on failure or the subscription on success.
+func (d *db) CreateSubscription(userId, bookId int64) (*Subscription, error) {
+	bookQuery := fmt.Sprintf("SELECT b.title, %s::int FROM %s b WHERE b.id = $1", availableExpr, booksTableName)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (book_id, user_id)
+	VALUES ($1, $2)
+	ON CONFLICT (book_id, user_id) WHERE notified_at IS NULL DO NOTHING
+	RETURNING id, created_at`, subscriptionsTableName)
+
+	existingQuery := fmt.Sprintf(`
+	SELECT id, created_at FROM %s
+	WHERE book_id = $1 AND user_id = $2 AND notified_at IS NULL`, subscriptionsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	subscription := Subscription{
+		BookId: bookId,
+		UserId: userId,
+	}
+
+	var available int32
+	if err := tx.QueryRow(ctx, bookQuery, bookId).Scan(&subscription.Title, &available); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to find book: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if available > 0 {
+		return nil, apperror.ErrInStock
+	}
+
+	err = tx.QueryRow(ctx, insertQuery, bookId, userId).Scan(&subscription.Id, &subscription.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, existingQuery, bookId, userId).Scan(&subscription.Id, &subscription.CreatedAt)
+	}
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create subscription query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &subscription, nil
+}
+
+// FindSubscriptions finds pending subscriptions of the user.
+// Returns an error on failure.
+func (d *db) FindSubscriptions(userId int64) ([]*Subscription, error) {
+	query := fmt.Sprintf(`
+	SELECT s.id, s.book_id, b.title, s.user_id, s.created_at
+	FROM %s s
+	JOIN %s b ON b.id = s.book_id
+	WHERE s.user_id = $1 AND s.notified_at IS NULL
+	ORDER BY s.id`, subscriptionsTableName, booksTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find subscriptions query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*Subscription, 0)
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.Id, &s.BookId, &s.Title, &s.UserId, &s.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to scan subscription: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read subscriptions: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes the pending subscription of the user to the book.
+// Returns ErrNoRows if there is no such subscription or an error on failure.
+func (d *db) DeleteSubscription(userId, bookId int64) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE user_id = $1 AND book_id = $2 AND notified_at IS NULL`, subscriptionsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, userId, bookId)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindDueSubscriptions finds pending subscriptions to books which are available again.
+// Returns an error on failure.
+func (d *db) FindDueSubscriptions() ([]*DueSubscription, error) {
+	query := fmt.Sprintf(`
+	SELECT s.id, s.book_id, b.title, u.username, u.email
+	FROM %s s
+	JOIN %s b ON b.id = s.book_id
+	JOIN users u ON u.id = s.user_id
+	WHERE s.notified_at IS NULL AND %s > 0
+	ORDER BY s.id
+	LIMIT %d`, subscriptionsTableName, booksTableName, availableExpr, dueSubscriptionsLimit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find due subscriptions query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*DueSubscription, 0)
+	for rows.Next() {
+		var s DueSubscription
+		if err := rows.Scan(&s.Id, &s.BookId, &s.Title, &s.Username, &s.Email); err != nil {
+			err = fmt.Errorf("failed to scan due subscription: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read due subscriptions: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// MarkSubscriptionNotified marks the subscription as fired.
+// Returns an error on failure.
+func (d *db) MarkSubscriptionNotified(id int64) error {
+	query := fmt.Sprintf("UPDATE %s SET notified_at = now() WHERE id = $1", subscriptionsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	if _, err := d.conn.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark subscription as notified: %v", err)
+	}
+
+	return nil
+}
+
+// scanAlerts scans and closes rows of alerts.
+func scanAlerts(rows pgx.Rows) ([]*Alert, error) {
+	defer rows.Close()
+
+	alerts := make([]*Alert, 0)
+	for rows.Next() {
+		var a Alert
+		err := rows.Scan(
+			&a.Id,
+			&a.BookId,
+			&a.Title,
+			&a.OnHand,
+			&a.Threshold,
+			&a.CreatedAt,
+			&a.EmailedAt,
+			&a.PostedAt,
+			&a.NotifiedAt,
+			&a.ResolvedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %v", err)
+		}
+		alerts = append(alerts, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alerts: %v", err)
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,186 @@
+package restock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/mailer"
+	"github.com/juicyluv/ReadyRead/internal/webhook"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes restock service functionality.
+type Service interface {
+	SetThreshold(ctx context.Context, input *ThresholdDTO) error
+	GetAlerts(ctx context.Context, filter *AlertFilter) ([]*Alert, error)
+	Subscribe(ctx context.Context, input *SubscriptionDTO) (*Subscription, error)
+	GetSubscriptions(ctx context.Context, userId int64) ([]*Subscription, error)
+	Unsubscribe(ctx context.Context, userId, bookId int64) error
+	Notify(ctx context.Context) error
+}
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	mailer      mailer.Mailer
+	staffEmails []string
+	webhook     *webhook.Client
+}
+
+// NewService returns a new instance that implements Service interface.
+// Low-stock alerts are mailed to staff emails and posted to the webhook,
+// if they are configured. Webhook may be nil.
+func NewService(
+	storage Storage,
+	mailer mailer.Mailer,
+	staffEmails []string,
+	webhook *webhook.Client,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:      logger,
+		storage:     storage,
+		mailer:      mailer,
+		staffEmails: staffEmails,
+		webhook:     webhook,
+	}
+}
+
+func (s *service) SetThreshold(ctx context.Context, input *ThresholdDTO) error {
+	err := s.storage.SetThreshold(input.BookId, input.Threshold)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set reorder threshold: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetAlerts(ctx context.Context, filter *AlertFilter) ([]*Alert, error) {
+	alerts, err := s.storage.FindAlerts(filter)
+	if err != nil {
+		s.logger.Warnf("cannot find alerts: %v", err)
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (s *service) Subscribe(ctx context.Context, input *SubscriptionDTO) (*Subscription, error) {
+	subscription, err := s.storage.CreateSubscription(input.UserId, input.BookId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInStock) {
+			s.logger.Errorf("failed to create subscription: %v", err)
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *service) GetSubscriptions(ctx context.Context, userId int64) ([]*Subscription, error) {
+	subscriptions, err := s.storage.FindSubscriptions(userId)
+	if err != nil {
+		s.logger.Warnf("cannot find subscriptions: %v", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (s *service) Unsubscribe(ctx context.Context, userId, bookId int64) error {
+	err := s.storage.DeleteSubscription(userId, bookId)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("failed to delete subscription: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Notify raises low-stock alerts and notifies staff about them, then
+// notifies subscribers of books which are back in stock. Alerts and
+// subscriptions are marked only after they are sent, so failed ones
+// are retried on the next call.
+func (s *service) Notify(ctx context.Context) error {
+	alerts, err := s.storage.RaiseAlerts()
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		if err := s.sendAlert(ctx, alert); err != nil {
+			s.logger.Errorf("failed to send low-stock alert of book %d: %v", alert.BookId, err)
+			continue
+		}
+		if err := s.storage.MarkAlertNotified(alert.Id); err != nil {
+			return err
+		}
+	}
+
+	subscriptions, err := s.storage.FindDueSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		subject := fmt.Sprintf("%q is back in stock", subscription.Title)
+		body := fmt.Sprintf(
+			"Hi, %s!\n\n%q is available again. Order it before it's sold out.\n\nReadyRead",
+			subscription.Username,
+			subscription.Title,
+		)
+
+		if err := s.mailer.Send([]string{subscription.Email}, subject, body); err != nil {
+			s.logger.Errorf("failed to send restock notification of book %d: %v", subscription.BookId, err)
+			continue
+		}
+		if err := s.storage.MarkSubscriptionNotified(subscription.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendAlert sends the alert to staff through all configured channels.
+// Every channel is marked once the alert is sent through it, so a failing
+// channel doesn't make the others send the alert again. Returns the first
+// error if some channel failed.
+func (s *service) sendAlert(ctx context.Context, alert *Alert) error {
+	var sendErr error
+
+	if len(s.staffEmails) > 0 && alert.EmailedAt == nil {
+		subject := fmt.Sprintf("Low stock: %s", alert.Title)
+		body := fmt.Sprintf(
+			"%q (book %d) is running low: %d copies on hand, reorder threshold is %d.",
+			alert.Title,
+			alert.BookId,
+			alert.OnHand,
+			alert.Threshold,
+		)
+		if err := s.mailer.Send(s.staffEmails, subject, body); err != nil {
+			sendErr = fmt.Errorf("failed to mail alert: %v", err)
+		} else if err := s.storage.MarkAlertEmailed(alert.Id); err != nil {
+			return err
+		}
+	}
+
+	if s.webhook != nil && alert.PostedAt == nil {
+		if err := s.webhook.Post(ctx, EventLowStock, alert); err != nil {
+			if sendErr == nil {
+				sendErr = fmt.Errorf("failed to post alert: %v", err)
+			}
+		} else if err := s.storage.MarkAlertPosted(alert.Id); err != nil {
+			return err
+		}
+	}
+
+	return sendErr
+}
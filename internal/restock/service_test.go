@@ -0,0 +1,130 @@
+package restock
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juicyluv/ReadyRead/internal/webhook"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryStorage keeps alerts in memory. Methods of Storage
+// which are not used by Notify are not implemented.
+type memoryStorage struct {
+	Storage
+	alerts []*Alert
+}
+
+func (s *memoryStorage) RaiseAlerts() ([]*Alert, error) {
+	pending := make([]*Alert, 0)
+	for _, a := range s.alerts {
+		if a.NotifiedAt == nil {
+			copied := *a
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (s *memoryStorage) mark(id int64, set func(a *Alert, now *time.Time)) error {
+	now := time.Now()
+	for _, a := range s.alerts {
+		if a.Id == id {
+			set(a, &now)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStorage) MarkAlertEmailed(id int64) error {
+	return s.mark(id, func(a *Alert, now *time.Time) { a.EmailedAt = now })
+}
+
+func (s *memoryStorage) MarkAlertPosted(id int64) error {
+	return s.mark(id, func(a *Alert, now *time.Time) { a.PostedAt = now })
+}
+
+func (s *memoryStorage) MarkAlertNotified(id int64) error {
+	return s.mark(id, func(a *Alert, now *time.Time) { a.NotifiedAt = now })
+}
+
+func (s *memoryStorage) FindDueSubscriptions() ([]*DueSubscription, error) {
+	return nil, nil
+}
+
+// countingMailer counts sent emails and fails if err is set.
+type countingMailer struct {
+	sent int
+	err  error
+}
+
+func (m *countingMailer) Send(to []string, subject, body string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent++
+	return nil
+}
+
+func newTestLogger() logger.Logger {
+	l := logrus.New()
+	l.Out = ioutil.Discard
+	return logger.Logger{Entry: logrus.NewEntry(l)}
+}
+
+func TestNotifyRetriesFailedChannel(t *testing.T) {
+	tests := []struct {
+		name       string
+		mailErr    error
+		webhookErr bool
+		wantMails  int
+		wantPosts  int
+	}{
+		{name: "webhook fails", webhookErr: true, wantMails: 1, wantPosts: 2},
+		{name: "mail fails", mailErr: errors.New("smtp is down"), wantMails: 1, wantPosts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := 0
+			failing := tt.webhookErr
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				posts++
+				if failing {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer srv.Close()
+
+			storage := &memoryStorage{alerts: []*Alert{{Id: 1, BookId: 7, Title: "Dune", OnHand: 1, Threshold: 2}}}
+			mailer := &countingMailer{err: tt.mailErr}
+			s := NewService(storage, mailer, []string{"staff@readyread.com"}, webhook.NewClient(srv.URL, ""), newTestLogger())
+
+			if err := s.Notify(context.Background()); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if storage.alerts[0].NotifiedAt != nil {
+				t.Fatal("alert is notified after a channel failed")
+			}
+
+			failing = false
+			mailer.err = nil
+			if err := s.Notify(context.Background()); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+
+			if storage.alerts[0].NotifiedAt == nil {
+				t.Error("alert is not notified after all channels succeeded")
+			}
+			if mailer.sent != tt.wantMails || posts != tt.wantPosts {
+				t.Errorf("sent %d emails and %d posts, want %d and %d", mailer.sent, posts, tt.wantMails, tt.wantPosts)
+			}
+		})
+	}
+}
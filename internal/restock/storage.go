@@ -0,0 +1,16 @@
+package restock
+
+// Storage describes a restock storage functionality.
+type Storage interface {
+	SetThreshold(bookId int64, threshold *int32) error
+	RaiseAlerts() ([]*Alert, error)
+	MarkAlertEmailed(id int64) error
+	MarkAlertPosted(id int64) error
+	MarkAlertNotified(id int64) error
+	FindAlerts(filter *AlertFilter) ([]*Alert, error)
+	CreateSubscription(userId, bookId int64) (*Subscription, error)
+	FindSubscriptions(userId int64) ([]*Subscription, error)
+	DeleteSubscription(userId, bookId int64) error
+	FindDueSubscriptions() ([]*DueSubscription, error)
+	MarkSubscriptionNotified(id int64) error
+}
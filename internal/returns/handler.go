@@ -0,0 +1,254 @@
+package returns
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	orderReturnsURL = "/api/orders/:id/returns"
+	returnsURL      = "/api/returns"
+	returnURL       = "/api/returns/:id"
+	statusURL       = "/api/returns/:id/status"
+)
+
+// Handler handles requests specified to return service.
+type Handler struct {
+	logger        logger.Logger
+	returnService Service
+}
+
+// NewHandler returns a new return Handler instance.
+func NewHandler(logger logger.Logger, returnService Service) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		returnService: returnService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, orderReturnsURL, h.ListOrderReturns)
+	router.HandlerFunc(http.MethodPost, orderReturnsURL, h.CreateReturn)
+	router.HandlerFunc(http.MethodGet, returnsURL, h.ListReturns)
+	router.HandlerFunc(http.MethodGet, returnURL, h.GetReturn)
+	router.HandlerFunc(http.MethodPatch, statusURL, h.ChangeStatus)
+}
+
+// ListOrderReturns godoc
+// @Summary List order returns
+// @Description Get returns of the order with their items, newest first.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Success 200 {array} Return
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/returns [get]
+func (h *Handler) ListOrderReturns(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST ORDER RETURNS")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	returns, err := h.returnService.GetByOrder(r.Context(), orderId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, returns)
+}
+
+// CreateReturn godoc
+// @Summary Request return
+// @Description Request the return of copies of paid order lines with the reason. Digital copies cannot be returned. Staff approve or reject the request. The request is recorded in the order history.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Order id"
+// @Param input body CreateReturnDTO true "JSON input"
+// @Success 201 {object} Return
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /orders/{id}/returns [post]
+func (h *Handler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE RETURN")
+
+	orderId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input CreateReturnDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.OrderId = orderId
+
+	ret, err := h.returnService.Create(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrOrderNotReturnable):
+			response.Conflict(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrReturnQuantityExceeded),
+			errors.Is(err, apperror.ErrDigitalNotReturnable):
+			response.BadRequest(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, ret)
+}
+
+// ListReturns godoc
+// @Summary List returns
+// @Description Get returns of all orders, newest first. Admin only.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param status query string false "Return status" Enums(requested, approved, rejected, received)
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Return
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /returns [get]
+func (h *Handler) ListReturns(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST RETURNS")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if _, ok := events[status]; status != "" && !ok {
+		response.BadRequest(w, "status must be requested, approved, rejected or received", "")
+		return
+	}
+
+	returns, err := h.returnService.GetAll(r.Context(), &Filter{
+		Status: status,
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	})
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, returns)
+}
+
+// GetReturn godoc
+// @Summary Show return
+// @Description Get return by id with its items.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Return id"
+// @Success 200 {object} Return
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /returns/{id} [get]
+func (h *Handler) GetReturn(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET RETURN")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ret, err := h.returnService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ret)
+}
+
+// ChangeStatus godoc
+// @Summary Change return status
+// @Description Approve or reject the requested return, or mark books of the approved return received. Received books are returned to stock and the refund goes through the payment provider. The refund amount defaults to the paid amount of returned copies without shipping, it is split across payments of the order when no payment covers it. Amounts refunded from payments are fixed by the first attempt. The return is marked received once books are restocked and refunded, so the request can be retried if either fails. Every change is recorded in the order history. Admin only.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Return id"
+// @Param input body StatusDTO true "JSON input"
+// @Success 200 {object} Return
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /returns/{id}/status [patch]
+func (h *Handler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CHANGE RETURN STATUS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input StatusDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	ret, err := h.returnService.ChangeStatus(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidReturnTransition),
+			errors.Is(err, apperror.ErrRefundExceedsPayment):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ret)
+}
@@ -0,0 +1,210 @@
+package returns
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// StatusRequested is a status of the return requested by the customer.
+	StatusRequested = "requested"
+	// StatusApproved is a status of the return accepted by staff,
+	// the customer sends the books back.
+	StatusApproved = "approved"
+	// StatusRejected is a status of the return declined by staff.
+	StatusRejected = "rejected"
+	// StatusReceived is a status of the return which books are received,
+	// restocked and refunded.
+	StatusReceived = "received"
+
+	// Reasons of returns.
+	ReasonDamaged        = "damaged"
+	ReasonWrongItem      = "wrong_item"
+	ReasonNotAsDescribed = "not_as_described"
+	ReasonOther          = "other"
+
+	// Events of the order history recorded on steps of returns.
+	EventRequested = "return_requested"
+	EventApproved  = "return_approved"
+	EventRejected  = "return_rejected"
+	EventReceived  = "return_received"
+)
+
+// transitions are statuses the return can change to from its status.
+var transitions = map[string][]string{
+	StatusRequested: {StatusApproved, StatusRejected},
+	StatusApproved:  {StatusReceived},
+}
+
+// events are events of the order history recorded when the return
+// changes to the status.
+var events = map[string]string{
+	StatusRequested: EventRequested,
+	StatusApproved:  EventApproved,
+	StatusRejected:  EventRejected,
+	StatusReceived:  EventReceived,
+}
+
+// Return represents the return of order lines requested by the customer.
+// Comment is left by the customer and Note by staff. RefundAmount is the
+// amount refunded to the customer once books are received, it's in the
+// currency of the order. Refunds are amounts refunded from payments of the
+// order, they are set before the refund is made.
+type Return struct {
+	Id           int64            `json:"id" example:"1"`
+	OrderId      int64            `json:"orderId" example:"1001"`
+	Status       string           `json:"status" example:"requested"`
+	Reason       string           `json:"reason" example:"damaged"`
+	Comment      *string          `json:"comment,omitempty" example:"The cover is torn"`
+	Note         *string          `json:"note,omitempty" example:"Replacement is out of stock"`
+	RefundAmount *decimal.Decimal `json:"refundAmount,omitempty" swaggertype:"string" example:"11.49"`
+	Refunds      []Refund         `json:"refunds"`
+	Items        []Item           `json:"items"`
+	CreatedAt    time.Time        `json:"createdAt" example:"2022-03-05T10:00:00Z"`
+	UpdatedAt    time.Time        `json:"updatedAt" example:"2022-03-06T09:00:00Z"`
+} // @name Return
+
+// Refund is the amount of the return refunded from the payment.
+type Refund struct {
+	PaymentId int64           `json:"paymentId" example:"1"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"11.49"`
+} // @name ReturnRefund
+
+// checkStatus checks whether the return can change to the status.
+// Returns ErrInvalidReturnTransition if not.
+func (r *Return) checkStatus(status string) error {
+	for _, s := range transitions[r.Status] {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", apperror.ErrInvalidReturnTransition, r.Status, status)
+}
+
+// copies returns the number of copies returned.
+func (r *Return) copies() int32 {
+	var copies int32
+	for _, item := range r.Items {
+		copies += item.Quantity
+	}
+	return copies
+}
+
+// Item is the number of copies of the order line returned.
+// BookId is missing if the book has been deleted.
+type Item struct {
+	OrderItemId int64  `json:"orderItemId" example:"5001"`
+	BookId      *int64 `json:"bookId,omitempty" example:"123"`
+	Title       string `json:"title" example:"War and Peace"`
+	Quantity    int32  `json:"quantity" example:"1"`
+} // @name ReturnItem
+
+// StatusChange is the change of the return status by staff.
+type StatusChange struct {
+	Status       string
+	Note         *string
+	RefundAmount *decimal.Decimal
+}
+
+// Filter is used to filter and paginate returns list.
+type Filter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// CreateReturnDTO is used to request the return of order lines.
+type CreateReturnDTO struct {
+	OrderId int64     `json:"-"`
+	Reason  string    `json:"reason" example:"damaged"`
+	Comment *string   `json:"comment,omitempty" example:"The cover is torn"`
+	Items   []ItemDTO `json:"items"`
+} // @name CreateReturnInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *CreateReturnDTO) Validate() error {
+	err := validation.ValidateStruct(
+		r,
+		validation.Field(
+			&r.Reason,
+			validation.Required,
+			validation.In(ReasonDamaged, ReasonWrongItem, ReasonNotAsDescribed, ReasonOther),
+		),
+		validation.Field(&r.Comment, validation.RuneLength(1, 1000)),
+		validation.Field(&r.Items, validation.Required, validation.Length(1, 100)),
+	)
+	if err != nil {
+		return err
+	}
+
+	lines := make(map[int64]bool, len(r.Items))
+	for _, item := range r.Items {
+		if lines[item.OrderItemId] {
+			return validation.Errors{
+				"items": fmt.Errorf("order line %d is listed more than once", item.OrderItemId),
+			}
+		}
+		lines[item.OrderItemId] = true
+	}
+
+	return nil
+}
+
+// ItemDTO is the number of copies of the order line to return.
+type ItemDTO struct {
+	OrderItemId int64 `json:"orderItemId" example:"5001"`
+	Quantity    int32 `json:"quantity" example:"1"`
+} // @name ReturnItemInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (i ItemDTO) Validate() error {
+	return validation.ValidateStruct(
+		&i,
+		validation.Field(&i.OrderItemId, validation.Required, validation.Min(1)),
+		validation.Field(&i.Quantity, validation.Required, validation.Min(1)),
+	)
+}
+
+// StatusDTO is used to change the status of the return. RefundAmount
+// overrides the amount refunded once books are received, by default
+// returned lines are refunded as paid without shipping.
+type StatusDTO struct {
+	Id           int64            `json:"-"`
+	Status       string           `json:"status" example:"approved"`
+	Note         *string          `json:"note,omitempty" example:"Replacement is out of stock"`
+	RefundAmount *decimal.Decimal `json:"refundAmount,omitempty" swaggertype:"string" example:"11.49"`
+} // @name ReturnStatusInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (s *StatusDTO) Validate() error {
+	err := validation.ValidateStruct(
+		s,
+		validation.Field(
+			&s.Status,
+			validation.Required,
+			validation.In(StatusApproved, StatusRejected, StatusReceived),
+		),
+		validation.Field(&s.Note, validation.RuneLength(1, 500)),
+		validation.Field(&s.RefundAmount, validator.MinDecimal(decimal.Zero)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if s.RefundAmount != nil && s.Status != StatusReceived {
+		return validation.Errors{
+			"refundAmount": errors.New("must be blank unless books are received"),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+package returns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName        = "return_requests"
+	itemsTableName   = "return_request_items"
+	refundsTableName = "return_refunds"
+	ordersTableName  = "orders"
+
+	// selectQuery selects returns with their refunds and items aggregated
+	// to JSON arrays.
+	selectQuery = `
+	SELECT r.id, r.order_id, r.status, r.reason, r.comment, r.note, r.refund_amount,
+		r.created_at, r.updated_at,
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'paymentId', rr.payment_id, 'amount', rr.amount
+			) ORDER BY rr.payment_id)
+			FROM return_refunds rr
+			WHERE rr.return_id = r.id
+		), '[]'),
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'orderItemId', ri.order_item_id, 'bookId', i.book_id, 'title', i.title, 'quantity', ri.quantity
+			) ORDER BY i.id)
+			FROM return_request_items ri
+			JOIN order_items i ON i.id = ri.order_item_id
+			WHERE ri.return_id = r.id
+		), '[]')
+	FROM return_requests r`
+
+	// leftQuery selects lines of the order with the number of copies which
+	// are not returned yet. Copies of rejected returns can be returned again.
+	leftQuery = `
+	SELECT i.id, i.digital, i.quantity - COALESCE((
+		SELECT SUM(ri.quantity)
+		FROM return_request_items ri
+		JOIN return_requests r ON r.id = ri.return_id
+		WHERE ri.order_item_id = i.id AND r.status <> 'rejected'
+	), 0)::int
+	FROM order_items i
+	WHERE i.order_id = $1`
+)
+
+// Check whether db implements return storage interface.
+var _ Storage = &db{}
+
+// db implements return storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new return storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the requested return with its items. The order is locked,
+// so concurrent returns of the same order are checked one by one.
+// Returns ErrNoRows if the order doesn't exist, ErrOrderNotReturnable if
+// it's not paid, ErrReturnQuantityExceeded if some line has fewer copies
+// left to return or doesn't belong to the order, ErrDigitalNotReturnable
+// if some line is a digital copy, an error on failure or the inserted
+// return on success.
+func (d *db) Create(ret *Return) (*Return, error) {
+	orderQuery := fmt.Sprintf("SELECT status FROM %s WHERE id = $1 FOR UPDATE", ordersTableName)
+
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (order_id, status, reason, comment)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`, tableName)
+
+	itemQuery := fmt.Sprintf(`
+	INSERT INTO %s (return_id, order_item_id, quantity)
+	VALUES ($1, $2, $3)`, itemsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx, orderQuery, ret.OrderId).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find order query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if status != order.StatusPaid && status != order.StatusShipped && status != order.StatusDelivered {
+		return nil, fmt.Errorf("%w: order is %s", apperror.ErrOrderNotReturnable, status)
+	}
+
+	left, err := findLeft(ctx, tx, ret.OrderId)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	for _, item := range ret.Items {
+		line := left[item.OrderItemId]
+		if line.digital {
+			return nil, fmt.Errorf("%w: order line %d", apperror.ErrDigitalNotReturnable, item.OrderItemId)
+		}
+		if item.Quantity > line.quantity {
+			return nil, fmt.Errorf(
+				"%w: %d of order line %d",
+				apperror.ErrReturnQuantityExceeded,
+				line.quantity,
+				item.OrderItemId,
+			)
+		}
+	}
+
+	if err := tx.QueryRow(ctx, insertQuery, ret.OrderId, ret.Status, ret.Reason, ret.Comment).Scan(&ret.Id); err != nil {
+		err = fmt.Errorf("failed to execute create return query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	for _, item := range ret.Items {
+		if _, err := tx.Exec(ctx, itemQuery, ret.Id, item.OrderItemId, item.Quantity); err != nil {
+			err = fmt.Errorf("failed to execute create return item query: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	created, err := scanReturn(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1", ret.Id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find created return: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return created, nil
+}
+
+// FindById finds the return with specified id with its items.
+// Returns ErrNoRows if the return doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Return, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	ret, err := scanReturn(d.conn.QueryRow(ctx, selectQuery+" WHERE r.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find return by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// FindByOrder finds returns of the order, newest first.
+// Returns an error on failure.
+func (d *db) FindByOrder(orderId int64) ([]*Return, error) {
+	return d.find(selectQuery+" WHERE r.order_id = $1 ORDER BY r.id DESC", orderId)
+}
+
+// FindAll finds returns which match the given filter, newest first.
+// Returns an error on failure.
+func (d *db) FindAll(filter *Filter) ([]*Return, error) {
+	query := selectQuery + " WHERE r.status = $1 OR $1 = '' ORDER BY r.id DESC LIMIT $2 OFFSET $3"
+	return d.find(query, filter.Status, filter.Limit, filter.Offset)
+}
+
+// ChangeStatus changes the status of the return. The refund amount is set
+// when books are received. Returns ErrNoRows if the return doesn't exist,
+// ErrInvalidReturnTransition if it can't change to the status, an error on
+// failure or the changed return on success.
+func (d *db) ChangeStatus(id int64, change *StatusChange) (*Return, error) {
+	updateQuery := fmt.Sprintf(`
+	UPDATE %s SET status = $2, note = COALESCE($3, note), refund_amount = $4, updated_at = now()
+	WHERE id = $1`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ret, err := scanReturn(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1 FOR UPDATE OF r", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find return by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := ret.checkStatus(change.Status); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, updateQuery, id, change.Status, change.Note, change.RefundAmount); err != nil {
+		err = fmt.Errorf("failed to execute change return status query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	changed, err := scanReturn(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1", id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find changed return: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return changed, nil
+}
+
+// SetRefunds sets amounts the approved return is refunded from payments.
+// The return is locked, so refunds which are set already are kept and
+// concurrent receives refund the same amounts. Returns ErrNoRows if the
+// return or some payment doesn't exist, ErrInvalidReturnTransition if the
+// return can't be received, an error on failure or the return with its
+// refunds on success.
+func (d *db) SetRefunds(id int64, refunds []Refund) (*Return, error) {
+	insertQuery := fmt.Sprintf(`
+	INSERT INTO %s (return_id, payment_id, amount)
+	VALUES ($1, $2, $3)`, refundsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ret, err := scanReturn(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1 FOR UPDATE OF r", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find return by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err := ret.checkStatus(StatusReceived); err != nil {
+		return nil, err
+	}
+
+	if len(ret.Refunds) > 0 {
+		return ret, nil
+	}
+
+	for _, refund := range refunds {
+		if _, err := tx.Exec(ctx, insertQuery, id, refund.PaymentId, refund.Amount); err != nil {
+			if apperror.IsForeignKeyViolation(err) {
+				return nil, apperror.ErrNoRows
+			}
+			err = fmt.Errorf("failed to execute create return refund query: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	changed, err := scanReturn(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1", id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refunded return: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return changed, nil
+}
+
+// find finds returns with the query of selectQuery and its arguments.
+func (d *db) find(query string, args ...interface{}) ([]*Return, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find returns query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	returns := make([]*Return, 0)
+	for rows.Next() {
+		ret, err := scanReturn(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan return: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read returns: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+// orderLine is the line of the order with the number of copies left to return.
+type orderLine struct {
+	digital  bool
+	quantity int32
+}
+
+// findLeft returns the number of copies left to return by lines of the order.
+func findLeft(ctx context.Context, tx pgx.Tx, orderId int64) (map[int64]orderLine, error) {
+	rows, err := tx.Query(ctx, leftQuery, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find order lines query: %v", err)
+	}
+	defer rows.Close()
+
+	left := make(map[int64]orderLine)
+	for rows.Next() {
+		var id int64
+		var line orderLine
+		if err := rows.Scan(&id, &line.digital, &line.quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan order line: %v", err)
+		}
+		left[id] = line
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read order lines: %v", err)
+	}
+
+	return left, nil
+}
+
+// scanReturn scans the row of selectQuery.
+func scanReturn(row pgx.Row) (*Return, error) {
+	var r Return
+	err := row.Scan(
+		&r.Id,
+		&r.OrderId,
+		&r.Status,
+		&r.Reason,
+		&r.Comment,
+		&r.Note,
+		&r.RefundAmount,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+		&r.Refunds,
+		&r.Items,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,338 @@
+package returns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/inventory"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/internal/payment"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/shopspring/decimal"
+)
+
+// customerActor is the actor of return movements recorded in the stock ledger.
+const customerActor = "customer"
+
+// Service describes return service functionality.
+type Service interface {
+	Create(ctx context.Context, input *CreateReturnDTO) (*Return, error)
+	ChangeStatus(ctx context.Context, input *StatusDTO) (*Return, error)
+	GetById(ctx context.Context, id int64) (*Return, error)
+	GetByOrder(ctx context.Context, orderId int64) ([]*Return, error)
+	GetAll(ctx context.Context, filter *Filter) ([]*Return, error)
+}
+
+type service struct {
+	logger           logger.Logger
+	storage          Storage
+	orderService     order.Service
+	inventoryService inventory.Service
+	paymentService   payment.Service
+	currencyService  currency.Service
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(
+	storage Storage,
+	orderService order.Service,
+	inventoryService inventory.Service,
+	paymentService payment.Service,
+	currencyService currency.Service,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:           logger,
+		storage:          storage,
+		orderService:     orderService,
+		inventoryService: inventoryService,
+		paymentService:   paymentService,
+		currencyService:  currencyService,
+	}
+}
+
+// Create requests the return of order lines. The request is recorded in
+// the order history. Returns ErrNoRows if the order doesn't exist,
+// ErrOrderNotReturnable if it's not paid, ErrReturnQuantityExceeded if
+// some line has fewer copies left to return and ErrDigitalNotReturnable
+// if some line is a digital copy.
+func (s *service) Create(ctx context.Context, input *CreateReturnDTO) (*Return, error) {
+	items := make([]Item, 0, len(input.Items))
+	for _, item := range input.Items {
+		items = append(items, Item{OrderItemId: item.OrderItemId, Quantity: item.Quantity})
+	}
+
+	ret, err := s.storage.Create(&Return{
+		OrderId: input.OrderId,
+		Status:  StatusRequested,
+		Reason:  input.Reason,
+		Comment: input.Comment,
+		Items:   items,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) &&
+			!errors.Is(err, apperror.ErrOrderNotReturnable) &&
+			!errors.Is(err, apperror.ErrReturnQuantityExceeded) &&
+			!errors.Is(err, apperror.ErrDigitalNotReturnable) {
+			s.logger.Errorf("failed to create return: %v", err)
+		}
+		return nil, err
+	}
+
+	note := fmt.Sprintf("return %d of %d copies requested: %s", ret.Id, ret.copies(), ret.Reason)
+	if ret.Comment != nil {
+		note += ", " + *ret.Comment
+	}
+	s.record(ctx, ret, note)
+
+	return ret, nil
+}
+
+// ChangeStatus moves the return along its status flow: requested to
+// approved or rejected, approved to received. Received books are
+// restocked and refunded. Every change is recorded in the order history.
+// Returns ErrNoRows if the return doesn't exist, ErrInvalidReturnTransition
+// if it can't change to the status and ErrRefundExceedsPayment if the order
+// has no payments to refund the amount from.
+func (s *service) ChangeStatus(ctx context.Context, input *StatusDTO) (*Return, error) {
+	if input.Status == StatusReceived {
+		return s.receive(ctx, input)
+	}
+
+	ret, err := s.changeStatus(&StatusChange{Status: input.Status, Note: input.Note}, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("return %d %s", ret.Id, ret.Status)
+	if input.Note != nil {
+		note += ": " + *input.Note
+	}
+	s.record(ctx, ret, note)
+
+	return ret, nil
+}
+
+// receive restocks books of the approved return, refunds the amount
+// through the payment provider and marks the return received. The amount
+// is the paid amount of returned copies unless staff set it. The return is
+// marked received last, so the request can be retried if some step failed:
+// books are restocked once and refunds of the return are resumed instead
+// of being made again.
+func (s *service) receive(ctx context.Context, input *StatusDTO) (*Return, error) {
+	ret, err := s.GetById(ctx, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ret.checkStatus(StatusReceived); err != nil {
+		return nil, err
+	}
+
+	o, err := s.orderService.GetById(ctx, ret.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := input.RefundAmount
+	if amount == nil {
+		paid, err := s.paidAmount(ctx, o, ret)
+		if err != nil {
+			return nil, err
+		}
+		amount = &paid
+	}
+
+	movements := make([]*inventory.Movement, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		if item.BookId == nil {
+			continue
+		}
+		movements = append(movements, &inventory.Movement{
+			BookId:   *item.BookId,
+			Type:     inventory.MovementReturn,
+			Quantity: item.Quantity,
+			Reason:   "returned by the customer: " + ret.Reason,
+			Actor:    customerActor,
+		})
+	}
+
+	if len(movements) > 0 {
+		reference := fmt.Sprintf("return %d", ret.Id)
+		if _, err := s.inventoryService.RecordOnce(ctx, reference, movements...); err != nil {
+			return nil, err
+		}
+	}
+
+	if amount.IsPositive() {
+		refunded, err := s.refund(ctx, ret, *amount)
+		if err != nil {
+			return nil, err
+		}
+		amount = &refunded
+	}
+
+	ret, err = s.changeStatus(&StatusChange{
+		Status:       StatusReceived,
+		Note:         input.Note,
+		RefundAmount: amount,
+	}, input.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("return %d received, %d copies restocked", ret.Id, ret.copies())
+	if input.Note != nil {
+		note += ": " + *input.Note
+	}
+	s.record(ctx, ret, note)
+
+	return ret, nil
+}
+
+// refund refunds the amount of the return from captured payments of the
+// order, the latest first, splitting it when no payment covers the whole
+// amount. Amounts refunded from payments are set with the return locked
+// before they are refunded and refunds are made with the reference of the
+// return, so concurrent and retried receives refund the return once.
+// Returns the refunded amount, which is the amount set by the first
+// receive.
+func (s *service) refund(ctx context.Context, ret *Return, amount decimal.Decimal) (decimal.Decimal, error) {
+	refunds := ret.Refunds
+	if len(refunds) == 0 {
+		payments, err := s.paymentService.GetByOrder(ctx, ret.OrderId)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		left := amount
+		for _, p := range payments {
+			part := decimal.Min(left, p.Refundable())
+			if !part.IsPositive() {
+				continue
+			}
+			refunds = append(refunds, Refund{PaymentId: p.Id, Amount: part})
+			if left = left.Sub(part); !left.IsPositive() {
+				break
+			}
+		}
+
+		if left.IsPositive() {
+			s.logger.Warnf("return %d is received, but order %d has no payments to refund %s from", ret.Id, ret.OrderId, amount)
+			return decimal.Zero, fmt.Errorf("%w: order %d", apperror.ErrRefundExceedsPayment, ret.OrderId)
+		}
+
+		set, err := s.storage.SetRefunds(ret.Id, refunds)
+		if err != nil {
+			if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInvalidReturnTransition) {
+				s.logger.Errorf("failed to set refunds of return %d: %v", ret.Id, err)
+			}
+			return decimal.Zero, err
+		}
+		refunds = set.Refunds
+	}
+
+	reference := fmt.Sprintf("return %d", ret.Id)
+	reason := fmt.Sprintf("return %d: %s", ret.Id, ret.Reason)
+	refunded := decimal.Zero
+	for _, r := range refunds {
+		if _, err := s.paymentService.Refund(ctx, &payment.RefundDTO{
+			PaymentId: r.PaymentId,
+			Amount:    r.Amount,
+			Reason:    &reason,
+			Reference: &reference,
+		}); err != nil {
+			return decimal.Zero, err
+		}
+		refunded = refunded.Add(r.Amount)
+	}
+
+	return refunded, nil
+}
+
+// paidAmount returns the amount the customer paid for returned copies:
+// their share of the line total less discounts, plus the tax unless prices
+// include it, rounded in the currency of the order.
+func (s *service) paidAmount(ctx context.Context, o *order.Order, ret *Return) (decimal.Decimal, error) {
+	lines := make(map[int64]order.OrderItem, len(o.Items))
+	for _, item := range o.Items {
+		lines[item.Id] = item
+	}
+
+	amount := decimal.Zero
+	for _, item := range ret.Items {
+		line := lines[item.OrderItemId]
+		total := line.UnitPrice.Mul(decimal.New(int64(line.Quantity), 0)).Sub(line.Discount)
+		if !o.PricesIncludeTax {
+			total = total.Add(line.Tax)
+		}
+		share := total.Mul(decimal.New(int64(item.Quantity), 0)).Div(decimal.New(int64(line.Quantity), 0))
+		amount = amount.Add(share)
+	}
+
+	rates, err := s.currencyService.Rates(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if cur, ok := rates.Currency(o.Currency); ok {
+		return cur.Round(amount), nil
+	}
+	return amount.Round(2), nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Return, error) {
+	ret, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find return by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *service) GetByOrder(ctx context.Context, orderId int64) ([]*Return, error) {
+	returns, err := s.storage.FindByOrder(orderId)
+	if err != nil {
+		s.logger.Warnf("cannot find order returns: %v", err)
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter *Filter) ([]*Return, error) {
+	returns, err := s.storage.FindAll(filter)
+	if err != nil {
+		s.logger.Warnf("cannot find returns: %v", err)
+		return nil, err
+	}
+
+	return returns, nil
+}
+
+// changeStatus changes the status of the return in the storage.
+func (s *service) changeStatus(change *StatusChange, id int64) (*Return, error) {
+	ret, err := s.storage.ChangeStatus(id, change)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrInvalidReturnTransition) {
+			s.logger.Errorf("failed to change return status: %v", err)
+		}
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// record records the current status of the return in the order history.
+// The return has changed already, so failures are logged only.
+func (s *service) record(ctx context.Context, ret *Return, note string) {
+	if err := s.orderService.RecordEvent(ctx, ret.OrderId, events[ret.Status], note); err != nil {
+		s.logger.Warnf("cannot record return %d in order history: %v", ret.Id, err)
+	}
+}
@@ -0,0 +1,11 @@
+package returns
+
+// Storage describes a return storage functionality.
+type Storage interface {
+	Create(ret *Return) (*Return, error)
+	FindById(id int64) (*Return, error)
+	FindByOrder(orderId int64) ([]*Return, error)
+	FindAll(filter *Filter) ([]*Return, error)
+	ChangeStatus(id int64, change *StatusChange) (*Return, error)
+	SetRefunds(id int64, refunds []Refund) (*Return, error)
+}
@@ -0,0 +1,503 @@
+package review
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	bookReviewsURL = "/api/books/:id/reviews"
+	userReviewsURL = "/api/users/:id/reviews"
+	userReviewURL  = "/api/users/:id/reviews/:reviewId"
+	reviewsURL     = "/api/reviews"
+	reviewURL      = "/api/reviews/:id"
+	reportsURL     = "/api/reviews/:id/reports"
+	moderationURL  = "/api/reviews/:id/moderation"
+)
+
+// Handler handles requests specified to review service.
+type Handler struct {
+	logger        logger.Logger
+	reviewService Service
+}
+
+// NewHandler returns a new review Handler instance.
+func NewHandler(logger logger.Logger, reviewService Service) handler.Handling {
+	return &Handler{
+		logger:        logger,
+		reviewService: reviewService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, bookReviewsURL, h.ListBookReviews)
+	router.HandlerFunc(http.MethodGet, userReviewsURL, h.ListUserReviews)
+	router.HandlerFunc(http.MethodPost, userReviewsURL, h.CreateReview)
+	router.HandlerFunc(http.MethodPut, userReviewURL, h.UpdateReview)
+	router.HandlerFunc(http.MethodDelete, userReviewURL, h.DeleteReview)
+	router.HandlerFunc(http.MethodGet, reviewsURL, h.ListReviews)
+	router.HandlerFunc(http.MethodGet, reviewURL, h.GetReview)
+	router.HandlerFunc(http.MethodGet, reportsURL, h.ListReports)
+	router.HandlerFunc(http.MethodPost, reportsURL, h.ReportReview)
+	router.HandlerFunc(http.MethodPatch, moderationURL, h.ModerateReview)
+}
+
+// ListBookReviews godoc
+// @Summary List book reviews
+// @Description Get published reviews of the book, latest first. The average rating and the distribution of ratings are returned with the book.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Book id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /books/{id}/reviews [get]
+func (h *Handler) ListBookReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST BOOK REVIEWS")
+
+	bookId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	reviews, err := h.reviewService.GetAll(r.Context(), &Filter{
+		BookId: &bookId,
+		Status: StatusPublished,
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	})
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reviews)
+}
+
+// ListUserReviews godoc
+// @Summary List user reviews
+// @Description Get reviews of the user including hidden ones, latest first.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/reviews [get]
+func (h *Handler) ListUserReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST USER REVIEWS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	reviews, err := h.reviewService.GetAll(r.Context(), &Filter{
+		UserId: &userId,
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	})
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reviews)
+}
+
+// CreateReview godoc
+// @Summary Review book
+// @Description Rate the book from 1 to 5 stars with an optional title and text. Only books of paid orders of the user can be reviewed, once per user.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param input body CreateReviewDTO true "JSON input"
+// @Success 201 {object} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/reviews [post]
+func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE REVIEW")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input CreateReviewDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+
+	review, err := h.reviewService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrReviewNotAllowed) || errors.Is(err, apperror.ErrReviewExists) {
+			response.Conflict(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, review)
+}
+
+// UpdateReview godoc
+// @Summary Update review
+// @Description Edit the rating and the text of the review of the user.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param reviewId path int64 true "Review id"
+// @Param input body UpdateReviewDTO true "JSON input"
+// @Success 200 {object} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/reviews/{reviewId} [put]
+func (h *Handler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE REVIEW")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	reviewId, err := handler.ReadParam64(r, "reviewId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input UpdateReviewDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = reviewId
+	input.UserId = userId
+
+	review, err := h.reviewService.Update(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Summary Delete review
+// @Description Delete the review of the user with its reports.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param reviewId path int64 true "Review id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/reviews/{reviewId} [delete]
+func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE REVIEW")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	reviewId, err := handler.ReadParam64(r, "reviewId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.reviewService.Delete(r.Context(), reviewId, userId); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ListReviews godoc
+// @Summary List reviews
+// @Description Get reviews of all books, latest first. Reported reviews are the moderation queue, they are listed by their oldest open report. Admin only.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param status query string false "Review status" Enums(published, hidden)
+// @Param flagged query bool false "Whether reviews are flagged"
+// @Param reported query bool false "Only reviews with open reports"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {array} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /reviews [get]
+func (h *Handler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST REVIEWS")
+
+	pagination, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &Filter{
+		Status: query.Get("status"),
+		Limit:  pagination.Limit(),
+		Offset: pagination.Offset(),
+	}
+
+	if filter.Status != "" && filter.Status != StatusPublished && filter.Status != StatusHidden {
+		response.BadRequest(w, "status must be published or hidden", "")
+		return
+	}
+
+	if value := query.Get("flagged"); value != "" {
+		flagged, err := strconv.ParseBool(value)
+		if err != nil {
+			response.BadRequest(w, "flagged must have type bool", "")
+			return
+		}
+		filter.Flagged = &flagged
+	}
+
+	if value := query.Get("reported"); value != "" {
+		filter.Reported, err = strconv.ParseBool(value)
+		if err != nil {
+			response.BadRequest(w, "reported must have type bool", "")
+			return
+		}
+	}
+
+	reviews, err := h.reviewService.GetAll(r.Context(), filter)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reviews)
+}
+
+// GetReview godoc
+// @Summary Show review
+// @Description Get the review by id.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Review id"
+// @Success 200 {object} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /reviews/{id} [get]
+func (h *Handler) GetReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET REVIEW")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	review, err := h.reviewService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, review)
+}
+
+// ListReports godoc
+// @Summary List review reports
+// @Description Get reports of the review, open ones first. Admin only.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Review id"
+// @Success 200 {array} Report
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /reviews/{id}/reports [get]
+func (h *Handler) ListReports(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST REVIEW REPORTS")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	reports, err := h.reviewService.GetReports(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reports)
+}
+
+// ReportReview godoc
+// @Summary Report review
+// @Description Report the review to staff. The user can report the review again once staff have moderated it.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Review id"
+// @Param input body ReportDTO true "JSON input"
+// @Success 201 {object} Report
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /reviews/{id}/reports [post]
+func (h *Handler) ReportReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REPORT REVIEW")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ReportDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.ReviewId = id
+
+	report, err := h.reviewService.Report(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrReviewReported):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, report)
+}
+
+// ModerateReview godoc
+// @Summary Moderate review
+// @Description Hide or publish the review, flag or unflag it and leave a note. Missing fields are left as is. Open reports of the review are resolved. Admin only.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Review id"
+// @Param input body ModerateDTO true "JSON input"
+// @Success 200 {object} Review
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /reviews/{id}/moderation [patch]
+func (h *Handler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("MODERATE REVIEW")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input ModerateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	review, err := h.reviewService.Moderate(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, review)
+}
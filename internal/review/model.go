@@ -0,0 +1,144 @@
+package review
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+const (
+	// StatusPublished is a status of the review shown with the book.
+	StatusPublished = "published"
+	// StatusHidden is a status of the review hidden by staff.
+	StatusHidden = "hidden"
+)
+
+// Review represents the review of the book by the customer who bought it.
+// Hidden reviews are shown neither with the book nor in its rating. Flagged
+// reviews are marked by staff for a closer look. OpenReports is the number
+// of reports staff haven't resolved yet.
+type Review struct {
+	Id             int64     `json:"id" example:"1"`
+	BookId         int64     `json:"bookId" example:"123"`
+	UserId         int64     `json:"userId" example:"7"`
+	Username       string    `json:"username" example:"reader"`
+	Rating         int16     `json:"rating" example:"5"`
+	Title          *string   `json:"title,omitempty" example:"A masterpiece"`
+	Body           *string   `json:"body,omitempty" example:"Long, but worth every page."`
+	Status         string    `json:"status" example:"published"`
+	Flagged        bool      `json:"flagged" example:"false"`
+	ModerationNote *string   `json:"moderationNote,omitempty" example:"Contains spoilers"`
+	OpenReports    int32     `json:"openReports" example:"0"`
+	CreatedAt      time.Time `json:"createdAt" example:"2022-03-05T10:00:00Z"`
+	UpdatedAt      time.Time `json:"updatedAt" example:"2022-03-06T09:00:00Z"`
+} // @name Review
+
+// Report represents the report of the review by the user.
+// ResolvedAt is set once staff moderate the review.
+type Report struct {
+	Id         int64      `json:"id" example:"1"`
+	ReviewId   int64      `json:"reviewId" example:"1"`
+	UserId     int64      `json:"userId" example:"8"`
+	Reason     string     `json:"reason" example:"Spoilers in the first line"`
+	CreatedAt  time.Time  `json:"createdAt" example:"2022-03-07T10:00:00Z"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" example:"2022-03-07T12:00:00Z"`
+} // @name ReviewReport
+
+// Filter is used to filter and paginate reviews list. Reported reviews are
+// the moderation queue, they are listed by their oldest open report.
+type Filter struct {
+	BookId   *int64
+	UserId   *int64
+	Status   string
+	Flagged  *bool
+	Reported bool
+	Limit    int
+	Offset   int
+}
+
+// CreateReviewDTO is used to review the book.
+type CreateReviewDTO struct {
+	UserId int64   `json:"-"`
+	BookId int64   `json:"bookId" example:"123"`
+	Rating int16   `json:"rating" example:"5"`
+	Title  *string `json:"title,omitempty" example:"A masterpiece"`
+	Body   *string `json:"body,omitempty" example:"Long, but worth every page."`
+} // @name CreateReviewInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *CreateReviewDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.BookId, validation.Required, validation.Min(1)),
+		validation.Field(&r.Rating, validation.Required, validation.Min(1), validation.Max(5)),
+		validation.Field(&r.Title, validation.RuneLength(1, 200)),
+		validation.Field(&r.Body, validation.RuneLength(1, 5000)),
+	)
+}
+
+// UpdateReviewDTO is used to edit the review by its author.
+type UpdateReviewDTO struct {
+	Id     int64   `json:"-"`
+	UserId int64   `json:"-"`
+	Rating int16   `json:"rating" example:"4"`
+	Title  *string `json:"title,omitempty" example:"A masterpiece"`
+	Body   *string `json:"body,omitempty" example:"Long, but worth every page."`
+} // @name UpdateReviewInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *UpdateReviewDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Rating, validation.Required, validation.Min(1), validation.Max(5)),
+		validation.Field(&r.Title, validation.RuneLength(1, 200)),
+		validation.Field(&r.Body, validation.RuneLength(1, 5000)),
+	)
+}
+
+// ReportDTO is used to report the review to staff.
+type ReportDTO struct {
+	ReviewId int64  `json:"-"`
+	UserId   int64  `json:"userId" example:"8"`
+	Reason   string `json:"reason" example:"Spoilers in the first line"`
+} // @name ReportReviewInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *ReportDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.UserId, validation.Required, validation.Min(1)),
+		validation.Field(&r.Reason, validation.Required, validation.RuneLength(1, 500)),
+	)
+}
+
+// ModerateDTO is used to moderate the review by staff. Missing fields
+// are left as is. Open reports of the review are resolved.
+type ModerateDTO struct {
+	Id      int64   `json:"-"`
+	Status  *string `json:"status,omitempty" example:"hidden"`
+	Flagged *bool   `json:"flagged,omitempty" example:"false"`
+	Note    *string `json:"note,omitempty" example:"Contains spoilers"`
+} // @name ModerateReviewInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (m *ModerateDTO) Validate() error {
+	err := validation.ValidateStruct(
+		m,
+		validation.Field(&m.Status, validation.In(StatusPublished, StatusHidden)),
+		validation.Field(&m.Note, validation.RuneLength(1, 500)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if m.Status == nil && m.Flagged == nil && m.Note == nil {
+		return errors.New("status, flagged or note is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,402 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName        = "reviews"
+	reportsTableName = "review_reports"
+
+	// selectQuery selects reviews with usernames of their authors
+	// and the number of open reports.
+	selectQuery = `
+	SELECT r.id, r.book_id, r.user_id, u.username, r.rating, r.title, r.body,
+		r.status, r.flagged, r.moderation_note,
+		(SELECT COUNT(*) FROM review_reports rr WHERE rr.review_id = r.id AND rr.resolved_at IS NULL)::int,
+		r.created_at, r.updated_at
+	FROM reviews r
+	JOIN users u ON u.id = r.user_id`
+
+	// reportedCondition matches reviews with open reports.
+	reportedCondition = "EXISTS (SELECT 1 FROM review_reports rr WHERE rr.review_id = r.id AND rr.resolved_at IS NULL)"
+
+	// reportedOrder lists reviews by their oldest open report.
+	reportedOrder = "(SELECT MIN(rr.created_at) FROM review_reports rr WHERE rr.review_id = r.id AND rr.resolved_at IS NULL)"
+
+	reportColumns = "id, review_id, user_id, reason, created_at, resolved_at"
+)
+
+// Check whether db implements review storage interface.
+var _ Storage = &db{}
+
+// db implements review storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new review storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the review of the book if the user has bought it, i.e.
+// some paid order of the user has the book. Returns ErrReviewNotAllowed
+// if the user hasn't bought the book, ErrReviewExists if the user has
+// reviewed it already, an error on failure or the inserted review on success.
+func (d *db) Create(input *CreateReviewDTO) (*Review, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (book_id, user_id, rating, title, body)
+	SELECT $1::bigint, $2::bigint, $3::smallint, $4::text, $5::text
+	WHERE EXISTS (
+		SELECT 1
+		FROM orders o
+		JOIN order_items i ON i.order_id = o.id
+		WHERE o.user_id = $2 AND i.book_id = $1 AND o.status IN ('paid', 'shipped', 'delivered')
+	)
+	RETURNING id`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	var id int64
+	err := d.conn.QueryRow(ctx, query, input.BookId, input.UserId, input.Rating, input.Title, input.Body).Scan(&id)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, apperror.ErrReviewNotAllowed
+		case apperror.IsUniqueViolation(err):
+			return nil, apperror.ErrReviewExists
+		}
+		err = fmt.Errorf("failed to execute create review query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return d.FindById(id)
+}
+
+// FindById finds the review with specified id.
+// Returns ErrNoRows if the review doesn't exist or an error on failure.
+func (d *db) FindById(id int64) (*Review, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	review, err := scanReview(d.conn.QueryRow(ctx, selectQuery+" WHERE r.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find review by id query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// FindAll finds reviews which match the given filter, newest first.
+// Reported reviews are listed by their oldest open report.
+// Returns an error on failure.
+func (d *db) FindAll(filter *Filter) ([]*Review, error) {
+	where, args := filterConditions(filter)
+	argId := len(args) + 1
+
+	order := "r.id DESC"
+	if filter.Reported {
+		order = reportedOrder + ", r.id"
+	}
+
+	query := selectQuery + where
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", order, argId, argId+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find all reviews query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]*Review, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan review: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read reviews: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Update updates the rating and the text of the review of the user.
+// Returns ErrNoRows if the user has no such review, an error on failure
+// or the updated review on success.
+func (d *db) Update(input *UpdateReviewDTO) (*Review, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET rating = $3, title = $4, body = $5, updated_at = now()
+	WHERE id = $1 AND user_id = $2`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, input.Id, input.UserId, input.Rating, input.Title, input.Body)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update review query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return d.FindById(input.Id)
+}
+
+// Delete deletes the review of the user with its reports.
+// Returns ErrNoRows if the user has no such review or an error on failure.
+func (d *db) Delete(id, userId int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete review query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// Report inserts the report of the review by the user. Returns ErrNoRows
+// if the review or the user doesn't exist, ErrReviewReported if the user
+// has an open report of the review, an error on failure or the inserted
+// report on success.
+func (d *db) Report(input *ReportDTO) (*Report, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (review_id, user_id, reason)
+	VALUES ($1, $2, $3)
+	RETURNING %s`, reportsTableName, reportColumns)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	report, err := scanReport(d.conn.QueryRow(ctx, query, input.ReviewId, input.UserId, input.Reason))
+	if err != nil {
+		switch {
+		case apperror.IsForeignKeyViolation(err):
+			return nil, apperror.ErrNoRows
+		case apperror.IsUniqueViolation(err):
+			return nil, apperror.ErrReviewReported
+		}
+		err = fmt.Errorf("failed to execute report review query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// FindReports finds reports of the review, open ones first.
+// Returns an error on failure.
+func (d *db) FindReports(reviewId int64) ([]*Report, error) {
+	query := fmt.Sprintf(`
+	SELECT %s FROM %s
+	WHERE review_id = $1
+	ORDER BY resolved_at DESC NULLS FIRST, id`, reportColumns, reportsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, reviewId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find review reports query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]*Report, 0)
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan review report: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read review reports: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// Moderate changes the status, the flag and the moderation note of the
+// review and resolves its open reports. Missing values are left as is.
+// Returns ErrNoRows if the review doesn't exist, an error on failure or
+// the moderated review on success.
+func (d *db) Moderate(input *ModerateDTO) (*Review, error) {
+	updateQuery := fmt.Sprintf(`
+	UPDATE %s SET
+		status = COALESCE($2, status),
+		flagged = COALESCE($3, flagged),
+		moderation_note = COALESCE($4, moderation_note),
+		updated_at = now()
+	WHERE id = $1`, tableName)
+
+	resolveQuery := fmt.Sprintf(`
+	UPDATE %s SET resolved_at = now()
+	WHERE review_id = $1 AND resolved_at IS NULL`, reportsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, updateQuery, input.Id, input.Status, input.Flagged, input.Note)
+	if err != nil {
+		err = fmt.Errorf("failed to execute moderate review query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	if _, err := tx.Exec(ctx, resolveQuery, input.Id); err != nil {
+		err = fmt.Errorf("failed to execute resolve review reports query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	review, err := scanReview(tx.QueryRow(ctx, selectQuery+" WHERE r.id = $1", input.Id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find moderated review: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return review, nil
+}
+
+// filterConditions builds the WHERE clause and its arguments from the filter.
+func filterConditions(filter *Filter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if filter.BookId != nil {
+		conditions = append(conditions, fmt.Sprintf("r.book_id = $%d", argId))
+		args = append(args, *filter.BookId)
+		argId++
+	}
+
+	if filter.UserId != nil {
+		conditions = append(conditions, fmt.Sprintf("r.user_id = $%d", argId))
+		args = append(args, *filter.UserId)
+		argId++
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("r.status = $%d", argId))
+		args = append(args, filter.Status)
+		argId++
+	}
+
+	if filter.Flagged != nil {
+		conditions = append(conditions, fmt.Sprintf("r.flagged = $%d", argId))
+		args = append(args, *filter.Flagged)
+	}
+
+	if filter.Reported {
+		conditions = append(conditions, reportedCondition)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanReview scans the row of selectQuery.
+func scanReview(row pgx.Row) (*Review, error) {
+	var r Review
+	err := row.Scan(
+		&r.Id,
+		&r.BookId,
+		&r.UserId,
+		&r.Username,
+		&r.Rating,
+		&r.Title,
+		&r.Body,
+		&r.Status,
+		&r.Flagged,
+		&r.ModerationNote,
+		&r.OpenReports,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// scanReport scans the row of reportColumns.
+func scanReport(row pgx.Row) (*Report, error) {
+	var r Report
+	err := row.Scan(&r.Id, &r.ReviewId, &r.UserId, &r.Reason, &r.CreatedAt, &r.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,137 @@
+package review
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes review service functionality.
+type Service interface {
+	Create(ctx context.Context, input *CreateReviewDTO) (*Review, error)
+	GetById(ctx context.Context, id int64) (*Review, error)
+	GetAll(ctx context.Context, filter *Filter) ([]*Review, error)
+	Update(ctx context.Context, input *UpdateReviewDTO) (*Review, error)
+	Delete(ctx context.Context, id, userId int64) error
+	Report(ctx context.Context, input *ReportDTO) (*Report, error)
+	GetReports(ctx context.Context, reviewId int64) ([]*Report, error)
+	Moderate(ctx context.Context, input *ModerateDTO) (*Review, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+// Create reviews the book by the user. Returns ErrReviewNotAllowed if
+// the user hasn't bought the book and ErrReviewExists if the user has
+// reviewed it already.
+func (s *service) Create(ctx context.Context, input *CreateReviewDTO) (*Review, error) {
+	review, err := s.storage.Create(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrReviewNotAllowed) && !errors.Is(err, apperror.ErrReviewExists) {
+			s.logger.Errorf("failed to create review: %v", err)
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *service) GetById(ctx context.Context, id int64) (*Review, error) {
+	review, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find review by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter *Filter) ([]*Review, error) {
+	reviews, err := s.storage.FindAll(filter)
+	if err != nil {
+		s.logger.Warnf("cannot find reviews: %v", err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Update edits the review of the user.
+// Returns ErrNoRows if the user has no such review.
+func (s *service) Update(ctx context.Context, input *UpdateReviewDTO) (*Review, error) {
+	review, err := s.storage.Update(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to update review: %v", err)
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// Delete deletes the review of the user.
+// Returns ErrNoRows if the user has no such review.
+func (s *service) Delete(ctx context.Context, id, userId int64) error {
+	if err := s.storage.Delete(id, userId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete review: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Report reports the review to staff. Returns ErrNoRows if the review
+// doesn't exist and ErrReviewReported if the user has reported it already
+// and staff haven't moderated it since.
+func (s *service) Report(ctx context.Context, input *ReportDTO) (*Report, error) {
+	report, err := s.storage.Report(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrReviewReported) {
+			s.logger.Errorf("failed to report review: %v", err)
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *service) GetReports(ctx context.Context, reviewId int64) ([]*Report, error) {
+	reports, err := s.storage.FindReports(reviewId)
+	if err != nil {
+		s.logger.Warnf("cannot find review reports: %v", err)
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// Moderate changes the status, the flag or the note of the review and
+// resolves its open reports. Returns ErrNoRows if the review doesn't exist.
+func (s *service) Moderate(ctx context.Context, input *ModerateDTO) (*Review, error) {
+	review, err := s.storage.Moderate(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to moderate review: %v", err)
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
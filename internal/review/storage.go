@@ -0,0 +1,13 @@
+package review
+
+// Storage describes a review storage functionality.
+type Storage interface {
+	Create(input *CreateReviewDTO) (*Review, error)
+	FindById(id int64) (*Review, error)
+	FindAll(filter *Filter) ([]*Review, error)
+	Update(input *UpdateReviewDTO) (*Review, error)
+	Delete(id, userId int64) error
+	Report(input *ReportDTO) (*Report, error)
+	FindReports(reviewId int64) ([]*Report, error)
+	Moderate(input *ModerateDTO) (*Review, error)
+}
@@ -2,14 +2,45 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/config"
+	"github.com/juicyluv/ReadyRead/internal/address"
 	"github.com/juicyluv/ReadyRead/internal/author"
+	"github.com/juicyluv/ReadyRead/internal/basket"
+	"github.com/juicyluv/ReadyRead/internal/blob"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/collection"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/ebook"
+	"github.com/juicyluv/ReadyRead/internal/exporter"
+	"github.com/juicyluv/ReadyRead/internal/genre"
+	"github.com/juicyluv/ReadyRead/internal/importer"
+	"github.com/juicyluv/ReadyRead/internal/inventory"
+	"github.com/juicyluv/ReadyRead/internal/invoice"
+	"github.com/juicyluv/ReadyRead/internal/language"
+	"github.com/juicyluv/ReadyRead/internal/mailer"
+	"github.com/juicyluv/ReadyRead/internal/media"
 	"github.com/juicyluv/ReadyRead/internal/openapi"
+	"github.com/juicyluv/ReadyRead/internal/order"
+	"github.com/juicyluv/ReadyRead/internal/payment"
+	"github.com/juicyluv/ReadyRead/internal/pricing"
+	"github.com/juicyluv/ReadyRead/internal/promo"
+	"github.com/juicyluv/ReadyRead/internal/publisher"
+	"github.com/juicyluv/ReadyRead/internal/recommendation"
+	"github.com/juicyluv/ReadyRead/internal/restock"
+	"github.com/juicyluv/ReadyRead/internal/returns"
+	"github.com/juicyluv/ReadyRead/internal/review"
+	"github.com/juicyluv/ReadyRead/internal/shipping"
+	"github.com/juicyluv/ReadyRead/internal/tax"
 	"github.com/juicyluv/ReadyRead/internal/user"
+	"github.com/juicyluv/ReadyRead/internal/webhook"
+	"github.com/juicyluv/ReadyRead/internal/wishlist"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 	"github.com/julienschmidt/httprouter"
 )
@@ -20,10 +51,15 @@ type Server struct {
 	logger  *logger.Logger
 	cfg     *config.Config
 	handler *httprouter.Router
+
+	// ctx is cancelled on shutdown to stop background jobs.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewServer returns a new Server instance.
 func NewServer(cfg *config.Config, handler *httprouter.Router, logger *logger.Logger) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Server{
 		server: &http.Server{
 			Handler:        handler,
@@ -35,35 +71,394 @@ func NewServer(cfg *config.Config, handler *httprouter.Router, logger *logger.Lo
 		logger:  logger,
 		cfg:     cfg,
 		handler: handler,
+		ctx:     ctx,
+		cancel:  cancel,
 	}
 }
 
 // Run initializes storages, services, handlers and then starts http server. Returns an error on failure.
-func (s *Server) Run(dbConn *pgx.Conn) error {
+func (s *Server) Run(dbPool *pgxpool.Pool) error {
 	reqTimeout := s.cfg.DB.RequestTimeout
 
+	store, err := s.newBlobStore()
+	if err != nil {
+		return err
+	}
+	maxUploadSize := s.cfg.Storage.MaxUploadSize << 20
+	images := media.NewProcessor(store, maxUploadSize)
+
+	mail, err := s.newMailer()
+	if err != nil {
+		return err
+	}
+
 	s.logger.Info("initializing routes")
 
-	userStorage := user.NewStorage(dbConn, reqTimeout)
+	userStorage := user.NewStorage(dbPool, reqTimeout)
 	userService := user.NewService(userStorage, *s.logger)
 	userHandler := user.NewHandler(*s.logger, userService)
 	userHandler.Register(s.handler)
 	s.logger.Info("initialized user routes")
 
-	authorStorage := author.NewStorage(dbConn, reqTimeout)
-	authorService := author.NewService(authorStorage, *s.logger)
-	authorHandler := author.NewHandler(*s.logger, authorService)
+	addressStorage := address.NewStorage(dbPool, reqTimeout)
+	addressService := address.NewService(addressStorage, *s.logger)
+	addressHandler := address.NewHandler(*s.logger, addressService)
+	addressHandler.Register(s.handler)
+	s.logger.Info("initialized address routes")
+
+	currencyStorage := currency.NewStorage(dbPool, reqTimeout)
+	currencyService := currency.NewService(currencyStorage, *s.logger)
+	currencyHandler := currency.NewHandler(*s.logger, currencyService)
+	currencyHandler.Register(s.handler)
+	s.logger.Info("initialized currency routes")
+
+	bookStorage := book.NewStorage(dbPool, reqTimeout)
+	bookService := book.NewService(bookStorage, images, currencyService, *s.logger)
+	bookHandler := book.NewHandler(*s.logger, bookService, maxUploadSize)
+	bookHandler.Register(s.handler)
+	s.logger.Info("initialized book routes")
+
+	authorStorage := author.NewStorage(dbPool, reqTimeout)
+	authorService := author.NewService(authorStorage, images, *s.logger)
+	authorHandler := author.NewHandler(*s.logger, authorService, bookService, maxUploadSize)
 	authorHandler.Register(s.handler)
 	s.logger.Info("initialized author routes")
 
+	genreStorage := genre.NewStorage(dbPool, reqTimeout)
+	genreService := genre.NewService(genreStorage, *s.logger)
+	genreHandler := genre.NewHandler(*s.logger, genreService)
+	genreHandler.Register(s.handler)
+	s.logger.Info("initialized genre routes")
+
+	languageStorage := language.NewStorage(dbPool, reqTimeout)
+	languageService := language.NewService(languageStorage, *s.logger)
+	languageHandler := language.NewHandler(*s.logger, languageService)
+	languageHandler.Register(s.handler)
+	s.logger.Info("initialized language routes")
+
+	publisherStorage := publisher.NewStorage(dbPool, reqTimeout)
+	publisherService := publisher.NewService(publisherStorage, *s.logger)
+	publisherHandler := publisher.NewHandler(*s.logger, publisherService)
+	publisherHandler.Register(s.handler)
+	s.logger.Info("initialized publisher routes")
+
+	importStorage := importer.NewStorage(dbPool)
+	importService := importer.NewService(importStorage, currencyService, *s.logger)
+	importHandler := importer.NewHandler(*s.logger, importService)
+	importHandler.Register(s.handler)
+	s.logger.Info("initialized import routes")
+
+	exportHandler := exporter.NewHandler(*s.logger, bookService)
+	exportHandler.Register(s.handler)
+	s.logger.Info("initialized export routes")
+
+	inventoryStorage := inventory.NewStorage(dbPool, reqTimeout)
+	inventoryService := inventory.NewService(inventoryStorage, *s.logger)
+	inventoryHandler := inventory.NewHandler(*s.logger, inventoryService)
+	inventoryHandler.Register(s.handler)
+	s.logger.Info("initialized inventory routes")
+
+	pricingStorage := pricing.NewStorage(dbPool, reqTimeout)
+	pricingService := pricing.NewService(pricingStorage, *s.logger)
+	pricingHandler := pricing.NewHandler(*s.logger, pricingService)
+	pricingHandler.Register(s.handler)
+	s.logger.Info("initialized pricing routes")
+
+	if interval := s.cfg.Pricing.ApplyInterval; interval > 0 {
+		scheduler := pricing.NewScheduler(*s.logger, pricingService, time.Duration(interval)*time.Second)
+		go scheduler.Run(s.ctx)
+		s.logger.Infof("started price scheduler with %ds interval", interval)
+	}
+
+	orderStorage := order.NewStorage(dbPool, reqTimeout)
+	orderService := order.NewService(orderStorage, *s.logger)
+	orderHandler := order.NewHandler(*s.logger, orderService)
+	orderHandler.Register(s.handler)
+	s.logger.Info("initialized order routes")
+
+	promoStorage := promo.NewStorage(dbPool, reqTimeout)
+	promoService := promo.NewService(promoStorage, *s.logger)
+	promoHandler := promo.NewHandler(*s.logger, promoService)
+	promoHandler.Register(s.handler)
+	s.logger.Info("initialized promo code routes")
+
+	taxStorage := tax.NewStorage(dbPool, reqTimeout)
+	taxService := tax.NewService(taxStorage, s.cfg.Tax.PricesIncludeTax, *s.logger)
+	taxHandler := tax.NewHandler(*s.logger, taxService)
+	taxHandler.Register(s.handler)
+	s.logger.Info("initialized tax routes")
+
+	shippingStorage := shipping.NewStorage(dbPool, reqTimeout)
+	shippingService := shipping.NewService(shippingStorage, *s.logger)
+	shippingHandler := shipping.NewHandler(*s.logger, shippingService)
+	shippingHandler.Register(s.handler)
+	s.logger.Info("initialized shipping routes")
+
+	basketStorage := basket.NewStorage(dbPool, reqTimeout)
+	basketService := basket.NewService(
+		basketStorage,
+		inventoryService,
+		orderService,
+		promoService,
+		addressService,
+		currencyService,
+		taxService,
+		shippingService,
+		userService,
+		time.Duration(s.cfg.Inventory.ReservationTTL)*time.Minute,
+		time.Duration(s.cfg.Inventory.CheckoutTTL)*time.Minute,
+		int32(s.cfg.Shipping.DefaultWeight),
+		*s.logger,
+	)
+	basketHandler := basket.NewHandler(*s.logger, basketService)
+	basketHandler.Register(s.handler)
+	s.logger.Info("initialized basket routes")
+
+	signingKey, err := s.newSigningKey()
+	if err != nil {
+		return err
+	}
+
+	ebookStorage := ebook.NewStorage(dbPool, reqTimeout)
+	ebookService := ebook.NewService(
+		ebookStorage,
+		store,
+		bookService,
+		signingKey,
+		s.cfg.Ebooks.DownloadURL,
+		time.Duration(s.cfg.Ebooks.LinkTTL)*time.Minute,
+		int32(s.cfg.Ebooks.DownloadLimit),
+		*s.logger,
+	)
+	ebookHandler := ebook.NewHandler(*s.logger, ebookService, s.cfg.Ebooks.MaxFileSize<<20)
+	ebookHandler.Register(s.handler)
+	s.logger.Info("initialized ebook routes")
+
+	provider, err := s.newPaymentProvider()
+	if err != nil {
+		return err
+	}
+
+	paymentStorage := payment.NewStorage(dbPool, reqTimeout)
+	paymentService := payment.NewService(
+		paymentStorage,
+		provider,
+		orderService,
+		inventoryService,
+		ebookService,
+		*s.logger,
+	)
+	paymentHandler := payment.NewHandler(*s.logger, paymentService)
+	paymentHandler.Register(s.handler)
+	s.logger.Info("initialized payment routes")
+
+	returnStorage := returns.NewStorage(dbPool, reqTimeout)
+	returnService := returns.NewService(
+		returnStorage,
+		orderService,
+		inventoryService,
+		paymentService,
+		currencyService,
+		*s.logger,
+	)
+	returnHandler := returns.NewHandler(*s.logger, returnService)
+	returnHandler.Register(s.handler)
+	s.logger.Info("initialized returns routes")
+
+	seller := s.cfg.Invoices.Seller
+	invoiceStorage := invoice.NewStorage(dbPool, reqTimeout)
+	invoiceService := invoice.NewService(
+		invoiceStorage,
+		orderService,
+		userService,
+		currencyService,
+		invoice.Seller{
+			Name:    seller.Name,
+			Address: seller.Address,
+			TaxId:   seller.TaxId,
+			Email:   seller.Email,
+		},
+		*s.logger,
+	)
+	invoiceHandler := invoice.NewHandler(*s.logger, invoiceService)
+	invoiceHandler.Register(s.handler)
+	s.logger.Info("initialized invoice routes")
+
+	reviewStorage := review.NewStorage(dbPool, reqTimeout)
+	reviewService := review.NewService(reviewStorage, *s.logger)
+	reviewHandler := review.NewHandler(*s.logger, reviewService)
+	reviewHandler.Register(s.handler)
+	s.logger.Info("initialized review routes")
+
+	wishlistStorage := wishlist.NewStorage(dbPool, reqTimeout)
+	wishlistService := wishlist.NewService(wishlistStorage, basketService, currencyService, *s.logger)
+	wishlistHandler := wishlist.NewHandler(*s.logger, wishlistService)
+	wishlistHandler.Register(s.handler)
+	s.logger.Info("initialized wishlist routes")
+
+	recommendationStorage := recommendation.NewStorage(dbPool, reqTimeout)
+	recommendationService := recommendation.NewService(
+		recommendationStorage,
+		bookService,
+		s.cfg.Recommendations.Size,
+		*s.logger,
+	)
+	recommendationHandler := recommendation.NewHandler(*s.logger, recommendationService)
+	recommendationHandler.Register(s.handler)
+	s.logger.Info("initialized recommendation routes")
+
+	collectionCacheTTL := time.Duration(s.cfg.Collections.CacheTTL) * time.Second
+	collectionStorage := collection.NewStorage(dbPool, reqTimeout)
+	collectionService := collection.NewService(collectionStorage, bookService, collectionCacheTTL, *s.logger)
+	collectionHandler := collection.NewHandler(*s.logger, collectionService, collectionCacheTTL)
+	collectionHandler.Register(s.handler)
+	s.logger.Info("initialized collection routes")
+
+	if interval := s.cfg.Invoices.IssueInterval; interval > 0 {
+		issuer := invoice.NewIssuer(*s.logger, invoiceService, time.Duration(interval)*time.Second)
+		go issuer.Run(s.ctx)
+		s.logger.Infof("started invoice issuer with %ds interval", interval)
+	}
+
+	if interval := s.cfg.Recommendations.RefreshInterval; interval > 0 {
+		refresher := recommendation.NewRefresher(*s.logger, recommendationService, time.Duration(interval)*time.Second)
+		go refresher.Run(s.ctx)
+		s.logger.Infof("started recommendations refresher with %ds interval", interval)
+	}
+
+	if interval := s.cfg.Inventory.SweepInterval; interval > 0 {
+		sweeper := inventory.NewSweeper(*s.logger, inventoryService, time.Duration(interval)*time.Second)
+		go sweeper.Run(s.ctx)
+		s.logger.Infof("started reservations sweeper with %ds interval", interval)
+	}
+
+	var alertsWebhook *webhook.Client
+	if url := s.cfg.Notifications.WebhookURL; url != "" {
+		alertsWebhook = webhook.NewClient(url, s.cfg.Notifications.WebhookSecret)
+	}
+
+	restockStorage := restock.NewStorage(dbPool, reqTimeout)
+	restockService := restock.NewService(
+		restockStorage,
+		mail,
+		s.cfg.Notifications.StaffEmails,
+		alertsWebhook,
+		*s.logger,
+	)
+	restockHandler := restock.NewHandler(*s.logger, restockService)
+	restockHandler.Register(s.handler)
+	s.logger.Info("initialized restock routes")
+
+	if interval := s.cfg.Notifications.Interval; interval > 0 {
+		notifier := restock.NewNotifier(*s.logger, restockService, time.Duration(interval)*time.Second)
+		go notifier.Run(s.ctx)
+		s.logger.Infof("started stock notifier with %ds interval", interval)
+	}
+
 	openapi.InitSwagger(s.handler)
 	s.logger.Info("initialized documentation")
 
 	return s.server.ListenAndServe()
 }
 
-// Shutdown closes all connections and shuts down http server.
+// newBlobStore creates a blob store for uploaded files with configured driver.
+// Files of the local store are served by the server itself under /media.
+func (s *Server) newBlobStore() (blob.Store, error) {
+	cfg := s.cfg.Storage
+
+	switch cfg.Driver {
+	case "local":
+		store, err := blob.NewLocalStore(cfg.LocalDir, cfg.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		s.handler.ServeFiles("/media/*filepath", http.Dir(cfg.LocalDir))
+		s.logger.Infof("storing uploaded files in %s", cfg.LocalDir)
+		return store, nil
+	case "s3":
+		store, err := blob.NewS3Store(blob.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PublicURL: cfg.S3.PublicURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Infof("storing uploaded files in s3 bucket %s", cfg.S3.Bucket)
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// newMailer creates a mailer with configured SMTP server.
+// Emails are written to the log if the server is not configured.
+func (s *Server) newMailer() (mailer.Mailer, error) {
+	cfg := s.cfg.Mailer
+
+	if cfg.Host == "" {
+		s.logger.Warn("smtp host is not set, emails are written to the log")
+		return mailer.NewLogMailer(*s.logger), nil
+	}
+
+	return mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	})
+}
+
+// newPaymentProvider creates the configured payment provider. The fake
+// provider signs its webhook events with a random secret if it's not set.
+func (s *Server) newPaymentProvider() (payment.Provider, error) {
+	cfg := s.cfg.Payments
+
+	switch cfg.Provider {
+	case "fake":
+		secret := cfg.WebhookSecret
+		if secret == "" {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return nil, fmt.Errorf("cannot generate payments webhook secret: %v", err)
+			}
+			secret = hex.EncodeToString(key)
+		}
+		s.logger.Warn("payments are processed by the fake provider")
+		return payment.NewFakeProvider(
+			cfg.Fake.WebhookURL,
+			secret,
+			time.Duration(cfg.Fake.Delay)*time.Second,
+			*s.logger,
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+	}
+}
+
+// newSigningKey returns the key download links of ebooks are signed with.
+// A random key is generated if it's not set.
+func (s *Server) newSigningKey() ([]byte, error) {
+	if key := s.cfg.Ebooks.SigningKey; key != "" {
+		return []byte(key), nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("cannot generate ebooks signing key: %v", err)
+	}
+	s.logger.Warn("ebooks signing key is not set, download links stop working on restart")
+
+	return key, nil
+}
+
+// Shutdown stops background jobs, closes all connections and shuts down http server.
 // It uses httpServer.Shutdown() method. Returns an error on failure.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.cancel()
 	return s.server.Shutdown(ctx)
 }
@@ -0,0 +1,68 @@
+package shipping
+
+import (
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+// Convert returns copies of the rates with amounts converted from the base
+// currency to the given one. Returns ErrUnknownCurrency if the currency
+// has no exchange rate.
+func Convert(rates []Rate, exchange *currency.Rates, to string) ([]Rate, error) {
+	base := exchange.Base().Code
+
+	convert := func(amount *decimal.Decimal) (*decimal.Decimal, error) {
+		if amount == nil {
+			return nil, nil
+		}
+		converted, err := exchange.Convert(*amount, base, to)
+		if err != nil {
+			return nil, err
+		}
+		return &converted, nil
+	}
+
+	converted := make([]Rate, len(rates))
+	for i, r := range rates {
+		price, err := exchange.Convert(r.Price, base, to)
+		if err != nil {
+			return nil, err
+		}
+		r.Price = price
+
+		if r.MinTotal, err = convert(r.MinTotal); err != nil {
+			return nil, err
+		}
+		if r.FreeOver, err = convert(r.FreeOver); err != nil {
+			return nil, err
+		}
+
+		converted[i] = r
+	}
+
+	return converted, nil
+}
+
+// Calculate returns the cost of shipping the parcel to the region with
+// rates of the method for its country and whether the shipping is free.
+// The most specific matching rate is used. The last result is false if
+// no rate matches the parcel.
+func Calculate(rates []Rate, region *string, parcel Parcel) (decimal.Decimal, bool, bool) {
+	var found *Rate
+	for i := range rates {
+		r := &rates[i]
+		if r.matches(region, parcel) && (found == nil || r.narrower(found)) {
+			found = r
+		}
+	}
+
+	if found == nil {
+		return decimal.Zero, false, false
+	}
+
+	if found.FreeOver != nil && !parcel.Total.LessThan(*found.FreeOver) {
+		return decimal.Zero, true, true
+	}
+
+	return found.Price, false, true
+}
@@ -0,0 +1,65 @@
+package shipping
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(v string) decimal.Decimal { return decimal.RequireFromString(v) }
+
+func decPtr(v string) *decimal.Decimal {
+	d := dec(v)
+	return &d
+}
+
+func stringPtr(v string) *string { return &v }
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestCalculate(t *testing.T) {
+	bavaria := stringPtr("Bavaria")
+
+	country := Rate{Id: 1, Country: "DE", Price: dec("4.99"), FreeOver: decPtr("50")}
+	light := Rate{Id: 2, Country: "DE", MaxWeight: int32Ptr(500), Price: dec("2.99")}
+	large := Rate{Id: 3, Country: "DE", MinTotal: decPtr("20"), Price: dec("3.99")}
+	region := Rate{Id: 4, Country: "DE", Region: bavaria, MaxWeight: int32Ptr(2000), Price: dec("6.99")}
+
+	tests := []struct {
+		name      string
+		rates     []Rate
+		region    *string
+		parcel    Parcel
+		wantCost  string
+		wantFree  bool
+		wantFound bool
+	}{
+		{name: "country rate", rates: []Rate{country}, parcel: Parcel{Weight: 1000, Total: dec("10")}, wantCost: "4.99", wantFound: true},
+		{name: "free over total", rates: []Rate{country}, parcel: Parcel{Weight: 1000, Total: dec("50")}, wantCost: "0", wantFree: true, wantFound: true},
+		{name: "just under free total", rates: []Rate{country}, parcel: Parcel{Weight: 1000, Total: dec("49.99")}, wantCost: "4.99", wantFound: true},
+		{name: "lighter limit wins", rates: []Rate{country, light}, parcel: Parcel{Weight: 500, Total: dec("10")}, wantCost: "2.99", wantFound: true},
+		{name: "too heavy for limit", rates: []Rate{country, light}, parcel: Parcel{Weight: 501, Total: dec("10")}, wantCost: "4.99", wantFound: true},
+		{name: "minimum total wins", rates: []Rate{country, large}, parcel: Parcel{Weight: 1000, Total: dec("20")}, wantCost: "3.99", wantFound: true},
+		{name: "under minimum total", rates: []Rate{large}, parcel: Parcel{Weight: 1000, Total: dec("19.99")}, wantCost: "0"},
+		{name: "region beats country", rates: []Rate{light, country, region}, region: bavaria, parcel: Parcel{Weight: 400, Total: dec("10")}, wantCost: "6.99", wantFound: true},
+		{name: "region in another case", rates: []Rate{country, region}, region: stringPtr("BAVARIA"), parcel: Parcel{Weight: 400, Total: dec("10")}, wantCost: "6.99", wantFound: true},
+		{name: "another region", rates: []Rate{country, region}, region: stringPtr("Saxony"), parcel: Parcel{Weight: 400, Total: dec("10")}, wantCost: "4.99", wantFound: true},
+		{name: "no region", rates: []Rate{region}, parcel: Parcel{Weight: 400, Total: dec("10")}, wantCost: "0"},
+		{name: "no rates", parcel: Parcel{Weight: 400, Total: dec("10")}, wantCost: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, free, found := Calculate(tt.rates, tt.region, tt.parcel)
+			if found != tt.wantFound {
+				t.Fatalf("Calculate() found = %v, want %v", found, tt.wantFound)
+			}
+			if free != tt.wantFree {
+				t.Errorf("Calculate() free = %v, want %v", free, tt.wantFree)
+			}
+			if !cost.Equal(dec(tt.wantCost)) {
+				t.Errorf("Calculate() cost = %s, want %s", cost, tt.wantCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package shipping
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	methodsURL = "/api/shipping-methods"
+	methodURL  = "/api/shipping-methods/:id"
+	ratesURL   = "/api/shipping-methods/:id/rates"
+	rateURL    = "/api/shipping-methods/:id/rates/:rateId"
+)
+
+// Handler handles requests specified to shipping service.
+type Handler struct {
+	logger          logger.Logger
+	shippingService Service
+}
+
+// NewHandler returns a new shipping Handler instance.
+func NewHandler(logger logger.Logger, shippingService Service) handler.Handling {
+	return &Handler{
+		logger:          logger,
+		shippingService: shippingService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, methodsURL, h.ListShippingMethods)
+	router.HandlerFunc(http.MethodPost, methodsURL, h.CreateShippingMethod)
+	router.HandlerFunc(http.MethodGet, methodURL, h.GetShippingMethod)
+	router.HandlerFunc(http.MethodPut, methodURL, h.UpdateShippingMethod)
+	router.HandlerFunc(http.MethodDelete, methodURL, h.DeleteShippingMethod)
+	router.HandlerFunc(http.MethodPost, ratesURL, h.CreateShippingRate)
+	router.HandlerFunc(http.MethodPut, rateURL, h.UpdateShippingRate)
+	router.HandlerFunc(http.MethodDelete, rateURL, h.DeleteShippingRate)
+}
+
+// ListShippingMethods godoc
+// @Summary List shipping methods
+// @Description Get shipping methods with their rates, including inactive ones. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Success 200 {array} Method
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods [get]
+func (h *Handler) ListShippingMethods(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST SHIPPING METHODS")
+
+	methods, err := h.shippingService.GetMethods(r.Context(), false)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, methods)
+}
+
+// GetShippingMethod godoc
+// @Summary Show shipping method
+// @Description Get shipping method by id with its rates. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Success 200 {object} Method
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id} [get]
+func (h *Handler) GetShippingMethod(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET SHIPPING METHOD")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	method, err := h.shippingService.GetMethod(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, method)
+}
+
+// CreateShippingMethod godoc
+// @Summary Create shipping method
+// @Description Create shipping method. Add rates to make it available for destinations. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param input body MethodDTO true "JSON input"
+// @Success 201 {object} Method
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods [post]
+func (h *Handler) CreateShippingMethod(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE SHIPPING METHOD")
+
+	var input MethodDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	method, err := h.shippingService.CreateMethod(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShippingMethodExists) {
+			response.Conflict(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, method)
+}
+
+// UpdateShippingMethod godoc
+// @Summary Update shipping method
+// @Description Update shipping method with specified id. Inactive methods can't be chosen for baskets, placed orders keep their shipping cost. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Param input body MethodDTO true "JSON input"
+// @Success 200 {object} Method
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id} [put]
+func (h *Handler) UpdateShippingMethod(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE SHIPPING METHOD")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input MethodDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	method, err := h.shippingService.UpdateMethod(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrShippingMethodExists):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, method)
+}
+
+// DeleteShippingMethod godoc
+// @Summary Delete shipping method
+// @Description Delete shipping method with its rates. Placed orders keep the name of the method. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id} [delete]
+func (h *Handler) DeleteShippingMethod(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE SHIPPING METHOD")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.shippingService.DeleteMethod(r.Context(), id); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// CreateShippingRate godoc
+// @Summary Create shipping rate
+// @Description Add the rate to the shipping method. Amounts are in the base currency. The most specific rate matching the parcel is used: rates of the region win over rates of the country, then lighter weight limits and higher minimum totals win. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Param input body RateDTO true "JSON input"
+// @Success 201 {object} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id}/rates [post]
+func (h *Handler) CreateShippingRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE SHIPPING RATE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input RateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Country = strings.ToUpper(input.Country)
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.MethodId = id
+
+	rate, err := h.shippingService.CreateRate(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, rate)
+}
+
+// UpdateShippingRate godoc
+// @Summary Update shipping rate
+// @Description Update the rate of the shipping method. Amounts are in the base currency. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Param rateId path int64 true "Shipping rate id"
+// @Param input body RateDTO true "JSON input"
+// @Success 200 {object} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id}/rates/{rateId} [put]
+func (h *Handler) UpdateShippingRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE SHIPPING RATE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	rateId, err := handler.ReadParam64(r, "rateId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input RateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	input.Country = strings.ToUpper(input.Country)
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = rateId
+	input.MethodId = id
+
+	rate, err := h.shippingService.UpdateRate(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rate)
+}
+
+// DeleteShippingRate godoc
+// @Summary Delete shipping rate
+// @Description Delete the rate of the shipping method. Admin only.
+// @Tags shipping
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Shipping method id"
+// @Param rateId path int64 true "Shipping rate id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /shipping-methods/{id}/rates/{rateId} [delete]
+func (h *Handler) DeleteShippingRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE SHIPPING RATE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	rateId, err := handler.ReadParam64(r, "rateId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.shippingService.DeleteRate(r.Context(), id, rateId); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,160 @@
+package shipping
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// KindCourier is a kind of delivery to the door by courier.
+	KindCourier = "courier"
+	// KindPickup is a kind of delivery to a pickup point.
+	KindPickup = "pickup"
+	// KindPost is a kind of delivery by post.
+	KindPost = "post"
+)
+
+// codePattern matches codes of shipping methods, e.g. "dhl-express".
+var codePattern = regexp.MustCompile(`^[a-z0-9]+([_-][a-z0-9]+)*$`)
+
+// Method represents the way orders are delivered.
+// Inactive methods can't be chosen for baskets.
+type Method struct {
+	Id          int64     `json:"id" example:"1"`
+	Code        string    `json:"code" example:"courier-express"`
+	Name        string    `json:"name" example:"Express courier"`
+	Kind        string    `json:"kind" example:"courier"`
+	Description *string   `json:"description,omitempty" example:"Delivery to the door in 1-2 days"`
+	Active      bool      `json:"active" example:"true"`
+	Rates       []Rate    `json:"rates"`
+	CreatedAt   time.Time `json:"createdAt" example:"2022-02-20T12:00:00Z"`
+} // @name ShippingMethod
+
+// MethodDTO is used to create or update the shipping method.
+type MethodDTO struct {
+	Id          int64   `json:"-"`
+	Code        string  `json:"code" example:"courier-express"`
+	Name        string  `json:"name" example:"Express courier"`
+	Kind        string  `json:"kind" example:"courier"`
+	Description *string `json:"description,omitempty" example:"Delivery to the door in 1-2 days"`
+	Active      bool    `json:"active" example:"true"`
+} // @name ShippingMethodInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (m *MethodDTO) Validate() error {
+	return validation.ValidateStruct(
+		m,
+		validation.Field(
+			&m.Code,
+			validation.Required,
+			validation.Length(2, 50),
+			validation.Match(codePattern).Error("must contain only lower case letters, digits, hyphens and underscores"),
+		),
+		validation.Field(&m.Name, validation.Required, validation.RuneLength(1, 100), validator.Text),
+		validation.Field(&m.Kind, validation.Required, validation.In(KindCourier, KindPickup, KindPost)),
+		validation.Field(&m.Description, validation.RuneLength(1, 500)),
+	)
+}
+
+// Rate represents the price of shipping to the country by the method.
+// Rates without a region apply to the whole country. MaxWeight in grams and
+// MinTotal limit parcels the rate applies to, the shipping is free once
+// the parcel total reaches FreeOver. Amounts are in the base currency.
+type Rate struct {
+	Id        int64            `json:"id" example:"1"`
+	MethodId  int64            `json:"methodId" example:"1"`
+	Country   string           `json:"country" example:"DE"`
+	Region    *string          `json:"region,omitempty" example:"Bavaria"`
+	MaxWeight *int32           `json:"maxWeight,omitempty" example:"2000"`
+	MinTotal  *decimal.Decimal `json:"minTotal,omitempty" swaggertype:"string" example:"20"`
+	Price     decimal.Decimal  `json:"price" swaggertype:"string" example:"4.99"`
+	FreeOver  *decimal.Decimal `json:"freeOver,omitempty" swaggertype:"string" example:"50"`
+} // @name ShippingRate
+
+// RateDTO is used to create or update the rate of the shipping method.
+type RateDTO struct {
+	Id        int64            `json:"-"`
+	MethodId  int64            `json:"-"`
+	Country   string           `json:"country" example:"DE"`
+	Region    *string          `json:"region,omitempty" example:"Bavaria"`
+	MaxWeight *int32           `json:"maxWeight,omitempty" example:"2000"`
+	MinTotal  *decimal.Decimal `json:"minTotal,omitempty" swaggertype:"string" example:"20"`
+	Price     decimal.Decimal  `json:"price" swaggertype:"string" example:"4.99"`
+	FreeOver  *decimal.Decimal `json:"freeOver,omitempty" swaggertype:"string" example:"50"`
+} // @name ShippingRateInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *RateDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Country, validation.Required, validator.CountryCode),
+		validation.Field(&r.Region, validation.RuneLength(1, 100), validator.Text),
+		validation.Field(&r.MaxWeight, validation.Min(1)),
+		validation.Field(&r.MinTotal, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&r.Price, validator.MinDecimal(decimal.Zero)),
+		validation.Field(&r.FreeOver, validator.MinDecimal(decimal.Zero)),
+	)
+}
+
+// Parcel is what is shipped. Weight is in grams, Total is the price
+// of the books after discounts.
+type Parcel struct {
+	Weight int64
+	Total  decimal.Decimal
+}
+
+// Quote is the cost of shipping the parcel by the method.
+type Quote struct {
+	MethodId int64           `json:"methodId" example:"1"`
+	Code     string          `json:"code" example:"courier-express"`
+	Name     string          `json:"name" example:"Express courier"`
+	Kind     string          `json:"kind" example:"courier"`
+	Cost     decimal.Decimal `json:"cost" swaggertype:"string" example:"4.99"`
+	Free     bool            `json:"free" example:"false"`
+} // @name ShippingQuote
+
+// matches reports whether the rate applies to the parcel shipped to the
+// region. Regions are compared case-insensitively.
+func (r *Rate) matches(region *string, parcel Parcel) bool {
+	if r.Region != nil && (region == nil || !strings.EqualFold(*r.Region, *region)) {
+		return false
+	}
+
+	if r.MaxWeight != nil && parcel.Weight > int64(*r.MaxWeight) {
+		return false
+	}
+
+	return r.MinTotal == nil || !parcel.Total.LessThan(*r.MinTotal)
+}
+
+// narrower reports whether the rate is more specific than the other one.
+// Rates of the region win over rates of the country, then lighter weight
+// limits and higher minimum totals win.
+func (r *Rate) narrower(other *Rate) bool {
+	if (r.Region != nil) != (other.Region != nil) {
+		return r.Region != nil
+	}
+
+	if !equalWeight(r.MaxWeight, other.MaxWeight) {
+		return other.MaxWeight == nil || r.MaxWeight != nil && *r.MaxWeight < *other.MaxWeight
+	}
+
+	if r.MinTotal == nil || other.MinTotal == nil {
+		return r.MinTotal != nil
+	}
+	return r.MinTotal.GreaterThan(*other.MinTotal)
+}
+
+func equalWeight(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,291 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	methodsTableName = "shipping_methods"
+	ratesTableName   = "shipping_rates"
+
+	// selectQuery selects shipping methods with their rates aggregated to a JSON array.
+	selectQuery = `
+	SELECT m.id, m.code, m.name, m.kind, m.description, m.active, m.created_at,
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'id', r.id, 'methodId', r.method_id, 'country', r.country, 'region', r.region,
+				'maxWeight', r.max_weight_g, 'minTotal', r.min_total, 'price', r.price, 'freeOver', r.free_over
+			) ORDER BY r.country, r.region NULLS FIRST, r.max_weight_g NULLS LAST, r.min_total NULLS FIRST)
+			FROM shipping_rates r
+			WHERE r.method_id = m.id
+		), '[]')
+	FROM shipping_methods m`
+)
+
+// Check whether db implements shipping storage interface.
+var _ Storage = &db{}
+
+// db implements shipping storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new shipping storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// FindMethods finds shipping methods with their rates ordered by name.
+// Only active methods are returned if activeOnly is set.
+// Returns an error on failure.
+func (d *db) FindMethods(activeOnly bool) ([]*Method, error) {
+	query := selectQuery + " WHERE m.active OR NOT $1 ORDER BY m.name, m.id"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, activeOnly)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find shipping methods query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := make([]*Method, 0)
+	for rows.Next() {
+		m, err := scanMethod(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan shipping method: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read shipping methods: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return methods, nil
+}
+
+// FindMethod finds the shipping method with specified id with its rates.
+// Returns ErrNoRows if the method doesn't exist or an error on failure.
+func (d *db) FindMethod(id int64) (*Method, error) {
+	query := selectQuery + " WHERE m.id = $1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	m, err := scanMethod(d.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find shipping method query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// CreateMethod inserts the shipping method. Returns ErrShippingMethodExists
+// if the code is taken, an error on failure or the inserted method on success.
+func (d *db) CreateMethod(method *Method) (*Method, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (code, name, kind, description, active)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at`, methodsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(ctx, query, method.Code, method.Name, method.Kind, method.Description, method.Active).
+		Scan(&method.Id, &method.CreatedAt)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrShippingMethodExists
+		}
+		err = fmt.Errorf("failed to execute create shipping method query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	method.Rates = make([]Rate, 0)
+
+	return method, nil
+}
+
+// UpdateMethod updates the shipping method. Returns ErrNoRows if the method
+// doesn't exist, ErrShippingMethodExists if the code is taken, an error on
+// failure or the updated method with its rates on success.
+func (d *db) UpdateMethod(method *Method) (*Method, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET code = $2, name = $3, kind = $4, description = $5, active = $6
+	WHERE id = $1`, methodsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, method.Id, method.Code, method.Name, method.Kind, method.Description, method.Active)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrShippingMethodExists
+		}
+		err = fmt.Errorf("failed to execute update shipping method query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return d.FindMethod(method.Id)
+}
+
+// DeleteMethod deletes the shipping method with its rates. Orders keep the
+// name of the method. Returns ErrNoRows if the method doesn't exist or an
+// error on failure.
+func (d *db) DeleteMethod(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", methodsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete shipping method query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateRate inserts the rate of the shipping method. Returns ErrNoRows if
+// the method doesn't exist, an error on failure or the inserted rate on success.
+func (d *db) CreateRate(rate *Rate) (*Rate, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (method_id, country, region, max_weight_g, min_total, price, free_over)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id`, ratesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(
+		ctx,
+		query,
+		rate.MethodId,
+		rate.Country,
+		rate.Region,
+		rate.MaxWeight,
+		rate.MinTotal,
+		rate.Price,
+		rate.FreeOver,
+	).Scan(&rate.Id)
+	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute create shipping rate query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// UpdateRate updates the rate of the shipping method. Returns ErrNoRows if
+// the method has no such rate, an error on failure or the updated rate
+// on success.
+func (d *db) UpdateRate(rate *Rate) (*Rate, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET country = $3, region = $4, max_weight_g = $5, min_total = $6, price = $7, free_over = $8
+	WHERE id = $1 AND method_id = $2`, ratesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(
+		ctx,
+		query,
+		rate.Id,
+		rate.MethodId,
+		rate.Country,
+		rate.Region,
+		rate.MaxWeight,
+		rate.MinTotal,
+		rate.Price,
+		rate.FreeOver,
+	)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update shipping rate query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return rate, nil
+}
+
+// DeleteRate deletes the rate of the shipping method.
+// Returns ErrNoRows if the method has no such rate or an error on failure.
+func (d *db) DeleteRate(methodId, id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND method_id = $2", ratesTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, id, methodId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete shipping rate query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanMethod scans a row selected with selectQuery into a shipping method.
+func scanMethod(row pgx.Row) (*Method, error) {
+	var m Method
+	err := row.Scan(&m.Id, &m.Code, &m.Name, &m.Kind, &m.Description, &m.Active, &m.CreatedAt, &m.Rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
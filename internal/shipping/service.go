@@ -0,0 +1,256 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/tax"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes shipping service functionality.
+type Service interface {
+	GetMethods(ctx context.Context, activeOnly bool) ([]*Method, error)
+	GetMethod(ctx context.Context, id int64) (*Method, error)
+	CreateMethod(ctx context.Context, input *MethodDTO) (*Method, error)
+	UpdateMethod(ctx context.Context, input *MethodDTO) (*Method, error)
+	DeleteMethod(ctx context.Context, id int64) error
+	CreateRate(ctx context.Context, input *RateDTO) (*Rate, error)
+	UpdateRate(ctx context.Context, input *RateDTO) (*Rate, error)
+	DeleteRate(ctx context.Context, methodId, id int64) error
+	Quote(ctx context.Context, methodId int64, dest *tax.Destination, parcel Parcel, rates *currency.Rates, to string) (*Quote, error)
+	QuoteAll(ctx context.Context, dest *tax.Destination, parcel Parcel, rates *currency.Rates, to string) ([]*Quote, error)
+}
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+// NewService returns a new instance that implements Service interface.
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+func (s *service) GetMethods(ctx context.Context, activeOnly bool) ([]*Method, error) {
+	methods, err := s.storage.FindMethods(activeOnly)
+	if err != nil {
+		s.logger.Warnf("cannot find shipping methods: %v", err)
+		return nil, err
+	}
+
+	return methods, nil
+}
+
+func (s *service) GetMethod(ctx context.Context, id int64) (*Method, error) {
+	method, err := s.storage.FindMethod(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find shipping method by id: %v", err)
+		}
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s *service) CreateMethod(ctx context.Context, input *MethodDTO) (*Method, error) {
+	method, err := s.storage.CreateMethod(&Method{
+		Code:        input.Code,
+		Name:        input.Name,
+		Kind:        input.Kind,
+		Description: input.Description,
+		Active:      input.Active,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrShippingMethodExists) {
+			s.logger.Errorf("failed to create shipping method: %v", err)
+		}
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s *service) UpdateMethod(ctx context.Context, input *MethodDTO) (*Method, error) {
+	method, err := s.storage.UpdateMethod(&Method{
+		Id:          input.Id,
+		Code:        input.Code,
+		Name:        input.Name,
+		Kind:        input.Kind,
+		Description: input.Description,
+		Active:      input.Active,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrShippingMethodExists) {
+			s.logger.Errorf("failed to update shipping method: %v", err)
+		}
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s *service) DeleteMethod(ctx context.Context, id int64) error {
+	err := s.storage.DeleteMethod(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete shipping method: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) CreateRate(ctx context.Context, input *RateDTO) (*Rate, error) {
+	rate, err := s.storage.CreateRate(rateFromDTO(input))
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to create shipping rate: %v", err)
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *service) UpdateRate(ctx context.Context, input *RateDTO) (*Rate, error) {
+	rate, err := s.storage.UpdateRate(rateFromDTO(input))
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to update shipping rate: %v", err)
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *service) DeleteRate(ctx context.Context, methodId, id int64) error {
+	err := s.storage.DeleteRate(methodId, id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete shipping rate: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Quote calculates the cost of shipping the parcel to the destination by
+// the method in the given currency. The parcel total is in that currency
+// too. Returns ErrNoRows if the method doesn't exist or is inactive and
+// ErrShippingUnavailable if it has no rate for the destination and parcel.
+func (s *service) Quote(
+	ctx context.Context,
+	methodId int64,
+	dest *tax.Destination,
+	parcel Parcel,
+	rates *currency.Rates,
+	to string,
+) (*Quote, error) {
+	method, err := s.GetMethod(ctx, methodId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !method.Active {
+		return nil, apperror.ErrNoRows
+	}
+
+	quote, err := quote(method, dest, parcel, rates, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote == nil {
+		return nil, apperror.ErrShippingUnavailable
+	}
+
+	return quote, nil
+}
+
+// QuoteAll calculates costs of shipping the parcel to the destination by
+// active methods which have a rate for it, cheapest first.
+func (s *service) QuoteAll(
+	ctx context.Context,
+	dest *tax.Destination,
+	parcel Parcel,
+	rates *currency.Rates,
+	to string,
+) ([]*Quote, error) {
+	methods, err := s.GetMethods(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]*Quote, 0, len(methods))
+	for _, method := range methods {
+		quote, err := quote(method, dest, parcel, rates, to)
+		if err != nil {
+			return nil, err
+		}
+		if quote != nil {
+			quotes = append(quotes, quote)
+		}
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].Cost.LessThan(quotes[j].Cost)
+	})
+
+	return quotes, nil
+}
+
+// quote calculates the cost of shipping the parcel to the destination by
+// the method. Returns nil if the method has no rate for the destination
+// and parcel.
+func quote(method *Method, dest *tax.Destination, parcel Parcel, rates *currency.Rates, to string) (*Quote, error) {
+	countryRates := make([]Rate, 0, len(method.Rates))
+	for _, r := range method.Rates {
+		if r.Country == dest.Country {
+			countryRates = append(countryRates, r)
+		}
+	}
+
+	countryRates, err := Convert(countryRates, rates, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cost, free, ok := Calculate(countryRates, dest.Region, parcel)
+	if !ok {
+		return nil, nil
+	}
+
+	return &Quote{
+		MethodId: method.Id,
+		Code:     method.Code,
+		Name:     method.Name,
+		Kind:     method.Kind,
+		Cost:     cost,
+		Free:     free,
+	}, nil
+}
+
+// rateFromDTO returns the rate of the input.
+func rateFromDTO(input *RateDTO) *Rate {
+	return &Rate{
+		Id:        input.Id,
+		MethodId:  input.MethodId,
+		Country:   input.Country,
+		Region:    input.Region,
+		MaxWeight: input.MaxWeight,
+		MinTotal:  input.MinTotal,
+		Price:     input.Price,
+		FreeOver:  input.FreeOver,
+	}
+}
@@ -0,0 +1,13 @@
+package shipping
+
+// Storage describes a shipping storage functionality.
+type Storage interface {
+	FindMethods(activeOnly bool) ([]*Method, error)
+	FindMethod(id int64) (*Method, error)
+	CreateMethod(method *Method) (*Method, error)
+	UpdateMethod(method *Method) (*Method, error)
+	DeleteMethod(id int64) error
+	CreateRate(rate *Rate) (*Rate, error)
+	UpdateRate(rate *Rate) (*Rate, error)
+	DeleteRate(methodId, id int64) error
+}
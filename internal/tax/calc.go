@@ -0,0 +1,52 @@
+package tax
+
+import (
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+// Calculate calculates the tax of the lines shipped to the region with
+// rates of its country. Lines without a matching rate are not taxed.
+// If prices include tax, the tax is the part of the amount, otherwise
+// it's added on top of it. Taxes are rounded by the rules of the currency.
+func Calculate(rates []*Rate, region *string, lines []Line, pricesIncludeTax bool, cur *currency.Currency) []LineTax {
+	hundred := decimal.New(100, 0)
+
+	taxes := make([]LineTax, len(lines))
+	for i, l := range lines {
+		rate := lookup(rates, region, l.Category)
+		if rate.IsZero() {
+			continue
+		}
+
+		var amount decimal.Decimal
+		if pricesIncludeTax {
+			amount = l.Amount.Sub(l.Amount.Mul(hundred).Div(hundred.Add(rate)))
+		} else {
+			amount = l.Amount.Mul(rate).Div(hundred)
+		}
+
+		taxes[i] = LineTax{
+			Rate:   rate,
+			Amount: cur.Round(amount),
+		}
+	}
+
+	return taxes
+}
+
+// lookup returns the most specific rate for books of the category
+// shipped to the region or zero if there is none.
+func lookup(rates []*Rate, region *string, category string) decimal.Decimal {
+	var found *Rate
+	for _, r := range rates {
+		if r.matches(region, category) && (found == nil || r.specificity() > found.specificity()) {
+			found = r
+		}
+	}
+
+	if found == nil {
+		return decimal.Zero
+	}
+	return found.Rate
+}
@@ -0,0 +1,68 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+var euro = &currency.Currency{Code: "EUR", Decimals: 2, Rounding: currency.RoundHalfUp}
+
+func dec(v string) decimal.Decimal { return decimal.RequireFromString(v) }
+
+func stringPtr(v string) *string { return &v }
+
+// rate returns the rate of Germany with optional region and category.
+func rate(value string, region, category *string) *Rate {
+	return &Rate{Country: "DE", Region: region, Category: category, Rate: dec(value)}
+}
+
+func TestCalculate(t *testing.T) {
+	ebook := stringPtr(CategoryEbook)
+	bavaria := stringPtr("Bavaria")
+
+	rates := []*Rate{
+		rate("19", nil, nil),
+		rate("7", nil, ebook),
+		rate("10", bavaria, nil),
+		rate("5", bavaria, ebook),
+	}
+
+	tests := []struct {
+		name       string
+		rates      []*Rate
+		region     *string
+		line       Line
+		includeTax bool
+		wantRate   string
+		wantAmount string
+	}{
+		{name: "added on top", rates: rates, line: Line{Category: CategoryPrint, Amount: dec("10")}, wantRate: "19", wantAmount: "1.9"},
+		{name: "included in price", rates: rates, line: Line{Category: CategoryPrint, Amount: dec("11.9")}, includeTax: true, wantRate: "19", wantAmount: "1.9"},
+		{name: "rounded on top", rates: rates, line: Line{Category: CategoryPrint, Amount: dec("0.99")}, wantRate: "19", wantAmount: "0.19"},
+		{name: "rounded included", rates: rates, line: Line{Category: CategoryPrint, Amount: dec("0.99")}, includeTax: true, wantRate: "19", wantAmount: "0.16"},
+		{name: "category rate", rates: rates, line: Line{Category: CategoryEbook, Amount: dec("10")}, wantRate: "7", wantAmount: "0.7"},
+		{name: "region beats category", rates: rates[:3], region: bavaria, line: Line{Category: CategoryEbook, Amount: dec("10")}, wantRate: "10", wantAmount: "1"},
+		{name: "region and category", rates: rates, region: bavaria, line: Line{Category: CategoryEbook, Amount: dec("10")}, wantRate: "5", wantAmount: "0.5"},
+		{name: "region in another case", rates: rates, region: stringPtr("BAVARIA"), line: Line{Category: CategoryPrint, Amount: dec("10")}, wantRate: "10", wantAmount: "1"},
+		{name: "another region", rates: rates, region: stringPtr("Saxony"), line: Line{Category: CategoryPrint, Amount: dec("10")}, wantRate: "19", wantAmount: "1.9"},
+		{name: "no matching rate", rates: rates[2:], line: Line{Category: CategoryPrint, Amount: dec("10")}, wantRate: "0", wantAmount: "0"},
+		{name: "no rates", line: Line{Category: CategoryPrint, Amount: dec("10")}, wantRate: "0", wantAmount: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes := Calculate(tt.rates, tt.region, []Line{tt.line}, tt.includeTax, euro)
+			if len(taxes) != 1 {
+				t.Fatalf("got %d taxes, want 1", len(taxes))
+			}
+			if !taxes[0].Rate.Equal(dec(tt.wantRate)) {
+				t.Errorf("rate = %s, want %s", taxes[0].Rate, tt.wantRate)
+			}
+			if !taxes[0].Amount.Equal(dec(tt.wantAmount)) {
+				t.Errorf("amount = %s, want %s", taxes[0].Amount, tt.wantAmount)
+			}
+		})
+	}
+}
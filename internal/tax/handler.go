@@ -0,0 +1,199 @@
+package tax
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	taxRatesURL = "/api/tax-rates"
+	taxRateURL  = "/api/tax-rates/:id"
+)
+
+// Handler handles requests specified to tax service.
+type Handler struct {
+	logger     logger.Logger
+	taxService Service
+}
+
+// NewHandler returns a new tax Handler instance.
+func NewHandler(logger logger.Logger, taxService Service) handler.Handling {
+	return &Handler{
+		logger:     logger,
+		taxService: taxService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, taxRatesURL, h.ListTaxRates)
+	router.HandlerFunc(http.MethodPost, taxRatesURL, h.CreateTaxRate)
+	router.HandlerFunc(http.MethodPut, taxRateURL, h.UpdateTaxRate)
+	router.HandlerFunc(http.MethodDelete, taxRateURL, h.DeleteTaxRate)
+}
+
+// ListTaxRates godoc
+// @Summary List tax rates
+// @Description Get tax rates of the country or of all countries. Admin only.
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Param country query string false "Country code, e.g. DE"
+// @Success 200 {array} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /tax-rates [get]
+func (h *Handler) ListTaxRates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST TAX RATES")
+
+	var country *string
+	if value := r.URL.Query().Get("country"); value != "" {
+		value = strings.ToUpper(value)
+		if err := validation.Validate(value, validator.CountryCode); err != nil {
+			response.BadRequest(w, "country "+err.Error(), "")
+			return
+		}
+		country = &value
+	}
+
+	rates, err := h.taxService.GetAll(r.Context(), country)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rates)
+}
+
+// CreateTaxRate godoc
+// @Summary Create tax rate
+// @Description Create tax rate of the country. Rates without a region apply to the whole country, rates without a category apply to print books and ebooks. The most specific rate is applied, region is more specific than category. Admin only.
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Param input body RateDTO true "JSON input"
+// @Success 201 {object} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /tax-rates [post]
+func (h *Handler) CreateTaxRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE TAX RATE")
+
+	var input RateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	rate, err := h.taxService.Create(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrTaxRateExists) {
+			response.Conflict(w, err.Error(), "update the existing rate instead")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, rate)
+}
+
+// UpdateTaxRate godoc
+// @Summary Update tax rate
+// @Description Update tax rate with specified id. Orders keep the tax they were placed with. Admin only.
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Tax rate id"
+// @Param input body RateDTO true "JSON input"
+// @Success 200 {object} Rate
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /tax-rates/{id} [put]
+func (h *Handler) UpdateTaxRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE TAX RATE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input RateDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = id
+
+	rate, err := h.taxService.Update(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrTaxRateExists):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rate)
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete tax rate
+// @Description Delete tax rate with specified id. Admin only.
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Param id path int64 true "Tax rate id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /tax-rates/{id} [delete]
+func (h *Handler) DeleteTaxRate(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE TAX RATE")
+
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err := h.taxService.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,116 @@
+package tax
+
+import (
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/internal/book"
+	"github.com/juicyluv/ReadyRead/internal/validator"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// CategoryPrint is a tax category of printed books.
+	CategoryPrint = "print"
+	// CategoryEbook is a tax category of ebooks.
+	CategoryEbook = "ebook"
+)
+
+// CategoryOf returns the tax category of books of the format.
+func CategoryOf(format string) string {
+	if format == book.FormatEbook {
+		return CategoryEbook
+	}
+	return CategoryPrint
+}
+
+// Rate represents the tax rate of the country. Rate is a percentage of
+// the price. Rates without a region apply to the whole country, rates
+// without a category apply to all books. The most specific rate wins.
+type Rate struct {
+	Id        int64           `json:"id" example:"1"`
+	Country   string          `json:"country" example:"DE"`
+	Region    *string         `json:"region,omitempty" example:"Bavaria"`
+	Category  *string         `json:"category,omitempty" example:"ebook"`
+	Rate      decimal.Decimal `json:"rate" swaggertype:"string" example:"19"`
+	CreatedAt time.Time       `json:"createdAt" example:"2022-02-20T12:00:00Z"`
+} // @name TaxRate
+
+// RateDTO is used to create or update the tax rate.
+type RateDTO struct {
+	Id       int64           `json:"-"`
+	Country  string          `json:"country" example:"DE"`
+	Region   *string         `json:"region,omitempty" example:"Bavaria"`
+	Category *string         `json:"category,omitempty" example:"ebook"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"19"`
+} // @name TaxRateInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (r *RateDTO) Validate() error {
+	return validation.ValidateStruct(
+		r,
+		validation.Field(&r.Country, validation.Required, validator.CountryCode),
+		validation.Field(&r.Region, validation.RuneLength(1, 100)),
+		validation.Field(&r.Category, validation.In(CategoryPrint, CategoryEbook)),
+		validation.Field(
+			&r.Rate,
+			validator.MinDecimal(decimal.Zero),
+			validator.MaxDecimal(decimal.New(100, 0)),
+		),
+	)
+}
+
+// Destination is the place the order is shipped to.
+// Tax rates of the order are chosen by its country and region.
+type Destination struct {
+	Country string  `json:"country" example:"DE"`
+	Region  *string `json:"region,omitempty" example:"Bavaria"`
+} // @name ShippingDestination
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (d *Destination) Validate() error {
+	return validation.ValidateStruct(
+		d,
+		validation.Field(&d.Country, validation.Required, validator.CountryCode),
+		validation.Field(&d.Region, validation.RuneLength(1, 100)),
+	)
+}
+
+// Line is an order line the tax is calculated for.
+// Amount is what the customer pays for the line after discounts.
+type Line struct {
+	Category string
+	Amount   decimal.Decimal
+}
+
+// LineTax is the tax of the order line.
+type LineTax struct {
+	Rate   decimal.Decimal
+	Amount decimal.Decimal
+}
+
+// matches reports whether the rate applies to books of the category
+// shipped to the region. Regions are compared case-insensitively.
+func (r *Rate) matches(region *string, category string) bool {
+	if r.Region != nil && (region == nil || !strings.EqualFold(*r.Region, *region)) {
+		return false
+	}
+
+	return r.Category == nil || *r.Category == category
+}
+
+// specificity tells how specific the rate is. Region is more specific
+// than category.
+func (r *Rate) specificity() int {
+	s := 0
+	if r.Region != nil {
+		s += 2
+	}
+	if r.Category != nil {
+		s++
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const tableName = "tax_rates"
+
+// Check whether db implements tax rate storage interface.
+var _ Storage = &db{}
+
+// db implements tax rate storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new tax rate storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// FindAll finds tax rates of the country or of all countries if it's nil.
+// Rates are ordered by country, rates of the whole country go first.
+// Returns an error on failure.
+func (d *db) FindAll(country *string) ([]*Rate, error) {
+	query := fmt.Sprintf(`
+	SELECT id, country, region, category, rate, created_at
+	FROM %s
+	WHERE $1::char(2) IS NULL OR country = $1
+	ORDER BY country, region NULLS FIRST, category NULLS FIRST`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, country)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find tax rates query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]*Rate, 0)
+	for rows.Next() {
+		var r Rate
+		if err := rows.Scan(&r.Id, &r.Country, &r.Region, &r.Category, &r.Rate, &r.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to scan tax rate: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		rates = append(rates, &r)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read tax rates: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Create inserts the tax rate. Returns ErrTaxRateExists if the country,
+// region and category already have a rate, regions are compared
+// case-insensitively. Returns an error on failure or the inserted rate
+// on success.
+func (d *db) Create(rate *Rate) (*Rate, error) {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (country, region, category, rate)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(ctx, query, rate.Country, rate.Region, rate.Category, rate.Rate).
+		Scan(&rate.Id, &rate.CreatedAt)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrTaxRateExists
+		}
+		err = fmt.Errorf("failed to execute create tax rate query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// Update updates the tax rate. Returns ErrNoRows if the rate doesn't exist,
+// ErrTaxRateExists if the new scope already has a rate, an error on failure
+// or the updated rate on success.
+func (d *db) Update(rate *Rate) (*Rate, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET country = $2, region = $3, category = $4, rate = $5
+	WHERE id = $1
+	RETURNING created_at`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	err := d.conn.QueryRow(ctx, query, rate.Id, rate.Country, rate.Region, rate.Category, rate.Rate).
+		Scan(&rate.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrTaxRateExists
+		}
+		err = fmt.Errorf("failed to execute update tax rate query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// Delete deletes the tax rate with specified id.
+// Returns ErrNoRows if the rate doesn't exist or an error on failure.
+func (d *db) Delete(id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	tag, err := d.conn.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete tax rate query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package tax
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// Service describes tax service functionality.
+type Service interface {
+	GetAll(ctx context.Context, country *string) ([]*Rate, error)
+	Create(ctx context.Context, input *RateDTO) (*Rate, error)
+	Update(ctx context.Context, input *RateDTO) (*Rate, error)
+	Delete(ctx context.Context, id int64) error
+	Calculate(ctx context.Context, dest *Destination, lines []Line, cur *currency.Currency) ([]LineTax, error)
+	PricesIncludeTax() bool
+}
+
+type service struct {
+	logger           logger.Logger
+	storage          Storage
+	pricesIncludeTax bool
+}
+
+// NewService returns a new instance that implements Service interface.
+// If pricesIncludeTax is set, book prices are treated as including tax,
+// otherwise the tax is added on top of them.
+func NewService(storage Storage, pricesIncludeTax bool, logger logger.Logger) Service {
+	return &service{
+		logger:           logger,
+		storage:          storage,
+		pricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, country *string) ([]*Rate, error) {
+	rates, err := s.storage.FindAll(country)
+	if err != nil {
+		s.logger.Warnf("cannot find tax rates: %v", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (s *service) Create(ctx context.Context, input *RateDTO) (*Rate, error) {
+	rate, err := s.storage.Create(&Rate{
+		Country:  input.Country,
+		Region:   input.Region,
+		Category: input.Category,
+		Rate:     input.Rate,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrTaxRateExists) {
+			s.logger.Errorf("failed to create tax rate: %v", err)
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *service) Update(ctx context.Context, input *RateDTO) (*Rate, error) {
+	rate, err := s.storage.Update(&Rate{
+		Id:       input.Id,
+		Country:  input.Country,
+		Region:   input.Region,
+		Category: input.Category,
+		Rate:     input.Rate,
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrTaxRateExists) {
+			s.logger.Errorf("failed to update tax rate: %v", err)
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	err := s.storage.Delete(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete tax rate: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Calculate calculates the tax of the lines shipped to the destination.
+// Returns an error on failure.
+func (s *service) Calculate(ctx context.Context, dest *Destination, lines []Line, cur *currency.Currency) ([]LineTax, error) {
+	rates, err := s.GetAll(ctx, &dest.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	return Calculate(rates, dest.Region, lines, s.pricesIncludeTax, cur), nil
+}
+
+// PricesIncludeTax reports whether book prices include tax.
+func (s *service) PricesIncludeTax() bool {
+	return s.pricesIncludeTax
+}
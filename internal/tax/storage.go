@@ -0,0 +1,9 @@
+package tax
+
+// Storage describes a tax rate storage functionality.
+type Storage interface {
+	FindAll(country *string) ([]*Rate, error)
+	Create(rate *Rate) (*Rate, error)
+	Update(rate *Rate) (*Rate, error)
+	Delete(id int64) error
+}
@@ -0,0 +1,309 @@
+// Package testdb provides disposable Postgres databases for integration
+// tests of storages.
+//
+// The database server is taken from the TEST_DATABASE_DSN environment
+// variable. If it's not set, a temporary cluster is started with initdb and
+// pg_ctl found in PATH or in /usr/lib/postgresql/*/bin and stopped once tests
+// of the package are done. Tests are skipped only if neither is available,
+// failures to start the found binaries fail the tests.
+//
+// All migrations are applied once to a template database. Every test gets
+// its own database cloned from the template, which is dropped when the test
+// ends, so tests can commit transactions and run in parallel.
+package testdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	shopspring "github.com/jackc/pgtype/ext/shopspring-numeric"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	// dsnEnv is the environment variable with the DSN of the database
+	// server to run tests against. The user must be able to create databases.
+	dsnEnv = "TEST_DATABASE_DSN"
+
+	// setupTimeout limits starting the server and applying migrations.
+	setupTimeout = time.Minute
+
+	// queryTimeout limits creating and dropping databases of tests.
+	queryTimeout = 30 * time.Second
+)
+
+// errUnavailable is returned when there is no database server to run tests
+// against, tests are skipped then.
+var errUnavailable = errors.New("postgres is not available")
+
+var (
+	once     sync.Once
+	setupErr error
+
+	// admin is the config of connections to the maintenance database.
+	admin *pgx.ConnConfig
+	// template is the name of the database with all migrations applied.
+	template string
+	// stop stops the server started for tests, if any.
+	stop func()
+
+	// counter makes names of test databases unique within the process.
+	counter int64
+)
+
+// Main initializes the logger used by storages, runs tests of the package
+// and removes the template database and the server started for them.
+// It should be called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testdb.Main(m))
+//	}
+func Main(m *testing.M) int {
+	logger.Init()
+
+	code := m.Run()
+	teardown()
+
+	return code
+}
+
+// New returns a connection pool of a new database with all migrations
+// applied. The database is dropped when the test and its subtests complete.
+// The test is skipped if there is no database server to run it against.
+func New(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	once.Do(func() {
+		setupErr = setup()
+	})
+	if errors.Is(setupErr, errUnavailable) {
+		t.Skip(setupErr)
+	}
+	if setupErr != nil {
+		t.Fatalf("cannot set up test database: %v", setupErr)
+	}
+
+	name := fmt.Sprintf("%s_%d", template, atomic.AddInt64(&counter, 1))
+	if err := execAdmin(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", name, template)); err != nil {
+		t.Fatalf("cannot create test database: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	// Money is scanned into decimals the same way the server does.
+	poolConfig, err := pgxpool.ParseConfig("")
+	if err != nil {
+		execAdmin("DROP DATABASE IF EXISTS " + name)
+		t.Fatalf("cannot create pool config: %v", err)
+	}
+	poolConfig.ConnConfig = configFor(name)
+	poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		conn.ConnInfo().RegisterDataType(pgtype.DataType{
+			Value: &shopspring.Numeric{},
+			Name:  "numeric",
+			OID:   pgtype.NumericOID,
+		})
+		return nil
+	}
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		execAdmin("DROP DATABASE IF EXISTS " + name)
+		t.Fatalf("cannot connect to test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		pool.Close()
+		if err := execAdmin("DROP DATABASE IF EXISTS " + name); err != nil {
+			t.Errorf("cannot drop test database: %v", err)
+		}
+	})
+
+	return pool
+}
+
+// setup connects to the database server, starting one if TEST_DATABASE_DSN
+// is not set, and creates the template database.
+func setup() error {
+	dsn := os.Getenv(dsnEnv)
+	if dsn == "" {
+		var err error
+		if dsn, stop, err = start(); err != nil {
+			return err
+		}
+	}
+
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %v", dsnEnv, err)
+	}
+	admin = config
+
+	template = fmt.Sprintf("readyread_test_%d", os.Getpid())
+	if err := execAdmin("CREATE DATABASE " + template); err != nil {
+		return err
+	}
+
+	if err := migrate(template); err != nil {
+		execAdmin("DROP DATABASE IF EXISTS " + template)
+		template = ""
+		return err
+	}
+
+	return nil
+}
+
+// teardown drops the template database and stops the started server.
+func teardown() {
+	if template != "" {
+		if err := execAdmin("DROP DATABASE IF EXISTS " + template); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot drop template database: %v\n", err)
+		}
+	}
+
+	if stop != nil {
+		stop()
+	}
+}
+
+// migrate applies all up migrations to the database in order.
+func migrate(database string) error {
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", dir)
+	}
+	sort.Strings(files)
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	conn, err := pgx.ConnectConfig(ctx, configFor(database))
+	if err != nil {
+		return fmt.Errorf("cannot connect to template database: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	for _, file := range files {
+		query, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		// Queries without arguments are sent with the simple protocol,
+		// so every migration can have several statements.
+		if _, err := conn.Exec(ctx, string(query)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// execAdmin executes the query in the maintenance database. Databases can't
+// be created or dropped inside transactions, so every query gets its own
+// connection, which can't be open to the template while it's cloned.
+func execAdmin(query string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	conn, err := pgx.ConnectConfig(ctx, admin.Copy())
+	if err != nil {
+		return fmt.Errorf("cannot connect to database server: %v", err)
+	}
+	defer conn.Close(ctx)
+
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to execute %q: %v", query, err)
+	}
+
+	return nil
+}
+
+// configFor returns the config of connections to the database.
+func configFor(database string) *pgx.ConnConfig {
+	config := admin.Copy()
+	config.Database = database
+	return config
+}
+
+// start starts a temporary cluster which only listens on a unix socket in
+// a temporary directory. Returns its DSN and the function to stop it,
+// errUnavailable if there are no server binaries or an error if they fail.
+func start() (string, func(), error) {
+	bin, err := findBinaries()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "readyread-pg-")
+	if err != nil {
+		return "", nil, err
+	}
+	data := filepath.Join(dir, "data")
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	initdb := exec.CommandContext(ctx, filepath.Join(bin, "initdb"),
+		"-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-locale", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("initdb failed: %v: %s", err, out)
+	}
+
+	pgCtl := filepath.Join(bin, "pg_ctl")
+	options := fmt.Sprintf("-c listen_addresses='' -k %s -F", dir)
+	run := exec.CommandContext(ctx, pgCtl,
+		"-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", options, "-w", "start")
+	if out, err := run.CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("pg_ctl failed: %v: %s", err, out)
+	}
+
+	stop := func() {
+		if out, err := exec.Command(pgCtl, "-D", data, "-m", "immediate", "-w", "stop").CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot stop test database server: %v: %s\n", err, out)
+		}
+		os.RemoveAll(dir)
+	}
+
+	return fmt.Sprintf("host=%s user=postgres dbname=postgres sslmode=disable", dir), stop, nil
+}
+
+// findBinaries returns the directory with initdb and pg_ctl.
+func findBinaries() (string, error) {
+	if path, err := exec.LookPath("initdb"); err == nil {
+		return filepath.Dir(path), nil
+	}
+
+	// Debian and Ubuntu packages don't put server binaries in PATH.
+	dirs, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	sort.Strings(dirs)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if _, err := os.Stat(filepath.Join(dirs[i], "initdb")); err == nil {
+			return dirs[i], nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: set %s or install postgres server binaries", errUnavailable, dsnEnv)
+}
@@ -197,6 +197,8 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 		case apperror.ErrWrongPassword:
 			response.BadRequest(w, err.Error(), "")
+		case apperror.ErrInvalidReference:
+			response.BadRequest(w, apperror.ErrUnknownCurrency.Error(), "")
 		default:
 			response.InternalError(w, err.Error(), "")
 		}
@@ -208,7 +210,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 // UpdateUserPartially godoc
 // @Summary Update user
-// @Description Partially update the user with provided current password.
+// @Description Partially update the user with provided current password. Currency is the preferred display currency of prices.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -248,6 +250,8 @@ func (h *Handler) UpdateUserPartially(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 		case apperror.ErrWrongPassword:
 			response.BadRequest(w, err.Error(), "")
+		case apperror.ErrInvalidReference:
+			response.BadRequest(w, apperror.ErrUnknownCurrency.Error(), "")
 		default:
 			response.InternalError(w, err.Error(), "")
 		}
@@ -5,10 +5,13 @@ import (
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/juicyluv/ReadyRead/internal/validator"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// User represents the user model.
+// User represents the user model. Address is the free-text address
+// which is deprecated in favor of the address book and kept for
+// existing clients.
 type User struct {
 	Id           int64   `json:"id" example:"123"`
 	Email        string  `json:"email" example:"admin@example.com"`
@@ -17,6 +20,7 @@ type User struct {
 	Verified     bool    `json:"verified" example:"true"`
 	Address      *string `json:"address,omitempty"`
 	PhoneNumber  *string `json:"phoneNumber,omitempty"`
+	Currency     *string `json:"currency,omitempty" example:"EUR"`
 	RegisteredAt string  `json:"registeredAt" example:"2022/02/24"`
 } // @name User
 
@@ -121,6 +125,7 @@ type UpdateUserPartiallyDTO struct {
 	NewPassword *string `json:"newPassword" example:"nEwPas5worD"`
 	Address     *string `json:"address" example:"Russia, Moscow, Malaya Semenovskaya, 12"`
 	PhoneNumber *string `json:"phoneNumber" example:"88005553535"`
+	Currency    *string `json:"currency" example:"EUR"`
 } // @name UpdateUserPartiallyInput
 
 // Validate will validates current struct fields.
@@ -134,6 +139,7 @@ func (u *UpdateUserPartiallyDTO) Validate() error {
 		validation.Field(&u.NewPassword, validation.Length(6, 24), is.Alphanumeric),
 		validation.Field(&u.Address, is.ASCII, validation.Length(3, 100)),
 		validation.Field(&u.PhoneNumber, is.Alphanumeric, validation.Length(5, 12)),
+		validation.Field(&u.Currency, validator.CurrencyCode),
 	)
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/juicyluv/ReadyRead/internal/apperror"
 	"github.com/juicyluv/ReadyRead/pkg/logger"
 )
@@ -22,12 +23,12 @@ var _ Storage = &db{}
 // db implements user storage interface.
 type db struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 // NewStorage returns a new user storage instance.
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &db{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -67,7 +68,7 @@ func (d *db) Create(user *User) (*User, error) {
 // Returns an error on failure.
 func (d *db) FindByEmail(email string) (*User, error) {
 	query := fmt.Sprintf(`
-	SELECT id, username, email, password, verified, address, phone_number, TO_CHAR(registered_at, 'DD-MM-YYYY')
+	SELECT id, username, email, password, verified, address, phone_number, currency, TO_CHAR(registered_at, 'DD-MM-YYYY')
 	FROM %s 
 	WHERE email = $1`, tableName)
 
@@ -84,6 +85,7 @@ func (d *db) FindByEmail(email string) (*User, error) {
 		&found.Verified,
 		&found.Address,
 		&found.PhoneNumber,
+		&found.Currency,
 		&found.RegisteredAt,
 	)
 
@@ -104,7 +106,7 @@ func (d *db) FindByEmail(email string) (*User, error) {
 // Returns an error on failure.
 func (d *db) FindByUsername(username string) (*User, error) {
 	query := fmt.Sprintf(`
-	SELECT id, username, email, password, verified, address, phone_number, TO_CHAR(registered_at, 'DD-MM-YYYY')
+	SELECT id, username, email, password, verified, address, phone_number, currency, TO_CHAR(registered_at, 'DD-MM-YYYY')
 	FROM %s 
 	WHERE username = $1`, tableName)
 
@@ -121,6 +123,7 @@ func (d *db) FindByUsername(username string) (*User, error) {
 		&found.Verified,
 		&found.Address,
 		&found.PhoneNumber,
+		&found.Currency,
 		&found.RegisteredAt,
 	)
 	if err != nil {
@@ -140,7 +143,7 @@ func (d *db) FindByUsername(username string) (*User, error) {
 // Returns an error on failure.
 func (d *db) FindById(id int64) (*User, error) {
 	query := fmt.Sprintf(`
-	SELECT id, username, email, password, verified, address, phone_number, TO_CHAR(registered_at, 'DD-MM-YYYY')
+	SELECT id, username, email, password, verified, address, phone_number, currency, TO_CHAR(registered_at, 'DD-MM-YYYY')
 	FROM %s 
 	WHERE id = $1`, tableName)
 
@@ -157,6 +160,7 @@ func (d *db) FindById(id int64) (*User, error) {
 		&found.Verified,
 		&found.Address,
 		&found.PhoneNumber,
+		&found.Currency,
 		&found.RegisteredAt,
 	)
 	if err != nil {
@@ -209,7 +213,8 @@ func (d *db) Update(user *UpdateUserDTO) error {
 }
 
 // UpdatePartially partially updates the user with specified values.
-// If user with this id doesn't exist, returns ErrNoRows or an error on failure.
+// If user with this id doesn't exist, returns ErrNoRows, ErrInvalidReference
+// if the currency is not supported or an error on failure.
 func (d *db) UpdatePartially(user *UpdateUserPartiallyDTO) error {
 	values := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -245,6 +250,12 @@ func (d *db) UpdatePartially(user *UpdateUserPartiallyDTO) error {
 		argId++
 	}
 
+	if user.Currency != nil {
+		values = append(values, fmt.Sprintf("currency=$%d", argId))
+		args = append(args, *user.Currency)
+		argId++
+	}
+
 	valuesQuery := strings.Join(values, ", ")
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", tableName, valuesQuery, argId)
 	args = append(args, user.Id)
@@ -254,6 +265,9 @@ func (d *db) UpdatePartially(user *UpdateUserPartiallyDTO) error {
 
 	result, err := d.conn.Exec(ctx, query, args...)
 	if err != nil {
+		if apperror.IsForeignKeyViolation(err) {
+			return apperror.ErrInvalidReference
+		}
 		return fmt.Errorf("failed to update user partially: %v", err)
 	}
 
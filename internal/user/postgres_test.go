@@ -0,0 +1,211 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/testdb"
+)
+
+// newStorage returns the storage backed by a new test database.
+func newStorage(t *testing.T) Storage {
+	t.Helper()
+
+	return NewStorage(testdb.New(t), 5)
+}
+
+// mustCreate creates the user with specified username and email.
+func mustCreate(t *testing.T, s Storage, username, email string) *User {
+	t.Helper()
+
+	user, err := s.Create(&User{Username: username, Email: email, Password: "hash"})
+	if err != nil {
+		t.Fatalf("cannot create user %s: %v", username, err)
+	}
+	return user
+}
+
+func stringPtr(v string) *string { return &v }
+
+func TestCreate(t *testing.T) {
+	s := newStorage(t)
+
+	created, err := s.Create(&User{Username: "admin", Email: "admin@example.com", Password: "hash"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created.Id == 0 || created.RegisteredAt == "" {
+		t.Errorf("Create() = %+v, want id and registration date", created)
+	}
+
+	got, err := s.FindById(created.Id)
+	if err != nil {
+		t.Fatalf("FindById() error = %v", err)
+	}
+	if got.Verified || got.PhoneNumber != nil || got.Currency != nil {
+		t.Errorf("FindById() = %+v, want defaults", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	s := newStorage(t)
+	created := mustCreate(t, s, "admin", "admin@example.com")
+
+	tests := []struct {
+		name    string
+		find    func() (*User, error)
+		wantErr error
+	}{
+		{name: "by id", find: func() (*User, error) { return s.FindById(created.Id) }},
+		{name: "by id not found", find: func() (*User, error) { return s.FindById(created.Id + 1) }, wantErr: apperror.ErrNoRows},
+		{name: "by email", find: func() (*User, error) { return s.FindByEmail("admin@example.com") }},
+		{name: "by email not found", find: func() (*User, error) { return s.FindByEmail("nobody@example.com") }, wantErr: apperror.ErrNoRows},
+		{name: "by username", find: func() (*User, error) { return s.FindByUsername("admin") }},
+		{name: "by username not found", find: func() (*User, error) { return s.FindByUsername("nobody") }, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.find()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if *got != *created {
+				t.Errorf("got %+v, want %+v", got, created)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s := newStorage(t)
+	created := mustCreate(t, s, "admin", "admin@example.com")
+
+	tests := []struct {
+		name    string
+		input   *UpdateUserDTO
+		wantErr error
+	}{
+		{
+			name: "existing",
+			input: &UpdateUserDTO{
+				Id:          created.Id,
+				Username:    "root",
+				Email:       "root@example.com",
+				Password:    "new hash",
+				PhoneNumber: stringPtr("88005553535"),
+			},
+		},
+		{
+			name:    "not found",
+			input:   &UpdateUserDTO{Id: created.Id + 1, Username: "root", Email: "root@example.com", Password: "hash"},
+			wantErr: apperror.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Update(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if got.Username != tt.input.Username || got.Email != tt.input.Email ||
+				got.Password != tt.input.Password || *got.PhoneNumber != *tt.input.PhoneNumber {
+				t.Errorf("FindById() = %+v, want %+v", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestUpdatePartially(t *testing.T) {
+	s := newStorage(t)
+	created := mustCreate(t, s, "admin", "admin@example.com")
+
+	tests := []struct {
+		name    string
+		input   *UpdateUserPartiallyDTO
+		check   func(u *User) bool
+		wantErr error
+	}{
+		{
+			name:  "username",
+			input: &UpdateUserPartiallyDTO{Id: created.Id, Username: stringPtr("root")},
+			check: func(u *User) bool { return u.Username == "root" && u.Email == "admin@example.com" },
+		},
+		{
+			name:  "password and currency",
+			input: &UpdateUserPartiallyDTO{Id: created.Id, NewPassword: stringPtr("new hash"), Currency: stringPtr("EUR")},
+			check: func(u *User) bool { return u.Password == "new hash" && *u.Currency == "EUR" },
+		},
+		{
+			name:    "unknown currency",
+			input:   &UpdateUserPartiallyDTO{Id: created.Id, Currency: stringPtr("XXX")},
+			wantErr: apperror.ErrInvalidReference,
+		},
+		{
+			name:    "not found",
+			input:   &UpdateUserPartiallyDTO{Id: created.Id + 1, Username: stringPtr("nobody")},
+			wantErr: apperror.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.UpdatePartially(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdatePartially() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := s.FindById(tt.input.Id)
+			if err != nil {
+				t.Fatalf("FindById() error = %v", err)
+			}
+			if !tt.check(got) {
+				t.Errorf("FindById() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s := newStorage(t)
+	created := mustCreate(t, s, "admin", "admin@example.com")
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+	}{
+		{name: "existing", id: created.Id},
+		{name: "already deleted", id: created.Id, wantErr: apperror.ErrNoRows},
+		{name: "not found", id: created.Id + 1, wantErr: apperror.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Delete(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := s.FindById(tt.id)
+			if !errors.Is(err, apperror.ErrNoRows) {
+				t.Errorf("FindById() error = %v, want %v", err, apperror.ErrNoRows)
+			}
+		})
+	}
+}
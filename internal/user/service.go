@@ -169,7 +169,9 @@ func (s *service) UpdatePartially(ctx context.Context, user *UpdateUserPartially
 
 	err = s.storage.UpdatePartially(user)
 	if err != nil {
-		s.logger.Errorf("failed to partially update user: %v", err)
+		if !errors.Is(err, apperror.ErrInvalidReference) {
+			s.logger.Errorf("failed to partially update user: %v", err)
+		}
 		return err
 	}
 
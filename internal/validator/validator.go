@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/juicyluv/ReadyRead/pkg/isbn"
+	"github.com/shopspring/decimal"
+)
+
+// Name validates that a string is a name written in any script.
+// Letters may be separated by single spaces, hyphens, apostrophes or dots,
+// so names like "Лев Толстой", "O'Brien" or "J. R. R. Tolkien" are allowed.
+// Letters may be followed by combining marks of any kind, e.g. "मोहन".
+var Name = validation.NewStringRule(
+	isName,
+	"must contain only letters, spaces, hyphens, apostrophes and dots",
+)
+
+// isName reports whether s is a valid name.
+func isName(s string) bool {
+	var prev rune
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+		case unicode.IsMark(r):
+			// Combining marks are allowed only after letters.
+			if i == 0 || !unicode.IsLetter(prev) && !unicode.IsMark(prev) {
+				return false
+			}
+		case r == ' ':
+			// Spaces are allowed after letters and dots, e.g. "J. Smith".
+			if i == 0 || prev == ' ' || prev == '-' || prev == '\'' || prev == '’' {
+				return false
+			}
+		case r == '-', r == '\'', r == '’', r == '.':
+			if i == 0 || !unicode.IsLetter(prev) && !unicode.IsMark(prev) {
+				return false
+			}
+		default:
+			return false
+		}
+		prev = r
+	}
+
+	return unicode.IsLetter(prev) || unicode.IsMark(prev) || prev == '.'
+}
+
+// Text validates that a string is a single line of text in any script.
+// Control characters are not allowed and the text must not be blank.
+var Text = validation.NewStringRule(isText, "must be a single line of printable characters")
+
+// isText reports whether s is a single line of printable text.
+func isText(s string) bool {
+	blank := true
+	for _, r := range s {
+		if !unicode.IsPrint(r) && r != ' ' {
+			return false
+		}
+		if !unicode.IsSpace(r) {
+			blank = false
+		}
+	}
+	return !blank
+}
+
+// ISBN validates that a string is a valid ISBN-10 or ISBN-13 with correct check digit.
+var ISBN = validation.NewStringRule(isbn.IsValid, "must be a valid ISBN-10 or ISBN-13")
+
+// CurrencyCode validates that a string is an ISO 4217 currency code, e.g. "EUR".
+var CurrencyCode = validation.NewStringRule(isCurrencyCode, "must be a three-letter currency code in upper case")
+
+// isCurrencyCode reports whether s consists of three upper case latin letters.
+func isCurrencyCode(s string) bool {
+	return isUpperLatin(s, 3)
+}
+
+// CountryCode validates that a string is an ISO 3166-1 alpha-2 country code, e.g. "DE".
+var CountryCode = validation.NewStringRule(isCountryCode, "must be a two-letter country code in upper case")
+
+// isCountryCode reports whether s consists of two upper case latin letters.
+func isCountryCode(s string) bool {
+	return isUpperLatin(s, 2)
+}
+
+// isUpperLatin reports whether s consists of n upper case latin letters.
+func isUpperLatin(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// MinDecimal returns a rule which validates that a decimal is not less than min.
+// Nil pointers are valid.
+func MinDecimal(min decimal.Decimal) validation.Rule {
+	return validation.By(func(value interface{}) error {
+		d, ok, err := decimalValue(value)
+		if !ok {
+			return err
+		}
+
+		if d.LessThan(min) {
+			return fmt.Errorf("must be no less than %s", min)
+		}
+
+		return nil
+	})
+}
+
+// MaxDecimal returns a rule which validates that a decimal is not greater than max.
+// Nil pointers are valid.
+func MaxDecimal(max decimal.Decimal) validation.Rule {
+	return validation.By(func(value interface{}) error {
+		d, ok, err := decimalValue(value)
+		if !ok {
+			return err
+		}
+
+		if d.GreaterThan(max) {
+			return fmt.Errorf("must be no greater than %s", max)
+		}
+
+		return nil
+	})
+}
+
+// decimalValue returns the decimal of the value, which is a decimal or
+// a pointer to it. Reports false if the pointer is nil or returns an error
+// if the value is not a decimal. validation.Indirect can't be used, it
+// turns decimals into strings as they implement driver.Valuer.
+func decimalValue(value interface{}) (decimal.Decimal, bool, error) {
+	switch v := value.(type) {
+	case decimal.Decimal:
+		return v, true, nil
+	case *decimal.Decimal:
+		if v == nil {
+			return decimal.Zero, false, nil
+		}
+		return *v, true, nil
+	default:
+		return decimal.Zero, false, errors.New("must be a decimal number")
+	}
+}
@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestIsName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "latin", input: "Leo Tolstoy", want: true},
+		{name: "cyrillic", input: "Лев Толстой", want: true},
+		{name: "apostrophe", input: "O'Brien", want: true},
+		{name: "typographic apostrophe", input: "O’Brien", want: true},
+		{name: "hyphen", input: "Jean-Paul Sartre", want: true},
+		{name: "initials", input: "J. R. R. Tolkien", want: true},
+		{name: "trailing dot", input: "Martin Luther King Jr.", want: true},
+		{name: "nonspacing mark", input: "José", want: true},
+		{name: "devanagari spacing mark", input: "मोहन", want: true},
+		{name: "tamil spacing mark", input: "கார்த்திக்", want: true},
+		{name: "enclosing mark", input: "A⃝", want: true},
+		{name: "cjk", input: "村上春樹", want: true},
+		{name: "empty", input: "", want: false},
+		{name: "leading mark", input: "́Jose", want: false},
+		{name: "mark after space", input: "Jose ा", want: false},
+		{name: "leading space", input: " Leo", want: false},
+		{name: "trailing space", input: "Leo ", want: false},
+		{name: "double space", input: "Leo  Tolstoy", want: false},
+		{name: "double hyphen", input: "Jean--Paul", want: false},
+		{name: "trailing hyphen", input: "Jean-", want: false},
+		{name: "space after hyphen", input: "Jean- Paul", want: false},
+		{name: "digit", input: "Leo 2", want: false},
+		{name: "punctuation", input: "Leo!", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isName(tt.input); got != tt.want {
+				t.Errorf("isName(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "text", input: "War and Peace, vol. 1", want: true},
+		{name: "unicode", input: "Война и мир — том 1", want: true},
+		{name: "empty", input: "", want: false},
+		{name: "blank", input: "   ", want: false},
+		{name: "new line", input: "War\nPeace", want: false},
+		{name: "tab", input: "War\tPeace", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isText(tt.input); got != tt.want {
+				t.Errorf("isText(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodes(t *testing.T) {
+	tests := []struct {
+		input    string
+		currency bool
+		country  bool
+	}{
+		{input: "EUR", currency: true},
+		{input: "DE", country: true},
+		{input: "eur"},
+		{input: "EU1"},
+		{input: "EURO"},
+		{input: ""},
+	}
+
+	for _, tt := range tests {
+		if got := isCurrencyCode(tt.input); got != tt.currency {
+			t.Errorf("isCurrencyCode(%q) = %v, want %v", tt.input, got, tt.currency)
+		}
+		if got := isCountryCode(tt.input); got != tt.country {
+			t.Errorf("isCountryCode(%q) = %v, want %v", tt.input, got, tt.country)
+		}
+	}
+}
+
+func TestDecimalRange(t *testing.T) {
+	min := MinDecimal(decimal.New(1, -2))
+	max := MaxDecimal(decimal.New(1000, 0))
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantMin bool
+		wantMax bool
+	}{
+		{name: "in range", value: decimal.New(5, 0), wantMin: true, wantMax: true},
+		{name: "minimum", value: decimal.New(1, -2), wantMin: true, wantMax: true},
+		{name: "maximum", value: decimal.New(1000, 0), wantMin: true, wantMax: true},
+		{name: "too small", value: decimal.Zero, wantMax: true},
+		{name: "too large", value: decimal.New(10001, -1), wantMin: true},
+		{name: "nil", value: (*decimal.Decimal)(nil), wantMin: true, wantMax: true},
+		{name: "not decimal", value: "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := min.Validate(tt.value); (err == nil) != tt.wantMin {
+				t.Errorf("MinDecimal() error = %v, want valid %v", err, tt.wantMin)
+			}
+			if err := max.Validate(tt.value); (err == nil) != tt.wantMax {
+				t.Errorf("MaxDecimal() error = %v, want valid %v", err, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	// eventHeader is a header with the name of the event.
+	eventHeader = "X-ReadyRead-Event"
+	// signatureHeader is a header with HMAC-SHA256 signature of the body.
+	signatureHeader = "X-ReadyRead-Signature"
+
+	// requestTimeout limits a single webhook request.
+	requestTimeout = 10 * time.Second
+)
+
+// Event is a body of the webhook request.
+type Event struct {
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// Client posts events to the webhook URL. If the secret is set, bodies are
+// signed with HMAC-SHA256 and the hex signature is sent as "sha256=<hex>"
+// in X-ReadyRead-Signature header, so receivers can verify the sender.
+type Client struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewClient returns a new webhook Client.
+func NewClient(url, secret string) *Client {
+	return &Client{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Post sends the event with given data. Returns an error if the request
+// failed or the receiver responded with non-2xx status code.
+func (c *Client) Post(ctx context.Context, event string, data interface{}) error {
+	body, err := json.Marshal(Event{
+		Event: event,
+		Time:  time.Now().UTC(),
+		Data:  data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, event)
+
+	if len(c.secret) > 0 {
+		req.Header.Set(signatureHeader, sign(c.secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Verify reports whether the body of the webhook request is signed with the secret.
+func Verify(secret string, header http.Header, body []byte) bool {
+	expected := sign([]byte(secret), body)
+	return hmac.Equal([]byte(header.Get(signatureHeader)), []byte(expected))
+}
+
+// sign returns the signature of the body sent in X-ReadyRead-Signature header.
+func sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// received is a webhook request received by the test server.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver returns the server which responds with the status code
+// and sends received requests to the channel.
+func newReceiver(t *testing.T, status int) (*httptest.Server, chan received) {
+	requests := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func TestSign(t *testing.T) {
+	got := sign([]byte("key"), []byte("The quick brown fox jumps over the lazy dog"))
+
+	want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Errorf("sign() = %q, want %q", got, want)
+	}
+}
+
+func TestPost(t *testing.T) {
+	srv, requests := newReceiver(t, http.StatusNoContent)
+
+	err := NewClient(srv.URL, "secret").Post(context.Background(), "stock.low", map[string]int{"bookId": 1})
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	req := <-requests
+	if got := req.header.Get(eventHeader); got != "stock.low" {
+		t.Errorf("event header = %q, want stock.low", got)
+	}
+	if got := req.header.Get(signatureHeader); got != sign([]byte("secret"), req.body) {
+		t.Errorf("signature header = %q", got)
+	}
+
+	var event Event
+	if err := json.Unmarshal(req.body, &event); err != nil {
+		t.Fatalf("cannot decode event: %v", err)
+	}
+	if event.Event != "stock.low" || event.Time.IsZero() {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestPostUnsigned(t *testing.T) {
+	srv, requests := newReceiver(t, http.StatusOK)
+
+	if err := NewClient(srv.URL, "").Post(context.Background(), "stock.low", nil); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	if got := (<-requests).header.Get(signatureHeader); got != "" {
+		t.Errorf("signature header = %q, want none", got)
+	}
+}
+
+func TestPostRejected(t *testing.T) {
+	srv, _ := newReceiver(t, http.StatusInternalServerError)
+
+	if err := NewClient(srv.URL, "secret").Post(context.Background(), "stock.low", nil); err == nil {
+		t.Error("Post() error = nil, want an error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"stock.low"}`)
+
+	header := make(http.Header)
+	header.Set(signatureHeader, sign([]byte("secret"), body))
+
+	tests := []struct {
+		name   string
+		secret string
+		header http.Header
+		body   []byte
+		want   bool
+	}{
+		{name: "valid", secret: "secret", header: header, body: body, want: true},
+		{name: "another secret", secret: "other", header: header, body: body},
+		{name: "changed body", secret: "secret", header: header, body: []byte(`{"event":"stock.high"}`)},
+		{name: "no signature", secret: "secret", header: make(http.Header), body: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.header, tt.body); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
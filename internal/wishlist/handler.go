@@ -0,0 +1,534 @@
+package wishlist
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/internal/handler"
+	"github.com/juicyluv/ReadyRead/internal/response"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	wishlistsURL = "/api/users/:id/wishlists"
+	wishlistURL  = "/api/users/:id/wishlists/:wishlistId"
+	shareURL     = "/api/users/:id/wishlists/:wishlistId/share"
+	itemsURL     = "/api/users/:id/wishlists/:wishlistId/items"
+	itemURL      = "/api/users/:id/wishlists/:wishlistId/items/:bookId"
+	moveURL      = "/api/users/:id/wishlists/:wishlistId/items/:bookId/basket"
+	sharedURL    = "/api/wishlists/:token"
+)
+
+// Handler handles requests specified to wishlist service.
+type Handler struct {
+	logger          logger.Logger
+	wishlistService Service
+}
+
+// NewHandler returns a new wishlist Handler instance.
+func NewHandler(logger logger.Logger, wishlistService Service) handler.Handling {
+	return &Handler{
+		logger:          logger,
+		wishlistService: wishlistService,
+	}
+}
+
+// Register registers new routes for router.
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, wishlistsURL, h.ListWishlists)
+	router.HandlerFunc(http.MethodPost, wishlistsURL, h.CreateWishlist)
+	router.HandlerFunc(http.MethodGet, wishlistURL, h.GetWishlist)
+	router.HandlerFunc(http.MethodPut, wishlistURL, h.UpdateWishlist)
+	router.HandlerFunc(http.MethodDelete, wishlistURL, h.DeleteWishlist)
+	router.HandlerFunc(http.MethodPut, shareURL, h.ShareWishlist)
+	router.HandlerFunc(http.MethodDelete, shareURL, h.UnshareWishlist)
+	router.HandlerFunc(http.MethodPost, itemsURL, h.AddWishlistItem)
+	router.HandlerFunc(http.MethodDelete, itemURL, h.RemoveWishlistItem)
+	router.HandlerFunc(http.MethodPost, moveURL, h.MoveToBasket)
+	router.HandlerFunc(http.MethodGet, sharedURL, h.GetSharedWishlist)
+}
+
+// ListWishlists godoc
+// @Summary List user wishlists
+// @Description Get wishlists of the user ordered by name, without their items.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Success 200 {array} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists [get]
+func (h *Handler) ListWishlists(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("LIST WISHLISTS")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	wishlists, err := h.wishlistService.GetByUser(r.Context(), userId)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlists)
+}
+
+// CreateWishlist godoc
+// @Summary Create user wishlist
+// @Description Create the named wishlist of the user. Names of wishlists of the user are unique.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param input body WishlistDTO true "JSON input"
+// @Success 201 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists [post]
+func (h *Handler) CreateWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("CREATE WISHLIST")
+
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input WishlistDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+
+	wishlist, err := h.wishlistService.Create(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrWishlistExists):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, wishlist)
+}
+
+// GetWishlist godoc
+// @Summary Show user wishlist
+// @Description Get the wishlist of the user with its items, latest added first. Items show whether the book got cheaper or came back in stock since it was added.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId} [get]
+func (h *Handler) GetWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET WISHLIST")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.GetById(ctx, userId, wishlistId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// UpdateWishlist godoc
+// @Summary Rename user wishlist
+// @Description Rename the wishlist of the user.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body WishlistDTO true "JSON input"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId} [put]
+func (h *Handler) UpdateWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UPDATE WISHLIST")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	var input WishlistDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.Id = wishlistId
+	input.UserId = userId
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.Update(ctx, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrWishlistExists):
+			response.Conflict(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// DeleteWishlist godoc
+// @Summary Delete user wishlist
+// @Description Delete the wishlist of the user with its items. Its share link stops working.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Success 200
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId} [delete]
+func (h *Handler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("DELETE WISHLIST")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.wishlistService.Delete(r.Context(), userId, wishlistId); err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ShareWishlist godoc
+// @Summary Share user wishlist
+// @Description Make the wishlist of the user available by a link with an unguessable share token. The wishlist which is shared already keeps its token.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId}/share [put]
+func (h *Handler) ShareWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("SHARE WISHLIST")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.Share(ctx, userId, wishlistId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// UnshareWishlist godoc
+// @Summary Stop sharing user wishlist
+// @Description Revoke the share token of the wishlist of the user, its link stops working.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId}/share [delete]
+func (h *Handler) UnshareWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("UNSHARE WISHLIST")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.Unshare(ctx, userId, wishlistId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// AddWishlistItem godoc
+// @Summary Add book to wishlist
+// @Description Add the book to the wishlist of the user. Its price and whether it's in stock are remembered to show price drops and restocks. The book which is in the wishlist already is left as is.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body AddItemDTO true "JSON input"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId}/items [post]
+func (h *Handler) AddWishlistItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("ADD WISHLIST ITEM")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	var input AddItemDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.AddItem(ctx, userId, wishlistId, input.BookId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// RemoveWishlistItem godoc
+// @Summary Remove book from wishlist
+// @Description Remove the book from the wishlist of the user.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param bookId path int64 true "Book id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Wishlist
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId}/items/{bookId} [delete]
+func (h *Handler) RemoveWishlistItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("REMOVE WISHLIST ITEM")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	bookId, err := handler.ReadParam64(r, "bookId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.RemoveItem(ctx, userId, wishlistId, bookId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// MoveToBasket godoc
+// @Summary Move book from wishlist to basket
+// @Description Reserve copies of the book and put them in the basket of the user, in addition to copies which are there already, then remove the book from the wishlist. One copy is added if count is not set.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param id path int64 true "User id"
+// @Param wishlistId path int64 true "Wishlist id"
+// @Param bookId path int64 true "Book id"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Param input body MoveDTO false "JSON input"
+// @Success 200 {object} basket.Basket
+// @Failure 400 {object} apperror.AppError
+// @Failure 404 {object} apperror.AppError
+// @Failure 409 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /users/{id}/wishlists/{wishlistId}/items/{bookId}/basket [post]
+func (h *Handler) MoveToBasket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("MOVE WISHLIST ITEM TO BASKET")
+
+	userId, wishlistId, ok := readIds(w, r)
+	if !ok {
+		return
+	}
+
+	bookId, err := handler.ReadParam64(r, "bookId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	var input MoveDTO
+	if r.ContentLength != 0 {
+		if err := response.ReadJSON(w, r, &input); err != nil {
+			response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+			return
+		}
+	}
+
+	if err := input.Validate(); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrValidationFailed.Error())
+		return
+	}
+
+	input.UserId = userId
+	input.WishlistId = wishlistId
+	input.BookId = bookId
+
+	ctx := currency.NewContext(w, r)
+	basket, err := h.wishlistService.MoveToBasket(ctx, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrNoRows):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInsufficientStock):
+			response.Conflict(w, err.Error(), "there are not enough available copies of the book")
+		default:
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, basket)
+}
+
+// GetSharedWishlist godoc
+// @Summary Show shared wishlist
+// @Description Get the wishlist shared with the token with its items.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Param Accept-Currency header string false "Currencies to show prices in, e.g. EUR, GBP;q=0.5"
+// @Success 200 {object} Wishlist
+// @Failure 404 {object} apperror.AppError
+// @Failure 500 {object} apperror.AppError
+// @Router /wishlists/{token} [get]
+func (h *Handler) GetSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("GET SHARED WISHLIST")
+
+	token := httprouter.ParamsFromContext(r.Context()).ByName("token")
+
+	ctx := currency.NewContext(w, r)
+	wishlist, err := h.wishlistService.GetShared(ctx, token)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNoRows) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, wishlist)
+}
+
+// readIds reads ids of the user and the wishlist from the URL.
+// Responds with Bad Request and returns false if some id is invalid.
+func readIds(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userId, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return 0, 0, false
+	}
+
+	wishlistId, err := handler.ReadParam64(r, "wishlistId")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return 0, 0, false
+	}
+
+	return userId, wishlistId, true
+}
@@ -0,0 +1,93 @@
+package wishlist
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/shopspring/decimal"
+)
+
+// Wishlist represents the named list of books the user wants to buy later.
+// The wishlist is shared by a link with ShareToken while the token is set.
+// Items are listed when the wishlist itself is shown.
+type Wishlist struct {
+	Id         int64     `json:"id" example:"1"`
+	UserId     int64     `json:"userId" example:"7"`
+	Name       string    `json:"name" example:"Birthday"`
+	ShareToken *string   `json:"shareToken,omitempty" example:"q8J2xk1bT9n4LrYvZc0sWmEa5uHdPf3G"`
+	ItemCount  int32     `json:"itemCount" example:"3"`
+	Items      []Item    `json:"items,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" example:"2022-03-01T12:00:00Z"`
+	UpdatedAt  time.Time `json:"updatedAt" example:"2022-03-01T12:00:00Z"`
+} // @name Wishlist
+
+// Item represents the book in the wishlist. Price is the price to pay now
+// and AddedPrice is the price to pay when the book was added, PriceDrop
+// reports whether the book got cheaper since. Available is the number of
+// copies which are not reserved, BackInStock reports whether the book was
+// out of stock when it was added and is available now. PriceNotConverted
+// is set if prices are left in the currency of the book, because it has
+// no exchange rate.
+type Item struct {
+	BookId            int64           `json:"bookId" example:"123"`
+	Title             string          `json:"title" example:"War and Peace"`
+	Price             decimal.Decimal `json:"price" swaggertype:"string" example:"11.49"`
+	ListPrice         decimal.Decimal `json:"listPrice" swaggertype:"string" example:"12.99"`
+	AddedPrice        decimal.Decimal `json:"addedPrice" swaggertype:"string" example:"12.99"`
+	Currency          string          `json:"currency" example:"USD"`
+	PriceNotConverted bool            `json:"priceNotConverted,omitempty" example:"false"`
+	PriceDrop         bool            `json:"priceDrop" example:"true"`
+	Available         int32           `json:"available" example:"4"`
+	InStock           bool            `json:"inStock" example:"true"`
+	BackInStock       bool            `json:"backInStock" example:"false"`
+	AddedAt           time.Time       `json:"addedAt" example:"2022-03-01T12:00:00Z"`
+	AddedInStock      bool            `json:"-"`
+} // @name WishlistItem
+
+// WishlistDTO is used to create or rename the wishlist.
+type WishlistDTO struct {
+	Id     int64  `json:"-"`
+	UserId int64  `json:"-"`
+	Name   string `json:"name" example:"Birthday"`
+} // @name WishlistInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (w *WishlistDTO) Validate() error {
+	return validation.ValidateStruct(
+		w,
+		validation.Field(&w.Name, validation.Required, validation.RuneLength(1, 100)),
+	)
+}
+
+// AddItemDTO is used to add the book to the wishlist.
+type AddItemDTO struct {
+	BookId int64 `json:"bookId" example:"123"`
+} // @name AddWishlistItemInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (i *AddItemDTO) Validate() error {
+	return validation.ValidateStruct(
+		i,
+		validation.Field(&i.BookId, validation.Required, validation.Min(1)),
+	)
+}
+
+// MoveDTO is used to move the book from the wishlist to the basket.
+// Count copies are added to the basket, one if it's not set.
+type MoveDTO struct {
+	UserId     int64 `json:"-"`
+	WishlistId int64 `json:"-"`
+	BookId     int64 `json:"-"`
+	Count      int32 `json:"count,omitempty" example:"1"`
+} // @name MoveWishlistItemInput
+
+// Validate will validates current struct fields.
+// Returns an error if something doesn't fit rules.
+func (m *MoveDTO) Validate() error {
+	return validation.ValidateStruct(
+		m,
+		validation.Field(&m.Count, validation.Min(1), validation.Max(100)),
+	)
+}
@@ -0,0 +1,341 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+const (
+	tableName      = "wishlists"
+	itemsTableName = "wishlist_items"
+
+	// selectQuery selects wishlists with the number of their items.
+	selectQuery = `
+	SELECT w.id, w.user_id, w.name, w.share_token,
+		(SELECT COUNT(*) FROM wishlist_items i WHERE i.wishlist_id = w.id)::int,
+		w.created_at, w.updated_at
+	FROM wishlists w`
+
+	// availableExpr is the number of book copies which are not reserved.
+	availableExpr = `(b.count - COALESCE((
+		SELECT SUM(r.quantity) FROM stock_reservations r
+		WHERE r.book_id = b.id AND r.expires_at > now()
+	), 0))`
+)
+
+// Check whether db implements wishlist storage interface.
+var _ Storage = &db{}
+
+// db implements wishlist storage interface.
+type db struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+// NewStorage returns a new wishlist storage instance.
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &db{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+// Create inserts the wishlist of the user. Returns ErrNoRows if the user
+// doesn't exist, ErrWishlistExists if the user has a wishlist with the
+// name, an error on failure or the inserted wishlist on success.
+func (d *db) Create(input *WishlistDTO) (*Wishlist, error) {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name) VALUES ($1, $2) RETURNING id", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	var id int64
+	if err := d.conn.QueryRow(ctx, query, input.UserId, input.Name).Scan(&id); err != nil {
+		switch {
+		case apperror.IsForeignKeyViolation(err):
+			return nil, apperror.ErrNoRows
+		case apperror.IsUniqueViolation(err):
+			return nil, apperror.ErrWishlistExists
+		}
+		err = fmt.Errorf("failed to execute create wishlist query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return d.FindById(input.UserId, id)
+}
+
+// FindByUser finds wishlists of the user ordered by name.
+// Returns an error on failure.
+func (d *db) FindByUser(userId int64) ([]*Wishlist, error) {
+	query := selectQuery + " WHERE w.user_id = $1 ORDER BY w.name, w.id"
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find wishlists query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	wishlists := make([]*Wishlist, 0)
+	for rows.Next() {
+		w, err := scanWishlist(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to scan wishlist: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		wishlists = append(wishlists, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read wishlists: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+// FindById finds the wishlist of the user with specified id.
+// Returns ErrNoRows if the user has no such wishlist or an error on failure.
+func (d *db) FindById(userId, id int64) (*Wishlist, error) {
+	return d.findOne(selectQuery+" WHERE w.id = $1 AND w.user_id = $2", id, userId)
+}
+
+// FindByToken finds the wishlist shared with the token.
+// Returns ErrNoRows if no wishlist is shared with it or an error on failure.
+func (d *db) FindByToken(token string) (*Wishlist, error) {
+	return d.findOne(selectQuery+" WHERE w.share_token = $1", token)
+}
+
+// Update renames the wishlist of the user. Returns ErrNoRows if the user
+// has no such wishlist, ErrWishlistExists if the user has another wishlist
+// with the name, an error on failure or the renamed wishlist on success.
+func (d *db) Update(input *WishlistDTO) (*Wishlist, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET name = $3, updated_at = now()
+	WHERE id = $1 AND user_id = $2`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, input.Id, input.UserId, input.Name)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil, apperror.ErrWishlistExists
+		}
+		err = fmt.Errorf("failed to execute update wishlist query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return d.FindById(input.UserId, input.Id)
+}
+
+// Delete deletes the wishlist of the user with its items.
+// Returns ErrNoRows if the user has no such wishlist or an error on failure.
+func (d *db) Delete(userId, id int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id, userId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete wishlist query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetShareToken sets the token the wishlist of the user is shared with,
+// null token stops sharing it. Returns ErrNoRows if the user has no such
+// wishlist, an error on failure or the updated wishlist on success.
+func (d *db) SetShareToken(userId, id int64, token *string) (*Wishlist, error) {
+	query := fmt.Sprintf(`
+	UPDATE %s SET share_token = $3, updated_at = now()
+	WHERE id = $1 AND user_id = $2`, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, id, userId, token)
+	if err != nil {
+		err = fmt.Errorf("failed to execute set wishlist share token query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrNoRows
+	}
+
+	return d.FindById(userId, id)
+}
+
+// FindItems finds items of the wishlist, latest added first. Items are
+// returned with prices effective now in the currency of the book and
+// the number of available copies. Returns an error on failure.
+func (d *db) FindItems(wishlistId int64) ([]Item, error) {
+	query := fmt.Sprintf(`
+	SELECT i.book_id, b.title, COALESCE(book_sale_price(b.id, now()), l.price), l.price,
+		i.added_price, b.currency, GREATEST(%s, 0)::int, i.added_in_stock, i.created_at
+	FROM %s i
+	JOIN books b ON b.id = i.book_id
+	CROSS JOIN LATERAL (SELECT COALESCE(book_list_price(b.id, now()), b.price) AS price) l
+	WHERE i.wishlist_id = $1
+	ORDER BY i.created_at DESC, i.book_id`, availableExpr, itemsTableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	rows, err := d.conn.Query(ctx, query, wishlistId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find wishlist items query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]Item, 0)
+	for rows.Next() {
+		var item Item
+		err := rows.Scan(
+			&item.BookId,
+			&item.Title,
+			&item.Price,
+			&item.ListPrice,
+			&item.AddedPrice,
+			&item.Currency,
+			&item.Available,
+			&item.AddedInStock,
+			&item.AddedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to scan wishlist item: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		err = fmt.Errorf("failed to read wishlist items: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// AddItem adds the book to the wishlist of the user with its price to pay
+// now and whether it's in stock. The book which is in the wishlist already
+// is left as is. Returns ErrNoRows if the user has no such wishlist or
+// the book doesn't exist, or an error on failure.
+func (d *db) AddItem(userId, wishlistId, bookId int64) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (wishlist_id, book_id, added_price, added_in_stock)
+	SELECT w.id, b.id,
+		COALESCE(book_sale_price(b.id, now()), book_list_price(b.id, now()), b.price),
+		%s > 0
+	FROM %s w, books b
+	WHERE w.id = $1 AND w.user_id = $2 AND b.id = $3`, itemsTableName, availableExpr, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, wishlistId, userId, bookId)
+	if err != nil {
+		if apperror.IsUniqueViolation(err) {
+			return nil
+		}
+		err = fmt.Errorf("failed to execute add wishlist item query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteItem removes the book from the wishlist of the user.
+// Returns ErrNoRows if the book is not in the wishlist of the user
+// or an error on failure.
+func (d *db) DeleteItem(userId, wishlistId, bookId int64) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s i
+	USING %s w
+	WHERE w.id = i.wishlist_id AND w.id = $1 AND w.user_id = $2 AND i.book_id = $3`, itemsTableName, tableName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	result, err := d.conn.Exec(ctx, query, wishlistId, userId, bookId)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete wishlist item query: %v", err)
+		d.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrNoRows
+	}
+
+	return nil
+}
+
+// findOne finds the wishlist by the query.
+// Returns ErrNoRows if there is no such wishlist or an error on failure.
+func (d *db) findOne(query string, args ...interface{}) (*Wishlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	w, err := scanWishlist(d.conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNoRows
+		}
+		err = fmt.Errorf("failed to execute find wishlist query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// scanWishlist scans the row of selectQuery.
+func scanWishlist(row pgx.Row) (*Wishlist, error) {
+	var w Wishlist
+	err := row.Scan(&w.Id, &w.UserId, &w.Name, &w.ShareToken, &w.ItemCount, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
@@ -0,0 +1,335 @@
+package wishlist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/juicyluv/ReadyRead/internal/apperror"
+	"github.com/juicyluv/ReadyRead/internal/basket"
+	"github.com/juicyluv/ReadyRead/internal/currency"
+	"github.com/juicyluv/ReadyRead/pkg/logger"
+)
+
+// tokenSize is the number of random bytes of share tokens.
+const tokenSize = 24
+
+// Service describes wishlist service functionality.
+type Service interface {
+	GetByUser(ctx context.Context, userId int64) ([]*Wishlist, error)
+	GetById(ctx context.Context, userId, id int64) (*Wishlist, error)
+	GetShared(ctx context.Context, token string) (*Wishlist, error)
+	Create(ctx context.Context, input *WishlistDTO) (*Wishlist, error)
+	Update(ctx context.Context, input *WishlistDTO) (*Wishlist, error)
+	Delete(ctx context.Context, userId, id int64) error
+	Share(ctx context.Context, userId, id int64) (*Wishlist, error)
+	Unshare(ctx context.Context, userId, id int64) (*Wishlist, error)
+	AddItem(ctx context.Context, userId, id, bookId int64) (*Wishlist, error)
+	RemoveItem(ctx context.Context, userId, id, bookId int64) (*Wishlist, error)
+	MoveToBasket(ctx context.Context, input *MoveDTO) (*basket.Basket, error)
+}
+
+type service struct {
+	logger          logger.Logger
+	storage         Storage
+	basketService   basket.Service
+	currencyService currency.Service
+}
+
+// NewService returns a new instance that implements Service interface.
+// Prices of wishlist items are converted to the currency accepted by
+// the client.
+func NewService(
+	storage Storage,
+	basketService basket.Service,
+	currencyService currency.Service,
+	logger logger.Logger,
+) Service {
+	return &service{
+		logger:          logger,
+		storage:         storage,
+		basketService:   basketService,
+		currencyService: currencyService,
+	}
+}
+
+func (s *service) GetByUser(ctx context.Context, userId int64) ([]*Wishlist, error) {
+	wishlists, err := s.storage.FindByUser(userId)
+	if err != nil {
+		s.logger.Warnf("cannot find wishlists: %v", err)
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+// GetById returns the wishlist of the user with its items.
+// Returns ErrNoRows if the user has no such wishlist.
+func (s *service) GetById(ctx context.Context, userId, id int64) (*Wishlist, error) {
+	wishlist, err := s.storage.FindById(userId, id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find wishlist by id: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// GetShared returns the wishlist shared with the token with its items.
+// Returns ErrNoRows if no wishlist is shared with the token.
+func (s *service) GetShared(ctx context.Context, token string) (*Wishlist, error) {
+	wishlist, err := s.storage.FindByToken(token)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Warnf("cannot find wishlist by token: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// Create creates the wishlist of the user. Returns ErrNoRows if the user
+// doesn't exist and ErrWishlistExists if the user has a wishlist with the name.
+func (s *service) Create(ctx context.Context, input *WishlistDTO) (*Wishlist, error) {
+	wishlist, err := s.storage.Create(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrWishlistExists) {
+			s.logger.Errorf("failed to create wishlist: %v", err)
+		}
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// Update renames the wishlist of the user. Returns ErrNoRows if the user
+// has no such wishlist and ErrWishlistExists if the user has another
+// wishlist with the name.
+func (s *service) Update(ctx context.Context, input *WishlistDTO) (*Wishlist, error) {
+	wishlist, err := s.storage.Update(input)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) && !errors.Is(err, apperror.ErrWishlistExists) {
+			s.logger.Errorf("failed to update wishlist: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+func (s *service) Delete(ctx context.Context, userId, id int64) error {
+	if err := s.storage.Delete(userId, id); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to delete wishlist: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Share makes the wishlist of the user available by a link with a new
+// random share token. The wishlist which is shared already keeps its token.
+// Returns ErrNoRows if the user has no such wishlist.
+func (s *service) Share(ctx context.Context, userId, id int64) (*Wishlist, error) {
+	wishlist, err := s.GetById(ctx, userId, id)
+	if err != nil || wishlist.ShareToken != nil {
+		return wishlist, err
+	}
+
+	key := make([]byte, tokenSize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("cannot generate share token: %v", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(key)
+
+	return s.setShareToken(ctx, userId, id, &token)
+}
+
+// Unshare stops sharing the wishlist of the user, its link stops working.
+// Returns ErrNoRows if the user has no such wishlist.
+func (s *service) Unshare(ctx context.Context, userId, id int64) (*Wishlist, error) {
+	return s.setShareToken(ctx, userId, id, nil)
+}
+
+// AddItem adds the book to the wishlist of the user. Returns ErrNoRows if
+// the user has no such wishlist or the book doesn't exist.
+func (s *service) AddItem(ctx context.Context, userId, id, bookId int64) (*Wishlist, error) {
+	if err := s.storage.AddItem(userId, id, bookId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to add wishlist item: %v", err)
+		}
+		return nil, err
+	}
+
+	return s.GetById(ctx, userId, id)
+}
+
+// RemoveItem removes the book from the wishlist of the user.
+// Returns ErrNoRows if the book is not in the wishlist of the user.
+func (s *service) RemoveItem(ctx context.Context, userId, id, bookId int64) (*Wishlist, error) {
+	if err := s.removeItem(userId, id, bookId); err != nil {
+		return nil, err
+	}
+
+	return s.GetById(ctx, userId, id)
+}
+
+// MoveToBasket puts copies of the book from the wishlist of the user in
+// the basket, in addition to copies which are there already, and removes
+// the book from the wishlist. Returns ErrNoRows if the book is not in
+// the wishlist of the user and ErrInsufficientStock if there are not
+// enough available copies.
+func (s *service) MoveToBasket(ctx context.Context, input *MoveDTO) (*basket.Basket, error) {
+	wishlist, err := s.GetById(ctx, input.UserId, input.WishlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, item := range wishlist.Items {
+		if item.BookId == input.BookId {
+			found = true
+		}
+	}
+	if !found {
+		return nil, apperror.ErrNoRows
+	}
+
+	b, err := s.basketService.Get(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	count := input.Count
+	if count == 0 {
+		count = 1
+	}
+	for _, item := range b.Items {
+		if item.BookId == input.BookId {
+			count += item.Count
+		}
+	}
+
+	b, err = s.basketService.SetItem(ctx, &basket.SetItemDTO{
+		UserId: input.UserId,
+		BookId: input.BookId,
+		Count:  count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.removeItem(input.UserId, input.WishlistId, input.BookId); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// setShareToken sets the share token of the wishlist of the user.
+func (s *service) setShareToken(ctx context.Context, userId, id int64, token *string) (*Wishlist, error) {
+	wishlist, err := s.storage.SetShareToken(userId, id, token)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to set wishlist share token: %v", err)
+		}
+		return nil, err
+	}
+
+	if err := s.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// removeItem removes the book from the wishlist of the user.
+func (s *service) removeItem(userId, id, bookId int64) error {
+	if err := s.storage.DeleteItem(userId, id, bookId); err != nil {
+		if !errors.Is(err, apperror.ErrNoRows) {
+			s.logger.Errorf("failed to remove wishlist item: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// loadItems loads items of the wishlist, marks price drops and restocks
+// and converts prices to the first currency accepted by the client which
+// has an exchange rate. Prices are left in the currency of the book if
+// the context carries no accepted currencies. Items which currency has no
+// exchange rate keep their prices and are flagged with PriceNotConverted.
+func (s *service) loadItems(ctx context.Context, wishlist *Wishlist) error {
+	items, err := s.storage.FindItems(wishlist.Id)
+	if err != nil {
+		s.logger.Warnf("cannot find wishlist items: %v", err)
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		item.PriceDrop = item.Price.LessThan(item.AddedPrice)
+		item.InStock = item.Available > 0
+		item.BackInStock = item.InStock && !item.AddedInStock
+	}
+	wishlist.Items = items
+
+	codes := currency.FromContext(ctx)
+	if len(codes) == 0 {
+		return nil
+	}
+
+	rates, err := s.currencyService.Rates(ctx)
+	if err != nil {
+		return err
+	}
+	target := rates.Select(codes...)
+
+	for i := range items {
+		item := &items[i]
+		if _, ok := rates.Rate(item.Currency); !ok {
+			s.logger.Warnf("cannot convert prices of book %d: no exchange rate of %s", item.BookId, item.Currency)
+			item.PriceNotConverted = true
+			continue
+		}
+
+		price, err := rates.Convert(item.Price, item.Currency, target.Code)
+		if err != nil {
+			return err
+		}
+		listPrice, err := rates.Convert(item.ListPrice, item.Currency, target.Code)
+		if err != nil {
+			return err
+		}
+		addedPrice, err := rates.Convert(item.AddedPrice, item.Currency, target.Code)
+		if err != nil {
+			return err
+		}
+
+		item.Price = price
+		item.ListPrice = listPrice
+		item.AddedPrice = addedPrice
+		item.Currency = target.Code
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package wishlist
+
+// Storage describes a wishlist storage functionality.
+type Storage interface {
+	Create(input *WishlistDTO) (*Wishlist, error)
+	FindByUser(userId int64) ([]*Wishlist, error)
+	FindById(userId, id int64) (*Wishlist, error)
+	FindByToken(token string) (*Wishlist, error)
+	Update(input *WishlistDTO) (*Wishlist, error)
+	Delete(userId, id int64) error
+	SetShareToken(userId, id int64, token *string) (*Wishlist, error)
+	FindItems(wishlistId int64) ([]Item, error)
+	AddItem(userId, wishlistId, bookId int64) error
+	DeleteItem(userId, wishlistId, bookId int64) error
+}
@@ -0,0 +1,7 @@
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_genre_id_fkey;
+ALTER TABLE books ADD CONSTRAINT books_genre_id_fkey
+    foreign key(genre_id) references genres(id) on delete cascade;
+
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_language_id_fkey;
+ALTER TABLE books ADD CONSTRAINT books_language_id_fkey
+    foreign key(language_id) references languages(id) on delete cascade;
@@ -0,0 +1,8 @@
+ALTER TABLE authors
+    DROP CONSTRAINT IF EXISTS authors_life_years_check,
+    DROP COLUMN biography,
+    DROP COLUMN birth_year,
+    DROP COLUMN death_year,
+    DROP COLUMN country,
+    DROP COLUMN photo_url,
+    DROP COLUMN pen_names;
@@ -0,0 +1,8 @@
+ALTER TABLE authors
+    ADD COLUMN biography text,
+    ADD COLUMN birth_year smallint,
+    ADD COLUMN death_year smallint,
+    ADD COLUMN country text,
+    ADD COLUMN photo_url text,
+    ADD COLUMN pen_names text[] not null default '{}',
+    ADD CONSTRAINT authors_life_years_check check (death_year IS NULL OR birth_year IS NULL OR death_year >= birth_year);
@@ -0,0 +1,37 @@
+ALTER TABLE books
+    ADD COLUMN author_id bigint,
+    ADD COLUMN genre_id smallint;
+
+UPDATE books b SET author_id = (
+    SELECT ba.author_id FROM book_authors ba
+    WHERE ba.book_id = b.id
+    ORDER BY ba.role <> 'author', ba.position
+    LIMIT 1
+);
+
+UPDATE books b SET genre_id = (
+    SELECT MIN(bg.genre_id) FROM book_genres bg WHERE bg.book_id = b.id
+);
+
+-- Books without an author or a genre can't be kept in the old schema.
+-- The rollback fails instead of deleting them with their orders, reviews
+-- and stock, they have to be fixed by hand first.
+DO $$
+DECLARE
+    missing bigint;
+BEGIN
+    SELECT COUNT(*) INTO missing FROM books WHERE author_id IS NULL OR genre_id IS NULL;
+    IF missing > 0 THEN
+        RAISE EXCEPTION '% books have no author or genre, assign them before rolling back', missing;
+    END IF;
+END;
+$$;
+
+ALTER TABLE books
+    ALTER COLUMN author_id SET NOT NULL,
+    ALTER COLUMN genre_id SET NOT NULL,
+    ADD CONSTRAINT books_author_id_fkey foreign key(author_id) references authors(id) on delete cascade,
+    ADD CONSTRAINT books_genre_id_fkey foreign key(genre_id) references genres(id) on delete restrict;
+
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS book_authors;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS book_authors(
+    book_id bigint not null,
+    author_id bigint not null,
+    role text not null default 'author',
+    position smallint not null default 0,
+
+    primary key(book_id, author_id, role),
+    check (role IN ('author', 'translator', 'illustrator', 'editor')),
+    foreign key(book_id) references books(id) on delete cascade,
+    foreign key(author_id) references authors(id) on delete restrict
+);
+
+CREATE INDEX IF NOT EXISTS book_authors_author_id_idx ON book_authors(author_id);
+
+CREATE TABLE IF NOT EXISTS book_genres(
+    book_id bigint not null,
+    genre_id smallint not null,
+
+    primary key(book_id, genre_id),
+    foreign key(book_id) references books(id) on delete cascade,
+    foreign key(genre_id) references genres(id) on delete restrict
+);
+
+CREATE INDEX IF NOT EXISTS book_genres_genre_id_idx ON book_genres(genre_id);
+
+INSERT INTO book_authors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 0 FROM books;
+
+INSERT INTO book_genres (book_id, genre_id)
+SELECT id, genre_id FROM books;
+
+ALTER TABLE books
+    DROP COLUMN author_id,
+    DROP COLUMN genre_id;
@@ -0,0 +1,14 @@
+ALTER TABLE books
+    DROP COLUMN work_id,
+    DROP COLUMN isbn,
+    DROP COLUMN publisher_id,
+    DROP COLUMN format,
+    DROP COLUMN edition,
+    DROP COLUMN publication_date,
+    DROP COLUMN width_mm,
+    DROP COLUMN height_mm,
+    DROP COLUMN thickness_mm,
+    DROP COLUMN weight_g;
+
+DROP TABLE IF EXISTS works;
+DROP TABLE IF EXISTS publishers;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS publishers(
+    id bigserial primary key,
+    name text not null unique,
+    country text,
+    website text
+);
+
+CREATE TABLE IF NOT EXISTS works(
+    id bigserial primary key,
+    title text not null
+);
+
+-- Every existing book becomes the only edition of its own work.
+INSERT INTO works (id, title) SELECT id, title FROM books;
+SELECT setval(pg_get_serial_sequence('works', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM works;
+
+ALTER TABLE books
+    ADD COLUMN work_id bigint,
+    ADD COLUMN isbn text unique,
+    ADD COLUMN publisher_id bigint,
+    ADD COLUMN format text not null default 'paperback',
+    ADD COLUMN edition text,
+    ADD COLUMN publication_date date,
+    ADD COLUMN width_mm smallint,
+    ADD COLUMN height_mm smallint,
+    ADD COLUMN thickness_mm smallint,
+    ADD COLUMN weight_g int,
+    ADD CONSTRAINT books_work_id_fkey foreign key(work_id) references works(id) on delete restrict,
+    ADD CONSTRAINT books_publisher_id_fkey foreign key(publisher_id) references publishers(id) on delete restrict,
+    ADD CONSTRAINT books_format_check check (format IN ('hardcover', 'paperback', 'ebook'));
+
+UPDATE books SET work_id = id;
+
+ALTER TABLE books ALTER COLUMN work_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS books_work_id_idx ON books(work_id);
@@ -0,0 +1,5 @@
+ALTER TABLE authors
+    DROP COLUMN photo;
+
+ALTER TABLE books
+    DROP COLUMN cover;
@@ -0,0 +1,5 @@
+ALTER TABLE books
+    ADD COLUMN cover jsonb;
+
+ALTER TABLE authors
+    ADD COLUMN photo jsonb;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS stock_movements(
+    id bigserial primary key,
+    book_id bigint not null,
+    type text not null,
+    quantity int not null,
+    reason text not null,
+    actor text not null,
+    reference text,
+    created_at timestamptz not null default now(),
+
+    foreign key(book_id) references books(id) on delete cascade,
+    constraint stock_movements_type_check check (type IN ('receipt', 'sale', 'return', 'adjustment', 'reservation')),
+    constraint stock_movements_quantity_check check (
+        (type IN ('receipt', 'return') AND quantity > 0) OR
+        (type = 'sale' AND quantity < 0) OR
+        (type IN ('adjustment', 'reservation') AND quantity <> 0)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_book_id_idx ON stock_movements(book_id, id);
+
+-- Movements are never changed. They may only be deleted together with their book.
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'stock movements are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE PROCEDURE stock_movements_append_only();
+
+-- Current counts become the opening balance of the ledger.
+INSERT INTO stock_movements (book_id, type, quantity, reason, actor)
+SELECT id, 'adjustment', count, 'opening balance', 'system' FROM books WHERE count <> 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_reservations;
+
+ALTER TABLE baskets_books DROP CONSTRAINT IF EXISTS baskets_books_pkey;
@@ -0,0 +1,19 @@
+ALTER TABLE baskets_books ADD PRIMARY KEY (basket_id, book_id);
+
+CREATE TABLE IF NOT EXISTS stock_reservations(
+    id bigserial primary key,
+    book_id bigint not null,
+    basket_id bigint,
+    quantity int not null check (quantity > 0),
+    expires_at timestamptz not null,
+    created_at timestamptz not null default now(),
+
+    foreign key(book_id) references books(id) on delete cascade,
+    -- Reservations of deleted baskets are kept until they expire,
+    -- so they are released in the stock ledger.
+    foreign key(basket_id) references baskets(id) on delete set null,
+    unique(basket_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_book_id_idx ON stock_reservations(book_id);
+CREATE INDEX IF NOT EXISTS stock_reservations_expires_at_idx ON stock_reservations(expires_at);
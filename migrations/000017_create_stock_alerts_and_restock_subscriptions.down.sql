@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS restock_subscriptions;
+DROP TABLE IF EXISTS stock_alerts;
+
+ALTER TABLE books DROP COLUMN IF EXISTS reorder_threshold;
@@ -0,0 +1,31 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS reorder_threshold int CHECK (reorder_threshold >= 0);
+
+CREATE TABLE IF NOT EXISTS stock_alerts(
+    id bigserial primary key,
+    book_id bigint not null,
+    on_hand int not null,
+    threshold int not null,
+    created_at timestamptz not null default now(),
+    notified_at timestamptz,
+    resolved_at timestamptz,
+
+    foreign key(book_id) references books(id) on delete cascade
+);
+
+-- A book has at most one open alert, it's resolved when the book is restocked.
+CREATE UNIQUE INDEX IF NOT EXISTS stock_alerts_open_idx ON stock_alerts(book_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS restock_subscriptions(
+    id bigserial primary key,
+    book_id bigint not null,
+    user_id bigint not null,
+    created_at timestamptz not null default now(),
+    notified_at timestamptz,
+
+    foreign key(book_id) references books(id) on delete cascade,
+    foreign key(user_id) references users(id) on delete cascade
+);
+
+-- A user has at most one pending subscription per book.
+CREATE UNIQUE INDEX IF NOT EXISTS restock_subscriptions_pending_idx
+    ON restock_subscriptions(book_id, user_id) WHERE notified_at IS NULL;
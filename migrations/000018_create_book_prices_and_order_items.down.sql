@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS order_items;
+
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_basket_id_key,
+    DROP CONSTRAINT IF EXISTS orders_status_check,
+    DROP COLUMN IF EXISTS status,
+    ALTER COLUMN id DROP DEFAULT;
+
+DROP SEQUENCE IF EXISTS orders_id_seq;
+
+DROP FUNCTION IF EXISTS book_sale_price(bigint, timestamptz);
+DROP FUNCTION IF EXISTS book_list_price(bigint, timestamptz);
+DROP TABLE IF EXISTS book_prices;
@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS book_prices(
+    id bigserial primary key,
+    book_id bigint not null,
+    kind text not null,
+    price decimal(10,2) not null check (price >= 0),
+    effective_from timestamptz not null,
+    effective_to timestamptz,
+    reason text,
+    created_at timestamptz not null default now(),
+
+    foreign key(book_id) references books(id) on delete cascade,
+    constraint book_prices_kind_check check (kind IN ('list', 'sale')),
+    constraint book_prices_period_check check (effective_to IS NULL OR effective_to > effective_from),
+    constraint book_prices_sale_end_check check (kind = 'list' OR effective_to IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS book_prices_book_id_idx ON book_prices(book_id, kind, effective_from);
+
+-- Current prices become the first records of the history.
+INSERT INTO book_prices (book_id, kind, price, effective_from, reason)
+SELECT id, 'list', price, now(), 'initial price' FROM books;
+
+-- book_list_price returns the list price of the book effective at the given moment.
+CREATE OR REPLACE FUNCTION book_list_price(book bigint, moment timestamptz) RETURNS decimal(10,2) AS $$
+    SELECT price FROM book_prices
+    WHERE book_id = book AND kind = 'list' AND effective_from <= moment
+        AND (effective_to IS NULL OR effective_to > moment)
+    ORDER BY effective_from DESC
+    LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+-- book_sale_price returns the sale price of the book effective at the given moment
+-- or null if there is no sale.
+CREATE OR REPLACE FUNCTION book_sale_price(book bigint, moment timestamptz) RETURNS decimal(10,2) AS $$
+    SELECT price FROM book_prices
+    WHERE book_id = book AND kind = 'sale' AND effective_from <= moment AND effective_to > moment
+    ORDER BY effective_from DESC
+    LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+CREATE SEQUENCE IF NOT EXISTS orders_id_seq OWNED BY orders.id;
+SELECT setval('orders_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM orders;
+
+ALTER TABLE orders
+    ALTER COLUMN id SET DEFAULT nextval('orders_id_seq'),
+    ADD COLUMN status text not null default 'pending',
+    ADD CONSTRAINT orders_status_check check (status IN ('pending', 'paid', 'cancelled')),
+    ADD CONSTRAINT orders_basket_id_key unique (basket_id);
+
+CREATE TABLE IF NOT EXISTS order_items(
+    id bigserial primary key,
+    order_id bigint not null,
+    book_id bigint,
+    title text not null,
+    quantity int not null check (quantity > 0),
+    list_price decimal(10,2) not null,
+    unit_price decimal(10,2) not null,
+
+    foreign key(order_id) references orders(id) on delete cascade,
+    -- Lines keep the title and prices of deleted books.
+    foreign key(book_id) references books(id) on delete set null
+);
+
+CREATE INDEX IF NOT EXISTS order_items_order_id_idx ON order_items(order_id);
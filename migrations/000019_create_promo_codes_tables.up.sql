@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS promo_codes(
+    id bigserial primary key,
+    code text not null,
+    description text,
+    kind text not null,
+    -- Percent off for percentage codes and amount off for fixed codes.
+    value decimal(10,2),
+    buy_quantity int,
+    get_quantity int,
+    -- Codes apply to books matching all of the specified scopes.
+    genre_id smallint,
+    author_id bigint,
+    book_id bigint,
+    min_total decimal(10,2) check (min_total > 0),
+    usage_limit int check (usage_limit > 0),
+    per_user_limit int check (per_user_limit > 0),
+    starts_at timestamptz not null default now(),
+    ends_at timestamptz,
+    stackable boolean not null default false,
+    created_at timestamptz not null default now(),
+
+    foreign key(genre_id) references genres(id) on delete cascade,
+    foreign key(author_id) references authors(id) on delete cascade,
+    foreign key(book_id) references books(id) on delete cascade,
+    constraint promo_codes_kind_check check (kind IN ('percentage', 'fixed', 'buy_x_get_y')),
+    constraint promo_codes_value_check check (
+        (kind = 'percentage' AND value > 0 AND value <= 100) OR
+        (kind = 'fixed' AND value > 0) OR
+        (kind = 'buy_x_get_y' AND buy_quantity > 0 AND get_quantity > 0)
+    ),
+    constraint promo_codes_period_check check (ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- Codes are case-insensitive.
+CREATE UNIQUE INDEX IF NOT EXISTS promo_codes_code_idx ON promo_codes(upper(code));
+
+CREATE TABLE IF NOT EXISTS baskets_promo_codes(
+    basket_id bigint not null,
+    promo_code_id bigint not null,
+    added_at timestamptz not null default now(),
+
+    primary key(basket_id, promo_code_id),
+    foreign key(basket_id) references baskets(id) on delete cascade,
+    foreign key(promo_code_id) references promo_codes(id) on delete cascade
+);
+
+CREATE TABLE IF NOT EXISTS promo_redemptions(
+    id bigserial primary key,
+    promo_code_id bigint,
+    code text not null,
+    order_id bigint not null,
+    user_id bigint not null,
+    amount decimal(10,2) not null check (amount > 0),
+    created_at timestamptz not null default now(),
+
+    -- Redemptions keep the code of deleted promo codes.
+    foreign key(promo_code_id) references promo_codes(id) on delete set null,
+    foreign key(order_id) references orders(id) on delete cascade,
+    foreign key(user_id) references users(id) on delete cascade,
+    unique(order_id, promo_code_id)
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_promo_code_id_idx ON promo_redemptions(promo_code_id, user_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount decimal(10,2) not null default 0;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS currencies(
+    code char(3) primary key,
+    name text not null,
+    -- Amounts are stored with two decimal places at most.
+    decimals smallint not null default 2 check (decimals BETWEEN 0 AND 2),
+    rounding text not null default 'half_up',
+    -- Amounts are rounded to a multiple of the increment if it's set, e.g. 0.05.
+    increment decimal(10,2) check (increment > 0),
+    base boolean not null default false,
+
+    constraint currencies_code_check check (code ~ '^[A-Z]{3}$'),
+    constraint currencies_rounding_check check (rounding IN ('half_up', 'half_even', 'up', 'down'))
+);
+
+-- There is exactly one base currency, exchange rates are relative to it.
+CREATE UNIQUE INDEX IF NOT EXISTS currencies_base_idx ON currencies(base) WHERE base;
+
+INSERT INTO currencies (code, name, decimals, rounding, increment, base) VALUES
+    ('USD', 'US Dollar', 2, 'half_up', NULL, true),
+    ('EUR', 'Euro', 2, 'half_even', NULL, false),
+    ('GBP', 'Pound Sterling', 2, 'half_up', NULL, false),
+    ('CHF', 'Swiss Franc', 2, 'half_up', 0.05, false),
+    ('JPY', 'Yen', 0, 'half_up', NULL, false)
+ON CONFLICT (code) DO NOTHING;
+
+-- rate is the number of units of the currency for one unit of the base currency.
+CREATE TABLE IF NOT EXISTS exchange_rates(
+    currency char(3) primary key,
+    rate decimal(20,10) not null check (rate > 0),
+    source text,
+    updated_at timestamptz not null default now(),
+
+    foreign key(currency) references currencies(code) on delete cascade
+);
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS currency char(3) not null default 'USD' references currencies(code);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS currency char(3) references currencies(code) on delete set null;
+
+-- Orders keep their currency and its exchange rate at checkout.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS currency char(3) not null default 'USD' references currencies(code),
+    ADD COLUMN IF NOT EXISTS exchange_rate decimal(20,10) not null default 1 check (exchange_rate > 0);
@@ -0,0 +1,16 @@
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS tax,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS discount;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS tax_region,
+    DROP COLUMN IF EXISTS tax_country,
+    DROP COLUMN IF EXISTS prices_include_tax,
+    DROP COLUMN IF EXISTS tax;
+
+ALTER TABLE baskets
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS country;
+
+DROP TABLE IF EXISTS tax_rates;
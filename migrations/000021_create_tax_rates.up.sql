@@ -0,0 +1,34 @@
+-- rate is a percentage of the price. Rates without a region apply to the
+-- whole country, rates without a category apply to all books.
+CREATE TABLE IF NOT EXISTS tax_rates(
+    id bigserial primary key,
+    country char(2) not null,
+    region text,
+    category text,
+    rate decimal(7,4) not null check (rate >= 0 AND rate <= 100),
+    created_at timestamptz not null default now(),
+
+    constraint tax_rates_country_check check (country ~ '^[A-Z]{2}$'),
+    constraint tax_rates_category_check check (category IN ('print', 'ebook'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_scope_idx
+    ON tax_rates(country, COALESCE(region, ''), COALESCE(category, ''));
+
+-- Shipping destination of the basket the tax is calculated for.
+ALTER TABLE baskets
+    ADD COLUMN IF NOT EXISTS country char(2),
+    ADD COLUMN IF NOT EXISTS region text;
+
+-- Orders keep the destination and the pricing mode the tax was calculated with.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS tax decimal(10,2) not null default 0,
+    ADD COLUMN IF NOT EXISTS prices_include_tax boolean not null default false,
+    ADD COLUMN IF NOT EXISTS tax_country char(2),
+    ADD COLUMN IF NOT EXISTS tax_region text;
+
+-- discount is the part of promo code discounts taken off the line.
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS discount decimal(10,2) not null default 0,
+    ADD COLUMN IF NOT EXISTS tax_rate decimal(7,4) not null default 0,
+    ADD COLUMN IF NOT EXISTS tax decimal(10,2) not null default 0;
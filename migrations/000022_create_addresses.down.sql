@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS billing_address,
+    DROP COLUMN IF EXISTS shipping_address;
+
+ALTER TABLE baskets DROP COLUMN IF EXISTS address_id;
+
+DROP TABLE IF EXISTS addresses;
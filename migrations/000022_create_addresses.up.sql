@@ -0,0 +1,39 @@
+-- Structured addresses replace the free-text address of the user. The
+-- free-text address is kept as deprecated, since it has no separate country
+-- and city to be moved to addresses.
+-- A user has at most one default shipping and one default billing address.
+CREATE TABLE IF NOT EXISTS addresses(
+    id bigserial primary key,
+    user_id bigint not null references users(id) on delete cascade,
+    recipient text not null,
+    lines text[] not null,
+    city text not null,
+    region text,
+    postal_code text,
+    country char(2) not null,
+    phone text,
+    default_shipping boolean not null default false,
+    default_billing boolean not null default false,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint addresses_lines_check check (cardinality(lines) BETWEEN 1 AND 3),
+    constraint addresses_country_check check (country ~ '^[A-Z]{2}$')
+);
+
+CREATE INDEX IF NOT EXISTS addresses_user_id_idx ON addresses(user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS addresses_default_shipping_idx
+    ON addresses(user_id) WHERE default_shipping;
+
+CREATE UNIQUE INDEX IF NOT EXISTS addresses_default_billing_idx
+    ON addresses(user_id) WHERE default_billing;
+
+-- Shipping address chosen for the basket.
+ALTER TABLE baskets
+    ADD COLUMN IF NOT EXISTS address_id bigint references addresses(id) on delete set null;
+
+-- Orders keep copies of their addresses, so they don't change with the user's address book.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_address jsonb,
+    ADD COLUMN IF NOT EXISTS billing_address jsonb;
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS order_status_changes;
+
+UPDATE orders SET status = 'paid' WHERE status IN ('shipped', 'delivered');
+
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_status_check,
+    ADD CONSTRAINT orders_status_check check (status IN ('pending', 'paid', 'cancelled'));
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS tracking_number,
+    DROP COLUMN IF EXISTS shipping_cost,
+    DROP COLUMN IF EXISTS shipping_kind,
+    DROP COLUMN IF EXISTS shipping_method,
+    DROP COLUMN IF EXISTS shipping_method_id;
+
+ALTER TABLE baskets DROP COLUMN IF EXISTS shipping_method_id;
+
+DROP TABLE IF EXISTS shipping_rates;
+DROP TABLE IF EXISTS shipping_methods;
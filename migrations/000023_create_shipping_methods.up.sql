@@ -0,0 +1,56 @@
+CREATE TABLE IF NOT EXISTS shipping_methods(
+    id bigserial primary key,
+    code text not null unique,
+    name text not null,
+    kind text not null,
+    description text,
+    active boolean not null default true,
+    created_at timestamptz not null default now(),
+
+    constraint shipping_methods_kind_check check (kind IN ('courier', 'pickup', 'post'))
+);
+
+-- Rates are in the base currency. Rates without a region apply to the whole
+-- country. max_weight_g and min_total limit parcels the rate applies to,
+-- shipping is free once the parcel total reaches free_over.
+CREATE TABLE IF NOT EXISTS shipping_rates(
+    id bigserial primary key,
+    method_id bigint not null references shipping_methods(id) on delete cascade,
+    country char(2) not null,
+    region text,
+    max_weight_g int check (max_weight_g > 0),
+    min_total decimal(10,2) check (min_total >= 0),
+    price decimal(10,2) not null check (price >= 0),
+    free_over decimal(10,2) check (free_over >= 0),
+
+    constraint shipping_rates_country_check check (country ~ '^[A-Z]{2}$')
+);
+
+CREATE INDEX IF NOT EXISTS shipping_rates_method_id_idx ON shipping_rates(method_id, country);
+
+-- Shipping method chosen for the basket.
+ALTER TABLE baskets
+    ADD COLUMN IF NOT EXISTS shipping_method_id bigint references shipping_methods(id) on delete set null;
+
+-- Orders keep the name and kind of the shipping method and its cost at checkout.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_method_id bigint references shipping_methods(id) on delete set null,
+    ADD COLUMN IF NOT EXISTS shipping_method text,
+    ADD COLUMN IF NOT EXISTS shipping_kind text,
+    ADD COLUMN IF NOT EXISTS shipping_cost decimal(10,2) not null default 0,
+    ADD COLUMN IF NOT EXISTS tracking_number text;
+
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_status_check,
+    ADD CONSTRAINT orders_status_check check (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled'));
+
+CREATE TABLE IF NOT EXISTS order_status_changes(
+    id bigserial primary key,
+    order_id bigint not null references orders(id) on delete cascade,
+    status text not null,
+    tracking_number text,
+    note text,
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS order_status_changes_order_id_idx ON order_status_changes(order_id);
@@ -0,0 +1,45 @@
+-- Payments of orders through the payment provider. intent_id identifies
+-- the payment at the provider, refunded is the sum of its refunds.
+CREATE TABLE IF NOT EXISTS payments(
+    id bigserial primary key,
+    order_id bigint not null references orders(id) on delete cascade,
+    provider text not null,
+    intent_id text not null,
+    client_secret text,
+    amount decimal(10,2) not null check (amount >= 0),
+    currency char(3) not null,
+    status text not null default 'pending',
+    failure_reason text,
+    refunded decimal(10,2) not null default 0,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint payments_intent_id_key unique (provider, intent_id),
+    constraint payments_status_check check (status IN ('pending', 'processing', 'succeeded', 'failed')),
+    constraint payments_refunded_check check (refunded >= 0 AND refunded <= amount)
+);
+
+CREATE INDEX IF NOT EXISTS payments_order_id_idx ON payments(order_id);
+
+CREATE TABLE IF NOT EXISTS payment_refunds(
+    id bigserial primary key,
+    payment_id bigint not null references payments(id) on delete cascade,
+    refund_id text not null,
+    amount decimal(10,2) not null check (amount > 0),
+    reason text,
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS payment_refunds_payment_id_idx ON payment_refunds(payment_id);
+
+-- Webhook events of providers which have been handled,
+-- so redelivered events are not applied twice.
+CREATE TABLE IF NOT EXISTS payment_events(
+    provider text not null,
+    event_id text not null,
+    type text not null,
+    payment_id bigint references payments(id) on delete cascade,
+    received_at timestamptz not null default now(),
+
+    primary key (provider, event_id)
+);
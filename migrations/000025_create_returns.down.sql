@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS return_request_items;
+DROP TABLE IF EXISTS return_requests;
+
+DELETE FROM order_status_changes WHERE event <> 'status_changed';
+
+ALTER TABLE order_status_changes DROP COLUMN IF EXISTS event;
@@ -0,0 +1,33 @@
+-- Order history records events of the order besides status changes,
+-- e.g. steps of returns and refunds.
+ALTER TABLE order_status_changes
+    ADD COLUMN IF NOT EXISTS event text not null default 'status_changed';
+
+-- Returns of order lines requested by customers. refund_amount and
+-- payment_id are set once returned books are received and refunded.
+CREATE TABLE IF NOT EXISTS return_requests(
+    id bigserial primary key,
+    order_id bigint not null references orders(id) on delete cascade,
+    status text not null default 'requested',
+    reason text not null,
+    comment text,
+    note text,
+    refund_amount decimal(10,2) check (refund_amount >= 0),
+    payment_id bigint references payments(id) on delete set null,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint return_requests_status_check check (status IN ('requested', 'approved', 'rejected', 'received')),
+    constraint return_requests_reason_check check (reason IN ('damaged', 'wrong_item', 'not_as_described', 'other'))
+);
+
+CREATE INDEX IF NOT EXISTS return_requests_order_id_idx ON return_requests(order_id);
+CREATE INDEX IF NOT EXISTS return_requests_status_idx ON return_requests(status);
+
+CREATE TABLE IF NOT EXISTS return_request_items(
+    return_id bigint not null references return_requests(id) on delete cascade,
+    order_item_id bigint not null references order_items(id) on delete cascade,
+    quantity int not null check (quantity > 0),
+
+    primary key (return_id, order_item_id)
+);
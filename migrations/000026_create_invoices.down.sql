@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS invoices;
+DROP FUNCTION IF EXISTS invoices_immutable();
+DROP TABLE IF EXISTS invoice_sequences;
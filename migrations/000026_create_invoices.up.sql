@@ -0,0 +1,48 @@
+-- Last numbers of the invoice and credit note series. Numbers are taken
+-- under the row lock in the transaction issuing the document, so series
+-- have no gaps.
+CREATE TABLE IF NOT EXISTS invoice_sequences(
+    kind text primary key,
+    last_number bigint not null default 0
+);
+
+INSERT INTO invoice_sequences (kind) VALUES ('invoice'), ('credit_note') ON CONFLICT DO NOTHING;
+
+-- Invoices of paid orders and credit notes of refunds. document is the
+-- snapshot of everything printed, so the document doesn't change with
+-- the order. Documents outlive their orders and refunds.
+CREATE TABLE IF NOT EXISTS invoices(
+    id bigserial primary key,
+    kind text not null,
+    number text not null unique,
+    order_id bigint references orders(id) on delete set null,
+    invoice_id bigint references invoices(id),
+    refund_id bigint references payment_refunds(id) on delete set null,
+    currency char(3) not null,
+    total decimal(10,2) not null check (total >= 0),
+    document jsonb not null,
+    issued_at timestamptz not null default now(),
+
+    constraint invoices_kind_check check (kind IN ('invoice', 'credit_note')),
+    constraint invoices_credit_note_check check ((kind = 'credit_note') = (invoice_id IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_order_id_key ON invoices(order_id) WHERE kind = 'invoice';
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_refund_id_key ON invoices(refund_id);
+CREATE INDEX IF NOT EXISTS invoices_order_id_idx ON invoices(order_id);
+
+-- Issued documents are never changed or deleted. Only references to deleted
+-- orders and refunds are cleared.
+CREATE OR REPLACE FUNCTION invoices_immutable() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND pg_trigger_depth() > 1
+        AND to_jsonb(NEW) - 'order_id' - 'refund_id' = to_jsonb(OLD) - 'order_id' - 'refund_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'invoices are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER invoices_immutable
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE PROCEDURE invoices_immutable();
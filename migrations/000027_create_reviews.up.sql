@@ -0,0 +1,35 @@
+-- Reviews of books by customers who bought them. Hidden reviews are
+-- shown neither in the book reviews nor in its rating, flagged ones are
+-- marked by staff for a closer look.
+CREATE TABLE IF NOT EXISTS reviews(
+    id bigserial primary key,
+    book_id bigint not null references books(id) on delete cascade,
+    user_id bigint not null references users(id) on delete cascade,
+    rating smallint not null,
+    title text,
+    body text,
+    status text not null default 'published',
+    flagged boolean not null default false,
+    moderation_note text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint reviews_user_id_book_id_key unique (user_id, book_id),
+    constraint reviews_rating_check check (rating BETWEEN 1 AND 5),
+    constraint reviews_status_check check (status IN ('published', 'hidden'))
+);
+
+CREATE INDEX IF NOT EXISTS reviews_book_id_idx ON reviews(book_id) WHERE status = 'published';
+
+-- Reports of reviews by users. Reports are resolved once staff moderate
+-- the review, the user may report it again after that.
+CREATE TABLE IF NOT EXISTS review_reports(
+    id bigserial primary key,
+    review_id bigint not null references reviews(id) on delete cascade,
+    user_id bigint not null references users(id) on delete cascade,
+    reason text not null,
+    created_at timestamptz not null default now(),
+    resolved_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS review_reports_open_key ON review_reports(review_id, user_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,26 @@
+-- Named wishlists of users. A wishlist can be shared by a link with
+-- its unguessable share token.
+CREATE TABLE IF NOT EXISTS wishlists(
+    id bigserial primary key,
+    user_id bigint not null references users(id) on delete cascade,
+    name text not null,
+    share_token text unique,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint wishlists_user_id_name_key unique (user_id, name)
+);
+
+-- Items keep the price to pay and whether the book was in stock when
+-- it was added, so price drops and restocks can be shown.
+CREATE TABLE IF NOT EXISTS wishlist_items(
+    wishlist_id bigint not null references wishlists(id) on delete cascade,
+    book_id bigint not null references books(id) on delete cascade,
+    added_price decimal(10,2) not null,
+    added_in_stock boolean not null,
+    created_at timestamptz not null default now(),
+
+    primary key (wishlist_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS wishlist_items_book_id_idx ON wishlist_items(book_id);
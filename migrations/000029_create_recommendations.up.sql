@@ -0,0 +1,32 @@
+-- Recommendations are precomputed periodically. Books bought together in
+-- paid orders come first, books sharing authors, genres and language fill
+-- up the list of books with few orders. Position ranks recommendations
+-- of the book starting from 1.
+CREATE TABLE IF NOT EXISTS book_recommendations(
+    book_id bigint not null references books(id) on delete cascade,
+    recommended_book_id bigint not null references books(id) on delete cascade,
+    kind text not null,
+    score double precision not null,
+    position int not null,
+    computed_at timestamptz not null default now(),
+
+    primary key (book_id, recommended_book_id),
+    constraint book_recommendations_kind_check check (kind IN ('bought_together', 'similar'))
+);
+
+CREATE INDEX IF NOT EXISTS book_recommendations_book_id_position_idx
+    ON book_recommendations(book_id, position);
+
+-- Personal feeds are built from recommendations of books the user bought.
+CREATE TABLE IF NOT EXISTS user_recommendations(
+    user_id bigint not null references users(id) on delete cascade,
+    book_id bigint not null references books(id) on delete cascade,
+    score double precision not null,
+    position int not null,
+    computed_at timestamptz not null default now(),
+
+    primary key (user_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_recommendations_user_id_position_idx
+    ON user_recommendations(user_id, position);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS collection_books;
+DROP TABLE IF EXISTS collections;
+DROP INDEX IF EXISTS orders_date_idx;
+ALTER TABLE books DROP COLUMN IF EXISTS created_at;
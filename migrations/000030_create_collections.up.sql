@@ -0,0 +1,34 @@
+-- Books remember when they were added to the catalog. Existing books are
+-- dated by their first stock movement, or by the migration if they have none.
+ALTER TABLE books ADD COLUMN IF NOT EXISTS created_at timestamptz not null default now();
+
+UPDATE books b SET created_at = m.created_at
+FROM (SELECT book_id, MIN(created_at) AS created_at FROM stock_movements GROUP BY book_id) m
+WHERE m.book_id = b.id;
+
+CREATE INDEX IF NOT EXISTS books_created_at_idx ON books(created_at);
+
+-- Bestsellers and trending books are counted from orders placed in a period.
+CREATE INDEX IF NOT EXISTS orders_date_idx ON orders(date);
+
+-- Collections curated by staff. Slugs of computed lists are reserved.
+CREATE TABLE IF NOT EXISTS collections(
+    id bigserial primary key,
+    slug text not null unique,
+    name text not null,
+    description text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    constraint collections_slug_check check (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    constraint collections_slug_reserved_check check (slug NOT IN ('bestsellers', 'new-arrivals', 'trending'))
+);
+
+-- Books of the collection in the order set by staff.
+CREATE TABLE IF NOT EXISTS collection_books(
+    collection_id bigint not null references collections(id) on delete cascade,
+    book_id bigint not null references books(id) on delete cascade,
+    position int not null,
+
+    primary key (collection_id, book_id)
+);
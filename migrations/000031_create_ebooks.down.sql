@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS entitlements;
+ALTER TABLE order_items DROP COLUMN IF EXISTS digital;
+DROP TABLE IF EXISTS book_files;
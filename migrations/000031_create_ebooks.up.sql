@@ -0,0 +1,33 @@
+-- Files of ebooks in the blob store, one per format.
+CREATE TABLE IF NOT EXISTS book_files(
+    book_id bigint not null references books(id) on delete cascade,
+    format text not null check (format IN ('epub', 'pdf')),
+    object_key text not null unique,
+    size bigint not null check (size > 0),
+    created_at timestamptz not null default now(),
+
+    primary key (book_id, format)
+);
+
+-- Digital lines need no stock and aren't shipped, they are delivered
+-- as entitlements once the order is paid.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS digital boolean not null default false;
+
+UPDATE order_items i SET digital = true
+FROM books b
+WHERE b.id = i.book_id AND b.format = 'ebook';
+
+-- Entitlements let customers download ebooks they paid for. Every digital
+-- order line gets one, it keeps the title of the deleted book.
+CREATE TABLE IF NOT EXISTS entitlements(
+    id bigserial primary key,
+    user_id bigint not null references users(id) on delete cascade,
+    order_item_id bigint not null unique references order_items(id) on delete cascade,
+    book_id bigint references books(id) on delete set null,
+    title text not null,
+    download_count int not null default 0 check (download_count >= 0),
+    download_limit int not null check (download_limit > 0),
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS entitlements_user_id_idx ON entitlements(user_id);
@@ -0,0 +1,5 @@
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS photo_url text;
+
+UPDATE authors
+SET photo_url = photo->>'originalUrl', photo = NULL
+WHERE photo->>'key' = '';
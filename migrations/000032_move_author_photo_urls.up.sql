@@ -0,0 +1,14 @@
+-- Photo URLs set before uploads existed become external photos, which have
+-- no key since their files are not kept in the blob store.
+UPDATE authors
+SET photo = jsonb_build_object(
+    'key', '',
+    'originalUrl', photo_url,
+    'mediumUrl', photo_url,
+    'thumbnailUrl', photo_url,
+    'width', 0,
+    'height', 0
+)
+WHERE photo IS NULL AND photo_url IS NOT NULL AND photo_url <> '';
+
+ALTER TABLE authors DROP COLUMN IF EXISTS photo_url;
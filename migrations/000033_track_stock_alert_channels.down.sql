@@ -0,0 +1,3 @@
+ALTER TABLE stock_alerts
+    DROP COLUMN IF EXISTS emailed_at,
+    DROP COLUMN IF EXISTS posted_at;
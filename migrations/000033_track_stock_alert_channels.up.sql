@@ -0,0 +1,7 @@
+-- Alerts are sent through every channel separately, so a failing channel
+-- is retried without resending the alert through the others.
+ALTER TABLE stock_alerts
+    ADD COLUMN IF NOT EXISTS emailed_at timestamptz,
+    ADD COLUMN IF NOT EXISTS posted_at timestamptz;
+
+UPDATE stock_alerts SET emailed_at = notified_at, posted_at = notified_at WHERE notified_at IS NOT NULL;
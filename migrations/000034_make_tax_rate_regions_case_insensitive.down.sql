@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tax_rates_scope_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_scope_idx
+    ON tax_rates(country, COALESCE(region, ''), COALESCE(category, ''));
@@ -0,0 +1,13 @@
+-- Regions are matched case-insensitively, so rates of the same region
+-- written in another case are duplicates. The oldest rate is kept.
+DELETE FROM tax_rates r
+USING tax_rates o
+WHERE o.id < r.id
+    AND o.country = r.country
+    AND lower(COALESCE(o.region, '')) = lower(COALESCE(r.region, ''))
+    AND COALESCE(o.category, '') = COALESCE(r.category, '');
+
+DROP INDEX IF EXISTS tax_rates_scope_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_scope_idx
+    ON tax_rates(country, lower(COALESCE(region, '')), COALESCE(category, ''));
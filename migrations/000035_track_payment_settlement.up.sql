@@ -0,0 +1,9 @@
+-- Orders of captured payments are settled in several steps which are
+-- retried until all of them succeed, settled_at marks settled payments.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS settled_at timestamptz;
+
+UPDATE payments p SET settled_at = p.updated_at
+FROM orders o
+WHERE o.id = p.order_id
+    AND p.status = 'succeeded'
+    AND o.status IN ('paid', 'shipped', 'delivered');
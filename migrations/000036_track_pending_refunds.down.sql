@@ -0,0 +1,9 @@
+-- Pending refunds have not been made, so there is nothing to keep.
+DELETE FROM payment_refunds WHERE status = 'pending';
+
+ALTER TABLE payment_refunds
+    DROP CONSTRAINT IF EXISTS payment_refunds_reference_key,
+    DROP CONSTRAINT IF EXISTS payment_refunds_status_check,
+    DROP COLUMN IF EXISTS reference,
+    DROP COLUMN IF EXISTS status,
+    ALTER COLUMN refund_id SET NOT NULL;
@@ -0,0 +1,15 @@
+-- Refunds are recorded as pending with the payment locked before they are
+-- sent to the provider, the id of the pending refund is the idempotency key
+-- of the provider refund. A refund which failed on the way stays pending
+-- and is sent again with the same key instead of refunding twice.
+-- reference identifies refunds which are retried, e.g. "return 5".
+ALTER TABLE payment_refunds
+    ALTER COLUMN refund_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS status text not null default 'succeeded',
+    ADD COLUMN IF NOT EXISTS reference text;
+
+ALTER TABLE payment_refunds
+    ADD CONSTRAINT payment_refunds_status_check check (
+        status IN ('pending', 'succeeded') AND (status = 'pending' OR refund_id IS NOT NULL)
+    ),
+    ADD CONSTRAINT payment_refunds_reference_key unique (payment_id, reference);
@@ -0,0 +1,13 @@
+-- Returns refunded from several payments keep the payment of the largest
+-- refund only.
+ALTER TABLE return_requests
+    ADD COLUMN IF NOT EXISTS payment_id bigint references payments(id) on delete set null;
+
+UPDATE return_requests r SET payment_id = (
+    SELECT payment_id FROM return_refunds
+    WHERE return_id = r.id
+    ORDER BY amount DESC, payment_id
+    LIMIT 1
+);
+
+DROP TABLE IF EXISTS return_refunds;
@@ -0,0 +1,30 @@
+-- Returns are refunded from several payments of the order when no payment
+-- covers the whole amount. return_refunds are amounts refunded from each
+-- payment, they are set once with the return locked, so concurrent
+-- receives refund the same amounts.
+CREATE TABLE IF NOT EXISTS return_refunds(
+    return_id bigint not null references return_requests(id) on delete cascade,
+    payment_id bigint not null references payments(id) on delete cascade,
+    amount decimal(10,2) not null check (amount > 0),
+
+    primary key (return_id, payment_id)
+);
+
+INSERT INTO return_refunds (return_id, payment_id, amount)
+SELECT id, payment_id, refund_amount
+FROM return_requests
+WHERE payment_id IS NOT NULL AND refund_amount > 0
+ON CONFLICT DO NOTHING;
+
+-- Refunds of returns are resumed by their reference, so refunds made
+-- before get the reference of their return and are not made again.
+UPDATE payment_refunds f SET reference = 'return ' || r.id
+FROM return_requests r
+WHERE r.payment_id = f.payment_id
+    AND f.reference IS NULL
+    AND f.id = (
+        SELECT min(id) FROM payment_refunds
+        WHERE payment_id = r.payment_id AND reason LIKE 'return ' || r.id || ':%'
+    );
+
+ALTER TABLE return_requests DROP COLUMN IF EXISTS payment_id;
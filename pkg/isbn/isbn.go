@@ -0,0 +1,147 @@
+// Package isbn implements validation and conversion of
+// International Standard Book Numbers.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidLength is returned when ISBN has neither 10 nor 13 digits.
+	ErrInvalidLength = errors.New("isbn must contain 10 or 13 digits")
+
+	// ErrInvalidCharacter is returned when ISBN contains an unexpected character.
+	ErrInvalidCharacter = errors.New("isbn contains invalid character")
+
+	// ErrInvalidPrefix is returned when ISBN-13 starts with neither 978 nor 979.
+	ErrInvalidPrefix = errors.New("isbn-13 must start with 978 or 979")
+
+	// ErrInvalidChecksum is returned when ISBN check digit is wrong.
+	ErrInvalidChecksum = errors.New("isbn check digit is invalid")
+
+	// ErrNotConvertible is returned when ISBN-13 has no ISBN-10 equivalent.
+	ErrNotConvertible = errors.New("only isbn with 978 prefix can be converted to isbn-10")
+)
+
+// clean removes hyphens and spaces from ISBN and upper-cases the X check digit.
+func clean(s string) string {
+	s = strings.ToUpper(s)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Normalize validates given ISBN-10 or ISBN-13, which may contain
+// hyphens and spaces, and returns it as ISBN-13 consisting of digits only.
+func Normalize(s string) (string, error) {
+	s = clean(s)
+
+	switch len(s) {
+	case 10:
+		if err := validate10(s); err != nil {
+			return "", err
+		}
+		return to13(s), nil
+	case 13:
+		if err := validate13(s); err != nil {
+			return "", err
+		}
+		return s, nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// IsValid reports whether s is a valid ISBN-10 or ISBN-13.
+func IsValid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// To10 converts ISBN to ISBN-10. Returns ErrNotConvertible
+// if ISBN-13 doesn't start with 978 prefix.
+func To10(s string) (string, error) {
+	s, err := Normalize(s)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(s, "978") {
+		return "", ErrNotConvertible
+	}
+
+	body := s[3:12]
+	return body + string(checkDigit10(body)), nil
+}
+
+// validate10 checks ISBN-10 characters and check digit.
+func validate10(s string) error {
+	for i, r := range s {
+		if (r < '0' || r > '9') && !(i == 9 && r == 'X') {
+			return ErrInvalidCharacter
+		}
+	}
+
+	if checkDigit10(s[:9]) != rune(s[9]) {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// validate13 checks ISBN-13 characters, prefix and check digit.
+func validate13(s string) error {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return ErrInvalidCharacter
+		}
+	}
+
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return ErrInvalidPrefix
+	}
+
+	if checkDigit13(s[:12]) != rune(s[12]) {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// to13 converts valid ISBN-10 to ISBN-13.
+func to13(s string) string {
+	body := "978" + s[:9]
+	return body + string(checkDigit13(body))
+}
+
+// checkDigit10 computes ISBN-10 check digit for the first 9 digits.
+func checkDigit10(body string) rune {
+	sum := 0
+	for i, r := range body {
+		sum += (10 - i) * int(r-'0')
+	}
+
+	digit := (11 - sum%11) % 11
+	if digit == 10 {
+		return 'X'
+	}
+	return rune('0' + digit)
+}
+
+// checkDigit13 computes ISBN-13 check digit for the first 12 digits.
+func checkDigit13(body string) rune {
+	sum := 0
+	for i, r := range body {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+
+	return rune('0' + (10-sum%10)%10)
+}
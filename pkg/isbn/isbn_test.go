@@ -0,0 +1,74 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "isbn-13", input: "9780306406157", want: "9780306406157"},
+		{name: "isbn-13 with hyphens", input: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "isbn-13 with 979 prefix", input: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "isbn-10", input: "0306406152", want: "9780306406157"},
+		{name: "isbn-10 with spaces", input: "0 306 40615 2", want: "9780306406157"},
+		{name: "isbn-10 with X check digit", input: "0-8044-2957-X", want: "9780804429573"},
+		{name: "isbn-10 with lower case x", input: "080442957x", want: "9780804429573"},
+		{name: "empty", input: "", wantErr: ErrInvalidLength},
+		{name: "too short", input: "030640615", wantErr: ErrInvalidLength},
+		{name: "too long", input: "97803064061570", wantErr: ErrInvalidLength},
+		{name: "isbn-10 letter", input: "03064O6152", wantErr: ErrInvalidCharacter},
+		{name: "isbn-10 X not last", input: "X306406152", wantErr: ErrInvalidCharacter},
+		{name: "isbn-13 X check digit", input: "978030640615X", wantErr: ErrInvalidCharacter},
+		{name: "isbn-13 unknown prefix", input: "9770306406157", wantErr: ErrInvalidPrefix},
+		{name: "isbn-10 wrong check digit", input: "0306406153", wantErr: ErrInvalidChecksum},
+		{name: "isbn-13 wrong check digit", input: "9780306406158", wantErr: ErrInvalidChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+			if valid := IsValid(tt.input); valid != (tt.wantErr == nil) {
+				t.Errorf("IsValid() = %v, want %v", valid, !valid)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "isbn-13", input: "978-0-306-40615-7", want: "0306406152"},
+		{name: "X check digit", input: "9780804429573", want: "080442957X"},
+		{name: "isbn-10", input: "0-8044-2957-x", want: "080442957X"},
+		{name: "979 prefix", input: "9791090636071", wantErr: ErrNotConvertible},
+		{name: "invalid", input: "9780306406158", wantErr: ErrInvalidChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To10(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("To10() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("To10() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package pdf
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// fontName is the PostScript name of the embedded font.
+const fontName = "DejaVuSansMono"
+
+// fontData is the TrueType font documents are set in. It's monospaced, so
+// columns of text line up, and covers Latin, Greek and Cyrillic scripts.
+// See fonts/LICENSE for its license.
+//
+//go:embed fonts/DejaVuSansMono.ttf
+var fontData []byte
+
+// mono is the parsed embedded font.
+var mono = mustParseFont(fontData)
+
+// subsetTables are tables of the font kept in subsets. They are all the
+// tables a PDF reader needs to render glyphs of embedded TrueType fonts.
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// errInvalidFont is returned when the font can't be parsed.
+var errInvalidFont = errors.New("invalid TrueType font")
+
+// font is a parsed TrueType font. Metrics are in font units.
+type font struct {
+	tables     map[string][]byte
+	unitsPerEm int
+	glyphs     map[rune]uint16
+	advances   []uint16
+	offsets    []uint32
+	bbox       [4]int16
+	ascent     int16
+	descent    int16
+}
+
+func mustParseFont(data []byte) *font {
+	f, err := parseFont(data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// parseFont parses tables of the TrueType font needed to embed it.
+func parseFont(data []byte) (*font, error) {
+	tables, err := readTables(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &font{tables: tables}
+	for _, tag := range []string{"cmap", "glyf", "head", "hhea", "hmtx", "loca", "maxp"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no %q table", errInvalidFont, tag)
+		}
+	}
+
+	head, hhea, maxp := f.tables["head"], f.tables["hhea"], f.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, errInvalidFont
+	}
+
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	for i := range f.bbox {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+	f.ascent = int16(binary.BigEndian.Uint16(hhea[4:]))
+	f.descent = int16(binary.BigEndian.Uint16(hhea[6:]))
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	if err := f.parseAdvances(numGlyphs, int(binary.BigEndian.Uint16(hhea[34:]))); err != nil {
+		return nil, err
+	}
+	if err := f.parseOffsets(numGlyphs, binary.BigEndian.Uint16(head[50:]) == 1); err != nil {
+		return nil, err
+	}
+	if err := f.parseCmap(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// readTables reads tables of the font file by their tags.
+func readTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errInvalidFont
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		entry := 12 + 16*i
+		if entry+16 > len(data) {
+			return nil, errInvalidFont
+		}
+		tag := string(data[entry : entry+4])
+		offset := int(binary.BigEndian.Uint32(data[entry+8:]))
+		length := int(binary.BigEndian.Uint32(data[entry+12:]))
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("%w: table %q is out of bounds", errInvalidFont, tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+
+	return tables, nil
+}
+
+// parseAdvances reads advance widths of glyphs. Glyphs after the last
+// horizontal metric have its advance width.
+func (f *font) parseAdvances(numGlyphs, numMetrics int) error {
+	hmtx := f.tables["hmtx"]
+	if numMetrics == 0 || numMetrics > numGlyphs || len(hmtx) < 4*numMetrics {
+		return fmt.Errorf("%w: bad horizontal metrics", errInvalidFont)
+	}
+
+	f.advances = make([]uint16, numGlyphs)
+	for i := range f.advances {
+		if i < numMetrics {
+			f.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+		} else {
+			f.advances[i] = f.advances[numMetrics-1]
+		}
+	}
+	return nil
+}
+
+// parseOffsets reads offsets of glyphs in the glyf table.
+func (f *font) parseOffsets(numGlyphs int, long bool) error {
+	loca := f.tables["loca"]
+	size := 2
+	if long {
+		size = 4
+	}
+	if len(loca) < size*(numGlyphs+1) {
+		return fmt.Errorf("%w: bad glyph locations", errInvalidFont)
+	}
+
+	f.offsets = make([]uint32, numGlyphs+1)
+	for i := range f.offsets {
+		if long {
+			f.offsets[i] = binary.BigEndian.Uint32(loca[4*i:])
+		} else {
+			f.offsets[i] = 2 * uint32(binary.BigEndian.Uint16(loca[2*i:]))
+		}
+		if f.offsets[i] > uint32(len(f.tables["glyf"])) || i > 0 && f.offsets[i] < f.offsets[i-1] {
+			return fmt.Errorf("%w: bad glyph locations", errInvalidFont)
+		}
+	}
+	return nil
+}
+
+// parseCmap reads the Unicode character map. The full repertoire map of
+// format 12 is preferred to the Basic Multilingual Plane map of format 4.
+func (f *font) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return errInvalidFont
+	}
+
+	var bmp, full []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+8*i+8 <= len(cmap); i++ {
+		record := cmap[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+
+		subtable := cmap[offset:]
+		switch format := binary.BigEndian.Uint16(subtable); {
+		case format == 4 && (platform == 0 || platform == 3 && encoding == 1):
+			bmp = subtable
+		case format == 12 && (platform == 0 || platform == 3 && encoding == 10):
+			full = subtable
+		}
+	}
+
+	f.glyphs = make(map[rune]uint16)
+	switch {
+	case full != nil:
+		return f.parseCmap12(full)
+	case bmp != nil:
+		return f.parseCmap4(bmp)
+	}
+	return fmt.Errorf("%w: no Unicode character map", errInvalidFont)
+}
+
+func (f *font) parseCmap4(subtable []byte) error {
+	if len(subtable) < 14 {
+		return errInvalidFont
+	}
+	segments := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	ends := 14
+	starts := ends + 2*segments + 2
+	deltas := starts + 2*segments
+	ranges := deltas + 2*segments
+	if len(subtable) < ranges+2*segments {
+		return errInvalidFont
+	}
+
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(subtable[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(subtable[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(subtable[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(subtable[ranges+2*i:]))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := uint16(c) + delta
+			if rangeOffset != 0 {
+				at := ranges + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(subtable) {
+					return errInvalidFont
+				}
+				if glyph = binary.BigEndian.Uint16(subtable[at:]); glyph != 0 {
+					glyph += delta
+				}
+			}
+			if glyph != 0 && int(glyph) < len(f.advances) {
+				f.glyphs[rune(c)] = glyph
+			}
+		}
+	}
+	return nil
+}
+
+func (f *font) parseCmap12(subtable []byte) error {
+	if len(subtable) < 16 {
+		return errInvalidFont
+	}
+	groups := int(binary.BigEndian.Uint32(subtable[12:]))
+	if len(subtable) < 16+12*groups {
+		return errInvalidFont
+	}
+
+	for i := 0; i < groups; i++ {
+		group := subtable[16+12*i:]
+		start := binary.BigEndian.Uint32(group)
+		end := binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if g := glyph + c - start; g != 0 && g < uint32(len(f.advances)) {
+				f.glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+	return nil
+}
+
+// glyph returns the glyph of the character and whether the font has it.
+func (f *font) glyph(r rune) (uint16, bool) {
+	g, ok := f.glyphs[r]
+	return g, ok
+}
+
+// width returns the advance width of the glyph in thousandths of the font size.
+func (f *font) width(glyph uint16) int {
+	return f.scale(int(f.advances[glyph]))
+}
+
+// scale converts font units to thousandths of the font size.
+func (f *font) scale(units int) int {
+	return int(math.Round(float64(units) * 1000 / float64(f.unitsPerEm)))
+}
+
+// glyphData returns the outline of the glyph.
+func (f *font) glyphData(glyph uint16) []byte {
+	return f.tables["glyf"][f.offsets[glyph]:f.offsets[glyph+1]]
+}
+
+// components returns glyphs the composite glyph is made of.
+// Simple glyphs have no components.
+func (f *font) components(glyph uint16) []uint16 {
+	data := f.glyphData(glyph)
+	if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+		return nil
+	}
+
+	const (
+		argsAreWords   = 0x0001
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		haveTwoByTwo   = 0x0080
+	)
+
+	// Every component has flags, the glyph number and at least two
+	// byte offsets, then optional words of its transformation.
+	var components []uint16
+	for at := 10; at+4 <= len(data); {
+		flags := binary.BigEndian.Uint16(data[at:])
+		components = append(components, binary.BigEndian.Uint16(data[at+2:]))
+
+		at += 6
+		if flags&argsAreWords != 0 {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return components
+}
+
+// subset returns the font program with outlines of the glyphs only.
+// Glyphs keep their numbers, other glyphs are left empty. Components of
+// composite glyphs and the missing glyph are kept as well.
+func (f *font) subset(glyphs []uint16) []byte {
+	keep := make(map[uint16]bool)
+	queue := append([]uint16{0}, glyphs...)
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		if keep[g] || int(g) >= len(f.advances) {
+			continue
+		}
+		keep[g] = true
+		queue = append(queue, f.components(g)...)
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*(len(f.advances)+1))
+	for g := range f.advances {
+		binary.BigEndian.PutUint32(loca[4*g:], uint32(glyf.Len()))
+		if keep[uint16(g)] {
+			glyf.Write(f.glyphData(uint16(g)))
+			glyf.Write(make([]byte, -glyf.Len()&3))
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*len(f.advances):], uint32(glyf.Len()))
+
+	// Offsets are long, the checksum adjustment is recomputed by readers
+	// which need it.
+	head := append([]byte{}, f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok {
+			if data, ok := f.tables[tag]; ok {
+				tables[tag] = data
+			}
+		}
+	}
+
+	return writeFont(tables)
+}
+
+// writeFont writes the TrueType font file of the tables.
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	searchRange, selector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		selector++
+	}
+
+	var buf bytes.Buffer
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange*16))
+	binary.BigEndian.PutUint16(header[8:], uint16(selector))
+	binary.BigEndian.PutUint16(header[10:], uint16((len(tags)-searchRange)*16))
+	buf.Write(header)
+
+	offset := 12 + 16*len(tags)
+	for _, tag := range tags {
+		data := tables[tag]
+		entry := make([]byte, 16)
+		copy(entry, tag)
+		binary.BigEndian.PutUint32(entry[4:], checksum(data))
+		binary.BigEndian.PutUint32(entry[8:], uint32(offset))
+		binary.BigEndian.PutUint32(entry[12:], uint32(len(data)))
+		buf.Write(entry)
+		offset += (len(data) + 3) &^ 3
+	}
+
+	for _, tag := range tags {
+		buf.Write(tables[tag])
+		buf.Write(make([]byte, -len(tables[tag])&3))
+	}
+
+	return buf.Bytes()
+}
+
+// checksum returns the checksum of the table.
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
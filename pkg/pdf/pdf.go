@@ -0,0 +1,329 @@
+// Package pdf writes plain text documents in Portable Document Format.
+//
+// Text is set in the embedded DejaVu Sans Mono font on A4 pages, so columns
+// of text line up and Latin, Greek and Cyrillic scripts are printed as is.
+// Documents embed the subset of the font with their glyphs only. Characters
+// the font lacks are printed as question marks.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// Columns is the number of characters fitting a line.
+	// Longer lines are wrapped.
+	Columns = 92
+	// Rows is the number of lines fitting a page.
+	Rows = 62
+
+	// Page is A4 in points with equal margins.
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 48
+
+	fontSize = 9
+	leading  = 12
+
+	// Font flags of the descriptor: fixed pitch and nonsymbolic.
+	fontFlags = 1 | 32
+	// stemWidth is the thickness of vertical stems, readers don't
+	// use it for embedded fonts.
+	stemWidth = 80
+)
+
+// Write writes the text as a PDF document with the title to w. Lines are
+// wrapped at Columns characters and pages are filled with Rows lines,
+// a form feed character starts a new page.
+func Write(w io.Writer, title, text string) error {
+	pages := paginate(text)
+	used := make(glyphSet)
+
+	// Objects are numbered from 1: the catalog, the page tree, the font,
+	// the document info, the descendant font, its descriptor, its program
+	// and its map to Unicode, then every page followed by its content
+	// stream. Font objects are made once all glyphs are known.
+	objects := [][]byte{
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		nil,
+		nil,
+		[]byte(fmt.Sprintf("<< /Title %s /Producer (ReadyRead) >>", textString(title))),
+		nil,
+		nil,
+		nil,
+		nil,
+	}
+
+	kids := make([]string, 0, len(pages))
+	for _, page := range pages {
+		pageId := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageId))
+
+		content, err := stream(page, used)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, pageId+1,
+		)), content)
+	}
+
+	objects[1] = []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	if err := fontObjects(objects[2:8], used); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(object)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// fontObjects makes objects of the font subset to the used glyphs: the
+// composite font, then after the document info the descendant font, its
+// descriptor, its program and its map to Unicode.
+func fontObjects(objects [][]byte, used glyphSet) error {
+	glyphs := used.sorted()
+	name := subsetTag(glyphs) + "+" + fontName
+
+	// Glyphs are as wide as the space unless they are listed.
+	space, _ := mono.glyph(' ')
+	widths := make([]string, 0)
+	for _, g := range glyphs {
+		if w := mono.width(g); w != mono.width(space) {
+			widths = append(widths, fmt.Sprintf("%d [%d]", g, w))
+		}
+	}
+
+	subset := mono.subset(glyphs)
+	program, err := streamObject(subset, fmt.Sprintf(" /Length1 %d", len(subset)))
+	if err != nil {
+		return err
+	}
+
+	toUnicode, err := streamObject(used.cmap(), "")
+	if err != nil {
+		return err
+	}
+
+	objects[0] = []byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [5 0 R] /ToUnicode 8 0 R >>",
+		name,
+	))
+	objects[2] = []byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor 6 0 R /CIDToGIDMap /Identity /DW %d /W [%s] >>",
+		name, mono.width(space), strings.Join(widths, " "),
+	))
+	objects[3] = []byte(fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d] /ItalicAngle 0 "+
+			"/Ascent %d /Descent %d /CapHeight %d /StemV %d /FontFile2 7 0 R >>",
+		name, fontFlags,
+		mono.scale(int(mono.bbox[0])), mono.scale(int(mono.bbox[1])),
+		mono.scale(int(mono.bbox[2])), mono.scale(int(mono.bbox[3])),
+		mono.scale(int(mono.ascent)), mono.scale(int(mono.descent)), mono.scale(int(mono.ascent)),
+		stemWidth,
+	))
+	objects[4] = program
+	objects[5] = toUnicode
+
+	return nil
+}
+
+// subsetTag returns the tag of the font subset with the glyphs. Subsets
+// with the same glyphs have the same tag.
+func subsetTag(glyphs []uint16) string {
+	hash := crc32.NewIEEE()
+	for _, g := range glyphs {
+		hash.Write([]byte{byte(g >> 8), byte(g)})
+	}
+
+	sum := hash.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	return string(tag)
+}
+
+// paginate splits the text into pages of wrapped lines.
+// The document has at least one page even if the text is empty.
+func paginate(text string) [][]string {
+	text = strings.ReplaceAll(text, "\r", "")
+	text = strings.ReplaceAll(text, "\t", "    ")
+
+	pages := make([][]string, 0, 1)
+	for _, part := range strings.Split(text, "\f") {
+		var page []string
+		for _, line := range strings.Split(strings.TrimSuffix(part, "\n"), "\n") {
+			for _, wrapped := range wrap(line) {
+				if len(page) == Rows {
+					pages = append(pages, page)
+					page = nil
+				}
+				page = append(page, wrapped)
+			}
+		}
+		pages = append(pages, page)
+	}
+
+	return pages
+}
+
+// wrap splits the line into lines of Columns characters at most.
+func wrap(line string) []string {
+	var lines []string
+	for utf8.RuneCountInString(line) > Columns {
+		cut := 0
+		for i := 0; i < Columns; i++ {
+			_, size := utf8.DecodeRuneInString(line[cut:])
+			cut += size
+		}
+		lines = append(lines, line[:cut])
+		line = line[cut:]
+	}
+	return append(lines, line)
+}
+
+// stream returns the compressed content stream object which shows lines
+// of the page from its top. Glyphs of lines are added to the used ones.
+func stream(lines []string, used glyphSet) ([]byte, error) {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin-fontSize)
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("T* ")
+		}
+		fmt.Fprintf(&content, "%s Tj\n", used.encode(line))
+	}
+	content.WriteString("ET")
+
+	return streamObject(content.Bytes(), "")
+}
+
+// streamObject returns the stream object with the compressed data and
+// additional entries of its dictionary.
+func streamObject(data []byte, entries string) ([]byte, error) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	var object bytes.Buffer
+	fmt.Fprintf(&object, "<< /Length %d /Filter /FlateDecode%s >>\nstream\n", compressed.Len(), entries)
+	compressed.WriteTo(&object)
+	object.WriteString("\nendstream")
+
+	return object.Bytes(), nil
+}
+
+// glyphSet maps glyphs used by the document to characters they show.
+type glyphSet map[uint16]rune
+
+// encode returns the string as a PDF hex string of glyphs of its characters
+// and adds them to the set. Characters the font lacks are shown as
+// question marks.
+func (s glyphSet) encode(text string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range text {
+		g, ok := mono.glyph(r)
+		if !ok {
+			r = '?'
+			g, _ = mono.glyph(r)
+		}
+		if _, ok := s[g]; !ok {
+			s[g] = r
+		}
+		fmt.Fprintf(&b, "%04X", g)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// sorted returns glyphs of the set in ascending order.
+func (s glyphSet) sorted() []uint16 {
+	glyphs := make([]uint16, 0, len(s))
+	for g := range s {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// cmap returns the CMap which maps glyphs of the set to their characters,
+// so text of the document can be searched and copied.
+func (s glyphSet) cmap() []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// Blocks of mappings have 100 entries at most.
+	glyphs := s.sorted()
+	for len(glyphs) > 0 {
+		block := glyphs
+		if len(block) > 100 {
+			block = block[:100]
+		}
+		glyphs = glyphs[len(block):]
+
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(block))
+		for _, g := range block {
+			fmt.Fprintf(&b, "<%04X> <", g)
+			for _, unit := range utf16.Encode([]rune{s[g]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return b.Bytes()
+}
+
+// textString returns the string as a PDF text string in UTF-16.
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// objectPattern matches objects of the document.
+var objectPattern = regexp.MustCompile(`(?s)(\d+) 0 obj\n(.*?)\nendobj\n`)
+
+// dump returns the readable dump of the document: its objects with
+// decompressed streams, the font program is replaced with its size.
+// It fails the test if the cross-reference table doesn't point to objects.
+func dump(t *testing.T, data []byte) string {
+	t.Helper()
+
+	xref := bytes.LastIndex(data, []byte("\nstartxref\n"))
+	if xref < 0 {
+		t.Fatal("no startxref")
+	}
+	start, err := strconv.Atoi(strings.Fields(string(data[xref+len("\nstartxref\n"):]))[0])
+	if err != nil || !bytes.HasPrefix(data[start:], []byte("xref\n")) {
+		t.Fatalf("startxref %d doesn't point to the cross-reference table", start)
+	}
+
+	var b strings.Builder
+	matches := objectPattern.FindAllSubmatchIndex(data, -1)
+	for i, m := range matches {
+		id := string(data[m[2]:m[3]])
+		if id != strconv.Itoa(i+1) {
+			t.Fatalf("object %s is numbered out of order", id)
+		}
+		entry := fmt.Sprintf("%010d 00000 n \n", m[0])
+		if !bytes.Contains(data[start:], []byte(entry)) {
+			t.Errorf("object %s at %d is not in the cross-reference table", id, m[0])
+		}
+
+		body := data[m[4]:m[5]]
+		at := bytes.Index(body, []byte("\nstream\n"))
+		if at < 0 {
+			fmt.Fprintf(&b, "%s: %s\n", id, body)
+			continue
+		}
+
+		dict := string(body[:at])
+		zr, err := zlib.NewReader(bytes.NewReader(bytes.TrimSuffix(body[at+len("\nstream\n"):], []byte("\nendstream"))))
+		if err != nil {
+			t.Fatalf("object %s: %v", id, err)
+		}
+		content, err := ioutil.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("object %s: %v", id, err)
+		}
+
+		// Compressed lengths depend on the zlib implementation.
+		dict = regexp.MustCompile(`/Length \d+ `).ReplaceAllString(dict, "")
+		if strings.Contains(dict, "/Length1") {
+			fmt.Fprintf(&b, "%s: %s\n[font program of %d bytes]\n", id, dict, len(content))
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n%s\n", id, dict, content)
+	}
+
+	return b.String()
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		text  string
+	}{
+		{name: "empty", title: "Empty"},
+		{name: "latin", title: "Invoice INV-2022-000042", text: "Invoice (copy) \\ Total: 18.38 EUR\n\tÀ la carte, 2× café"},
+		{name: "unicode", title: "Счёт № 42", text: "Лев Толстой — «Война и мир»\nΚαλημέρα, 1 234,50 ₽\nEmoji 😀 is missing"},
+		{name: "pages", title: "Pages", text: strings.Repeat("0123456789", 10) + "\fsecond page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.title, tt.text); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-1.4\n")) || !bytes.HasSuffix(buf.Bytes(), []byte("%%EOF\n")) {
+				t.Fatal("document has no header or end of file marker")
+			}
+
+			got := dump(t, buf.Bytes())
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("Write() dump differs from %s, go test -update rewrites it:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	long := strings.Repeat("я", Columns+8)
+
+	tests := []struct {
+		name  string
+		text  string
+		pages []int
+	}{
+		{name: "empty", text: "", pages: []int{1}},
+		{name: "trailing newline", text: "a\nb\n", pages: []int{2}},
+		{name: "form feed", text: "a\fb\nc", pages: []int{1, 2}},
+		{name: "wrapped by characters", text: long, pages: []int{2}},
+		{name: "full page", text: strings.Repeat("a\n", Rows+1), pages: []int{Rows, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := paginate(tt.text)
+			got := make([]int, 0, len(pages))
+			for _, page := range pages {
+				got = append(got, len(page))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.pages) {
+				t.Errorf("paginate() lines = %v, want %v", got, tt.pages)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	used := make(glyphSet)
+
+	question, _ := mono.glyph('?')
+	zhe, ok := mono.glyph('Ж')
+	if !ok {
+		t.Fatal("font has no Cyrillic glyphs")
+	}
+
+	want := fmt.Sprintf("<%04X%04X>", zhe, question)
+	if got := used.encode("Ж😀"); got != want {
+		t.Errorf("encode() = %s, want %s", got, want)
+	}
+	if used[zhe] != 'Ж' || used[question] != '?' {
+		t.Errorf("used glyphs = %v", used)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	// Accented letters are composites of the letter and the accent.
+	var composite uint16
+	for _, r := range "ÅÄÖÉЁй" {
+		if g, ok := mono.glyph(r); ok && len(mono.components(g)) > 0 {
+			composite = g
+			break
+		}
+	}
+	if composite == 0 {
+		t.Fatal("font has no composite glyphs")
+	}
+
+	letter, _ := mono.glyph('A')
+	tables, err := readTables(mono.subset([]uint16{letter, composite}))
+	if err != nil {
+		t.Fatalf("readTables() error = %v", err)
+	}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok {
+			t.Errorf("subset has no %q table", tag)
+		}
+	}
+
+	subset := &font{tables: tables, advances: mono.advances}
+	if err := subset.parseOffsets(len(mono.advances), true); err != nil {
+		t.Fatalf("parseOffsets() error = %v", err)
+	}
+
+	kept := map[uint16]bool{0: true, letter: true, composite: true}
+	for _, g := range mono.components(composite) {
+		kept[g] = true
+	}
+
+	for g := range mono.advances {
+		data := subset.glyphData(uint16(g))
+		switch {
+		case kept[uint16(g)] && !bytes.HasPrefix(data, mono.glyphData(uint16(g))):
+			t.Errorf("glyph %d differs from the font", g)
+		case !kept[uint16(g)] && len(data) > 0:
+			t.Errorf("glyph %d is not removed", g)
+		}
+	}
+}